@@ -0,0 +1,170 @@
+package owl
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// AssetKind classifies the subresources of a page
+type AssetKind string
+
+const (
+	AssetScript AssetKind = "script"
+	AssetStyle  AssetKind = "style"
+	AssetFont   AssetKind = "font"
+	AssetImage  AssetKind = "image"
+)
+
+// Subresource is a script, stylesheet, font or image loaded by a page
+type Subresource struct {
+	Kind AssetKind
+	URL  *url.URL
+	// ThirdParty is set when the asset is served from another site than the page
+	ThirdParty bool
+	// Integrity holds the subresource integrity metadata, empty when the element has none
+	Integrity   string
+	CrossOrigin string
+	// Size is the Content-Length reported by Client.AuditAssets, -1 when unknown
+	Size        int64
+	ContentType string
+	// Err is the error of the HEAD request made by Client.AuditAssets, an HTTPError when the status is not 2xx
+	Err error
+}
+
+// SRIApplicable reports whether browsers check subresource integrity for the asset,
+// which is the case for scripts and stylesheets
+func (s Subresource) SRIApplicable() bool {
+	return s.Kind == AssetScript || s.Kind == AssetStyle
+}
+
+// AssetReport summarizes the subresources of a page
+type AssetReport struct {
+	Assets []Subresource
+	// TotalSize adds up the known sizes of the assets
+	TotalSize  int64
+	ThirdParty int
+	// MissingSRI counts third-party scripts and stylesheets without integrity metadata
+	MissingSRI int
+}
+
+// Subresources returns the scripts, stylesheets, preloaded fonts and images below the Node
+// in document order, with their URLs resolved against base or the document's <base href>.
+// A nil base stands for the URL the document was fetched from
+func (r *Root) Subresources(base *url.URL) []Subresource {
+	page := r.pageURL(base)
+	base = documentBase(r.Node, page)
+	var assets []Subresource
+	add := func(kind AssetKind, n *html.Node, ref string) {
+		ref = strings.TrimSpace(ref)
+		if ref == "" || strings.HasPrefix(ref, "data:") {
+			return
+		}
+		u, err := url.Parse(ref)
+		if err != nil {
+			return
+		}
+		if base != nil {
+			u = base.ResolveReference(u)
+		}
+		assets = append(assets, Subresource{
+			Kind:        kind,
+			URL:         u,
+			ThirdParty:  page != nil && u.Host != "" && !sameSite(u.Hostname(), page.Hostname()),
+			Integrity:   strings.TrimSpace(attrValue(n, "integrity")),
+			CrossOrigin: attrValue(n, "crossorigin"),
+			Size:        -1,
+		})
+	}
+	walk(r.Node, func(n *html.Node) bool {
+		if n.Type != html.ElementNode {
+			return true
+		}
+		switch n.Data {
+		case "script":
+			add(AssetScript, n, attrValue(n, "src"))
+		case "img":
+			add(AssetImage, n, attrValue(n, "src"))
+		case "link":
+			rel := strings.Fields(strings.ToLower(attrValue(n, "rel")))
+			switch {
+			case containsString(rel, "stylesheet"):
+				add(AssetStyle, n, attrValue(n, "href"))
+			case containsString(rel, "preload") || containsString(rel, "prefetch"):
+				switch attrValue(n, "as") {
+				case "font":
+					add(AssetFont, n, attrValue(n, "href"))
+				case "style":
+					add(AssetStyle, n, attrValue(n, "href"))
+				case "script":
+					add(AssetScript, n, attrValue(n, "href"))
+				case "image":
+					add(AssetImage, n, attrValue(n, "href"))
+				}
+			}
+		}
+		return true
+	})
+	return assets
+}
+
+// AuditAssets inventories the subresources of page and asks for the size of each one with a HEAD request.
+// Failed requests are reported on the asset itself
+func (c *Client) AuditAssets(page *Root, base *url.URL) AssetReport {
+	var report AssetReport
+	for _, asset := range page.Subresources(base) {
+		if asset.URL.IsAbs() {
+			asset.Size, asset.ContentType, asset.Err = c.head(asset.URL.String())
+		}
+		if asset.Size > 0 {
+			report.TotalSize += asset.Size
+		}
+		if asset.ThirdParty {
+			report.ThirdParty++
+			if asset.SRIApplicable() && asset.Integrity == "" {
+				report.MissingSRI++
+			}
+		}
+		report.Assets = append(report.Assets, asset)
+	}
+	return report
+}
+
+// head returns the Content-Length and Content-Type the server reports for url.
+// Responses with a status other than 2xx fail with an HTTPError
+func (c *Client) head(url string) (int64, string, error) {
+	resp, release, err := c.do(http.MethodHead, url, nil)
+	if err != nil {
+		return -1, "", err
+	}
+	release()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return -1, "", newHTTPError(&Response{StatusCode: resp.StatusCode, FinalURL: resp.Request.URL})
+	}
+	return resp.ContentLength, resp.Header.Get("Content-Type"), nil
+}
+
+// httpClient returns the wrapped http.Client, http.DefaultClient when there is none
+func (c *Client) httpClient() *http.Client {
+	if c.Client == nil {
+		return http.DefaultClient
+	}
+	return c.Client
+}
+
+// sameSite reports whether the hosts are equal or one is a subdomain of the other
+func sameSite(a, b string) bool {
+	a, b = strings.ToLower(a), strings.ToLower(b)
+	return a == b || strings.HasSuffix(a, "."+b) || strings.HasSuffix(b, "."+a)
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
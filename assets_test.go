@@ -0,0 +1,68 @@
+package owl
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditAssets(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodHead, r.Method)
+		w.Header().Set("Content-Type", "application/javascript")
+		w.Header().Set("Content-Length", "1200")
+	}))
+	defer srv.Close()
+
+	page := HTMLParseFromString(`<html><head>
+		<link rel="stylesheet" href="/site.css">
+		<link rel="preload" href="/fonts/owl.woff2" as="font" crossorigin>
+		<script src="https://cdn.owl.invalid/lib.js"></script>
+		<script src="https://cdn.owl.invalid/safe.js" integrity="sha384-abc" crossorigin="anonymous"></script>
+		<script>inline()</script>
+	</head><body><img src="img/owl.png"><img src="data:image/png;base64,AAAA"></body></html>`)
+
+	base, _ := url.Parse(srv.URL + "/page")
+	assets := page.Subresources(base)
+	require.Len(t, assets, 5)
+	require.Equal(t, AssetStyle, assets[0].Kind)
+	require.Equal(t, AssetFont, assets[1].Kind)
+	require.True(t, assets[2].ThirdParty)
+	require.Equal(t, "sha384-abc", assets[3].Integrity)
+	require.Equal(t, srv.URL+"/img/owl.png", assets[4].URL.String())
+
+	report := HttpClientWrapper(srv.Client()).AuditAssets(page, base)
+	require.Equal(t, 2, report.ThirdParty)
+	require.Equal(t, 1, report.MissingSRI)
+	require.Equal(t, int64(1200), report.Assets[0].Size)
+	require.Equal(t, int64(1200*3), report.TotalSize)
+	require.Error(t, report.Assets[2].Err)
+}
+
+func TestAuditAssetsMissing(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing.png" {
+			w.Header().Set("Content-Length", "1500")
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Length", "300")
+	}))
+	defer srv.Close()
+
+	page := HTMLParseFromString(`<img src="/owl.png"><img src="/missing.png">`)
+	base, _ := url.Parse(srv.URL + "/")
+	report := HttpClientWrapper(srv.Client()).AuditAssets(page, base)
+	require.Len(t, report.Assets, 2)
+	require.NoError(t, report.Assets[0].Err)
+	missing := report.Assets[1]
+	var httpErr *HTTPError
+	require.ErrorAs(t, missing.Err, &httpErr)
+	require.Equal(t, http.StatusNotFound, httpErr.StatusCode)
+	require.Equal(t, srv.URL+"/missing.png", httpErr.URL)
+	require.Equal(t, int64(-1), missing.Size)
+	require.Equal(t, int64(300), report.TotalSize)
+}
@@ -0,0 +1,58 @@
+package owl
+
+import (
+	"encoding/base64"
+	"net/http"
+)
+
+// credentials is a header carrying the credentials of a Client
+type credentials struct {
+	header, value string
+}
+
+// SetBasicAuth sends user and password with HTTP Basic authentication in every request of c,
+// replacing the credentials set before. Credentials are sent whatever the host of the request
+func (c *Client) SetBasicAuth(user, password string) {
+	token := base64.StdEncoding.EncodeToString([]byte(user + ":" + password))
+	c.auth.Store(&credentials{header: "Authorization", value: "Basic " + token})
+}
+
+// SetBearerToken sends token as a Bearer token in every request of c, replacing the credentials set before.
+// Credentials are sent whatever the host of the request
+func (c *Client) SetBearerToken(token string) {
+	c.auth.Store(&credentials{header: "Authorization", value: "Bearer " + token})
+}
+
+// SetAPIKey sends key in the header of every request of c, such as X-API-Key, replacing the credentials
+// set before. Credentials are sent whatever the host of the request, redirects included
+func (c *Client) SetAPIKey(header, key string) {
+	c.auth.Store(&credentials{header: header, value: key})
+}
+
+// ClearAuth stops sending the credentials set before
+func (c *Client) ClearAuth() {
+	c.auth.Store(nil)
+}
+
+// authorize sets the credentials of c on req
+func (c *Client) authorize(req *http.Request) {
+	if cred := c.auth.Load(); cred != nil {
+		req.Header.Set(cred.header, cred.value)
+	}
+}
+
+// reauthenticate renews the credentials of c after resp to req was 401 Unauthorized and reports
+// whether req can be sent again with them
+func (c *Client) reauthenticate(req *http.Request, resp *http.Response) (bool, error) {
+	if c.Reauthenticate == nil || resp.StatusCode != http.StatusUnauthorized {
+		return false, nil
+	}
+	if req.Body != nil && req.GetBody == nil {
+		return false, nil
+	}
+	if err := c.Reauthenticate(c, resp); err != nil {
+		return false, err
+	}
+	c.authorize(req)
+	return true, nil
+}
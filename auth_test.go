@@ -0,0 +1,95 @@
+package owl
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuth(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, password, ok := r.BasicAuth(); ok {
+			w.Write([]byte("basic " + user + " " + password))
+			return
+		}
+		w.Write([]byte(r.Header.Get("Authorization") + "|" + r.Header.Get("X-Api-Key")))
+	}))
+	defer srv.Close()
+	client := NewClient(&Parameters{HttpClient: srv.Client()})
+	body := func(opts ...RequestOption) string {
+		resp, err := client.GetResponse(srv.URL, opts...)
+		require.NoError(t, err)
+		return string(resp.Body)
+	}
+
+	client.SetBasicAuth("owl", "s3cret")
+	require.Equal(t, "basic owl s3cret", body())
+	client.SetBearerToken("token")
+	require.Equal(t, "Bearer token|", body())
+	require.Equal(t, "Bearer other|", body(WithHeader("Authorization", "Bearer other")))
+	client.SetAPIKey("X-API-Key", "key")
+	require.Equal(t, "|key", body())
+	client.ClearAuth()
+	require.Equal(t, "|", body())
+	// The header map of the parameters is left alone
+	require.NotContains(t, DefaultParameters.Header, "Authorization")
+}
+
+func TestReauthenticate(t *testing.T) {
+	valid := "fresh"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+valid {
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body := new(strings.Builder)
+		if r.Body != nil {
+			buf := make([]byte, 64)
+			n, _ := r.Body.Read(buf)
+			body.Write(buf[:n])
+		}
+		w.Write([]byte("ok " + body.String()))
+	}))
+	defer srv.Close()
+
+	client := &Client{Client: srv.Client()}
+	client.SetBearerToken("expired")
+	renewals := 0
+	client.Reauthenticate = func(c *Client, resp *http.Response) error {
+		renewals++
+		require.Contains(t, resp.Header.Get("WWW-Authenticate"), "invalid_token")
+		c.SetBearerToken("fresh")
+		return nil
+	}
+	resp, err := client.GetResponse(srv.URL)
+	require.NoError(t, err)
+	require.Equal(t, "ok ", string(resp.Body))
+	require.Equal(t, 1, renewals)
+
+	// Bodies are sent again
+	client.SetBearerToken("expired")
+	r, err := client.Post(srv.URL, "text/plain", "hello")
+	require.NoError(t, err)
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, "ok hello", string(data))
+	require.Equal(t, 2, renewals)
+
+	// Credentials are renewed once per request
+	valid = "never"
+	resp, err = client.GetResponse(srv.URL)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	require.Equal(t, 3, renewals)
+
+	failed := errors.New("login server down")
+	client.Reauthenticate = func(*Client, *http.Response) error { return failed }
+	_, err = client.GetResponse(srv.URL)
+	require.ErrorIs(t, err, failed)
+}
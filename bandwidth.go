@@ -0,0 +1,90 @@
+package owl
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// BandwidthLimiter limits how fast response bodies are read, in bytes per second.
+// A single BandwidthLimiter shares its bandwidth between all the bodies it throttles.
+// The RequestTimeout of the Client still covers the whole read of a body
+type BandwidthLimiter struct {
+	rate float64
+	// chunk is the size of the reads at most, so the bandwidth stays even
+	chunk int
+
+	mu     sync.Mutex
+	bucket bucket
+}
+
+// NewBandwidthLimiter returns a BandwidthLimiter of bytesPerSecond, with a burst of a tenth of a second
+func NewBandwidthLimiter(bytesPerSecond int64) *BandwidthLimiter {
+	rate := float64(max(bytesPerSecond, 1))
+	return &BandwidthLimiter{
+		rate:   rate,
+		chunk:  int(min(max(bytesPerSecond/10, 1), 32<<10)),
+		bucket: bucket{tokens: rate / 10, last: time.Now()},
+	}
+}
+
+// WithBandwidth limits how fast the body of the request is read, in bytes per second,
+// on top of the Bandwidth of the Client
+func WithBandwidth(bytesPerSecond int64) RequestOption {
+	return requestOptionFunc(func(r *requestConfig) { r.bandwidth = bytesPerSecond })
+}
+
+// reserve takes n bytes and returns how long to wait for them
+func (l *BandwidthLimiter) reserve(n int, now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.bucket.tokens = min(l.rate/10, l.bucket.tokens+now.Sub(l.bucket.last).Seconds()*l.rate)
+	l.bucket.last = now
+	l.bucket.tokens -= float64(n)
+	if l.bucket.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.bucket.tokens / l.rate * float64(time.Second))
+}
+
+// throttle makes the body of resp read within the bandwidth of limiters
+func throttle(resp *http.Response, limiters ...*BandwidthLimiter) {
+	var active []*BandwidthLimiter
+	for _, l := range limiters {
+		if l != nil {
+			active = append(active, l)
+		}
+	}
+	if len(active) > 0 {
+		resp.Body = &throttledBody{ReadCloser: resp.Body, ctx: resp.Request.Context(), limiters: active}
+	}
+}
+
+// throttledBody waits after every read until the bytes read fit in the bandwidth of its limiters
+type throttledBody struct {
+	io.ReadCloser
+	ctx      context.Context
+	limiters []*BandwidthLimiter
+}
+
+func (b *throttledBody) Read(p []byte) (int, error) {
+	for _, l := range b.limiters {
+		if len(p) > l.chunk {
+			p = p[:l.chunk]
+		}
+	}
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		now := time.Now()
+		var wait time.Duration
+		for _, l := range b.limiters {
+			wait = max(wait, l.reserve(n, now))
+		}
+		if serr := sleep(b.ctx, wait); serr != nil && err == nil {
+			err = serr
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,67 @@
+package owl
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBandwidth(t *testing.T) {
+	payload := bytes.Repeat([]byte("o"), 30000)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(payload)
+	}))
+	defer srv.Close()
+
+	client := &Client{Client: srv.Client()}
+	start := time.Now()
+	resp, err := client.GetResponse(srv.URL)
+	require.NoError(t, err)
+	require.Equal(t, payload, resp.Body)
+	require.Less(t, time.Since(start), 100*time.Millisecond)
+
+	// 30KB at 100KB/s with a burst of 10KB take 200ms
+	start = time.Now()
+	resp, err = client.GetResponse(srv.URL, WithBandwidth(100000))
+	require.NoError(t, err)
+	require.Equal(t, payload, resp.Body)
+	elapsed := time.Since(start)
+	require.GreaterOrEqual(t, elapsed, 180*time.Millisecond)
+	require.Less(t, elapsed, 2*time.Second)
+
+	// The bandwidth of the client is shared between the requests
+	client = NewClient(&Parameters{HttpClient: srv.Client(), Bandwidth: 200000})
+	start = time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.GetResponse(srv.URL)
+			require.NoError(t, err)
+			require.Len(t, resp.Body, len(payload))
+		}()
+	}
+	wg.Wait()
+	elapsed = time.Since(start)
+	require.GreaterOrEqual(t, elapsed, 180*time.Millisecond)
+	require.Less(t, elapsed, 2*time.Second)
+}
+
+func TestBandwidthReserve(t *testing.T) {
+	l := NewBandwidthLimiter(1000)
+	now := time.Now()
+	l.bucket.last = now
+	require.Equal(t, 100, l.chunk)
+	require.Zero(t, l.reserve(100, now))
+	require.Equal(t, 100*time.Millisecond, l.reserve(100, now))
+	require.Equal(t, 100*time.Millisecond, l.reserve(100, now.Add(100*time.Millisecond)))
+	// Unused bandwidth does not add up beyond the burst
+	require.Zero(t, l.reserve(100, now.Add(time.Hour)))
+	require.Equal(t, 50*time.Millisecond, l.reserve(50, now.Add(time.Hour)))
+}
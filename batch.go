@@ -0,0 +1,132 @@
+package owl
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// hostWorkers is the number of downloads of DownloadAll running at once against a single host
+const hostWorkers = 2
+
+// maxFilenameLength is the length of the filenames of DownloadAll at most, before their collision suffix
+const maxFilenameLength = 200
+
+// DownloadResult is the outcome of the download of one URL by DownloadAll
+type DownloadResult struct {
+	URL string
+	// Path is the file the URL is downloaded to, empty when the URL is invalid
+	Path string
+	Size int64
+	Err  error
+}
+
+// DownloadAll downloads urls to files in dir with up to workers downloads at once, and at most 2 per host.
+// Files are named after the last segment of the path of their URL, with a numbered suffix when the name is taken
+// by an existing file or another URL. Results are in the order of urls, the error joins the errors of all
+// the failed downloads
+func (c *Client) DownloadAll(urls []string, dir string, workers int) ([]DownloadResult, error) {
+	if workers < 1 {
+		workers = 1
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	results := make([]DownloadResult, len(urls))
+	hosts := make(map[string]chan struct{})
+	taken := make(map[string]bool)
+	jobs := make(chan int)
+	for i, raw := range urls {
+		results[i].URL = raw
+		u, err := url.Parse(raw)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		if _, ok := hosts[u.Host]; !ok {
+			hosts[u.Host] = make(chan struct{}, hostWorkers)
+		}
+		results[i].Path = filepath.Join(dir, freeFilename(dir, downloadFilename(u), taken))
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				result := &results[i]
+				u, _ := url.Parse(result.URL)
+				slots := hosts[u.Host]
+				slots <- struct{}{}
+				result.Size, result.Err = c.DownloadFile(result.URL, result.Path)
+				<-slots
+			}
+		}()
+	}
+	for i := range results {
+		if results[i].Err == nil {
+			jobs <- i
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	var errs []error
+	for _, result := range results {
+		if result.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", result.URL, result.Err))
+		}
+	}
+	return results, errors.Join(errs...)
+}
+
+// downloadFilename returns a filename for the download of u from the last segment of its path
+func downloadFilename(u *url.URL) string {
+	name := path.Base(u.Path)
+	if name == "/" {
+		name = ""
+	}
+	name = strings.Map(func(r rune) rune {
+		if r < ' ' || strings.ContainsRune(`<>:"/\|?*`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+	name = strings.Trim(name, ". ")
+	if name == "" {
+		name = "index"
+	}
+	if len(name) > maxFilenameLength {
+		ext := path.Ext(name)
+		if len(ext) > maxFilenameLength/2 {
+			ext = ""
+		}
+		name = strings.ToValidUTF8(name[:maxFilenameLength-len(ext)], "") + ext
+	}
+	return name
+}
+
+// freeFilename returns name, or name with a numbered suffix, so that it is neither in taken
+// nor the name of a file in dir, and adds it to taken
+func freeFilename(dir, name string, taken map[string]bool) string {
+	ext := path.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	candidate := name
+	for i := 1; ; i++ {
+		if !taken[strings.ToLower(candidate)] {
+			if _, err := os.Lstat(filepath.Join(dir, candidate)); os.IsNotExist(err) {
+				break
+			}
+		}
+		candidate = fmt.Sprintf("%s-%d%s", base, i, ext)
+	}
+	taken[strings.ToLower(candidate)] = true
+	return candidate
+}
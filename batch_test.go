@@ -0,0 +1,90 @@
+package owl
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownloadAll(t *testing.T) {
+	var mu sync.Mutex
+	running, peak := 0, 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/missing.pdf", http.NotFound)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		running++
+		peak = max(peak, running)
+		mu.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+		w.Write([]byte(r.URL.Path))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	client := &Client{Client: srv.Client()}
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "existing.png"), []byte("kept"), 0o644))
+	urls := []string{
+		srv.URL + "/a/owl.png",
+		srv.URL + "/b/owl.png",
+		srv.URL + "/existing.png",
+		srv.URL + "/",
+		srv.URL + "/missing.pdf",
+		"http://[::1",
+	}
+	results, err := client.DownloadAll(urls, dir, 8)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), srv.URL+"/missing.pdf")
+	require.Len(t, results, len(urls))
+	require.LessOrEqual(t, peak, 2)
+
+	names := make([]string, len(results))
+	for i, result := range results {
+		require.Equal(t, urls[i], result.URL)
+		if result.Path != "" {
+			names[i] = filepath.Base(result.Path)
+		}
+	}
+	require.Equal(t, []string{"owl.png", "owl-1.png", "existing-1.png", "index", "missing.pdf", ""}, names)
+	for _, i := range []int{0, 1, 2, 3} {
+		require.NoError(t, results[i].Err)
+		data, err := os.ReadFile(results[i].Path)
+		require.NoError(t, err)
+		require.Equal(t, int64(len(data)), results[i].Size)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "owl-1.png"))
+	require.NoError(t, err)
+	require.Equal(t, "/b/owl.png", string(data))
+	data, err = os.ReadFile(filepath.Join(dir, "existing.png"))
+	require.NoError(t, err)
+	require.Equal(t, "kept", string(data))
+	require.Error(t, results[4].Err)
+	require.Error(t, results[5].Err)
+}
+
+func TestDownloadFilename(t *testing.T) {
+	for raw, want := range map[string]string{
+		"http://example.com/files/report.pdf?x=1":                 "report.pdf",
+		"http://example.com/files/":                               "files",
+		"http://example.com":                                      "index",
+		"http://example.com/a%3Cb%3E.txt":                         "a_b_.txt",
+		"http://example.com/..":                                   "index",
+		"http://example.com/" + strings.Repeat("x", 300) + ".jpg": strings.Repeat("x", 196) + ".jpg",
+	} {
+		u, err := url.Parse(raw)
+		require.NoError(t, err)
+		require.Equal(t, want, downloadFilename(u), raw)
+	}
+}
@@ -0,0 +1,173 @@
+package owl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned for the requests to a host whose circuit is open, see CircuitBreaker
+var ErrCircuitOpen = errors.New("owl: circuit open")
+
+// BreakerState is the state of the circuit of a host
+type BreakerState int
+
+const (
+	// BreakerClosed lets the requests through
+	BreakerClosed BreakerState = iota
+	// BreakerOpen refuses the requests with ErrCircuitOpen
+	BreakerOpen
+	// BreakerHalfOpen lets a few probe requests through to tell whether the host recovered
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	}
+	return "closed"
+}
+
+// BreakerSettings configures a CircuitBreaker
+type BreakerSettings struct {
+	// Failures is the number of consecutive failed requests to a host that open its circuit, 5 when 0
+	Failures int
+	// OpenFor is how long a circuit stays open before probes are let through, 30s when 0
+	OpenFor time.Duration
+	// Probes is the number of requests let through at once by a half-open circuit, 1 when 0
+	Probes int
+	// IsFailure decides whether a request that got resp or err failed, DefaultIsFailure when nil
+	IsFailure func(resp *http.Response, err error) bool
+	// OnStateChange is called when the circuit of host changes state
+	OnStateChange func(host string, from, to BreakerState)
+}
+
+// DefaultIsFailure counts as failures the network errors and timeouts, 429 Too Many Requests
+// and the 5xx statuses other than 501 Not Implemented. Canceled requests do not count
+func DefaultIsFailure(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled)
+	}
+	return resp.StatusCode == http.StatusTooManyRequests ||
+		(resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented)
+}
+
+// CircuitBreaker stops sending requests to a host after consecutive failures, and lets probes through
+// once the circuit has been open for a while: the circuit closes again when they succeed
+type CircuitBreaker struct {
+	settings BreakerSettings
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+// circuit is the state of a host
+type circuit struct {
+	state    BreakerState
+	failures int
+	opened   time.Time
+	// probing is the number of probes in flight when half-open
+	probing int
+}
+
+// NewCircuitBreaker returns a CircuitBreaker with settings
+func NewCircuitBreaker(settings BreakerSettings) *CircuitBreaker {
+	if settings.Failures < 1 {
+		settings.Failures = 5
+	}
+	if settings.OpenFor <= 0 {
+		settings.OpenFor = 30 * time.Second
+	}
+	if settings.Probes < 1 {
+		settings.Probes = 1
+	}
+	if settings.IsFailure == nil {
+		settings.IsFailure = DefaultIsFailure
+	}
+	return &CircuitBreaker{settings: settings, circuits: make(map[string]*circuit)}
+}
+
+// State returns the state of the circuit of host
+func (b *CircuitBreaker) State(host string) BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c, ok := b.circuits[strings.ToLower(host)]
+	if !ok {
+		return BreakerClosed
+	}
+	if c.state == BreakerOpen && time.Since(c.opened) >= b.settings.OpenFor {
+		return BreakerHalfOpen
+	}
+	return c.state
+}
+
+// allow returns ErrCircuitOpen when a request to host may not be sent now,
+// or else done must be called with the outcome of the request
+func (b *CircuitBreaker) allow(host string, now time.Time) error {
+	host = strings.ToLower(host)
+	b.mu.Lock()
+	c, ok := b.circuits[host]
+	if !ok {
+		c = &circuit{}
+		b.circuits[host] = c
+	}
+	var changed bool
+	if c.state == BreakerOpen && now.Sub(c.opened) >= b.settings.OpenFor {
+		c.state, c.probing, changed = BreakerHalfOpen, 0, true
+	}
+	err := error(nil)
+	switch {
+	case c.state == BreakerOpen, c.state == BreakerHalfOpen && c.probing >= b.settings.Probes:
+		err = fmt.Errorf("%w for %s", ErrCircuitOpen, host)
+	case c.state == BreakerHalfOpen:
+		c.probing++
+	}
+	b.mu.Unlock()
+	if changed {
+		b.notify(host, BreakerOpen, BreakerHalfOpen)
+	}
+	return err
+}
+
+// done records the outcome of a request to host let through by allow
+func (b *CircuitBreaker) done(host string, resp *http.Response, err error, now time.Time) {
+	host = strings.ToLower(host)
+	failed := b.settings.IsFailure(resp, err)
+	b.mu.Lock()
+	c := b.circuits[host]
+	from := c.state
+	switch {
+	case c.state == BreakerHalfOpen && failed:
+		c.state, c.opened, c.probing = BreakerOpen, now, 0
+	case c.state == BreakerHalfOpen && errors.Is(err, context.Canceled):
+		// The probe did not tell anything
+		c.probing--
+	case c.state == BreakerHalfOpen:
+		c.state, c.failures, c.probing = BreakerClosed, 0, 0
+	case failed:
+		c.failures++
+		if c.state == BreakerClosed && c.failures >= b.settings.Failures {
+			c.state, c.opened = BreakerOpen, now
+		}
+	case err == nil:
+		c.failures = 0
+	}
+	to := c.state
+	b.mu.Unlock()
+	if from != to {
+		b.notify(host, from, to)
+	}
+}
+
+func (b *CircuitBreaker) notify(host string, from, to BreakerState) {
+	if b.settings.OnStateChange != nil {
+		b.settings.OnStateChange(host, from, to)
+	}
+}
@@ -0,0 +1,99 @@
+package owl
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var down atomic.Bool
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("up"))
+	}))
+	defer srv.Close()
+
+	type change struct{ from, to BreakerState }
+	var changes []change
+	client := NewClient(&Parameters{HttpClient: srv.Client(), Breaker: &BreakerSettings{
+		Failures: 3,
+		OpenFor:  50 * time.Millisecond,
+		OnStateChange: func(host string, from, to BreakerState) {
+			require.Equal(t, "127.0.0.1", host)
+			changes = append(changes, change{from, to})
+		},
+	}})
+	client.Retry = &RetryPolicy{MaxAttempts: 5, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+	down.Store(true)
+	resp, err := client.GetResponse(srv.URL)
+	// The third failure opens the circuit, the fourth attempt is refused and not retried
+	require.Nil(t, resp)
+	require.True(t, errors.Is(err, ErrCircuitOpen))
+	require.Equal(t, int32(3), hits.Load())
+	require.Equal(t, BreakerOpen, client.Breaker.State("127.0.0.1"))
+	_, err = client.GetResponse(srv.URL)
+	require.True(t, errors.Is(err, ErrCircuitOpen))
+	require.Equal(t, int32(3), hits.Load())
+
+	// A failed probe opens the circuit again
+	time.Sleep(60 * time.Millisecond)
+	require.Equal(t, BreakerHalfOpen, client.Breaker.State("127.0.0.1"))
+	client.Retry = nil
+	resp, err = client.GetResponse(srv.URL)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	require.Equal(t, BreakerOpen, client.Breaker.State("127.0.0.1"))
+
+	// A successful probe closes it
+	down.Store(false)
+	time.Sleep(60 * time.Millisecond)
+	resp, err = client.GetResponse(srv.URL)
+	require.NoError(t, err)
+	require.Equal(t, "up", string(resp.Body))
+	require.Equal(t, BreakerClosed, client.Breaker.State("127.0.0.1"))
+
+	require.Equal(t, []change{
+		{BreakerClosed, BreakerOpen},
+		{BreakerOpen, BreakerHalfOpen},
+		{BreakerHalfOpen, BreakerOpen},
+		{BreakerOpen, BreakerHalfOpen},
+		{BreakerHalfOpen, BreakerClosed},
+	}, changes)
+}
+
+func TestCircuitBreakerProbes(t *testing.T) {
+	b := NewCircuitBreaker(BreakerSettings{Failures: 1, OpenFor: time.Second})
+	now := time.Now()
+	require.NoError(t, b.allow("example.com", now))
+	b.done("example.com", nil, errors.New("connection refused"), now)
+	require.True(t, errors.Is(b.allow("Example.com", now), ErrCircuitOpen))
+
+	// Only one probe at a time once half-open
+	later := now.Add(time.Second)
+	require.NoError(t, b.allow("example.com", later))
+	require.True(t, errors.Is(b.allow("example.com", later), ErrCircuitOpen))
+	b.done("example.com", &http.Response{StatusCode: http.StatusOK}, nil, later)
+	require.NoError(t, b.allow("example.com", later))
+	require.NoError(t, b.allow("example.com", later))
+
+	// Successes reset the count of consecutive failures
+	b = NewCircuitBreaker(BreakerSettings{Failures: 2})
+	for _, status := range []int{500, 200, 502, 404, 503} {
+		require.NoError(t, b.allow("example.com", now))
+		b.done("example.com", &http.Response{StatusCode: status}, nil, now)
+	}
+	require.Equal(t, BreakerClosed, b.State("example.com"))
+	require.Equal(t, "half-open", BreakerHalfOpen.String())
+}
@@ -0,0 +1,68 @@
+package owl
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderTo(t *testing.T) {
+	li := HtmlRoot.Find("ul").Find("li")
+	var buf bytes.Buffer
+	require.NoError(t, li.RenderTo(&buf))
+	require.Equal(t, `<li>To a <a href="hello.jsp">JSP page</a> right?</li>`, buf.String())
+	require.Equal(t, buf.Bytes(), li.Render())
+
+	// Render must not hand out memory owned by the pool
+	first := li.Render()
+	HtmlRoot.Find("title").Render()
+	require.Equal(t, buf.Bytes(), first)
+}
+
+func TestFullTextTo(t *testing.T) {
+	li := HtmlRoot.Find("ul").Find("li")
+	var buf bytes.Buffer
+	require.NoError(t, li.FullTextTo(&buf))
+	require.Equal(t, "To a JSP page right?", buf.String())
+}
+
+func BenchmarkRender(b *testing.B) {
+	section := benchRoot().Find("section", "id", "s42")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		section.Render()
+	}
+}
+
+func BenchmarkRenderTo(b *testing.B) {
+	section := benchRoot().Find("section", "id", "s42")
+	var buf bytes.Buffer
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		section.RenderTo(&buf)
+	}
+}
+
+func BenchmarkFullText(b *testing.B) {
+	section := benchRoot().Find("section", "id", "s42")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = section.FullText()
+	}
+}
+
+func BenchmarkFullTextTo(b *testing.B) {
+	section := benchRoot().Find("section", "id", "s42")
+	var buf bytes.Buffer
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		section.FullTextTo(&buf)
+	}
+}
@@ -0,0 +1,237 @@
+package owl
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheEntry is a response stored in a Cache
+type CacheEntry struct {
+	// URL is the URL of the response after redirects
+	URL        string
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	// Stored is when the response was received or last validated
+	Stored time.Time
+}
+
+// fresh reports whether the entry can be used without asking the server, as its Cache-Control max-age says
+func (e *CacheEntry) fresh(now time.Time) bool {
+	directives := cacheControl(e.Header)
+	if _, ok := directives["no-cache"]; ok {
+		return false
+	}
+	maxAge, err := strconv.Atoi(directives["max-age"])
+	if err != nil {
+		return false
+	}
+	return now.Before(e.Stored.Add(time.Duration(maxAge) * time.Second))
+}
+
+// response returns the entry as the response to req
+func (e *CacheEntry) response(req *http.Request) (*http.Response, error) {
+	u, err := url.Parse(e.URL)
+	if err != nil {
+		return nil, err
+	}
+	req = req.Clone(req.Context())
+	req.URL = u
+	return &http.Response{
+		Status:        strconv.Itoa(e.StatusCode) + " " + http.StatusText(e.StatusCode),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}, nil
+}
+
+// cacheControl returns the directives of the Cache-Control header in lower case with their values
+func cacheControl(h http.Header) map[string]string {
+	directives := make(map[string]string)
+	for _, d := range strings.Split(h.Get("Cache-Control"), ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(d), "=")
+		if name != "" {
+			directives[strings.ToLower(name)] = strings.Trim(value, `"`)
+		}
+	}
+	return directives
+}
+
+// doCached answers req from the Cache of c when the entry of its URL is fresh, or else sends it with the
+// validators of the entry so a 304 Not Modified response is answered from the entry too.
+// Successful responses are stored once their body is read to the end
+func (c *Client) doCached(req *http.Request, cfg *requestConfig) (*http.Response, func(), error) {
+	key := req.URL.String()
+	entry, ok := c.Cache.Get(key)
+	if ok && entry.fresh(time.Now()) {
+		c.cacheLookup(req, true)
+		resp, err := entry.response(req)
+		return resp, func() {}, err
+	}
+	if ok {
+		if etag := entry.Header.Get("ETag"); etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		if modified := entry.Header.Get("Last-Modified"); modified != "" {
+			req.Header.Set("If-Modified-Since", modified)
+		}
+	}
+	resp, release, err := c.doRetry(req, cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	revalidated := ok && resp.StatusCode == http.StatusNotModified
+	c.cacheLookup(req, revalidated)
+	if revalidated {
+		discard(resp.Body)
+		release()
+		// The 304 response updates the headers of the entry
+		updated := *entry
+		updated.Header = entry.Header.Clone()
+		for name, values := range resp.Header {
+			updated.Header[name] = values
+		}
+		updated.Stored = time.Now()
+		c.Cache.Set(key, &updated)
+		resp, err := updated.response(req)
+		return resp, func() {}, err
+	}
+	if _, noStore := cacheControl(resp.Header)["no-store"]; resp.StatusCode == http.StatusOK && !noStore {
+		resp.Body = &cachingBody{ReadCloser: resp.Body, store: func(body []byte) {
+			c.Cache.Set(key, &CacheEntry{
+				URL:        resp.Request.URL.String(),
+				StatusCode: resp.StatusCode,
+				Header:     resp.Header.Clone(),
+				Body:       body,
+				Stored:     time.Now(),
+			})
+		}}
+	}
+	return resp, release, nil
+}
+
+// cacheLookup reports a lookup of the Cache for req to the Metrics and the Logger of c
+func (c *Client) cacheLookup(req *http.Request, hit bool) {
+	if c.Metrics != nil {
+		c.Metrics.CacheLookup(req.URL.Hostname(), hit)
+	}
+	if hit {
+		c.debug("owl: cache hit", "url", req.URL.String())
+	}
+}
+
+// cachingBody keeps what is read from a response body and stores it once the end is reached
+type cachingBody struct {
+	io.ReadCloser
+	buf   bytes.Buffer
+	store func([]byte)
+}
+
+func (b *cachingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.buf.Write(p[:n])
+	if err == io.EOF && b.store != nil {
+		b.store(b.buf.Bytes())
+		b.store = nil
+	}
+	return n, err
+}
+
+// MemoryCache is a Cache keeping the entries in memory
+type MemoryCache struct {
+	mu      sync.RWMutex
+	entries map[string]*CacheEntry
+}
+
+var _ Cache = (*MemoryCache)(nil)
+
+// NewMemoryCache returns an empty MemoryCache
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: make(map[string]*CacheEntry)}
+}
+
+func (m *MemoryCache) Get(url string) (*CacheEntry, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	e, ok := m.entries[url]
+	return e, ok
+}
+
+func (m *MemoryCache) Set(url string, entry *CacheEntry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[url] = entry
+}
+
+// DiskCache is a Cache keeping each entry in a JSON file of a directory, so it outlives the process
+type DiskCache struct {
+	dir string
+}
+
+var _ Cache = (*DiskCache)(nil)
+
+// NewDiskCache returns a DiskCache in dir, which is created when it does not exist
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &DiskCache{dir: dir}, nil
+}
+
+// path returns the file of the entry of url, named after its hash
+func (d *DiskCache) path(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(d.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// Get returns the entry of url, unreadable files count as missing entries
+func (d *DiskCache) Get(url string) (*CacheEntry, bool) {
+	data, err := os.ReadFile(d.path(url))
+	if err != nil {
+		return nil, false
+	}
+	var entry CacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false
+	}
+	return &entry, true
+}
+
+// Set writes the entry of url, through a temporary file so readers never see a partial entry.
+// Entries that can not be written are dropped
+func (d *DiskCache) Set(url string, entry *CacheEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	f, err := os.CreateTemp(d.dir, "entry-*")
+	if err != nil {
+		return
+	}
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), d.path(url))
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+}
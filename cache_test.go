@@ -0,0 +1,84 @@
+package owl
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache(t *testing.T) {
+	var hits, notModified atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/etag", func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		fmt.Fprint(w, "<title>Etag</title>")
+	})
+	mux.HandleFunc("/fresh", func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Cache-Control", "public, max-age=3600")
+		fmt.Fprint(w, "<title>Fresh</title>")
+	})
+	mux.HandleFunc("/nostore", func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Cache-Control", "no-store")
+		fmt.Fprint(w, "<title>Secret</title>")
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	dir := t.TempDir()
+	disk, err := NewDiskCache(dir)
+	require.NoError(t, err)
+	for name, cache := range map[string]Cache{"memory": NewMemoryCache(), "disk": disk} {
+		t.Run(name, func(t *testing.T) {
+			hits.Store(0)
+			notModified.Store(0)
+			client := &Client{Client: srv.Client(), Cache: cache}
+			for i := 0; i < 3; i++ {
+				doc, err := client.GetDocument(srv.URL + "/etag")
+				require.NoError(t, err)
+				require.Equal(t, "Etag", doc.Title().Text())
+				require.Equal(t, srv.URL+"/etag", doc.URL().String())
+			}
+			require.Equal(t, int32(3), hits.Load())
+			require.Equal(t, int32(2), notModified.Load())
+
+			hits.Store(0)
+			for i := 0; i < 3; i++ {
+				resp, err := client.GetResponse(srv.URL + "/fresh")
+				require.NoError(t, err)
+				require.Equal(t, "<title>Fresh</title>", string(resp.Body))
+			}
+			require.Equal(t, int32(1), hits.Load())
+
+			hits.Store(0)
+			for i := 0; i < 2; i++ {
+				_, err := client.GetResponse(srv.URL + "/nostore")
+				require.NoError(t, err)
+			}
+			require.Equal(t, int32(2), hits.Load())
+			_, ok := cache.Get(srv.URL + "/nostore")
+			require.False(t, ok)
+		})
+	}
+
+	// Entries on disk outlive the cache
+	reopened, err := NewDiskCache(dir)
+	require.NoError(t, err)
+	entry, ok := reopened.Get(srv.URL + "/fresh")
+	require.True(t, ok)
+	require.Equal(t, http.StatusOK, entry.StatusCode)
+	require.True(t, entry.fresh(time.Now()))
+	require.False(t, entry.fresh(time.Now().Add(2*time.Hour)))
+}
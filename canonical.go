@@ -0,0 +1,96 @@
+package owl
+
+import (
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Canonical returns the rel=canonical URL of the document resolved against BaseURL,
+// reporting whether the document has one that resolves to an absolute URL
+func (r *Root) Canonical() (*url.URL, bool) {
+	return r.linkURL("canonical")
+}
+
+// AMPURL returns the rel=amphtml URL of the document, the AMP variant of a regular page
+func (r *Root) AMPURL() (*url.URL, bool) {
+	return r.linkURL("amphtml")
+}
+
+// IsAMP reports whether the document is an AMP page, marked by an amp or ⚡ attribute on its html element
+func (r *Root) IsAMP() bool {
+	if r.Node == nil {
+		return false
+	}
+	top := r.Node
+	for top.Parent != nil {
+		top = top.Parent
+	}
+	var root *html.Node
+	walk(top, func(n *html.Node) bool {
+		if root == nil && n.Type == html.ElementNode && n.Data == "html" {
+			root = n
+		}
+		return root == nil
+	})
+	if root == nil {
+		return false
+	}
+	_, amp := attrLookup(root, "amp")
+	_, bolt := attrLookup(root, "⚡")
+	return amp || bolt
+}
+
+// GetCanonical returns the canonical version of page: page itself when it has no canonical URL
+// or is already at it, the document fetched from its canonical URL otherwise.
+// Following canonical links of crawled pages keeps duplicates such as AMP variants
+// and tracking parameters out of the results
+func (c *Client) GetCanonical(page *Root) (*Root, error) {
+	canonical, ok := page.Canonical()
+	if !ok {
+		return page, nil
+	}
+	if current := page.URL(); current != nil && sameURL(current, canonical) {
+		return page, nil
+	}
+	return c.GetDocument(canonical.String())
+}
+
+// linkURL returns the resolved href of the first link element of the document with rel
+func (r *Root) linkURL(rel string) (*url.URL, bool) {
+	if r.Node == nil {
+		return nil, false
+	}
+	top := r.Node
+	for top.Parent != nil {
+		top = top.Parent
+	}
+	var href string
+	found := false
+	walk(top, func(n *html.Node) bool {
+		if found {
+			return false
+		}
+		if n.Type == html.ElementNode && n.Data == "link" &&
+			containsString(strings.Fields(strings.ToLower(attrValue(n, "rel"))), rel) {
+			href, found = attrLookup(n, "href")
+		}
+		return !found
+	})
+	if !found || strings.TrimSpace(href) == "" {
+		return nil, false
+	}
+	u, err := r.ResolveURL(href)
+	if err != nil || !u.IsAbs() {
+		return nil, false
+	}
+	return u, true
+}
+
+// sameURL reports whether a and b point to the same resource, ignoring their fragments
+// and the case of their scheme and host
+func sameURL(a, b *url.URL) bool {
+	return strings.EqualFold(a.Scheme, b.Scheme) && strings.EqualFold(a.Host, b.Host) &&
+		a.EscapedPath() == b.EscapedPath() && a.RawQuery == b.RawQuery
+}
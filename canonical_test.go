@@ -0,0 +1,63 @@
+package owl
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonical(t *testing.T) {
+	page, _ := url.Parse("https://example.com/owls/amp?utm_source=feed")
+	amp := HTMLParseFromString(`<html ⚡ lang="en"><head>
+		<link rel="canonical" href="/owls">
+	</head><body></body></html>`).SetURL(page)
+	require.True(t, amp.IsAMP())
+	canonical, ok := amp.Canonical()
+	require.True(t, ok)
+	require.Equal(t, "https://example.com/owls", canonical.String())
+	_, ok = amp.AMPURL()
+	require.False(t, ok)
+
+	regular := HTMLParseFromString(`<html><head><link rel="amphtml" href="https://example.com/owls/amp"></head></html>`)
+	require.False(t, regular.IsAMP())
+	ampURL, ok := regular.AMPURL()
+	require.True(t, ok)
+	require.Equal(t, "https://example.com/owls/amp", ampURL.String())
+	_, ok = regular.Canonical()
+	require.False(t, ok)
+
+	require.True(t, HTMLParseFromString(`<html amp><body></body></html>`).Find("body").IsAMP())
+	_, ok = HTMLParseFromString(`<html><head><link rel="canonical" href="/relative"></head></html>`).Canonical()
+	require.False(t, ok)
+}
+
+func TestGetCanonical(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/owls/amp":
+			w.Write([]byte(`<html amp><head><link rel="canonical" href="/owls"><title>AMP</title></head></html>`))
+		case "/owls":
+			w.Write([]byte(`<html><head><link rel="canonical" href="` + srv.URL + `/owls"><title>Owls</title></head></html>`))
+		}
+	}))
+	defer srv.Close()
+
+	client := HttpClientWrapper(srv.Client())
+	client.RequestTimeout = 5 * time.Second
+	amp, err := client.GetDocument(srv.URL + "/owls/amp")
+	require.NoError(t, err)
+
+	canonical, err := client.GetCanonical(amp)
+	require.NoError(t, err)
+	require.Equal(t, "Owls", canonical.Find("title").Text())
+	require.Equal(t, srv.URL+"/owls", canonical.URL().String())
+
+	same, err := client.GetCanonical(canonical)
+	require.NoError(t, err)
+	require.Same(t, canonical, same)
+}
@@ -0,0 +1,109 @@
+package owl
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// ErrStoreNotSavable is returned by Crawler.Checkpoint when its Store is neither a MemoryStore nor a Frontier,
+// stores such as the owlbolt one keep their state on their own
+var ErrStoreNotSavable = errors.New("owl: the store of the crawler can not be saved")
+
+// checkpointVersion is the version of the format written by Checkpoint
+const checkpointVersion = 1
+
+// savedCrawl is the state of a crawl as written by Checkpoint
+type savedCrawl struct {
+	Version int                  `json:"version"`
+	Visited []string             `json:"visited"`
+	Queue   []savedRequest       `json:"queue"`
+	Pages   int                  `json:"pages"`
+	Hosts   map[string]savedHost `json:"hosts,omitempty"`
+}
+
+type savedRequest struct {
+	URL      string `json:"url"`
+	Depth    int    `json:"depth,omitempty"`
+	Referer  string `json:"referer,omitempty"`
+	Priority int    `json:"priority,omitempty"`
+}
+
+type savedHost struct {
+	Pages int `json:"pages"`
+}
+
+// Checkpoint writes the state of the crawl as JSON: the visited URLs, the queue and the pages queued
+// by host for MaxPages and MaxHostPages. It can be called while the crawl runs, the pages being
+// processed are then written to the queue so Resume fetches them again
+func (c *Crawler) Checkpoint(w io.Writer) error {
+	c.init()
+	store, ok := c.Store.(interface {
+		snapshot() ([]string, []CrawlRequest)
+	})
+	if !ok {
+		return ErrStoreNotSavable
+	}
+	c.mu.Lock()
+	visited, queue := store.snapshot()
+	active := make([]CrawlRequest, 0, len(c.active))
+	for _, req := range c.active {
+		active = append(active, req)
+	}
+	saved := savedCrawl{Version: checkpointVersion, Pages: c.pages, Hosts: make(map[string]savedHost, len(c.hosts))}
+	for name, host := range c.hosts {
+		if host.pages > 0 {
+			saved.Hosts[name] = savedHost{Pages: host.pages}
+		}
+	}
+	c.mu.Unlock()
+
+	sort.Strings(visited)
+	sort.Slice(active, func(i, j int) bool { return active[i].URL < active[j].URL })
+	saved.Visited = visited
+	saved.Queue = make([]savedRequest, 0, len(active)+len(queue))
+	for _, req := range append(active, queue...) {
+		saved.Queue = append(saved.Queue, savedRequest(req))
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(saved)
+}
+
+// Resume adds the state of a crawl written by Checkpoint to the Crawler, to be continued by Start.
+// Seeds given to Start that were visited before are skipped
+func (c *Crawler) Resume(r io.Reader) error {
+	var saved savedCrawl
+	if err := json.NewDecoder(r).Decode(&saved); err != nil {
+		return err
+	}
+	if saved.Version != checkpointVersion {
+		return fmt.Errorf("owl: unknown checkpoint version %d", saved.Version)
+	}
+	c.init()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, u := range saved.Visited {
+		if _, err := c.Store.MarkVisited(u); err != nil {
+			return err
+		}
+	}
+	for _, req := range saved.Queue {
+		if err := c.Store.Enqueue(CrawlRequest(req)); err != nil {
+			return err
+		}
+	}
+	c.pages += saved.Pages
+	for name, h := range saved.Hosts {
+		host := c.hosts[name]
+		if host == nil {
+			host = &crawlHost{}
+			c.hosts[name] = host
+		}
+		host.pages += h.Pages
+	}
+	c.wake.Broadcast()
+	return nil
+}
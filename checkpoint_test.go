@@ -0,0 +1,69 @@
+package owl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCrawlerCheckpoint(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Every page links to the next one
+		n, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/"))
+		fmt.Fprintf(w, `<a href="/%d">next</a>`, n+1)
+	}))
+	defer srv.Close()
+
+	var fetched []string
+	var running bytes.Buffer
+	newCrawler := func(cancel func()) *Crawler {
+		crawler := NewCrawler(&Client{Client: srv.Client()})
+		crawler.MaxPages = 6
+		crawler.OnHTML("a[href]", func(e *Root, ctx *CrawlContext) {
+			fetched = append(fetched, ctx.Request.URL)
+			href, _ := e.Attr("href")
+			ctx.Visit(href)
+			if len(fetched) == 3 {
+				require.NoError(t, ctx.crawler.Checkpoint(&running))
+				cancel()
+			}
+		})
+		return crawler
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	crawler := newCrawler(cancel)
+	require.ErrorIs(t, crawler.StartContext(ctx, srv.URL+"/"), context.Canceled)
+	var stopped bytes.Buffer
+	require.NoError(t, crawler.Checkpoint(&stopped))
+
+	// The checkpoint taken while /2 was processed queues it again
+	var saved savedCrawl
+	require.NoError(t, json.Unmarshal(running.Bytes(), &saved))
+	require.Equal(t, []savedRequest{{URL: srv.URL + "/2", Depth: 2, Referer: srv.URL + "/1"}, {URL: srv.URL + "/3", Depth: 3, Referer: srv.URL + "/2"}}, saved.Queue)
+	require.NoError(t, json.Unmarshal(stopped.Bytes(), &saved))
+	require.Equal(t, []savedRequest{{URL: srv.URL + "/3", Depth: 3, Referer: srv.URL + "/2"}}, saved.Queue)
+	require.Equal(t, 4, saved.Pages)
+	require.Len(t, saved.Visited, 4)
+
+	resumed := newCrawler(func() {})
+	require.NoError(t, resumed.Resume(&stopped))
+	require.NoError(t, resumed.Start(srv.URL+"/"))
+	require.Equal(t, []string{
+		srv.URL + "/", srv.URL + "/1", srv.URL + "/2", srv.URL + "/3", srv.URL + "/4", srv.URL + "/5",
+	}, fetched)
+
+	require.ErrorContains(t, resumed.Resume(strings.NewReader(`{"version":2}`)), "unknown checkpoint version 2")
+	resumed.Store = NewMemoryStore()
+	require.NoError(t, resumed.Checkpoint(&bytes.Buffer{}))
+	resumed.Store = &struct{ Store }{}
+	require.ErrorIs(t, resumed.Checkpoint(&bytes.Buffer{}), ErrStoreNotSavable)
+}
@@ -9,26 +9,98 @@ import (
 	"net/http"
 	netURL "net/url"
 	"strings"
+	"sync/atomic"
 	"time"
 
-	"golang.org/x/net/html/charset"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Client struct {
 	*http.Client
-	Header         map[string]string
+	Header map[string]string
+	// Cookies are sent with every request whatever its host.
+	//
+	// Deprecated: set cookies in the cookie jar of the http.Client instead, see Jar
 	Cookies        map[string]string
 	RequestTimeout time.Duration
+	// Robots refuses the requests it disallows for the User-Agent of Header when it is set
+	Robots RobotsPolicy
+	// Retry retries the requests that failed for reasons that may not last, requests are not retried when nil
+	Retry *RetryPolicy
+	// Limiter delays the requests, retries included, to be polite with the hosts when it is set
+	Limiter RateLimiter
+	// Breaker refuses the requests, retries included, to the hosts that keep failing when it is set
+	Breaker *CircuitBreaker
+	// Cache stores the responses to GET requests when it is set, see NewMemoryCache and NewDiskCache
+	Cache Cache
+	// MaxBodySize is the size in bytes response bodies can not exceed, they are not limited when it is 0
+	MaxBodySize int64
+	// Bandwidth limits how fast all the response bodies are read together when it is set, see WithBandwidth
+	Bandwidth *BandwidthLimiter
+	// StatusErrors makes the responses with a status other than 2xx fail with an *HTTPError,
+	// the response is still returned along with the error. See WithStatusErrors
+	StatusErrors bool
+	// MaxRefreshes is how many meta refreshes, Refresh headers and trivial JavaScript redirects
+	// GET requests follow, see Root.Redirect. They are not followed when it is 0
+	MaxRefreshes int
+	// Metrics collects measures of the requests when it is set
+	Metrics Metrics
+	// Logger receives the events of the requests when it is set, nothing is logged when it is nil
+	Logger Logger
+	// Renderer renders the pages of GetDocument and Visit in a browser when it is set, see WithoutRenderer.
+	// Only the Robots policy, the headers and the cookies of the Client apply to rendered pages
+	Renderer Renderer
+	// Tracer creates a span for every request and every parse of a response when it is set, see NewTracer
+	Tracer trace.Tracer
+	// Reauthenticate is called when a request gets a 401 Unauthorized response, to renew the credentials of c
+	// with SetBasicAuth, SetBearerToken or SetAPIKey. The request is then sent once more with them
+	Reauthenticate func(c *Client, resp *http.Response) error
+	// owned is the transport cloned by transport, which c configures in place
+	owned *http.Transport
+	// ownedClient is the copy of the http.Client made by ownClient, which c configures in place
+	ownedClient *http.Client
+	// err is the error of the Options NewClient could not apply, every request of c fails with it
+	err error
+	// auth holds the credentials sent with every request, see SetBasicAuth
+	auth atomic.Pointer[credentials]
 }
 
+// ErrDisallowedByRobots is returned for requests the Robots policy of the Client disallows
+var ErrDisallowedByRobots = errors.New("owl: URL disallowed by robots.txt")
+
+// Parameters configures a Client all at once, it is an Option replacing the whole configuration
+// of NewClient, DefaultParameters when it is nil.
+//
+// Deprecated: pass the Options of the fields to NewClient instead, such as WithTimeout and WithHTTPClient
 type Parameters struct {
 	Header         map[string]string
 	Cookies        map[string]string
 	RequestTimeout time.Duration
 	Timeout        time.Duration
 	HttpClient     *http.Client
+	// RateLimit limits the requests to each host, see NewRateLimiter
+	RateLimit *RateLimit
+	// Breaker stops the requests to the hosts that keep failing, see NewCircuitBreaker
+	Breaker *BreakerSettings
+	// MaxBodySize is the size in bytes response bodies can not exceed, they are not limited when it is 0
+	MaxBodySize int64
+	// MaxRefreshes is how many meta refreshes and JavaScript redirects GET requests follow, see Client.MaxRefreshes
+	MaxRefreshes int
+	// Bandwidth limits how fast response bodies are read in bytes per second, they are not limited when it is 0
+	Bandwidth int64
+	// Proxy chooses the proxy of each request, see ParseProxies
+	Proxy Proxy
+	// TLS configures the TLS connections when it is set.
+	// The requests fail with ErrNoTransport when Proxy, TLS or Transport are set and HttpClient
+	// has a Transport other than *http.Transport
+	TLS *TLSOptions
+	// Transport tunes the connections when it is set, see TransportOptions
+	Transport *TransportOptions
+	// Logger receives the events of the requests, see Client.Logger
+	Logger Logger
 }
 
+// DefaultParameters is the configuration NewClient starts from before applying its Options
 var DefaultParameters Parameters = Parameters{
 	Header: map[string]string{
 		"User-Agent":    "Owl Mozilla/5.0 Firefox/96.0",
@@ -45,59 +117,318 @@ func HttpClientWrapper(c *http.Client) *Client {
 	}
 }
 
-func NewClient(para *Parameters) *Client {
-	var (
-		client Client
-	)
-	if para != nil {
-		client.Header = DefaultParameters.Header
-		client.Cookies = DefaultParameters.Cookies
-		client.RequestTimeout = DefaultParameters.RequestTimeout
-		client.Timeout = DefaultParameters.Timeout
-	} else {
-		client.Header = para.Header
-		client.Cookies = para.Cookies
-		client.RequestTimeout = para.RequestTimeout
+// NewClient returns a Client configured by opts on top of DefaultParameters.
+// The http.Client of WithHTTPClient is used as is, and is copied before the Client changes its Transport or Jar.
+// Without one, a new http.Client with the timeout of WithTimeout is used.
+// When the Proxy, TLS or Transport options can not be applied, such as on an http.Client whose Transport
+// is not an *http.Transport, every request of the Client fails with ErrNoTransport
+func NewClient(opts ...Option) *Client {
+	cfg := newClientConfig(opts)
+	client := &Client{
+		Client:         cfg.HttpClient,
+		Header:         cfg.Header,
+		Cookies:        cfg.Cookies,
+		RequestTimeout: cfg.RequestTimeout,
+		Retry:          cfg.retry,
+		Cache:          cfg.cache,
 	}
-	if para.HttpClient != nil {
-		client.Client = &http.Client{
-			Timeout: client.Timeout,
-		}
+	if client.Client == nil {
+		client.Client = &http.Client{Timeout: cfg.Timeout}
+	}
+	if cfg.RateLimit != nil {
+		client.Limiter = NewRateLimiter(*cfg.RateLimit)
+	}
+	if cfg.Breaker != nil {
+		client.Breaker = NewCircuitBreaker(*cfg.Breaker)
+	}
+	client.MaxBodySize = cfg.MaxBodySize
+	client.MaxRefreshes = cfg.MaxRefreshes
+	if cfg.Bandwidth > 0 {
+		client.Bandwidth = NewBandwidthLimiter(cfg.Bandwidth)
+	}
+	client.Logger = cfg.Logger
+	var errs []error
+	if cfg.Proxy != nil {
+		errs = append(errs, client.SetProxy(cfg.Proxy))
+	}
+	if cfg.TLS != nil {
+		errs = append(errs, client.SetTLS(*cfg.TLS))
+	}
+	if cfg.Transport != nil {
+		errs = append(errs, client.SetTransportOptions(*cfg.Transport))
 	}
+	client.err = errors.Join(errs...)
+	return client
+}
 
-	return &client
+// ownClient makes c configure a copy of its http.Client, so the http.Client it was given,
+// which may be shared such as http.DefaultClient, is left alone
+func (c *Client) ownClient() {
+	if c.Client == nil {
+		c.Client = &http.Client{}
+		c.ownedClient = c.Client
+	}
+	if c.Client != c.ownedClient {
+		copied := *c.Client
+		c.Client = &copied
+		c.ownedClient = c.Client
+	}
 }
-func (c *Client) Post(url string, contentType string, body interface{}) (io.Reader, error) {
-	bodyReader, err := getBodyReader(body)
+
+// Post sends body to url with the Content-Type contentType. A *Multipart body is sent as multipart/form-data,
+// contentType is then replaced by the Content-Type holding the boundary. Other types than those of getBodyReader,
+// such as structs, are marshaled to JSON and sent as application/json when contentType is empty
+func (c *Client) Post(url string, contentType string, body interface{}, opts ...RequestOption) (io.Reader, error) {
+	bodyReader, bodyType, err := getBodyReader(body)
 	if err != nil {
 		return nil, err
 	}
-	c.Header = map[string]string{
-		"Content-Type": contentType,
+	if _, multipart := body.(*Multipart); bodyType != "" && (multipart || contentType == "") {
+		contentType = bodyType
+	}
+	if contentType != "" {
+		opts = append([]RequestOption{WithHeader("Content-Type", contentType)}, opts...)
 	}
-	return buildRequest(c, url, "POST", bodyReader)
+	return buildRequest(c, url, "POST", bodyReader, opts...)
+}
 
+// Get fetches url and returns its body decoded from the charset of the response
+func (c *Client) Get(url string, opts ...RequestOption) (io.Reader, error) {
+	return buildRequest(c, url, "GET", nil, opts...)
 }
 
-func (c *Client) Get(url string) (io.Reader, error) {
-	return buildRequest(c, url, "GET", nil)
+// GetResponse fetches url and returns the response with its body read. Unless status errors are on,
+// see StatusErrors, the response is returned whatever its status code
+func (c *Client) GetResponse(url string, opts ...RequestOption) (*Response, error) {
+	return c.response(http.MethodGet, url, nil, opts...)
 }
 
-func buildRequest(c *Client, url string, method string, body io.Reader) (io.Reader, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), c.RequestTimeout)
-	defer cancel()
-	req, err := http.NewRequestWithContext(ctx, method, url, body)
-	if err != nil {
+// GetDocument fetches and parses the document at url, rendered by the Renderer of c when it has one,
+// the returned Root records the final URL of the response, see Root.URL.
+// With status errors on, error pages are returned parsed along with the *HTTPError
+func (c *Client) GetDocument(url string, opts ...RequestOption) (*Root, error) {
+	if cfg := newRequestConfig(opts); c.Renderer != nil && !cfg.noRender {
+		return c.render(url, cfg)
+	}
+	resp, err := c.response(http.MethodGet, url, nil, opts...)
+	if resp == nil {
 		return nil, err
 	}
+	root := resp.Parse()
+	if root.Error != nil {
+		return root, root.Error.Err()
+	}
+	return root, err
+}
+
+// do sends a request with the parameters of c customized by opts, retrying it as the Retry policy of c says
+// and going through the Cache of c for GET requests without a Range header. Bodies longer than MaxBodySize fail with a *BodyTooLargeError.
+// The response body stays readable until release is called, which closes it and frees the request context
+func (c *Client) do(method, url string, body io.Reader, opts ...RequestOption) (*http.Response, func(), error) {
+	if c.err != nil {
+		return nil, nil, c.err
+	}
+	cfg := newRequestConfig(opts)
+	req, err := http.NewRequestWithContext(cfg.context(), method, url, body)
+	if err != nil {
+		return nil, nil, err
+	}
 	setParameters(req, c)
+	c.authorize(req)
+	cfg.apply(req)
+	if req.Header.Get("Accept-Encoding") == "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	if err := c.checkRobots(req); err != nil {
+		return nil, nil, err
+	}
+	var span trace.Span
+	if tracer := c.tracer(cfg); tracer != nil {
+		req, span = startSpan(req, tracer)
+	}
+	resp, release, err := c.roundTrip(req, cfg)
+	if span != nil {
+		release = endSpan(span, resp, release, err)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	var limiter *BandwidthLimiter
+	if cfg.bandwidth > 0 {
+		limiter = NewBandwidthLimiter(cfg.bandwidth)
+	}
+	throttle(resp, c.Bandwidth, limiter)
+	return resp, release, nil
+}
+
+// roundTrip sends req for do
+func (c *Client) roundTrip(req *http.Request, cfg *requestConfig) (*http.Response, func(), error) {
+	var resp *http.Response
+	var release func()
+	var err error
+	if c.Cache != nil && req.Method == http.MethodGet && req.Header.Get("Range") == "" {
+		resp, release, err = c.doCached(req, cfg)
+	} else {
+		resp, release, err = c.doRetry(req, cfg)
+	}
+	if err != nil || c.MaxBodySize <= 0 {
+		return resp, release, err
+	}
+	if resp.ContentLength > c.MaxBodySize {
+		release()
+		return nil, nil, &BodyTooLargeError{URL: resp.Request.URL.String(), Limit: c.MaxBodySize}
+	}
+	resp.Body = newLimitedBody(resp, c.MaxBodySize)
+	return resp, release, nil
+}
 
-	resp, err := c.Do(req)
+// doRetry sends req until an attempt is not retried, and once more when Reauthenticate renews the credentials
+func (c *Client) doRetry(req *http.Request, cfg *requestConfig) (*http.Response, func(), error) {
+	reauthenticated := false
+	for attempt := 1; ; attempt++ {
+		resp, release, err := c.send(req, attempt, cfg)
+		if err == nil && !reauthenticated {
+			again, authErr := c.reauthenticate(req, resp)
+			if authErr != nil {
+				release()
+				return nil, nil, authErr
+			}
+			if again {
+				reauthenticated = true
+				discard(resp.Body)
+				release()
+				continue
+			}
+		}
+		if req.Context().Err() != nil {
+			// The caller gave up, only the timeout of an attempt is worth another one
+			return resp, release, err
+		}
+		wait, retry := c.Retry.shouldRetry(attempt, req, resp, err)
+		if !retry {
+			return resp, release, err
+		}
+		if err == nil {
+			discard(resp.Body)
+			release()
+		}
+		if c.Metrics != nil {
+			c.Metrics.Retry(req.Method, req.URL.Hostname())
+		}
+		traceRetry(req, attempt)
+		c.info("owl: retry", "method", req.Method, "url", req.URL.String(), "attempt", attempt, "wait", wait)
+		if err := sleep(req.Context(), wait); err != nil {
+			return nil, nil, err
+		}
+	}
+}
+
+// send makes the attempt-th attempt of req within the timeout of the request once the Limiter and the Breaker of c allow it,
+// later attempts send the body again from GetBody. Compressed bodies are decoded, see decodeBody
+func (c *Client) send(req *http.Request, attempt int, cfg *requestConfig) (*http.Response, func(), error) {
+	// Waiting for the limiter does not count against the timeout
+	if c.Limiter != nil {
+		start := time.Now()
+		if err := c.Limiter.Wait(req.Context(), req.URL.Hostname()); err != nil {
+			return nil, nil, err
+		}
+		if waited := time.Since(start); waited >= time.Millisecond {
+			c.debug("owl: rate limit wait", "host", req.URL.Hostname(), "wait", waited)
+		}
+	}
+	ctx, cancel := req.Context(), context.CancelFunc(func() {})
+	if timeout := c.requestTimeout(cfg); timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	}
+	req = req.Clone(ctx)
+	if attempt > 1 && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			cancel()
+			return nil, nil, err
+		}
+		req.Body = body
+	}
+	if c.Breaker != nil {
+		if err := c.Breaker.allow(req.URL.Hostname(), time.Now()); err != nil {
+			cancel()
+			return nil, nil, err
+		}
+	}
+	start := time.Now()
+	resp, err := c.requestClient(cfg).Do(req)
+	if c.Breaker != nil {
+		c.Breaker.done(req.URL.Hostname(), resp, err, time.Now())
+	}
+	if c.Metrics != nil {
+		status := 0
+		if err == nil {
+			status = resp.StatusCode
+		}
+		c.Metrics.Request(req.Method, req.URL.Hostname(), status, time.Since(start))
+	}
 	if err != nil {
+		c.debug("owl: request failed", "method", req.Method, "url", req.URL.String(), "attempt", attempt, "error", err)
+		cancel()
+		return nil, nil, err
+	}
+	c.debug("owl: request", "method", req.Method, "url", req.URL.String(), "attempt", attempt,
+		"status", resp.StatusCode, "duration", time.Since(start))
+	if final := resp.Request.URL; final.String() != req.URL.String() {
+		c.debug("owl: redirect", "from", req.URL.String(), "to", final.String())
+	}
+	decodeBody(resp)
+	if c.Metrics != nil {
+		resp.Body = &countingBody{ReadCloser: resp.Body, host: req.URL.Hostname(), metrics: c.Metrics}
+	}
+	return resp, func() {
+		resp.Body.Close()
+		cancel()
+	}, nil
+}
+
+// countingBody reports the number of bytes read from a response body to metrics once it is closed
+type countingBody struct {
+	io.ReadCloser
+	host    string
+	metrics Metrics
+	read    int64
+	closed  bool
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.read += int64(n)
+	return n, err
+}
+
+func (b *countingBody) Close() error {
+	if !b.closed {
+		b.closed = true
+		b.metrics.Downloaded(b.host, b.read)
+	}
+	return b.ReadCloser.Close()
+}
+
+// buildRequest sends a request with the parameters of c and returns its body decoded from the charset of the response
+func buildRequest(c *Client, url string, method string, body io.Reader, opts ...RequestOption) (io.Reader, error) {
+	resp, err := c.response(method, url, body, opts...)
+	if resp == nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-	return charset.NewReader(resp.Body, resp.Header.Get("Content-Type"))
+	reader, decodeErr := resp.decodedBody()
+	if decodeErr != nil {
+		return nil, decodeErr
+	}
+	return reader, err
+}
+
+// checkRobots returns ErrDisallowedByRobots when the Robots policy of c disallows req
+func (c *Client) checkRobots(req *http.Request) error {
+	if c.Robots != nil && !c.Robots.Allowed(req.Header.Get("User-Agent"), req.URL) {
+		return ErrDisallowedByRobots
+	}
+	return nil
 }
 
 func setParameters(req *http.Request, c *Client) {
@@ -114,8 +445,9 @@ func setParameters(req *http.Request, c *Client) {
 	}
 }
 
-// getBodyReader serializes the body for a network request. See the test file for examples
-func getBodyReader(rawBody interface{}) (io.Reader, error) {
+// getBodyReader serializes the body for a network request.
+// The Content-Type of multipart and JSON marshaled bodies is returned along with them
+func getBodyReader(rawBody interface{}) (io.Reader, string, error) {
 	var bodyReader io.Reader
 
 	if rawBody != nil {
@@ -123,7 +455,7 @@ func getBodyReader(rawBody interface{}) (io.Reader, error) {
 		case map[string]string:
 			jsonBody, err := json.Marshal(body)
 			if err != nil {
-				return nil, err
+				return nil, "", err
 			}
 			bodyReader = bytes.NewBuffer(jsonBody)
 		case netURL.Values:
@@ -132,10 +464,18 @@ func getBodyReader(rawBody interface{}) (io.Reader, error) {
 			bodyReader = bytes.NewBuffer(body)
 		case string: //expects JSON format
 			bodyReader = strings.NewReader(body)
+		case *Multipart:
+			return body.encode()
+		case io.Reader:
+			return nil, "", errors.New("unable to determine the body type")
 		default:
-			return nil, errors.New("unable to determine the body type")
+			jsonBody, err := json.Marshal(body)
+			if err != nil {
+				return nil, "", err
+			}
+			return bytes.NewReader(jsonBody), "application/json", nil
 		}
 	}
 
-	return bodyReader, nil
+	return bodyReader, "", nil
 }
@@ -0,0 +1,197 @@
+// Command owl queries HTML documents from the shell with CSS selectors, to try selectors out
+// or extract a few values without writing a program:
+//
+//	owl get https://example.com/ --select "a[href]" --attr href --resolve
+//	curl -s https://example.com/ | owl get --select h1
+//	owl get page.html --select "div.price" --json
+//
+// The document is fetched when the argument is an http or https URL, read from the file it names otherwise,
+// and read from the standard input when it is - or missing. owl exits with 1 when nothing matches
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Patrickmitech/owl"
+)
+
+const usage = `Usage: owl get [URL | FILE | -] [flags]
+
+Prints the text, an attribute or the HTML of the elements of a document matching a CSS selector.
+
+Flags:
+`
+
+// headers are the repeated --header flags
+type headers map[string]string
+
+func (h headers) String() string {
+	return fmt.Sprint(map[string]string(h))
+}
+
+func (h headers) Set(value string) error {
+	name, v, ok := strings.Cut(value, ":")
+	if !ok {
+		return errors.New("headers are written Name: value")
+	}
+	h[strings.TrimSpace(name)] = strings.TrimSpace(v)
+	return nil
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs the command with args and returns its exit code
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] != "get" {
+		fmt.Fprint(stderr, usage)
+		fmt.Fprintln(stderr, "  run owl get -h for the flags")
+		return 2
+	}
+	fs := flag.NewFlagSet("owl get", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		fs.PrintDefaults()
+	}
+	selector := fs.String("select", "", "CSS selector of the elements, the whole document when empty")
+	attr := fs.String("attr", "", "print the value of this attribute instead of the text, elements without it are skipped")
+	printHTML := fs.Bool("html", false, "print the HTML of the elements instead of their text")
+	resolve := fs.Bool("resolve", false, "resolve the attribute values against the URL of the document, for href and src")
+	first := fs.Bool("first", false, "print the first element only")
+	asJSON := fs.Bool("json", false, "print a JSON array of the values")
+	base := fs.String("base", "", "URL of a document read from a file or the standard input, for --resolve")
+	userAgent := fs.String("user-agent", "", "User-Agent header of the request")
+	timeout := fs.Duration("timeout", 30*time.Second, "timeout of the request")
+	header := headers{}
+	fs.Var(header, "header", "header of the request, written Name: value, can be repeated")
+
+	// Flags may follow the argument, as in owl get URL --select a
+	var operands []string
+	rest := args[1:]
+	for {
+		if err := fs.Parse(rest); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return 0
+			}
+			return 2
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		operands = append(operands, fs.Arg(0))
+		rest = fs.Args()[1:]
+	}
+	if len(operands) > 1 {
+		fmt.Fprintln(stderr, "owl: one document at a time")
+		return 2
+	}
+	if *printHTML && *attr != "" {
+		fmt.Fprintln(stderr, "owl: --html and --attr can not be used together")
+		return 2
+	}
+
+	source := "-"
+	if len(operands) == 1 {
+		source = operands[0]
+	}
+	doc, err := load(source, stdin, *base, *userAgent, *timeout, header)
+	if err != nil {
+		fmt.Fprintln(stderr, "owl:", err)
+		return 1
+	}
+
+	elements := []*owl.Root{doc}
+	if *selector != "" {
+		matches := doc.Select(*selector)
+		if matches.Error != nil && matches.Error.Type == owl.ErrInvalidSelector {
+			fmt.Fprintln(stderr, "owl:", matches.Error.Err())
+			return 2
+		}
+		elements = matches.Roots
+	}
+	values := []string{}
+	for _, e := range elements {
+		if *first && len(values) == 1 {
+			break
+		}
+		switch {
+		case *attr != "":
+			value, ok := e.Attr(*attr)
+			if !ok {
+				continue
+			}
+			if *resolve {
+				if u, err := e.ResolveURL(value); err == nil {
+					value = u.String()
+				}
+			}
+			values = append(values, value)
+		case *printHTML:
+			values = append(values, string(e.Render()))
+		default:
+			values = append(values, e.FullTextOpts(owl.TextOptions{Trim: true, CollapseWhitespace: true, Separator: " "}))
+		}
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		enc.Encode(values)
+	} else {
+		for _, v := range values {
+			fmt.Fprintln(stdout, v)
+		}
+	}
+	if len(values) == 0 {
+		return 1
+	}
+	return 0
+}
+
+// load fetches or reads the document of source
+func load(source string, stdin io.Reader, base, userAgent string, timeout time.Duration, header headers) (*owl.Root, error) {
+	if u, err := url.Parse(source); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		opts := []owl.Option{owl.WithTimeout(timeout)}
+		if userAgent != "" {
+			opts = append(opts, owl.WithUserAgent(userAgent))
+		}
+		if len(header) > 0 {
+			opts = append(opts, owl.WithHeaders(header))
+		}
+		client := owl.NewClient(opts...)
+		client.StatusErrors = true
+		return client.GetDocument(source)
+	}
+	r := stdin
+	if source != "-" {
+		f, err := os.Open(source)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	doc := owl.HTMLParse(r)
+	if doc.Error != nil {
+		return nil, doc.Error.Err()
+	}
+	if base != "" {
+		u, err := url.Parse(base)
+		if err != nil {
+			return nil, err
+		}
+		doc.SetURL(u)
+	}
+	return doc, nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const page = `<html><head><title>Shop</title></head><body>
+	<div class="price">$ 10</div><div class="price">
+		$ 12 <b>sale</b></div>
+	<a href="/a">A</a><a>none</a><a href="https://other.example/b">B</a>
+</body></html>`
+
+func runOwl(t *testing.T, stdin string, args ...string) (string, string, int) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return stdout.String(), stderr.String(), code
+}
+
+func TestGet(t *testing.T) {
+	var userAgent, token string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent, token = r.UserAgent(), r.Header.Get("X-Token")
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, page)
+	}))
+	defer srv.Close()
+
+	out, _, code := runOwl(t, "", "get", srv.URL+"/shop", "--select", "div.price", "--user-agent", "owl-test", "--header", "X-Token: 42")
+	require.Equal(t, 0, code)
+	require.Equal(t, "$ 10\n$ 12 sale\n", out)
+	require.Equal(t, "owl-test", userAgent)
+	require.Equal(t, "42", token)
+
+	out, _, code = runOwl(t, "", "get", "--select", "a", "--attr", "href", "--resolve", "--json", srv.URL+"/shop")
+	require.Equal(t, 0, code)
+	require.Equal(t, "[\n  \""+srv.URL+"/a\",\n  \"https://other.example/b\"\n]\n", out)
+
+	_, errOut, code := runOwl(t, "", "get", srv.URL+"/missing")
+	require.Equal(t, 1, code)
+	require.Contains(t, errOut, "404")
+}
+
+func TestGetStdin(t *testing.T) {
+	out, _, code := runOwl(t, page, "get", "--select", "a[href]", "--attr", "href", "--first")
+	require.Equal(t, 0, code)
+	require.Equal(t, "/a\n", out)
+
+	out, _, code = runOwl(t, page, "get", "-", "--select", "a", "--attr", "href", "--resolve", "--base", "https://shop.example/x/")
+	require.Equal(t, 0, code)
+	require.Equal(t, "https://shop.example/a\nhttps://other.example/b\n", out)
+
+	out, _, code = runOwl(t, page, "get", "--select", "b", "--html", "--json")
+	require.Equal(t, 0, code)
+	require.Equal(t, "[\n  \"<b>sale</b>\"\n]\n", out)
+
+	out, _, code = runOwl(t, page, "get", "--select", "title")
+	require.Equal(t, 0, code)
+	require.Equal(t, "Shop\n", out)
+
+	out, _, code = runOwl(t, page, "get", "--select", "table", "--json")
+	require.Equal(t, 1, code)
+	require.Equal(t, "[]\n", out)
+}
+
+func TestGetFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "page.html")
+	require.NoError(t, os.WriteFile(path, []byte(page), 0o644))
+	out, _, code := runOwl(t, "", "get", path, "--select", "div.price", "--first")
+	require.Equal(t, 0, code)
+	require.Equal(t, "$ 10\n", out)
+
+	_, errOut, code := runOwl(t, "", "get", filepath.Join(t.TempDir(), "missing.html"))
+	require.Equal(t, 1, code)
+	require.Contains(t, errOut, "no such file")
+}
+
+func TestUsage(t *testing.T) {
+	for _, args := range [][]string{
+		{},
+		{"put"},
+		{"get", "--unknown"},
+		{"get", "a.html", "b.html"},
+		{"get", "--html", "--attr", "href"},
+		{"get", "--select", "a[["},
+	} {
+		_, errOut, code := runOwl(t, page, args...)
+		require.Equal(t, 2, code, args)
+		require.NotEmpty(t, errOut, args)
+	}
+	_, errOut, code := runOwl(t, "", "get", "-h")
+	require.Equal(t, 0, code)
+	require.Contains(t, errOut, "-select")
+}
@@ -0,0 +1,127 @@
+package owl
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Enricher completes a record with a document fetched from a URL found in the primary document, see Pipeline.Compose
+type Enricher struct {
+	// Link extracts the URL of the document from the primary document, such as
+	// Pipe(Select("a.reviews"), Attr("href")). Relative URLs are resolved against the primary document
+	Link *Pipeline
+	// Into names the field of the record set from the document. Structs and pointers to structs
+	// are filled by Unmarshal, other fields get the result of Value
+	Into string
+	// Value extracts the value of the field from the document when it is set, see Extract
+	Value *Pipeline
+	// Optional leaves the field as it is when the document can not be fetched or the Link finds nothing,
+	// instead of failing the extraction
+	Optional bool
+}
+
+// Composition extracts a record spanning several documents, see Pipeline.Compose
+type Composition struct {
+	primary   *Pipeline
+	enrichers []Enricher
+}
+
+// Compose returns a Composition extracting a record from the element p finds in a document,
+// completed by the documents the enrichers fetch, such as the reviews of a product page served
+// by another endpoint:
+//
+//	product := owl.Pipe(owl.Select(".product")).Compose(owl.Enricher{
+//		Link: owl.Pipe(owl.Select("a.reviews"), owl.Attr("href")),
+//		Into: "Reviews",
+//	})
+//	err := product.Extract(client, page, &record)
+func (p *Pipeline) Compose(enrichers ...Enricher) *Composition {
+	return &Composition{primary: p, enrichers: enrichers}
+}
+
+// Extract fills the struct v points to: Unmarshal decodes it from the element the primary Pipeline finds in root,
+// then every Enricher fetches its document with client, going through its retries, rate limits and cache
+func (c *Composition) Extract(client *Client, root *Root, v any, opts ...RequestOption) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("owl: Extract needs a non-nil pointer to a struct")
+	}
+	result, err := c.primary.Run(root)
+	if err != nil {
+		return err
+	}
+	element, ok := result.(*Root)
+	if !ok {
+		return fmt.Errorf("owl: primary pipeline result is %T, not *Root", result)
+	}
+	if err := Unmarshal(element, v); err != nil {
+		return err
+	}
+	for i, e := range c.enrichers {
+		if err := e.enrich(client, root, rv.Elem(), opts); err != nil && !e.Optional {
+			return fmt.Errorf("owl: enricher %d (%s): %w", i, e.Into, err)
+		}
+	}
+	return nil
+}
+
+// enrich fetches the document of e and sets the field Into of the struct record from it
+func (e Enricher) enrich(client *Client, root *Root, record reflect.Value, opts []RequestOption) error {
+	field := record.FieldByName(e.Into)
+	if !field.IsValid() || !field.CanSet() {
+		return fmt.Errorf("no exported field %s in %s", e.Into, record.Type())
+	}
+	unmarshaled := field.Kind() == reflect.Struct || field.Kind() == reflect.Pointer && field.Type().Elem().Kind() == reflect.Struct
+	if e.Value == nil && !unmarshaled {
+		return fmt.Errorf("field %s is a %s, it needs a Value pipeline", e.Into, field.Type())
+	}
+	link, err := Extract[string](root, e.Link)
+	if err != nil {
+		return err
+	}
+	u, err := root.ResolveURL(link)
+	if err != nil {
+		return err
+	}
+	doc, err := client.GetDocument(u.String(), append([]RequestOption{WithStatusErrors(true)}, opts...)...)
+	if err != nil {
+		return err
+	}
+	if e.Value != nil {
+		value, err := e.Value.Run(doc)
+		if err != nil {
+			return err
+		}
+		return setResult(field, value)
+	}
+	if field.Kind() == reflect.Struct {
+		return Unmarshal(doc, field.Addr().Interface())
+	}
+	target := reflect.New(field.Type().Elem())
+	if err := Unmarshal(doc, target.Interface()); err != nil {
+		return err
+	}
+	field.Set(target)
+	return nil
+}
+
+// setResult sets field to the result of a Pipeline, lists are converted element by element
+func setResult(field reflect.Value, value any) error {
+	if list, ok := value.([]any); ok && field.Kind() == reflect.Slice {
+		slice := reflect.MakeSlice(field.Type(), len(list), len(list))
+		for i, elem := range list {
+			if err := setResult(slice.Index(i), elem); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+		return nil
+	}
+	rv := reflect.ValueOf(value)
+	if !rv.IsValid() || !rv.Type().AssignableTo(field.Type()) {
+		return fmt.Errorf("pipeline result is %T, not %s", value, field.Type())
+	}
+	field.Set(rv)
+	return nil
+}
@@ -0,0 +1,78 @@
+package owl
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompose(t *testing.T) {
+	reviewsRequests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/product":
+			fmt.Fprint(w, `<div class="product"><h1>Owl plush</h1><span class="price">19.99</span>
+				<a class="reviews" href="/api/reviews?id=1">Reviews</a><a class="seller" href="/seller/7">Hoot</a>
+				<a class="faq" href="/faq">FAQ</a></div>`)
+		case "/api/reviews":
+			reviewsRequests++
+			if reviewsRequests == 1 {
+				// The retry policy of the client applies to the extra fetches
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			fmt.Fprint(w, `<ul><li>Soft</li><li>Cute</li></ul>`)
+		case "/seller/7":
+			fmt.Fprint(w, `<h2>Hoot &amp; co</h2><p class="city">Paris</p>`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	client := NewClient(WithHTTPClient(srv.Client()), WithRetry(RetryPolicy{MaxAttempts: 2}))
+
+	type seller struct {
+		Name string `owl:"h2"`
+		City string `owl:".city"`
+	}
+	type product struct {
+		Name    string  `owl:"h1"`
+		Price   float64 `owl:".price"`
+		Reviews []string
+		Seller  *seller
+		FAQ     string
+	}
+	page, err := client.GetDocument(srv.URL + "/product")
+	require.NoError(t, err)
+
+	composition := Pipe(Select(".product")).Compose(
+		Enricher{Link: Pipe(Select("a.reviews"), Attr("href")), Into: "Reviews", Value: Pipe(SelectAll("li"), Text())},
+		Enricher{Link: Pipe(Select("a.seller"), Attr("href")), Into: "Seller"},
+		Enricher{Link: Pipe(Select("a.faq"), Attr("href")), Into: "FAQ", Value: Pipe(Select("p"), Text()), Optional: true},
+	)
+	var p product
+	require.NoError(t, composition.Extract(client, page, &p))
+	require.Equal(t, "Owl plush", p.Name)
+	require.Equal(t, 19.99, p.Price)
+	require.Equal(t, []string{"Soft", "Cute"}, p.Reviews)
+	require.Equal(t, &seller{Name: "Hoot & co", City: "Paris"}, p.Seller)
+	require.Empty(t, p.FAQ)
+	require.Equal(t, 2, reviewsRequests)
+
+	// Enrichers that are not optional fail the extraction
+	err = Pipe().Compose(Enricher{Link: Pipe(Select("a.faq"), Attr("href")), Into: "FAQ", Value: Pipe(Text())}).
+		Extract(client, page, &p)
+	require.ErrorContains(t, err, "owl: enricher 0 (FAQ)")
+	var httpErr *HTTPError
+	require.ErrorAs(t, err, &httpErr)
+
+	err = Pipe().Compose(Enricher{Link: Pipe(Select("a.faq"), Attr("href")), Into: "Name"}).Extract(client, page, &p)
+	require.ErrorContains(t, err, "needs a Value pipeline")
+	err = Pipe().Compose(Enricher{Link: Pipe(Select("a.seller"), Attr("href")), Into: "Name", Value: Pipe(SelectAll("h2"))}).
+		Extract(client, page, &p)
+	require.ErrorContains(t, err, "pipeline result is []interface {}, not string")
+	require.Error(t, Pipe(SelectAll("h1")).Compose().Extract(client, page, &p))
+}
@@ -0,0 +1,188 @@
+package owl
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// maxFieldText is the length in characters above which the text of a field is suspicious,
+// such as the text of a whole page matched by a selector too broad
+const maxFieldText = 10000
+
+// Confidence estimates how much the values extracted from a page can be trusted, to filter out
+// the records of pages a Pipeline or the struct tags of Unmarshal no longer fit, see RunScored and UnmarshalScored
+type Confidence struct {
+	// Score goes from 0, nothing was extracted, to 1. It is the share of the Fields that were filled,
+	// lowered by a quarter of a field for every selector that matched several elements where one was expected,
+	// and by half a field for every text longer than the value of a field should be
+	Score float64 `json:"score"`
+	// Fields is the number of values extracted: the fields of a struct, or the elements of the list of a Pipeline
+	Fields int `json:"fields"`
+	// Filled is the number of the Fields that got a value other than whitespace
+	Filled int `json:"filled"`
+	// Issues tell what lowered the Score
+	Issues []string `json:"issues,omitempty"`
+
+	ambiguous  int
+	suspicious int
+}
+
+// choose records that what is named chose the first of n elements
+func (c *Confidence) choose(name string, n int) {
+	if c == nil || n <= 1 {
+		return
+	}
+	c.ambiguous++
+	c.Issues = append(c.Issues, fmt.Sprintf("%s: chose among %d elements", name, n))
+}
+
+// missing records a field without a value
+func (c *Confidence) missing(name, reason string) {
+	if c == nil {
+		return
+	}
+	c.Fields++
+	c.Issues = append(c.Issues, name+": "+reason)
+}
+
+// text records a field with the value s, long texts are only suspicious when checkLength is set
+func (c *Confidence) text(name, s string, checkLength bool) {
+	if c == nil {
+		return
+	}
+	if strings.TrimSpace(s) == "" {
+		c.missing(name, "empty")
+		return
+	}
+	c.Fields++
+	c.Filled++
+	if n := utf8.RuneCountInString(s); checkLength && n > maxFieldText {
+		c.suspicious++
+		c.Issues = append(c.Issues, fmt.Sprintf("%s: %d characters long", name, n))
+	}
+}
+
+// finish computes the Score
+func (c *Confidence) finish() {
+	if c.Fields == 0 {
+		c.Score = 0
+		return
+	}
+	score := (float64(c.Filled) - float64(c.ambiguous)/4 - float64(c.suspicious)/2) / float64(c.Fields)
+	c.Score = max(0, min(score, 1))
+}
+
+// RunScored runs the pipeline on root like Run, along with the Confidence of the result.
+// A failed run has a Score of 0
+func (p *Pipeline) RunScored(root *Root) (any, Confidence, error) {
+	c := Confidence{}
+	value, err := p.run(root, &c)
+	if err != nil {
+		c.missing("result", err.Error())
+		c.finish()
+		return nil, c, err
+	}
+	values, isList := value.([]any)
+	if !isList {
+		values = []any{value}
+	}
+	if len(values) == 0 {
+		c.missing("result", "empty list")
+	}
+	for i, v := range values {
+		name := "result"
+		if isList {
+			name = fmt.Sprintf("result[%d]", i)
+		}
+		switch v := v.(type) {
+		case string:
+			c.text(name, v, true)
+		case *Root:
+			if v == nil || v.Node == nil {
+				c.missing(name, "no element")
+				break
+			}
+			c.text(name, elementText(v), true)
+		case nil:
+			c.missing(name, "no value")
+		default:
+			c.text(name, fmt.Sprint(v), false)
+		}
+	}
+	c.finish()
+	return value, c, nil
+}
+
+// ExtractScored is Extract along with the Confidence of the result, see RunScored
+func ExtractScored[T any](root *Root, p *Pipeline) (T, Confidence, error) {
+	value, c, err := p.RunScored(root)
+	if err != nil {
+		var zero T
+		return zero, c, err
+	}
+	v, err := convertResult[T](value)
+	return v, c, err
+}
+
+// UnmarshalScored is Unmarshal along with the Confidence of the struct: its fields, and those of its nested structs,
+// count as filled when their selector matched an element with a value. Slices count as a single field
+func UnmarshalScored(root *Root, v any) (Confidence, error) {
+	c := Confidence{}
+	err := unmarshal(root, v, &c, nil)
+	c.finish()
+	return c, err
+}
+
+// ConfidenceMonitor follows the average Score of the last extractions to tell when it drops,
+// such as after a site changed its layout. It is safe for concurrent use
+type ConfidenceMonitor struct {
+	// Window is the number of the last scores averaged, 100 when 0
+	Window int
+	// Threshold is the average below which OnDrop is called
+	Threshold float64
+	// OnDrop is called with the average when it goes below Threshold once Window scores were added.
+	// It is not called again before the average goes back to Threshold or above
+	OnDrop func(average float64)
+
+	mu      sync.Mutex
+	scores  []float64
+	next    int
+	sum     float64
+	dropped bool
+}
+
+// Add adds the Score of c to the average
+func (m *ConfidenceMonitor) Add(c Confidence) {
+	m.mu.Lock()
+	window := m.Window
+	if window <= 0 {
+		window = 100
+	}
+	if len(m.scores) < window {
+		m.scores = append(m.scores, c.Score)
+	} else {
+		m.sum -= m.scores[m.next]
+		m.scores[m.next] = c.Score
+		m.next = (m.next + 1) % window
+	}
+	m.sum += c.Score
+	average := m.sum / float64(len(m.scores))
+	drop := len(m.scores) == window && average < m.Threshold && !m.dropped
+	m.dropped = len(m.scores) == window && average < m.Threshold
+	m.mu.Unlock()
+	if drop && m.OnDrop != nil {
+		m.OnDrop(average)
+	}
+}
+
+// Average returns the average Score of the last Window extractions, 0 before the first one
+func (m *ConfidenceMonitor) Average() float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.scores) == 0 {
+		return 0
+	}
+	return m.sum / float64(len(m.scores))
+}
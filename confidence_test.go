@@ -0,0 +1,87 @@
+package owl
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunScored(t *testing.T) {
+	doc := HTMLParseFromString(`<h1>Owl plush</h1><p class="price">19.99</p><p class="price">24.99</p><p class="empty"> </p>`)
+
+	title, c, err := ExtractScored[string](doc, Pipe(Select("h1"), Text()))
+	require.NoError(t, err)
+	require.Equal(t, "Owl plush", title)
+	require.Equal(t, Confidence{Score: 1, Fields: 1, Filled: 1}, c)
+
+	// Select took the first of two prices
+	price, c, err := ExtractScored[float64](doc, Pipe(Select(".price"), Text(), ParseFloat()))
+	require.NoError(t, err)
+	require.Equal(t, 19.99, price)
+	require.Equal(t, 0.75, c.Score)
+	require.Equal(t, []string{"step 0 (Select): chose among 2 elements"}, c.Issues)
+
+	_, c, err = Pipe(Select(".empty"), Text()).RunScored(doc)
+	require.NoError(t, err)
+	require.Zero(t, c.Score)
+	require.Equal(t, []string{"result: empty"}, c.Issues)
+
+	_, c, err = Pipe(Select(".missing"), Text()).RunScored(doc)
+	require.Error(t, err)
+	require.Zero(t, c.Score)
+
+	prices, c, err := ExtractScored[[]string](doc, Pipe(SelectAll("p"), Text()))
+	require.NoError(t, err)
+	require.Len(t, prices, 3)
+	require.Equal(t, 3, c.Fields)
+	require.Equal(t, 2, c.Filled)
+	require.InDelta(t, 2.0/3, c.Score, 1e-9)
+
+	_, c, err = Pipe(SelectAll("table")).RunScored(doc)
+	require.NoError(t, err)
+	require.Zero(t, c.Score)
+
+	long := HTMLParseFromString("<main>" + strings.Repeat("owl ", maxFieldText) + "</main>")
+	_, c, err = Pipe(Select("main"), Text()).RunScored(long)
+	require.NoError(t, err)
+	require.Equal(t, 0.5, c.Score)
+}
+
+func TestUnmarshalScored(t *testing.T) {
+	type product struct {
+		Name   string   `owl:"h1"`
+		Price  float64  `owl:".price"`
+		Color  string   `owl:".color"`
+		Sizes  []string `owl:".size"`
+		Seller struct {
+			Name string `owl:".name"`
+			City string `owl:".city"`
+		} `owl:".seller"`
+	}
+	doc := HTMLParseFromString(`<h1>Owl plush</h1><span class="price">19.99</span><span class="price">9.99</span>
+		<span class="size">S</span><span class="size">M</span><div class="seller"><b class="name">Hoot</b><i class="city"></i></div>`)
+	var p product
+	c, err := UnmarshalScored(doc, &p)
+	require.NoError(t, err)
+	require.Equal(t, "Hoot", p.Seller.Name)
+	require.Equal(t, 6, c.Fields)
+	require.Equal(t, 4, c.Filled)
+	require.InDelta(t, (4-0.25)/6, c.Score, 1e-9)
+	require.Equal(t, []string{
+		`Price: chose among 2 elements`,
+		`Color: no element matches ".color"`,
+		`Seller.City: empty`,
+	}, c.Issues)
+}
+
+func TestConfidenceMonitor(t *testing.T) {
+	var drops []float64
+	m := &ConfidenceMonitor{Window: 4, Threshold: 0.5, OnDrop: func(average float64) { drops = append(drops, average) }}
+	require.Zero(t, m.Average())
+	for _, score := range []float64{0, 0, 1, 1, 0, 0, 0, 1, 1, 1, 1, 0, 0, 0} {
+		m.Add(Confidence{Score: score})
+	}
+	require.Equal(t, []float64{0.25, 0.25}, drops)
+	require.Equal(t, 0.25, m.Average())
+}
@@ -0,0 +1,600 @@
+package owl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrAlreadyVisited is returned when visiting a URL the Crawler already visited or queued
+	ErrAlreadyVisited = errors.New("owl: URL already visited")
+	// ErrForbiddenDomain is returned when visiting a URL outside of the AllowedDomains of the Crawler
+	ErrForbiddenDomain = errors.New("owl: domain not allowed")
+	// ErrMaxDepth is returned when visiting a URL deeper than the MaxDepth of the Crawler
+	ErrMaxDepth = errors.New("owl: max depth reached")
+	// ErrFilteredURL is returned when visiting a URL the AllowURLs or DenyURLs of the Crawler keep out of the crawl
+	ErrFilteredURL = errors.New("owl: URL filtered out")
+	// ErrMaxPages is returned when visiting a URL once the Crawler queued MaxPages pages, or MaxHostPages of its host
+	ErrMaxPages = errors.New("owl: max pages reached")
+	// ErrDuplicatePage is reported to the OnError callbacks of a Crawler for the pages its Duplicates filter skips
+	ErrDuplicatePage = errors.New("owl: duplicate page")
+)
+
+// Crawler fetches pages from seed URLs and from the links its callbacks visit, calling the callbacks
+// registered for every page:
+//
+//	crawler := owl.NewCrawler(client)
+//	crawler.AllowedDomains = []string{"example.com"}
+//	crawler.OnHTML("a[href]", func(e *owl.Root, ctx *owl.CrawlContext) {
+//		href, _ := e.Attr("href")
+//		ctx.Visit(href)
+//	})
+//	err := crawler.Start("https://example.com/")
+//
+// The Robots policy of the Client is honored when links are visited, along with its crawl delays
+// when it has them, such as robots.Policy
+type Crawler struct {
+	// Client fetches the pages
+	Client *Client
+	// AllowedDomains restricts the crawl to these hosts and their subdomains, every host is allowed when empty
+	AllowedDomains []string
+	// MaxDepth is how many links away from the seeds pages are visited, there is no limit when 0
+	MaxDepth int
+	// AllowURLs restricts the crawl to the URLs matching one of these regular expressions, see Glob.
+	// Every URL is allowed when empty
+	AllowURLs []*regexp.Regexp
+	// DenyURLs keeps the URLs matching one of these regular expressions out of the crawl, see Glob
+	DenyURLs []*regexp.Regexp
+	// MaxPages is how many pages the crawl queues, seeds included, there is no limit when 0
+	MaxPages int
+	// MaxHostPages is how many pages of each host the crawl queues, there is no limit when 0
+	MaxHostPages int
+	// Priority returns the Priority of the pages to visit, their links being visited first
+	// by a Frontier. Pages have the priority 0 when nil
+	Priority func(req CrawlRequest) int
+	// NormalizeOptions configure the NormalizeURL the URLs to visit go through,
+	// so the URLs of a page are visited once
+	NormalizeOptions []NormalizeOption
+	// Preprocess changes the HTML pages before the Duplicates filter and the callbacks see them,
+	// such as removing the boilerplate of the site, see Root.Preprocess
+	Preprocess []Preprocessor
+	// Duplicates skips the callbacks of the HTML pages whose text is a near-duplicate
+	// of a page processed before, such as mirrors, reporting ErrDuplicatePage instead. Nothing is skipped when nil
+	Duplicates *DuplicateFilter
+	// Sink receives the records of CrawlContext.Emit, it is not closed when the crawl ends
+	Sink Sink
+	// Concurrency is the number of pages fetched at once, 1 when 0
+	Concurrency int
+	// RateLimit limits the requests of the crawl to each host, on top of the Limiter of the Client
+	RateLimit *RateLimit
+	// FetchFrames fetches the frames and iframes of the HTML pages that have the same origin as the page
+	// before the callbacks of the page, see CrawlContext.Frames. The frames are not visited as pages
+	FetchFrames bool
+	// Store keeps the visited URLs and the queue, a new Frontier when nil. Pages being fetched
+	// when the crawl is canceled are queued again, those being fetched when the program crashes are lost
+	Store Store
+
+	htmlCallbacks     []htmlCallback
+	responseCallbacks []func(*Response, *CrawlContext)
+	errorCallbacks    []func(error, *CrawlContext)
+
+	mu   sync.Mutex
+	wake *sync.Cond
+	// active are the requests being processed by URL
+	active map[string]CrawlRequest
+	// pages is the number of pages queued
+	pages   int
+	hosts   map[string]*crawlHost
+	limiter RateLimiter
+}
+
+// CrawlRequest is a page for the Crawler to visit
+type CrawlRequest struct {
+	URL string
+	// Depth is the number of links followed from a seed to the page, 0 for the seeds
+	Depth int
+	// Referer is the URL of the page that visited this one, empty for the seeds
+	Referer string
+	// Priority orders the queue of a Frontier, higher first. Stores with a plain queue,
+	// such as MemoryStore, ignore it
+	Priority int
+}
+
+// CrawlContext is given to the callbacks of a page, it is shared by the callbacks of that page
+type CrawlContext struct {
+	Request CrawlRequest
+	// Response is the response of the page, nil when the request failed
+	Response *Response
+
+	crawler *Crawler
+	ctx     context.Context
+	// doc is the parsed page, links resolve against its base URL
+	doc *Root
+	// frames are the frames of the page fetched when the Crawler FetchFrames
+	frames []Frame
+	mu     sync.Mutex
+	values map[string]interface{}
+}
+
+type htmlCallback struct {
+	selector string
+	fn       func(*Root, *CrawlContext)
+}
+
+// crawlHost is the state of the crawl of a host
+type crawlHost struct {
+	// next is when the crawl delay of the host lets the next request go
+	next time.Time
+	// pages is the number of pages of the host queued
+	pages int
+}
+
+// crawlDelayer is implemented by the robots policies telling how long to wait between two requests to a host
+type crawlDelayer interface {
+	CrawlDelay(userAgent string, u *url.URL) time.Duration
+}
+
+// NewCrawler returns a Crawler fetching pages with client, NewClient() when it is nil
+func NewCrawler(client *Client) *Crawler {
+	if client == nil {
+		client = NewClient()
+	}
+	return &Crawler{Client: client}
+}
+
+// OnHTML registers fn to be called with every element matching the CSS selector in the HTML pages
+func (c *Crawler) OnHTML(selector string, fn func(*Root, *CrawlContext)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.htmlCallbacks = append(c.htmlCallbacks, htmlCallback{selector: selector, fn: fn})
+}
+
+// OnResponse registers fn to be called with every response with a 2xx status, before the OnHTML callbacks
+func (c *Crawler) OnResponse(fn func(*Response, *CrawlContext)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.responseCallbacks = append(c.responseCallbacks, fn)
+}
+
+// OnError registers fn to be called when a page fails, responses with another status than 2xx fail
+// with an *HTTPError and the CrawlContext holds them
+func (c *Crawler) OnError(fn func(error, *CrawlContext)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.errorCallbacks = append(c.errorCallbacks, fn)
+}
+
+// Start crawls from the seeds until every visited page is processed, see StartContext
+func (c *Crawler) Start(seeds ...string) error {
+	return c.StartContext(context.Background(), seeds...)
+}
+
+// StartContext crawls from the seeds until every visited page is processed or ctx is done,
+// in which case it returns the error of ctx. Seeds that are not absolute http or https URLs are refused
+// before anything is fetched, those the crawl may not visit are reported to the OnError callbacks
+func (c *Crawler) StartContext(ctx context.Context, seeds ...string) error {
+	for _, seed := range seeds {
+		if _, err := crawlURL(seed); err != nil {
+			return err
+		}
+	}
+	c.init()
+	for _, seed := range seeds {
+		req := CrawlRequest{URL: seed}
+		if err := c.enqueue(req); err != nil && !errors.Is(err, ErrAlreadyVisited) {
+			c.fail(err, &CrawlContext{Request: req, crawler: c, ctx: ctx})
+		}
+	}
+
+	// The workers waiting for requests give up when ctx is done
+	stop := context.AfterFunc(ctx, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.wake.Broadcast()
+	})
+	defer stop()
+	workers := c.Concurrency
+	if workers < 1 {
+		workers = 1
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				req, ok := c.next(ctx)
+				if !ok {
+					return
+				}
+				if !c.process(ctx, req) {
+					// The page is fetched again when the crawl resumes
+					if err := c.Store.Enqueue(req); err != nil {
+						c.fail(err, &CrawlContext{Request: req, crawler: c, ctx: ctx})
+					}
+				}
+				c.done(req)
+			}
+		}()
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+// Visit queues the page at the absolute URL u as a seed, to be crawled by Start
+// or by the running crawl
+func (c *Crawler) Visit(u string) error {
+	if _, err := crawlURL(u); err != nil {
+		return err
+	}
+	c.init()
+	return c.enqueue(CrawlRequest{URL: u})
+}
+
+// init creates the state of the crawl on first use
+func (c *Crawler) init() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.wake != nil {
+		return
+	}
+	c.wake = sync.NewCond(&c.mu)
+	if c.Store == nil {
+		c.Store = NewFrontier()
+	}
+	c.hosts = make(map[string]*crawlHost)
+	c.active = make(map[string]CrawlRequest)
+	if c.RateLimit != nil {
+		c.limiter = NewRateLimiter(*c.RateLimit)
+	}
+}
+
+// enqueue queues req unless the crawl may not visit it
+func (c *Crawler) enqueue(req CrawlRequest) error {
+	u, err := crawlURL(req.URL)
+	if err != nil {
+		return err
+	}
+	var cfg normalizeConfig
+	for _, opt := range c.NormalizeOptions {
+		opt(&cfg)
+	}
+	normalizeURL(u, &cfg)
+	if !c.allowedDomain(u.Hostname()) {
+		return fmt.Errorf("%w: %s", ErrForbiddenDomain, u.Hostname())
+	}
+	if c.MaxDepth > 0 && req.Depth > c.MaxDepth {
+		return ErrMaxDepth
+	}
+	if robots := c.Client.Robots; robots != nil && !robots.Allowed(c.userAgent(), u) {
+		return ErrDisallowedByRobots
+	}
+	req.URL = u.String()
+	if !c.allowedURL(req.URL) {
+		return fmt.Errorf("%w: %s", ErrFilteredURL, req.URL)
+	}
+	if c.Priority != nil {
+		req.Priority = c.Priority(req)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	host := c.hosts[u.Host]
+	if host == nil {
+		host = &crawlHost{}
+		c.hosts[u.Host] = host
+	}
+	if c.MaxPages > 0 && c.pages >= c.MaxPages || c.MaxHostPages > 0 && host.pages >= c.MaxHostPages {
+		return ErrMaxPages
+	}
+	marked, err := c.Store.MarkVisited(req.URL)
+	if err != nil {
+		return err
+	}
+	if !marked {
+		return ErrAlreadyVisited
+	}
+	if err := c.Store.Enqueue(req); err != nil {
+		return err
+	}
+	c.pages++
+	host.pages++
+	c.wake.Signal()
+	return nil
+}
+
+// next returns the next request to process, false once the crawl is over or ctx is done.
+// Requests are dequeued under the lock, so no worker gives up while another one may still queue pages
+func (c *Crawler) next(ctx context.Context) (CrawlRequest, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for ctx.Err() == nil {
+		req, ok, err := c.Store.Dequeue()
+		if err != nil {
+			c.mu.Unlock()
+			c.fail(err, &CrawlContext{crawler: c, ctx: ctx})
+			c.mu.Lock()
+			break
+		}
+		if ok {
+			c.active[req.URL] = req
+			return req, true
+		}
+		if len(c.active) == 0 {
+			break
+		}
+		c.wake.Wait()
+	}
+	// Nothing is left to do, the other workers are done too
+	c.wake.Broadcast()
+	return CrawlRequest{}, false
+}
+
+// done records that a request returned by next was processed
+func (c *Crawler) done(req CrawlRequest) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.active, req.URL)
+	c.wake.Broadcast()
+}
+
+// process fetches the page of req and calls the callbacks, it returns false when ctx interrupted it
+func (c *Crawler) process(ctx context.Context, req CrawlRequest) bool {
+	cc := &CrawlContext{Request: req, crawler: c, ctx: ctx}
+	u, _ := url.Parse(req.URL)
+	if err := c.wait(ctx, u); err != nil {
+		return false
+	}
+	opts := []RequestOption{WithContext(ctx), WithStatusErrors(false)}
+	if req.Referer != "" {
+		opts = append(opts, WithHeader("Referer", req.Referer))
+	}
+	resp, err := c.Client.response(http.MethodGet, req.URL, nil, opts...)
+	if err != nil {
+		if ctx.Err() != nil {
+			return false
+		}
+		c.fail(err, cc)
+		return true
+	}
+	cc.Response = resp
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		c.fail(newHTTPError(resp), cc)
+		return true
+	}
+
+	c.mu.Lock()
+	responseCallbacks, htmlCallbacks := c.responseCallbacks, c.htmlCallbacks
+	c.mu.Unlock()
+	if isHTML(resp.ContentType) && (len(htmlCallbacks) > 0 || c.Duplicates != nil || c.FetchFrames) {
+		doc := resp.Parse()
+		if doc.Error != nil {
+			c.fail(doc.Error.Err(), cc)
+			return true
+		}
+		cc.doc = doc.Preprocess(c.Preprocess...)
+		if c.Duplicates != nil && c.Duplicates.Seen(doc.PlainText()) {
+			c.fail(fmt.Errorf("%w: %s", ErrDuplicatePage, req.URL), cc)
+			return true
+		}
+		if c.FetchFrames && !c.fetchFrames(ctx, cc) {
+			return false
+		}
+	}
+	for _, fn := range responseCallbacks {
+		fn(resp, cc)
+	}
+	if cc.doc == nil {
+		return true
+	}
+	for _, cb := range htmlCallbacks {
+		for _, e := range cc.doc.Select(cb.selector).Roots {
+			cb.fn(e, cc)
+		}
+	}
+	return true
+}
+
+// fetchFrames fetches the frames of the page of cc with the same origin as the page and parses those in HTML,
+// the others are left without a Root. It returns false when ctx interrupted it
+func (c *Crawler) fetchFrames(ctx context.Context, cc *CrawlContext) bool {
+	cc.frames = cc.doc.Frames(nil)
+	page := cc.Response.FinalURL
+	for i := range cc.frames {
+		f := &cc.frames[i]
+		if f.Root != nil || !f.SameOrigin(page) {
+			continue
+		}
+		if err := c.wait(ctx, f.URL); err != nil {
+			return false
+		}
+		resp, err := c.Client.response(http.MethodGet, f.URL.String(), nil,
+			WithContext(ctx), WithStatusErrors(false), WithHeader("Referer", page.String()))
+		switch {
+		case err != nil && ctx.Err() != nil:
+			return false
+		case err != nil:
+			c.fail(err, cc)
+		case resp.StatusCode < 200 || resp.StatusCode > 299:
+			c.fail(newHTTPError(resp), cc)
+		case isHTML(resp.ContentType):
+			if root := resp.Parse(); root.Error == nil {
+				f.Root = root
+			}
+		}
+	}
+	return true
+}
+
+// wait delays the request to u as the RateLimit of the crawl and the crawl delay of the host say
+func (c *Crawler) wait(ctx context.Context, u *url.URL) error {
+	if c.limiter != nil {
+		if err := c.limiter.Wait(ctx, u.Hostname()); err != nil {
+			return err
+		}
+	}
+	delayer, ok := c.Client.Robots.(crawlDelayer)
+	if !ok {
+		return nil
+	}
+	delay := delayer.CrawlDelay(c.userAgent(), u)
+	if delay <= 0 {
+		return nil
+	}
+	now := time.Now()
+	c.mu.Lock()
+	host, ok := c.hosts[u.Host]
+	if !ok {
+		host = &crawlHost{}
+		c.hosts[u.Host] = host
+	}
+	if host.next.Before(now) {
+		host.next = now
+	}
+	wait := host.next.Sub(now)
+	host.next = host.next.Add(delay)
+	c.mu.Unlock()
+	return sleep(ctx, wait)
+}
+
+func (c *Crawler) fail(err error, cc *CrawlContext) {
+	c.mu.Lock()
+	errorCallbacks := c.errorCallbacks
+	c.mu.Unlock()
+	for _, fn := range errorCallbacks {
+		fn(err, cc)
+	}
+}
+
+func (c *Crawler) allowedDomain(host string) bool {
+	if len(c.AllowedDomains) == 0 {
+		return true
+	}
+	host = strings.ToLower(host)
+	for _, domain := range c.AllowedDomains {
+		domain = strings.ToLower(domain)
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowedURL reports whether the AllowURLs and DenyURLs let the crawl visit u
+func (c *Crawler) allowedURL(u string) bool {
+	for _, re := range c.DenyURLs {
+		if re.MatchString(u) {
+			return false
+		}
+	}
+	if len(c.AllowURLs) == 0 {
+		return true
+	}
+	for _, re := range c.AllowURLs {
+		if re.MatchString(u) {
+			return true
+		}
+	}
+	return false
+}
+
+// userAgent returns the User-Agent the Client sends, for the robots policy
+func (c *Crawler) userAgent() string {
+	for name, value := range c.Client.Header {
+		if strings.EqualFold(name, "User-Agent") {
+			return value
+		}
+	}
+	return ""
+}
+
+// crawlURL parses u, which must be an absolute http or https URL, without its fragment
+func crawlURL(u string) (*url.URL, error) {
+	parsed, err := url.Parse(strings.TrimSpace(u))
+	if err != nil {
+		return nil, err
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" || parsed.Host == "" {
+		return nil, fmt.Errorf("owl: %q is not an http or https URL", u)
+	}
+	parsed.Fragment, parsed.RawFragment = "", ""
+	return parsed, nil
+}
+
+// isHTML reports whether a response with the Content-Type contentType holds a document to parse.
+// Documents are often served as text/plain by mistake, and responses without a type are sniffed as HTML
+func isHTML(contentType string) bool {
+	contentType = strings.ToLower(contentType)
+	return contentType == "" || strings.HasPrefix(contentType, "text/") || strings.Contains(contentType, "html")
+}
+
+// Visit queues the page at href, resolved against the base URL of the page, one link deeper than the page.
+// It returns the reason when the crawl may not visit it, such as ErrAlreadyVisited
+func (cc *CrawlContext) Visit(href string) error {
+	var target *url.URL
+	var err error
+	switch {
+	case cc.doc != nil:
+		target, err = cc.doc.ResolveURL(href)
+	case cc.Response != nil && cc.Response.FinalURL != nil:
+		if target, err = url.Parse(strings.TrimSpace(href)); err == nil {
+			target = cc.Response.FinalURL.ResolveReference(target)
+		}
+	default:
+		target, err = url.Parse(strings.TrimSpace(href))
+	}
+	if err != nil {
+		return err
+	}
+	referer := cc.Request.URL
+	if cc.Response != nil && cc.Response.FinalURL != nil {
+		referer = cc.Response.FinalURL.String()
+	}
+	return cc.crawler.enqueue(CrawlRequest{URL: target.String(), Depth: cc.Request.Depth + 1, Referer: referer})
+}
+
+// Frames returns the frames and iframes of the page, see Root.Frames. When the Crawler FetchFrames,
+// the frames with the same origin as the page have the Root of their document. It is nil for pages other than HTML
+func (cc *CrawlContext) Frames() []Frame {
+	if cc.frames == nil && cc.doc != nil {
+		cc.frames = cc.doc.Frames(nil)
+	}
+	return cc.frames
+}
+
+// Context returns the context of the crawl, done when the crawl is stopped
+func (cc *CrawlContext) Context() context.Context {
+	return cc.ctx
+}
+
+// Put stores value under key for the other callbacks of the page
+func (cc *CrawlContext) Put(key string, value interface{}) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	if cc.values == nil {
+		cc.values = make(map[string]interface{})
+	}
+	cc.values[key] = value
+}
+
+// Get returns the value stored under key by Put, nil when there is none
+func (cc *CrawlContext) Get(key string) interface{} {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	return cc.values[key]
+}
+
+// Emit writes record to the Sink of the Crawler, such as the struct filled by Unmarshal
+// or the value extracted by a Pipeline
+func (cc *CrawlContext) Emit(record interface{}) error {
+	if cc.crawler.Sink == nil {
+		return ErrNoSink
+	}
+	return cc.crawler.Sink.Write(record)
+}
@@ -0,0 +1,165 @@
+package owl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newSiteServer serves pages linking to each other: / links to /a and /b, /a to /a/deep and /missing,
+// /b back to / and to an external site
+func newSiteServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	pages := map[string]string{
+		"/":       `<title>home</title><a href="/a">A</a><a href="b#top">B</a>`,
+		"/a":      `<title>a</title><base href="/a/"><a href="deep">Deep</a><a href="/missing">Missing</a>`,
+		"/a/deep": `<title>deep</title>`,
+		"/b":      `<title>b</title><a href="/">Home</a><a href="https://other.example/">Other</a>`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, page)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestCrawler(t *testing.T) {
+	srv := newSiteServer(t)
+	host, _ := url.Parse(srv.URL)
+
+	crawler := NewCrawler(&Client{Client: srv.Client()})
+	crawler.AllowedDomains = []string{host.Hostname()}
+	crawler.Concurrency = 3
+
+	var mu sync.Mutex
+	var titles, failed, refused []string
+	referers := make(map[string]string)
+	crawler.OnHTML("a[href]", func(e *Root, ctx *CrawlContext) {
+		href, _ := e.Attr("href")
+		if err := ctx.Visit(href); err != nil && !errors.Is(err, ErrAlreadyVisited) {
+			mu.Lock()
+			refused = append(refused, href)
+			mu.Unlock()
+		}
+	})
+	crawler.OnResponse(func(resp *Response, ctx *CrawlContext) {
+		ctx.Put("status", resp.StatusCode)
+	})
+	crawler.OnHTML("title", func(e *Root, ctx *CrawlContext) {
+		require.Equal(t, 200, ctx.Get("status"))
+		mu.Lock()
+		defer mu.Unlock()
+		titles = append(titles, e.Text())
+		referers[e.Text()] = ctx.Request.Referer
+	})
+	crawler.OnError(func(err error, ctx *CrawlContext) {
+		mu.Lock()
+		defer mu.Unlock()
+		var httpErr *HTTPError
+		require.ErrorAs(t, err, &httpErr)
+		require.Equal(t, http.StatusNotFound, ctx.Response.StatusCode)
+		failed = append(failed, ctx.Request.URL)
+	})
+
+	require.NoError(t, crawler.Start(srv.URL+"/"))
+	sort.Strings(titles)
+	require.Equal(t, []string{"a", "b", "deep", "home"}, titles)
+	require.Equal(t, []string{srv.URL + "/missing"}, failed)
+	require.Equal(t, []string{"https://other.example/"}, refused)
+	require.Equal(t, srv.URL+"/a", referers["deep"])
+	require.Empty(t, referers["home"])
+
+	require.ErrorIs(t, crawler.Visit(srv.URL+"/a"), ErrAlreadyVisited)
+	require.Error(t, crawler.Start("/relative"))
+}
+
+func TestCrawlerMaxDepth(t *testing.T) {
+	srv := newSiteServer(t)
+	crawler := NewCrawler(&Client{Client: srv.Client()})
+	crawler.MaxDepth = 1
+	var visited []string
+	var depthErrs int
+	crawler.OnHTML("a[href]", func(e *Root, ctx *CrawlContext) {
+		href, _ := e.Attr("href")
+		if err := ctx.Visit(href); err == ErrMaxDepth {
+			depthErrs++
+		}
+	})
+	crawler.OnResponse(func(resp *Response, ctx *CrawlContext) {
+		visited = append(visited, resp.FinalURL.Path)
+	})
+	require.NoError(t, crawler.Start(srv.URL+"/"))
+	require.Equal(t, []string{"/", "/a", "/b"}, visited)
+	require.Equal(t, 4, depthErrs)
+}
+
+// delayRobots disallows /private and asks for a crawl delay
+type delayRobots struct{ delay time.Duration }
+
+func (r delayRobots) Allowed(userAgent string, u *url.URL) bool {
+	return !strings.HasPrefix(u.Path, "/private")
+}
+
+func (r delayRobots) CrawlDelay(userAgent string, u *url.URL) time.Duration {
+	return r.delay
+}
+
+func TestCrawlerPoliteness(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<a href="/1">1</a><a href="/2">2</a><a href="/private">private</a>`)
+	}))
+	defer srv.Close()
+	crawler := NewCrawler(&Client{Client: srv.Client(), Robots: delayRobots{delay: 30 * time.Millisecond}})
+	crawler.Concurrency = 3
+	var disallowed atomic.Int32
+	crawler.OnHTML("a[href]", func(e *Root, ctx *CrawlContext) {
+		href, _ := e.Attr("href")
+		if err := ctx.Visit(href); err == ErrDisallowedByRobots {
+			disallowed.Add(1)
+		}
+	})
+	start := time.Now()
+	require.NoError(t, crawler.Start(srv.URL+"/"))
+	// The three pages are 30ms apart
+	require.GreaterOrEqual(t, time.Since(start), 60*time.Millisecond)
+	require.Equal(t, int32(3), disallowed.Load())
+}
+
+func TestCrawlerStop(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Every page links to the next one
+		n, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/"))
+		fmt.Fprintf(w, `<a href="/%d">next</a>`, n+1)
+	}))
+	defer srv.Close()
+	crawler := NewCrawler(&Client{Client: srv.Client()})
+	ctx, cancel := context.WithCancel(context.Background())
+	pages := 0
+	crawler.OnHTML("a[href]", func(e *Root, cc *CrawlContext) {
+		if pages++; pages == 3 {
+			cancel()
+		}
+		href, _ := e.Attr("href")
+		cc.Visit(href)
+	})
+	require.ErrorIs(t, crawler.StartContext(ctx, srv.URL+"/"), context.Canceled)
+	require.Equal(t, 3, pages)
+}
@@ -0,0 +1,428 @@
+package owl
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Progress reports how far a download is
+type Progress struct {
+	// Downloaded is the number of bytes written so far, including the ones of a resumed download
+	Downloaded int64
+	// Total is the size of the download from its Content-Length, -1 when unknown
+	Total int64
+	// Percent is Downloaded as a percentage of Total, -1 when Total is unknown
+	Percent float64
+	// Rate is the average speed in bytes per second since the download started
+	Rate    float64
+	Elapsed time.Duration
+}
+
+// ChecksumError is returned when a download does not match the checksum of WithChecksum
+type ChecksumError struct {
+	URL      string
+	Expected string
+	Actual   string
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("owl: %s: checksum %s, expected %s", e.URL, e.Actual, e.Expected)
+}
+
+// DownloadOption configures DownloadTo and DownloadFile
+type DownloadOption func(*downloadConfig)
+
+type downloadConfig struct {
+	progress func(Progress)
+	request  []RequestOption
+	resume   bool
+	chunks   int
+	newHash  func() hash.Hash
+	checksum string
+}
+
+// WithProgress calls progress after every chunk written and once the download is complete
+func WithProgress(progress func(Progress)) DownloadOption {
+	return func(d *downloadConfig) { d.progress = progress }
+}
+
+// WithDownloadRequest customizes the requests of the download, see RequestOption
+func WithDownloadRequest(opts ...RequestOption) DownloadOption {
+	return func(d *downloadConfig) { d.request = append(d.request, opts...) }
+}
+
+// WithResume makes DownloadFile keep path.part when the download fails, and continue it with a Range request
+// the next time. The download starts over when the server answers with the whole file
+func WithResume() DownloadOption {
+	return func(d *downloadConfig) { d.resume = true }
+}
+
+// WithChunks makes DownloadFile fetch the file in n parallel Range requests when the server
+// advertises Accept-Ranges and the size of the file. Chunked downloads are not resumed
+func WithChunks(n int) DownloadOption {
+	return func(d *downloadConfig) { d.chunks = n }
+}
+
+// WithChecksum fails the download with a *ChecksumError unless the hash from newHash of the downloaded bytes
+// is sum in hexadecimal, such as WithChecksum(sha256.New, "9f86d0...")
+func WithChecksum(newHash func() hash.Hash, sum string) DownloadOption {
+	return func(d *downloadConfig) {
+		d.newHash = newHash
+		d.checksum = strings.ToLower(sum)
+	}
+}
+
+func newDownloadConfig(opts []DownloadOption) *downloadConfig {
+	cfg := &downloadConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// verify compares the sum of h with the expected checksum
+func (d *downloadConfig) verify(url string, h hash.Hash) error {
+	if actual := hex.EncodeToString(h.Sum(nil)); actual != d.checksum {
+		return &ChecksumError{URL: url, Expected: d.checksum, Actual: actual}
+	}
+	return nil
+}
+
+// DownloadTo writes the body of url to w as it arrives, without charset decoding,
+// and returns the number of bytes written. Responses with a status other than 2xx fail with an *HTTPError
+func (c *Client) DownloadTo(url string, w io.Writer, opts ...DownloadOption) (int64, error) {
+	cfg := newDownloadConfig(opts)
+	var h hash.Hash
+	if cfg.newHash != nil {
+		h = cfg.newHash()
+		w = io.MultiWriter(w, h)
+	}
+	resp, release, err := c.do(http.MethodGet, url, nil, cfg.request...)
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+	if err := statusError(resp); err != nil {
+		return 0, err
+	}
+	n, err := copyBody(w, resp.Body, 0, resp.ContentLength, cfg)
+	if err == nil && h != nil {
+		err = cfg.verify(url, h)
+	}
+	return n, err
+}
+
+// DownloadFile downloads url to the file at path like DownloadTo and returns the size of the file.
+// The body is written to path.part first and renamed once complete, so path never holds a partial download,
+// see WithResume and WithChunks
+func (c *Client) DownloadFile(url, path string, opts ...DownloadOption) (int64, error) {
+	cfg := newDownloadConfig(opts)
+	part := path + ".part"
+	var n int64
+	var err error
+	chunked := false
+	if cfg.chunks > 1 {
+		n, chunked, err = c.downloadChunks(url, part, cfg)
+	}
+	if !chunked && err == nil {
+		n, err = c.downloadPart(url, part, cfg)
+	}
+	if err == nil && cfg.newHash != nil {
+		if err = checkFile(url, part, cfg); err != nil {
+			// A corrupted part is not worth resuming
+			os.Remove(part)
+			return n, err
+		}
+	}
+	if err == nil {
+		err = os.Rename(part, path)
+	}
+	if err != nil {
+		if !cfg.resume || chunked {
+			os.Remove(part)
+		}
+		return n, err
+	}
+	return n, nil
+}
+
+// downloadPart downloads url to part in a single request, continuing part when resuming
+func (c *Client) downloadPart(url, part string, cfg *downloadConfig) (int64, error) {
+	var offset int64
+	opts := cfg.request
+	if cfg.resume {
+		if info, err := os.Stat(part); err == nil && info.Size() > 0 {
+			offset = info.Size()
+			// Ranges count bytes of the file as it is sent, without compression
+			opts = append(opts[:len(opts):len(opts)],
+				WithHeader("Range", fmt.Sprintf("bytes=%d-", offset)), WithHeader("Accept-Encoding", "identity"))
+		}
+	}
+	resp, release, err := c.do(http.MethodGet, url, nil, opts...)
+	if err != nil {
+		return 0, err
+	}
+	if offset > 0 && resp.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+		release()
+		if _, _, size, ok := contentRange(resp.Header.Get("Content-Range")); ok && size == offset {
+			// part is already the whole file
+			if cfg.progress != nil {
+				newProgress(offset, offset, cfg.progress).notify()
+			}
+			return offset, nil
+		}
+		// part does not match the file anymore
+		if err := os.Remove(part); err != nil {
+			return 0, err
+		}
+		return c.downloadPart(url, part, cfg)
+	}
+	defer release()
+	if err := statusError(resp); err != nil {
+		return 0, err
+	}
+
+	flag := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	total := resp.ContentLength
+	if offset > 0 && resp.StatusCode == http.StatusPartialContent {
+		start, _, size, ok := contentRange(resp.Header.Get("Content-Range"))
+		if !ok || start != offset {
+			return 0, fmt.Errorf("owl: %s: unexpected Content-Range %q", url, resp.Header.Get("Content-Range"))
+		}
+		flag = os.O_WRONLY | os.O_APPEND
+		if size >= 0 {
+			total = size
+		} else if total >= 0 {
+			total += offset
+		}
+	} else {
+		// The server sent the whole file
+		offset = 0
+	}
+	f, err := os.OpenFile(part, flag, 0o644)
+	if err != nil {
+		return 0, err
+	}
+	n, err := copyBody(f, resp.Body, offset, total, cfg)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return offset + n, err
+}
+
+// downloadChunks downloads url to part in parallel Range requests. It reports false without downloading
+// anything when the server does not advertise ranges and the size of url
+func (c *Client) downloadChunks(url, part string, cfg *downloadConfig) (int64, bool, error) {
+	opts := append(cfg.request[:len(cfg.request):len(cfg.request)], WithHeader("Accept-Encoding", "identity"))
+	resp, release, err := c.do(http.MethodHead, url, nil, opts...)
+	if err != nil {
+		return 0, false, err
+	}
+	release()
+	size := resp.ContentLength
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Accept-Ranges") != "bytes" || size <= 0 {
+		return 0, false, nil
+	}
+
+	f, err := os.Create(part)
+	if err != nil {
+		return 0, true, err
+	}
+	if err := f.Truncate(size); err != nil {
+		f.Close()
+		return 0, true, err
+	}
+	var tracker *progress
+	if cfg.progress != nil {
+		tracker = newProgress(0, size, cfg.progress)
+	}
+	chunk := (size + int64(cfg.chunks) - 1) / int64(cfg.chunks)
+	errs := make([]error, cfg.chunks)
+	var wg sync.WaitGroup
+	for i := range cfg.chunks {
+		start := int64(i) * chunk
+		if start >= size {
+			break
+		}
+		end := min(start+chunk, size) - 1
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = c.downloadRange(url, f, start, end, tracker, opts)
+		}()
+	}
+	wg.Wait()
+	err = errors.Join(errs...)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil && tracker != nil {
+		tracker.notify()
+	}
+	return size, true, err
+}
+
+// downloadRange writes the bytes from start to end of url to f at start
+func (c *Client) downloadRange(url string, f *os.File, start, end int64, tracker *progress, opts []RequestOption) error {
+	opts = append(opts[:len(opts):len(opts)], WithHeader("Range", fmt.Sprintf("bytes=%d-%d", start, end)))
+	resp, release, err := c.do(http.MethodGet, url, nil, opts...)
+	if err != nil {
+		return err
+	}
+	defer release()
+	if err := statusError(resp); err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusPartialContent {
+		return fmt.Errorf("owl: %s: the server ignored the range request", url)
+	}
+	if first, _, _, ok := contentRange(resp.Header.Get("Content-Range")); !ok || first != start {
+		return fmt.Errorf("owl: %s: unexpected Content-Range %q", url, resp.Header.Get("Content-Range"))
+	}
+	var w io.Writer = io.NewOffsetWriter(f, start)
+	if tracker != nil {
+		w = &progressWriter{w: w, progress: tracker}
+	}
+	n, err := io.Copy(w, io.LimitReader(resp.Body, end-start+1))
+	if err == nil && n != end-start+1 {
+		err = io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// checkFile verifies the checksum of the file at path
+func checkFile(url, path string, cfg *downloadConfig) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	h := cfg.newHash()
+	if _, err := io.Copy(h, f); err != nil {
+		return err
+	}
+	return cfg.verify(url, h)
+}
+
+// statusError returns an *HTTPError for responses with a status other than 2xx
+func statusError(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+		return nil
+	}
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, snippetSize))
+	return newHTTPError(&Response{StatusCode: resp.StatusCode, FinalURL: resp.Request.URL, Body: snippet})
+}
+
+// contentRange parses a Content-Range header of bytes. size is -1 when it is unknown,
+// start and end are -1 for the unsatisfied ranges of 416 responses
+func contentRange(value string) (start, end, size int64, ok bool) {
+	rest, found := strings.CutPrefix(value, "bytes ")
+	if !found {
+		return 0, 0, 0, false
+	}
+	rng, total, found := strings.Cut(rest, "/")
+	if !found {
+		return 0, 0, 0, false
+	}
+	size = -1
+	if total != "*" {
+		var err error
+		if size, err = strconv.ParseInt(total, 10, 64); err != nil {
+			return 0, 0, 0, false
+		}
+	}
+	if rng == "*" {
+		return -1, -1, size, true
+	}
+	first, last, found := strings.Cut(rng, "-")
+	if !found {
+		return 0, 0, 0, false
+	}
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	end, err = strconv.ParseInt(last, 10, 64)
+	if err != nil || end < start {
+		return 0, 0, 0, false
+	}
+	return start, end, size, true
+}
+
+// copyBody copies r to w, reporting the progress of the download when asked to.
+// offset is the number of bytes downloaded before, total the size of the whole download
+func copyBody(w io.Writer, r io.Reader, offset, total int64, cfg *downloadConfig) (int64, error) {
+	if cfg.progress == nil {
+		return io.Copy(w, r)
+	}
+	tracker := newProgress(offset, total, cfg.progress)
+	n, err := io.Copy(&progressWriter{w: w, progress: tracker}, r)
+	if err == nil {
+		tracker.notify()
+	}
+	return n, err
+}
+
+// progress tracks the bytes written by the progressWriters of a download
+type progress struct {
+	mu sync.Mutex
+	// resumed is the number of bytes downloaded before, left out of the rate
+	resumed int64
+	written int64
+	total   int64
+	start   time.Time
+	report  func(Progress)
+}
+
+func newProgress(resumed, total int64, report func(Progress)) *progress {
+	return &progress{resumed: resumed, written: resumed, total: total, start: time.Now(), report: report}
+}
+
+func (p *progress) add(n int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.written += n
+	p.notifyLocked()
+}
+
+func (p *progress) notify() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.notifyLocked()
+}
+
+func (p *progress) notifyLocked() {
+	progress := Progress{Downloaded: p.written, Total: p.total, Percent: -1, Elapsed: time.Since(p.start)}
+	if p.total > 0 {
+		progress.Percent = float64(p.written) * 100 / float64(p.total)
+	} else if p.total == 0 {
+		progress.Percent = 100
+	}
+	if seconds := progress.Elapsed.Seconds(); seconds > 0 {
+		progress.Rate = float64(p.written-p.resumed) / seconds
+	}
+	p.report(progress)
+}
+
+// progressWriter reports the writes to w to progress
+type progressWriter struct {
+	w        io.Writer
+	progress *progress
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	if n > 0 {
+		p.progress.add(int64(n))
+	}
+	return n, err
+}
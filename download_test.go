@@ -0,0 +1,174 @@
+package owl
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownload(t *testing.T) {
+	payload := strings.Repeat("owl", 50000)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/file", func(w http.ResponseWriter, r *http.Request) {
+		body := r.Header.Get("X-Token") + payload
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		w.Write([]byte(body))
+	})
+	mux.HandleFunc("/missing", http.NotFound)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	client := &Client{Client: srv.Client(), Header: map[string]string{"X-Token": "a"}}
+
+	var buf bytes.Buffer
+	var reports []Progress
+	n, err := client.DownloadTo(srv.URL+"/file", &buf, WithProgress(func(p Progress) { reports = append(reports, p) }))
+	require.NoError(t, err)
+	require.Equal(t, int64(len(payload)+1), n)
+	require.Equal(t, "a"+payload, buf.String())
+	require.Greater(t, len(reports), 1)
+	last := reports[len(reports)-1]
+	require.Equal(t, n, last.Downloaded)
+	require.Equal(t, n, last.Total)
+	require.Equal(t, float64(100), last.Percent)
+	for i := 1; i < len(reports); i++ {
+		require.GreaterOrEqual(t, reports[i].Downloaded, reports[i-1].Downloaded)
+	}
+
+	path := filepath.Join(t.TempDir(), "owls.bin")
+	n, err = client.DownloadFile(srv.URL+"/file", path, WithDownloadRequest(WithHeader("X-Token", "b")))
+	require.NoError(t, err)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "b"+payload, string(data))
+	require.Equal(t, int64(len(data)), n)
+
+	var httpErr *HTTPError
+	_, err = client.DownloadFile(srv.URL+"/missing", path+"2")
+	require.True(t, errors.As(err, &httpErr))
+	require.Equal(t, http.StatusNotFound, httpErr.StatusCode)
+	_, err = os.Stat(path + "2")
+	require.True(t, os.IsNotExist(err))
+	_, err = os.Stat(path + "2.part")
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestDownloadRanges(t *testing.T) {
+	payload := strings.Repeat("0123456789", 10000)
+	sum := sha256.Sum256([]byte(payload))
+	checksum := hex.EncodeToString(sum[:])
+	var mu sync.Mutex
+	var ranges []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/file", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			mu.Lock()
+			ranges = append(ranges, r.Header.Get("Range"))
+			mu.Unlock()
+		}
+		http.ServeContent(w, r, "file.bin", time.Time{}, strings.NewReader(payload))
+	})
+	mux.HandleFunc("/whole", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(payload))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	client := &Client{Client: srv.Client()}
+	dir := t.TempDir()
+
+	// Resuming continues the part where it stopped
+	path := filepath.Join(dir, "resumed.bin")
+	require.NoError(t, os.WriteFile(path+".part", []byte(payload[:30000]), 0o644))
+	var last Progress
+	n, err := client.DownloadFile(srv.URL+"/file", path, WithResume(), WithChecksum(sha256.New, checksum),
+		WithProgress(func(p Progress) { last = p }))
+	require.NoError(t, err)
+	require.Equal(t, int64(len(payload)), n)
+	require.Equal(t, []string{"bytes=30000-"}, ranges)
+	require.Equal(t, n, last.Downloaded)
+	require.Equal(t, n, last.Total)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, payload, string(data))
+
+	// A complete part is not downloaded again
+	ranges = nil
+	require.NoError(t, os.WriteFile(path+".part", []byte(payload), 0o644))
+	n, err = client.DownloadFile(srv.URL+"/file", path, WithResume())
+	require.NoError(t, err)
+	require.Equal(t, int64(len(payload)), n)
+	require.Equal(t, []string{"bytes=100000-"}, ranges)
+
+	// Servers without ranges send the whole file again
+	require.NoError(t, os.WriteFile(path+".part", []byte("garbage"), 0o644))
+	_, err = client.DownloadFile(srv.URL+"/whole", path, WithResume())
+	require.NoError(t, err)
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, payload, string(data))
+
+	// Chunks are fetched in parallel ranges
+	ranges = nil
+	var reports []Progress
+	path = filepath.Join(dir, "chunked.bin")
+	n, err = client.DownloadFile(srv.URL+"/file", path, WithChunks(4), WithChecksum(sha256.New, strings.ToUpper(checksum)),
+		WithProgress(func(p Progress) { reports = append(reports, p) }))
+	require.NoError(t, err)
+	require.Equal(t, int64(len(payload)), n)
+	require.ElementsMatch(t, []string{"bytes=0-24999", "bytes=25000-49999", "bytes=50000-74999", "bytes=75000-99999"}, ranges)
+	require.Equal(t, n, reports[len(reports)-1].Downloaded)
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, payload, string(data))
+
+	// Chunks fall back to a single request without ranges
+	path = filepath.Join(dir, "whole.bin")
+	_, err = client.DownloadFile(srv.URL+"/whole", path, WithChunks(4))
+	require.NoError(t, err)
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, payload, string(data))
+
+	// Checksum mismatches fail and leave nothing behind, even when resuming
+	var checksumErr *ChecksumError
+	path = filepath.Join(dir, "corrupted.bin")
+	_, err = client.DownloadFile(srv.URL+"/file", path, WithResume(), WithChecksum(sha256.New, strings.Repeat("0", 64)))
+	require.True(t, errors.As(err, &checksumErr))
+	require.Equal(t, checksum, checksumErr.Actual)
+	_, err = os.Stat(path + ".part")
+	require.True(t, os.IsNotExist(err))
+
+	var buf bytes.Buffer
+	_, err = client.DownloadTo(srv.URL+"/file", &buf, WithChecksum(sha256.New, checksum))
+	require.NoError(t, err)
+	_, err = client.DownloadTo(srv.URL+"/file", &buf, WithChecksum(sha256.New, "abc"))
+	require.True(t, errors.As(err, &checksumErr))
+}
+
+func TestContentRange(t *testing.T) {
+	start, end, size, ok := contentRange("bytes 10-19/100")
+	require.True(t, ok)
+	require.Equal(t, []int64{10, 19, 100}, []int64{start, end, size})
+	start, end, size, ok = contentRange("bytes */100")
+	require.True(t, ok)
+	require.Equal(t, []int64{-1, -1, 100}, []int64{start, end, size})
+	_, _, size, ok = contentRange("bytes 0-9/*")
+	require.True(t, ok)
+	require.Equal(t, int64(-1), size)
+	for _, invalid := range []string{"", "items 0-9/10", "bytes 9-0/10", "bytes 0-9", "bytes a-9/10"} {
+		_, _, _, ok = contentRange(invalid)
+		require.False(t, ok, invalid)
+	}
+}
@@ -0,0 +1,97 @@
+package owl
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// ElementContent is what NewElement puts in an element: an Attribute, a TextContent,
+// an HTMLContent or a *Root whose Node becomes a child
+type ElementContent interface {
+	addTo(r *Root) error
+}
+
+type attributeContent struct{ key, value string }
+
+func (a attributeContent) addTo(r *Root) error {
+	for i, attr := range r.Node.Attr {
+		if attr.Namespace == "" && attr.Key == a.key {
+			r.Node.Attr[i].Val = a.value
+			return nil
+		}
+	}
+	r.Node.Attr = append(r.Node.Attr, html.Attribute{Key: a.key, Val: a.value})
+	return nil
+}
+
+// Attribute sets the attribute key of the element to value, the last value given for a key wins
+func Attribute(key, value string) ElementContent {
+	return attributeContent{key: strings.ToLower(key), value: value}
+}
+
+type textContent string
+
+func (t textContent) addTo(r *Root) error {
+	r.Node.AppendChild(&html.Node{Type: html.TextNode, Data: string(t)})
+	return nil
+}
+
+// TextContent adds a text node holding s to the element, escaped when rendered
+func TextContent(s string) ElementContent {
+	return textContent(s)
+}
+
+type htmlContent string
+
+func (h htmlContent) addTo(r *Root) error {
+	return r.AppendHTML(string(h))
+}
+
+// HTMLContent adds the nodes of the HTML fragment s, parsed in the context of the element
+func HTMLContent(s string) ElementContent {
+	return htmlContent(s)
+}
+
+func (r *Root) addTo(parent *Root) error {
+	// Roots without a Node, such as failed Finds, add nothing
+	if r == nil || r.Node == nil {
+		return nil
+	}
+	return parent.Append(r)
+}
+
+// voidElements have no children, NewElement drops the content other than attributes given to them
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "source": true, "track": true, "wbr": true,
+}
+
+// NewElement returns a Root holding a new element with the tag name, outside of any document,
+// filled with contents in order. It can be rendered on its own or inserted into a document:
+//
+//	card := owl.NewElement("div", owl.Attribute("class", "card"),
+//		owl.NewElement("h2", owl.TextContent(title)),
+//		owl.NewElement("a", owl.Attribute("href", link), owl.TextContent("Read more")))
+//	err := doc.Find("main").Append(card)
+//
+// Roots given as contents are moved into the element, see Root.Append. Contents that can not be added
+// are skipped, the Error of the Root then holds the first of their errors with the type ErrAddingContent
+func NewElement(tag string, contents ...ElementContent) *Root {
+	tag = strings.ToLower(tag)
+	n := &html.Node{Type: html.ElementNode, Data: tag, DataAtom: atom.Lookup([]byte(tag))}
+	r := &Root{Node: n, NodeValue: tag, doc: &document{}}
+	for _, c := range contents {
+		if c == nil {
+			continue
+		}
+		if _, ok := c.(attributeContent); !ok && voidElements[tag] {
+			continue
+		}
+		if err := c.addTo(r); err != nil && r.Error == nil {
+			r.Error = newError(ErrAddingContent, err)
+		}
+	}
+	return r
+}
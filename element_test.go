@@ -0,0 +1,53 @@
+package owl
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewElement(t *testing.T) {
+	card := NewElement("DIV", Attribute("class", "card"), Attribute("ID", "first"),
+		NewElement("h2", TextContent("Owls & <friends>")),
+		NewElement("img", Attribute("src", "owl.png"), TextContent("dropped")),
+		HTMLContent(`<p>Read <a href="/owls">more</a></p>`),
+		Attribute("class", "card wide"),
+	)
+	require.Equal(t, `<div class="card wide" id="first"><h2>Owls &amp; &lt;friends&gt;</h2><img src="owl.png"/><p>Read <a href="/owls">more</a></p></div>`,
+		string(card.Render()))
+	require.Equal(t, "more", card.Find("a").Text())
+	require.Equal(t, "Owls & <friends>", card.Find("h2").Text())
+	class, _ := card.Attr("class")
+	require.Equal(t, "card wide", class)
+
+	doc := HTMLParseFromString(`<main><p>intro</p></main>`)
+	require.NoError(t, doc.Find("main").Append(card))
+	require.Equal(t, card.Node, doc.Find("div", "id", "first").Node)
+
+	// Roots already in a tree move into the new element, missing ones are skipped
+	list := NewElement("ul", NewElement("li", doc.Find("p")), doc.Find("table"), nil)
+	require.Equal(t, `<ul><li><p>intro</p></li></ul>`, string(list.Render()))
+	require.Equal(t, card.Node, doc.Find("main").Node.FirstChild)
+
+	// Rows parse as rows in a table
+	table := NewElement("table", HTMLContent("<tr><td>1</td></tr>"))
+	require.Equal(t, "1", table.Find("td").Text())
+	require.Nil(t, table.Error)
+}
+
+// failingContent is an ElementContent that can not be added
+type failingContent struct{ err error }
+
+func (f failingContent) addTo(r *Root) error {
+	return f.err
+}
+
+func TestNewElementErrors(t *testing.T) {
+	first, second := errors.New("first"), errors.New("second")
+	e := NewElement("p", TextContent("kept"), failingContent{first}, failingContent{second}, TextContent(" too"))
+	require.NotNil(t, e.Error)
+	require.Equal(t, ErrAddingContent, e.Error.Type)
+	require.ErrorIs(t, e.Error.Err(), first)
+	require.Equal(t, "<p>kept too</p>", string(e.Render()))
+}
@@ -0,0 +1,96 @@
+package owl
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// acceptEncoding is the Accept-Encoding a Client sends when the request has none
+const acceptEncoding = "gzip, deflate, br, zstd"
+
+// decoders create the readers of the content encodings a Client decodes
+var decoders = map[string]func(io.Reader) (io.ReadCloser, error){
+	"gzip":    func(r io.Reader) (io.ReadCloser, error) { return gzip.NewReader(r) },
+	"x-gzip":  func(r io.Reader) (io.ReadCloser, error) { return gzip.NewReader(r) },
+	"deflate": func(r io.Reader) (io.ReadCloser, error) { return zlib.NewReader(r) },
+	"br":      func(r io.Reader) (io.ReadCloser, error) { return io.NopCloser(brotli.NewReader(r)), nil },
+	"zstd": func(r io.Reader) (io.ReadCloser, error) {
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return zr.IOReadCloser(), nil
+	},
+}
+
+// decodeBody replaces the body of resp with its decoded content when its Content-Encoding is known,
+// the Content-Encoding and Content-Length headers then go away like net/http does for gzip
+func decodeBody(resp *http.Response) {
+	header := resp.Header.Get("Content-Encoding")
+	if header == "" || resp.Request.Method == http.MethodHead {
+		return
+	}
+	var encodings []string
+	for _, e := range strings.Split(header, ",") {
+		e = strings.ToLower(strings.TrimSpace(e))
+		if e == "identity" || e == "" {
+			continue
+		}
+		if _, ok := decoders[e]; !ok {
+			return
+		}
+		encodings = append(encodings, e)
+	}
+	resp.Body = &decodedBody{body: resp.Body, encodings: encodings}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+}
+
+// decodedBody decodes a body on first read, encodings are undone from the last applied one
+type decodedBody struct {
+	body      io.ReadCloser
+	encodings []string
+	r         io.Reader
+	closers   []io.Closer
+	err       error
+}
+
+func (b *decodedBody) Read(p []byte) (int, error) {
+	if b.r == nil && b.err == nil {
+		b.r = b.body
+		for i := len(b.encodings) - 1; i >= 0; i-- {
+			d, err := decoders[b.encodings[i]](b.r)
+			if err != nil {
+				b.err = err
+				break
+			}
+			b.closers = append(b.closers, d)
+			b.r = d
+		}
+	}
+	if b.err != nil {
+		return 0, b.err
+	}
+	return b.r.Read(p)
+}
+
+func (b *decodedBody) Close() error {
+	for _, c := range b.closers {
+		c.Close()
+	}
+	return b.body.Close()
+}
+
+// DecodeContent decodes the body of resp from its Content-Encoding like a Client does, for code handling
+// the raw responses of a transport
+func DecodeContent(resp *http.Response) {
+	decodeBody(resp)
+}
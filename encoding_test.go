@@ -0,0 +1,71 @@
+package owl
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentEncoding(t *testing.T) {
+	const page = "<title>Compressed owls</title>"
+	encoders := map[string]func(io.Writer) io.WriteCloser{
+		"gzip":    func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) },
+		"deflate": func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) },
+		"br":      func(w io.Writer) io.WriteCloser { return brotli.NewWriter(w) },
+		"zstd": func(w io.Writer) io.WriteCloser {
+			zw, _ := zstd.NewWriter(w)
+			return zw
+		},
+	}
+	var acceptEncodings []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		acceptEncodings = append(acceptEncodings, r.Header.Get("Accept-Encoding"))
+		encodings := strings.Split(r.URL.Query().Get("encoding"), ",")
+		var body bytes.Buffer
+		body.WriteString(page)
+		for _, e := range encodings {
+			if encoders[e] == nil {
+				continue
+			}
+			var next bytes.Buffer
+			enc := encoders[e](&next)
+			enc.Write(body.Bytes())
+			enc.Close()
+			body = next
+		}
+		w.Header().Set("Content-Encoding", r.URL.Query().Get("encoding"))
+		w.Write(body.Bytes())
+	}))
+	defer srv.Close()
+	client := &Client{Client: srv.Client()}
+
+	for _, encoding := range []string{"", "gzip", "deflate", "br", "zstd", "gzip,br"} {
+		resp, err := client.GetResponse(srv.URL + "/?encoding=" + encoding)
+		require.NoError(t, err, encoding)
+		require.Equal(t, page, string(resp.Body), encoding)
+		require.Empty(t, resp.Header.Get("Content-Encoding"), encoding)
+
+		doc, err := client.GetDocument(srv.URL + "/?encoding=" + encoding)
+		require.NoError(t, err, encoding)
+		require.Equal(t, "Compressed owls", doc.Title().Text(), encoding)
+	}
+	require.Equal(t, "gzip, deflate, br, zstd", acceptEncodings[0])
+
+	// Unknown encodings are left alone
+	resp, err := client.GetResponse(srv.URL + "/?encoding=compress")
+	require.NoError(t, err)
+	require.Equal(t, "compress", resp.Header.Get("Content-Encoding"))
+
+	_, err = client.GetResponse(srv.URL+"/?encoding=", WithHeader("Accept-Encoding", "identity"))
+	require.NoError(t, err)
+	require.Equal(t, "identity", acceptEncodings[len(acceptEncodings)-1])
+}
@@ -28,6 +28,10 @@ const (
 	ErrMarshallingPostRequest
 	// ErrReadingResponse will be returned if there was an error reading the response to our get request
 	ErrReadingResponse
+	// ErrInvalidSelector will be returned when a CSS selector could not be parsed
+	ErrInvalidSelector
+	// ErrAddingContent will be returned when NewElement could not add a content to the element
+	ErrAddingContent
 )
 
 // Error allows easier introspection on the type of error returned.
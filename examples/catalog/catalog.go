@@ -0,0 +1,151 @@
+// Package catalog crawls the product pages of a shop listed in its sitemap and writes them as JSON Lines,
+// see the catalog command. It shows how the parts of owl fit together: the sitemap seeds the crawl,
+// the Crawler follows the links within limits, Unmarshal extracts the products into structs,
+// a JSONLSink stores them and a checkpoint lets an interrupted crawl resume where it stopped
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/Patrickmitech/owl"
+	"github.com/Patrickmitech/owl/sitemap"
+)
+
+// Product is a record of the output
+type Product struct {
+	URL      string   `json:"url"`
+	SKU      string   `json:"sku" owl:"[itemprop=sku]" attr:"content"`
+	Name     string   `json:"name" owl:"h1,required"`
+	Price    float64  `json:"price" owl:"[itemprop=price]" attr:"content"`
+	Currency string   `json:"currency" owl:"[itemprop=priceCurrency]" attr:"content" default:"USD"`
+	Tags     []string `json:"tags,omitempty" owl:"ul.tags li"`
+	InStock  bool     `json:"in_stock" owl:"[itemprop=availability]" attr:"data-in-stock" default:"false"`
+}
+
+// Config tells what to crawl and where the results go
+type Config struct {
+	// Sitemap is the URL of the sitemap listing the seeds, the crawl stays on its host
+	Sitemap string
+	// Output is the JSON Lines file the products are appended to
+	Output string
+	// State is the file the state of an interrupted crawl is saved to and resumed from
+	State string
+	// MaxPages is how many pages the whole crawl visits, MaxDepth how many links away from the sitemap
+	MaxPages int
+	MaxDepth int
+	// Budget is how many pages a run processes before saving its state, there is no limit when 0
+	Budget int
+	// Every is the time between two requests to the shop
+	Every time.Duration
+}
+
+// ErrPaused is returned by Run when the crawl was saved to be resumed by the next run
+var ErrPaused = errors.New("catalog: crawl paused")
+
+// Run crawls the shop with client and returns the number of products written.
+// The crawl is saved to cfg.State when ctx is done or the budget is spent, and resumed from it when it exists
+func Run(ctx context.Context, client *owl.Client, cfg Config) (int, error) {
+	site, err := url.Parse(cfg.Sitemap)
+	if err != nil {
+		return 0, err
+	}
+	sm, err := sitemap.Fetch(client, cfg.Sitemap)
+	if err != nil {
+		return 0, err
+	}
+	seeds := make([]string, 0, len(sm.URLs))
+	for _, u := range sm.URLs {
+		seeds = append(seeds, u.Loc)
+	}
+
+	sink, err := owl.CreateJSONLSink(cfg.Output)
+	if err != nil {
+		return 0, err
+	}
+	defer sink.Close()
+	crawler := owl.NewCrawler(client)
+	crawler.AllowedDomains = []string{site.Hostname()}
+	crawler.MaxPages = cfg.MaxPages
+	crawler.MaxDepth = cfg.MaxDepth
+	crawler.RateLimit = &owl.RateLimit{Every: cfg.Every}
+	crawler.Sink = sink
+	if err := resume(crawler, cfg.State); err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	pages, products := 0, 0
+	crawler.OnResponse(func(resp *owl.Response, cc *owl.CrawlContext) {
+		// A single worker runs the callbacks, the crawl stops before the next page once the budget is spent
+		if pages++; cfg.Budget > 0 && pages >= cfg.Budget {
+			cancel()
+		}
+	})
+	crawler.OnHTML("a[href]", func(e *owl.Root, cc *owl.CrawlContext) {
+		href, _ := e.Attr("href")
+		cc.Visit(href)
+	})
+	var errs []error
+	crawler.OnHTML("article.product", func(e *owl.Root, cc *owl.CrawlContext) {
+		p := Product{URL: cc.Request.URL}
+		if err := owl.Unmarshal(e, &p); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", cc.Request.URL, err))
+			return
+		}
+		if err := cc.Emit(p); err != nil {
+			errs = append(errs, err)
+			return
+		}
+		products++
+	})
+	crawler.OnError(func(err error, cc *owl.CrawlContext) {
+		var httpErr *owl.HTTPError
+		if errors.As(err, &httpErr) {
+			errs = append(errs, err)
+		}
+	})
+
+	err = crawler.StartContext(ctx, seeds...)
+	if err != nil {
+		if saveErr := save(crawler, cfg.State); saveErr != nil {
+			return products, errors.Join(err, saveErr)
+		}
+		return products, ErrPaused
+	}
+	if err := os.Remove(cfg.State); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return products, err
+	}
+	return products, errors.Join(errs...)
+}
+
+// resume adds the state saved at path to the crawler, when there is one
+func resume(crawler *owl.Crawler, path string) error {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return crawler.Resume(f)
+}
+
+// save writes the state of the crawler to path
+func save(crawler *owl.Crawler, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := crawler.Checkpoint(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,114 @@
+package catalog
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/Patrickmitech/owl/owltest"
+	"github.com/stretchr/testify/require"
+)
+
+const sitemapXML = `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+	<url><loc>{{site}}/</loc></url>
+	<url><loc>{{site}}/products/owl-plush.html</loc><priority>0.8</priority></url>
+	<url><loc>{{site}}/products/barn-owl-lamp.html</loc></url>
+</urlset>`
+
+func TestCatalog(t *testing.T) {
+	srv := owltest.NewServer(t, "testdata")
+	srv.Page("/sitemap.xml", strings.ReplaceAll(sitemapXML, "{{site}}", srv.URL))
+	dir := t.TempDir()
+	cfg := Config{
+		Sitemap:  srv.URL + "/sitemap.xml",
+		Output:   filepath.Join(dir, "products.jsonl"),
+		State:    filepath.Join(dir, "crawl.json"),
+		MaxPages: 20,
+		MaxDepth: 2,
+		Budget:   3,
+	}
+
+	// The first run stops once its budget is spent and saves the crawl
+	first, err := Run(context.Background(), srv.Client(), cfg)
+	require.ErrorIs(t, err, ErrPaused)
+	require.FileExists(t, cfg.State)
+	require.Len(t, readProducts(t, cfg.Output), first)
+
+	// The second run resumes it, the pages of the first run are not fetched again
+	cfg.Budget = 0
+	second, err := Run(context.Background(), srv.Client(), cfg)
+	require.NoError(t, err)
+	require.NoFileExists(t, cfg.State)
+	require.Equal(t, 4, first+second)
+
+	products := readProducts(t, cfg.Output)
+	sort.Slice(products, func(i, j int) bool { return products[i].SKU < products[j].SKU })
+	require.Equal(t, []Product{
+		{URL: srv.URL + "/products/owl-plush.html", SKU: "OWL-001", Name: "Owl plush", Price: 19.99, Currency: "USD", Tags: []string{"toys", "soft"}, InStock: true},
+		{URL: srv.URL + "/products/owl-mug.html", SKU: "OWL-002", Name: "Owl mug", Price: 12.5, Currency: "USD", Tags: []string{"kitchen"}},
+		{URL: srv.URL + "/products/barn-owl-lamp.html", SKU: "OWL-003", Name: "Barn owl lamp", Price: 49, Currency: "USD", InStock: true},
+		{URL: srv.URL + "/products/snowy-owl-print.html", SKU: "OWL-004", Name: "Snowy owl print", Price: 35, Currency: "EUR", Tags: []string{"art", "prints"}, InStock: true},
+	}, products)
+
+	fetched := make(map[string]int)
+	for _, req := range srv.Requests() {
+		fetched[req.URL]++
+	}
+	require.Equal(t, map[string]int{
+		srv.URL + "/sitemap.xml":                   2,
+		srv.URL + "/":                              1,
+		srv.URL + "/products/":                     1,
+		srv.URL + "/products/owl-plush.html":       1,
+		srv.URL + "/products/owl-mug.html":         1,
+		srv.URL + "/products/barn-owl-lamp.html":   1,
+		srv.URL + "/products/snowy-owl-print.html": 1,
+	}, fetched)
+}
+
+func TestCatalogLimits(t *testing.T) {
+	srv := owltest.NewServer(t, "testdata")
+	srv.Page("/sitemap.xml", strings.ReplaceAll(sitemapXML, "{{site}}", srv.URL))
+	dir := t.TempDir()
+	cfg := Config{
+		Sitemap:  srv.URL + "/sitemap.xml",
+		Output:   filepath.Join(dir, "products.jsonl"),
+		State:    filepath.Join(dir, "crawl.json"),
+		MaxPages: 20,
+		MaxDepth: 1,
+	}
+
+	// The links of the pages one link away from the sitemap are not followed
+	n, err := Run(context.Background(), srv.Client(), cfg)
+	require.NoError(t, err)
+	require.Equal(t, 4, n)
+	srv.AssertNotRequested(t, "https://reviews.example.org/owl-plush")
+
+	cfg.MaxPages, cfg.MaxDepth = 2, 2
+	os.Remove(cfg.Output)
+	n, err = Run(context.Background(), srv.Client(), cfg)
+	require.NoError(t, err)
+	require.LessOrEqual(t, n, 2)
+}
+
+// readProducts reads the products written to the JSON Lines file at path
+func readProducts(t *testing.T, path string) []Product {
+	t.Helper()
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	var products []Product
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var p Product
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &p))
+		products = append(products, p)
+	}
+	require.NoError(t, scanner.Err())
+	return products
+}
@@ -0,0 +1,54 @@
+// Command catalog crawls the product pages of a shop listed in its sitemap and writes them as JSON Lines,
+// an example of an application built on owl:
+//
+//	catalog -sitemap https://shop.example/sitemap.xml -out products.jsonl -state crawl.json
+//
+// Interrupting the command, or reaching the -budget of pages of a run, saves the state of the crawl to
+// the -state file. Running the command again with the same file resumes the crawl
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/Patrickmitech/owl"
+	"github.com/Patrickmitech/owl/examples/catalog"
+)
+
+func main() {
+	var cfg catalog.Config
+	flag.StringVar(&cfg.Sitemap, "sitemap", "", "URL of the sitemap of the shop")
+	flag.StringVar(&cfg.Output, "out", "products.jsonl", "JSON Lines file the products are appended to")
+	flag.StringVar(&cfg.State, "state", "crawl.json", "file the state of an interrupted crawl is saved to")
+	flag.IntVar(&cfg.MaxPages, "max-pages", 1000, "pages the crawl visits at most")
+	flag.IntVar(&cfg.MaxDepth, "max-depth", 2, "links followed from the pages of the sitemap at most")
+	flag.IntVar(&cfg.Budget, "budget", 0, "pages a run processes before saving its state, 0 for no limit")
+	flag.DurationVar(&cfg.Every, "every", time.Second, "time between two requests to the shop")
+	flag.Parse()
+	if cfg.Sitemap == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	client := owl.NewClient(
+		owl.WithUserAgent("owl-catalog-example"),
+		owl.WithTimeout(30*time.Second),
+		owl.WithRetry(owl.RetryPolicy{MaxAttempts: 3}),
+	)
+	n, err := catalog.Run(ctx, client, cfg)
+	fmt.Fprintf(os.Stderr, "catalog: %d products written to %s\n", n, cfg.Output)
+	switch {
+	case errors.Is(err, catalog.ErrPaused):
+		fmt.Fprintf(os.Stderr, "catalog: crawl saved to %s, run again to resume it\n", cfg.State)
+	case err != nil:
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
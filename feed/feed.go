@@ -0,0 +1,368 @@
+// Package feed parses RSS and Atom feeds, such as those found by owl's Root.DiscoverFeeds
+package feed
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Patrickmitech/owl"
+	"golang.org/x/net/html/charset"
+)
+
+// ErrUnknownFormat is returned when the document is neither an RSS nor an Atom feed
+var ErrUnknownFormat = errors.New("feed: unknown feed format")
+
+// Feed is a parsed RSS or Atom feed
+type Feed struct {
+	// Format is "rss" for RSS 2.0 and RSS 1.0 feeds, "atom" for Atom feeds
+	Format      string
+	Title       string
+	Description string
+	// Link is the URL of the site of the feed
+	Link     string
+	Language string
+	Author   string
+	Image    string
+	// Updated is the zero time when the feed does not tell
+	Updated time.Time
+	Items   []Item
+}
+
+// Item is an entry of a feed
+type Item struct {
+	ID          string
+	Title       string
+	Link        string
+	Description string
+	// Content holds the full content of the item when the feed provides it apart from the description
+	Content    string
+	Author     string
+	Categories []string
+	// Published and Updated are the zero time when the feed does not tell
+	Published  time.Time
+	Updated    time.Time
+	Enclosures []Enclosure
+}
+
+// Enclosure is a media file attached to an item, such as a podcast episode
+type Enclosure struct {
+	URL    string
+	Type   string
+	Length int64
+}
+
+// Fetch gets the feed at url with f, an *owl.Client or a mock of it, and parses it
+func Fetch(f owl.Fetcher, url string) (*Feed, error) {
+	r, err := f.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(r)
+}
+
+// Parse reads an RSS 2.0, RSS 1.0 or Atom feed from r, the format is recognized by the root element
+func Parse(r io.Reader) (*Feed, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	root, err := rootElement(data)
+	if err != nil {
+		return nil, err
+	}
+	switch root {
+	case "rss", "RDF":
+		var doc rssDocument
+		if err := decode(data, &doc); err != nil {
+			return nil, err
+		}
+		return doc.feed(), nil
+	case "feed":
+		var doc atomFeed
+		if err := decode(data, &doc); err != nil {
+			return nil, err
+		}
+		return doc.feed(), nil
+	}
+	return nil, ErrUnknownFormat
+}
+
+// rootElement returns the local name of the root element of data
+func rootElement(data []byte) (string, error) {
+	d := newDecoder(data)
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return "", ErrUnknownFormat
+		}
+		if err != nil {
+			return "", fmt.Errorf("feed: %w", err)
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			return start.Name.Local, nil
+		}
+	}
+}
+
+func decode(data []byte, v interface{}) error {
+	if err := newDecoder(data).Decode(v); err != nil {
+		return fmt.Errorf("feed: %w", err)
+	}
+	return nil
+}
+
+// newDecoder returns a lenient decoder understanding the charsets feeds are commonly encoded in
+func newDecoder(data []byte) *xml.Decoder {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	d.CharsetReader = charset.NewReaderLabel
+	d.Strict = false
+	d.Entity = xml.HTMLEntity
+	return d
+}
+
+// timeLayouts are tried in order to parse the dates of feeds
+var timeLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04 -0700",
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+// parseTime returns the zero time when v matches none of timeLayouts
+func parseTime(v string) time.Time {
+	v = strings.TrimSpace(v)
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+type rssDocument struct {
+	Channel rssChannel `xml:"channel"`
+	// RSS 1.0 puts items and image next to the channel
+	Items []rssItem `xml:"item"`
+	Image rssImage  `xml:"image"`
+}
+
+type rssChannel struct {
+	Title          string    `xml:"title"`
+	Link           []string  `xml:"link"`
+	Description    string    `xml:"description"`
+	Language       string    `xml:"language"`
+	ManagingEditor string    `xml:"managingEditor"`
+	Creator        string    `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	LastBuildDate  string    `xml:"lastBuildDate"`
+	PubDate        string    `xml:"pubDate"`
+	Date           string    `xml:"http://purl.org/dc/elements/1.1/ date"`
+	Image          rssImage  `xml:"image"`
+	Items          []rssItem `xml:"item"`
+}
+
+type rssImage struct {
+	URL string `xml:"url"`
+}
+
+type rssItem struct {
+	GUID        string   `xml:"guid"`
+	About       string   `xml:"http://www.w3.org/1999/02/22-rdf-syntax-ns# about,attr"`
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	Description string   `xml:"description"`
+	Content     string   `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	Author      string   `xml:"author"`
+	Creator     string   `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Categories  []string `xml:"category"`
+	PubDate     string   `xml:"pubDate"`
+	Date        string   `xml:"http://purl.org/dc/elements/1.1/ date"`
+	Enclosures  []struct {
+		URL    string `xml:"url,attr"`
+		Type   string `xml:"type,attr"`
+		Length string `xml:"length,attr"`
+	} `xml:"enclosure"`
+}
+
+func (doc *rssDocument) feed() *Feed {
+	ch := doc.Channel
+	f := &Feed{
+		Format:      "rss",
+		Title:       strings.TrimSpace(ch.Title),
+		Description: strings.TrimSpace(ch.Description),
+		Language:    strings.TrimSpace(ch.Language),
+		Author:      strings.TrimSpace(first(ch.ManagingEditor, ch.Creator)),
+		Image:       strings.TrimSpace(first(ch.Image.URL, doc.Image.URL)),
+		Updated:     parseTime(first(ch.LastBuildDate, ch.PubDate, ch.Date)),
+	}
+	// The channel may hold an atom:link next to its own link, which decodes as an empty string
+	for _, link := range ch.Link {
+		if link = strings.TrimSpace(link); link != "" {
+			f.Link = link
+			break
+		}
+	}
+	for _, it := range append(ch.Items, doc.Items...) {
+		item := Item{
+			ID:          strings.TrimSpace(first(it.GUID, it.About, it.Link)),
+			Title:       strings.TrimSpace(it.Title),
+			Link:        strings.TrimSpace(it.Link),
+			Description: strings.TrimSpace(it.Description),
+			Content:     strings.TrimSpace(it.Content),
+			Author:      strings.TrimSpace(first(it.Author, it.Creator)),
+			Published:   parseTime(first(it.PubDate, it.Date)),
+		}
+		for _, c := range it.Categories {
+			if c = strings.TrimSpace(c); c != "" {
+				item.Categories = append(item.Categories, c)
+			}
+		}
+		for _, e := range it.Enclosures {
+			length, _ := strconv.ParseInt(strings.TrimSpace(e.Length), 10, 64)
+			item.Enclosures = append(item.Enclosures, Enclosure{URL: strings.TrimSpace(e.URL), Type: e.Type, Length: length})
+		}
+		f.Items = append(f.Items, item)
+	}
+	return f
+}
+
+type atomFeed struct {
+	Title    atomText    `xml:"title"`
+	Subtitle atomText    `xml:"subtitle"`
+	Links    []atomLink  `xml:"link"`
+	Lang     string      `xml:"http://www.w3.org/XML/1998/namespace lang,attr"`
+	Authors  []atomName  `xml:"author"`
+	Logo     string      `xml:"logo"`
+	Icon     string      `xml:"icon"`
+	Updated  string      `xml:"updated"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",innerxml"`
+}
+
+// String returns the content of t, html and xhtml constructs keep their markup
+func (t atomText) String() string {
+	body := strings.TrimSpace(t.Body)
+	if strings.HasPrefix(body, "<![CDATA[") && strings.HasSuffix(body, "]]>") {
+		return strings.TrimSpace(body[len("<![CDATA[") : len(body)-len("]]>")])
+	}
+	if t.Type == "xhtml" {
+		return body
+	}
+	var text strings.Builder
+	d := newDecoder([]byte("<t>" + body + "</t>"))
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			break
+		}
+		if c, ok := tok.(xml.CharData); ok {
+			text.Write(c)
+		}
+	}
+	return strings.TrimSpace(text.String())
+}
+
+type atomLink struct {
+	Href   string `xml:"href,attr"`
+	Rel    string `xml:"rel,attr"`
+	Type   string `xml:"type,attr"`
+	Length string `xml:"length,attr"`
+}
+
+type atomName struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	ID         string     `xml:"id"`
+	Title      atomText   `xml:"title"`
+	Links      []atomLink `xml:"link"`
+	Summary    atomText   `xml:"summary"`
+	Content    atomText   `xml:"content"`
+	Authors    []atomName `xml:"author"`
+	Categories []struct {
+		Term  string `xml:"term,attr"`
+		Label string `xml:"label,attr"`
+	} `xml:"category"`
+	Published string `xml:"published"`
+	Updated   string `xml:"updated"`
+}
+
+func (doc *atomFeed) feed() *Feed {
+	f := &Feed{
+		Format:      "atom",
+		Title:       doc.Title.String(),
+		Description: doc.Subtitle.String(),
+		Link:        alternate(doc.Links),
+		Language:    doc.Lang,
+		Author:      authors(doc.Authors),
+		Image:       strings.TrimSpace(first(doc.Logo, doc.Icon)),
+		Updated:     parseTime(doc.Updated),
+	}
+	for _, e := range doc.Entries {
+		item := Item{
+			ID:          strings.TrimSpace(e.ID),
+			Title:       e.Title.String(),
+			Link:        alternate(e.Links),
+			Description: e.Summary.String(),
+			Content:     e.Content.String(),
+			Author:      first(authors(e.Authors), f.Author),
+			Published:   parseTime(e.Published),
+			Updated:     parseTime(e.Updated),
+		}
+		for _, c := range e.Categories {
+			item.Categories = append(item.Categories, first(c.Label, c.Term))
+		}
+		for _, l := range e.Links {
+			if l.Rel == "enclosure" {
+				length, _ := strconv.ParseInt(l.Length, 10, 64)
+				item.Enclosures = append(item.Enclosures, Enclosure{URL: l.Href, Type: l.Type, Length: length})
+			}
+		}
+		f.Items = append(f.Items, item)
+	}
+	return f
+}
+
+// alternate returns the href of the alternate link, the rel of links defaults to alternate
+func alternate(links []atomLink) string {
+	for _, l := range links {
+		if l.Rel == "" || l.Rel == "alternate" {
+			return strings.TrimSpace(l.Href)
+		}
+	}
+	return ""
+}
+
+func authors(names []atomName) string {
+	var list []string
+	for _, n := range names {
+		if name := strings.TrimSpace(n.Name); name != "" {
+			list = append(list, name)
+		}
+	}
+	return strings.Join(list, ", ")
+}
+
+func first(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
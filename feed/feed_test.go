@@ -0,0 +1,130 @@
+package feed
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Patrickmitech/owl/owlmock"
+	"github.com/stretchr/testify/require"
+)
+
+const rssFeed = `<?xml version="1.0" encoding="ISO-8859-1"?>
+<rss version="2.0" xmlns:content="http://purl.org/rss/1.0/modules/content/"
+	xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:atom="http://www.w3.org/2005/Atom">
+<channel>
+	<title>Owl News</title>
+	<atom:link href="https://example.com/feed.xml" rel="self"/>
+	<link>https://example.com/</link>
+	<description>Caf` + "\xe9" + ` talk about owls</description>
+	<language>en</language>
+	<lastBuildDate>Wed, 01 May 2024 22:00:00 +0000</lastBuildDate>
+	<image><url>https://example.com/logo.png</url></image>
+	<item>
+		<title>Barn owls</title>
+		<link>https://example.com/barn</link>
+		<guid isPermaLink="false">post-1</guid>
+		<description><![CDATA[<p>Short</p>]]></description>
+		<content:encoded><![CDATA[<p>Long <b>story</b></p>]]></content:encoded>
+		<dc:creator>Olive</dc:creator>
+		<category>birds</category>
+		<category>night</category>
+		<pubDate>Tue, 30 Apr 2024 08:15:00 GMT</pubDate>
+		<enclosure url="https://example.com/hoot.mp3" type="audio/mpeg" length="1024"/>
+	</item>
+</channel>
+</rss>`
+
+const atomFeedXML = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xml:lang="en">
+	<title type="html">Owls &amp;amp; more</title>
+	<link href="https://example.org/feed" rel="self"/>
+	<link href="https://example.org/"/>
+	<updated>2024-05-01T22:00:00Z</updated>
+	<author><name>Olive</name></author>
+	<entry>
+		<id>urn:uuid:1</id>
+		<title>Snowy owls</title>
+		<link rel="alternate" href="https://example.org/snowy"/>
+		<link rel="enclosure" href="https://example.org/snowy.jpg" type="image/jpeg" length="2048"/>
+		<summary>Arctic birds</summary>
+		<content type="xhtml"><div xmlns="http://www.w3.org/1999/xhtml"><p>White</p></div></content>
+		<category term="arctic"/>
+		<published>2024-04-01T10:00:00+02:00</published>
+		<updated>2024-04-02T10:00:00Z</updated>
+	</entry>
+</feed>`
+
+const rdfFeed = `<?xml version="1.0"?>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/"
+	xmlns:dc="http://purl.org/dc/elements/1.1/">
+	<channel rdf:about="https://example.net/"><title>Old owls</title><link>https://example.net/</link></channel>
+	<item rdf:about="https://example.net/1"><title>First</title><link>https://example.net/1</link>
+		<dc:date>2003-12-13T18:30:02Z</dc:date></item>
+</rdf:RDF>`
+
+func TestParseRSS(t *testing.T) {
+	f, err := Parse(strings.NewReader(rssFeed))
+	require.NoError(t, err)
+	require.Equal(t, "rss", f.Format)
+	require.Equal(t, "Owl News", f.Title)
+	require.Equal(t, "Café talk about owls", f.Description)
+	require.Equal(t, "https://example.com/", f.Link)
+	require.Equal(t, "https://example.com/logo.png", f.Image)
+	require.Equal(t, time.Date(2024, 5, 1, 22, 0, 0, 0, time.UTC), f.Updated.UTC())
+	require.Len(t, f.Items, 1)
+
+	item := f.Items[0]
+	require.Equal(t, "post-1", item.ID)
+	require.Equal(t, "<p>Short</p>", item.Description)
+	require.Equal(t, "<p>Long <b>story</b></p>", item.Content)
+	require.Equal(t, "Olive", item.Author)
+	require.Equal(t, []string{"birds", "night"}, item.Categories)
+	require.Equal(t, time.Date(2024, 4, 30, 8, 15, 0, 0, time.UTC), item.Published.UTC())
+	require.Equal(t, []Enclosure{{URL: "https://example.com/hoot.mp3", Type: "audio/mpeg", Length: 1024}}, item.Enclosures)
+}
+
+func TestParseAtom(t *testing.T) {
+	f, err := Parse(strings.NewReader(atomFeedXML))
+	require.NoError(t, err)
+	require.Equal(t, "atom", f.Format)
+	require.Equal(t, "Owls &amp; more", f.Title)
+	require.Equal(t, "https://example.org/", f.Link)
+	require.Equal(t, "en", f.Language)
+	require.Len(t, f.Items, 1)
+
+	item := f.Items[0]
+	require.Equal(t, "urn:uuid:1", item.ID)
+	require.Equal(t, "https://example.org/snowy", item.Link)
+	require.Equal(t, "Arctic birds", item.Description)
+	require.Contains(t, item.Content, "<p>White</p>")
+	require.Equal(t, "Olive", item.Author)
+	require.Equal(t, []string{"arctic"}, item.Categories)
+	require.Equal(t, time.Date(2024, 4, 1, 8, 0, 0, 0, time.UTC), item.Published.UTC())
+	require.Equal(t, int64(2048), item.Enclosures[0].Length)
+}
+
+func TestParseRDF(t *testing.T) {
+	f, err := Parse(strings.NewReader(rdfFeed))
+	require.NoError(t, err)
+	require.Equal(t, "Old owls", f.Title)
+	require.Len(t, f.Items, 1)
+	require.Equal(t, "https://example.net/1", f.Items[0].ID)
+	require.Equal(t, 2003, f.Items[0].Published.Year())
+}
+
+func TestParseUnknown(t *testing.T) {
+	_, err := Parse(strings.NewReader(`<html><body>Not a feed</body></html>`))
+	require.ErrorIs(t, err, ErrUnknownFormat)
+	_, err = Parse(strings.NewReader(""))
+	require.ErrorIs(t, err, ErrUnknownFormat)
+}
+
+func TestFetch(t *testing.T) {
+	pages := owlmock.NewPages(map[string]string{"https://example.org/feed": atomFeedXML})
+	f, err := Fetch(pages, "https://example.org/feed")
+	require.NoError(t, err)
+	require.Equal(t, "atom", f.Format)
+	_, err = Fetch(pages, "https://example.org/missing")
+	require.Error(t, err)
+}
@@ -0,0 +1,63 @@
+package owl
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// FeedLink is a feed advertised by a document
+type FeedLink struct {
+	// URL is the href of the link, resolved against BaseURL when the document has one
+	URL   string
+	Title string
+	// Type is the media type of the feed, such as application/rss+xml
+	Type string
+}
+
+// feedTypes are the media types of the feeds DiscoverFeeds recognizes
+var feedTypes = []string{
+	"application/rss+xml", "application/atom+xml", "application/feed+json", "application/json",
+	"application/rdf+xml", "application/xml", "text/xml",
+}
+
+// DiscoverFeeds returns the RSS, Atom and JSON feeds the document the Node belongs to advertises
+// with link rel="alternate" or rel="feed" elements, in document order and without duplicates
+func (r *Root) DiscoverFeeds() []FeedLink {
+	if r.Node == nil {
+		return nil
+	}
+	top := r.Node
+	for top.Parent != nil {
+		top = top.Parent
+	}
+	var feeds []FeedLink
+	seen := make(map[string]bool)
+	walk(top, func(n *html.Node) bool {
+		if n.Type != html.ElementNode || (n.Data != "link" && n.Data != "a") {
+			return true
+		}
+		href, ok := attrLookup(n, "href")
+		if !ok || strings.TrimSpace(href) == "" {
+			return true
+		}
+		rel := strings.Fields(strings.ToLower(attrValue(n, "rel")))
+		typ := strings.ToLower(strings.TrimSpace(attrValue(n, "type")))
+		if mediaType, _, found := strings.Cut(typ, ";"); found {
+			typ = strings.TrimSpace(mediaType)
+		}
+		switch {
+		case containsString(rel, "alternate") && containsString(feedTypes, typ):
+		case containsString(rel, "feed") && (typ == "" || containsString(feedTypes, typ)):
+		default:
+			return true
+		}
+		u := r.resolveString(href)
+		if !seen[u] {
+			seen[u] = true
+			feeds = append(feeds, FeedLink{URL: u, Title: strings.TrimSpace(attrValue(n, "title")), Type: typ})
+		}
+		return true
+	})
+	return feeds
+}
@@ -0,0 +1,25 @@
+package owl
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscoverFeeds(t *testing.T) {
+	doc := HTMLParseFromString(`<html><head>
+		<link rel="alternate" type="application/rss+xml" title="Posts" href="/feed.xml">
+		<link rel="alternate" type="application/atom+xml; charset=utf-8" href="atom.xml">
+		<link rel="alternate" hreflang="de" href="/de/">
+		<link rel="alternate" type="application/rss+xml" href="/feed.xml">
+		<link rel="stylesheet" href="/style.css">
+	</head><body><a rel="feed" href="/comments.json" type="application/feed+json">Comments</a></body></html>`)
+	page, _ := url.Parse("https://example.com/blog/")
+	require.Equal(t, []FeedLink{
+		{URL: "https://example.com/feed.xml", Title: "Posts", Type: "application/rss+xml"},
+		{URL: "https://example.com/blog/atom.xml", Type: "application/atom+xml"},
+		{URL: "https://example.com/comments.json", Type: "application/feed+json"},
+	}, doc.SetURL(page).DiscoverFeeds())
+	require.Empty(t, HtmlRoot.DiscoverFeeds())
+}
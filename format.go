@@ -0,0 +1,229 @@
+package owl
+
+import (
+	"bufio"
+	"io"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// RenderOption configures Render and RenderTo
+type RenderOption func(*renderConfig)
+
+type renderConfig struct {
+	pretty   bool
+	indent   string
+	minify   bool
+	fragment bool
+}
+
+// Pretty puts block elements on lines of their own, indented by indent for every level, for debugging.
+// Whitespace is only added between block elements, so the page looks the same in a browser
+func Pretty(indent string) RenderOption {
+	return func(c *renderConfig) {
+		c.pretty = true
+		c.indent = indent
+	}
+}
+
+// Minify removes comments and the whitespace between block elements, and collapses the other runs
+// of whitespace into a single space, except in pre, textarea, script and style elements
+func Minify() RenderOption {
+	return func(c *renderConfig) {
+		c.minify = true
+	}
+}
+
+// Fragment renders the content of documents without the doctype and the html, head and body elements
+// the parser adds around it, such as for a snippet given to HTMLParseFromString.
+// Other nodes are rendered as they are, Render never adds wrappers to them
+func Fragment() RenderOption {
+	return func(c *renderConfig) {
+		c.fragment = true
+	}
+}
+
+// keptWhitespace are the elements whose text Minify leaves as it is
+var keptWhitespace = map[string]bool{"pre": true, "textarea": true, "script": true, "style": true}
+
+// renderNode writes n to w as configured by opts
+func renderNode(w io.Writer, n *html.Node, opts []RenderOption) error {
+	if len(opts) == 0 {
+		return html.Render(w, n)
+	}
+	cfg := renderConfig{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.minify {
+		// The tree of the Root stays as it is
+		n = cloneNode(n)
+		minify(n, false)
+	}
+	nodes := []*html.Node{n}
+	if cfg.fragment {
+		nodes = fragmentNodes(n)
+	}
+	if !cfg.pretty {
+		for _, n := range nodes {
+			if err := html.Render(w, n); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	bw := bufio.NewWriter(w)
+	p := printer{w: bw, indent: cfg.indent}
+	for _, n := range nodes {
+		p.node(n, 0)
+	}
+	if p.err != nil {
+		return p.err
+	}
+	return bw.Flush()
+}
+
+// fragmentNodes returns the nodes of the content of n, without the doctype and the html, head and body elements
+func fragmentNodes(n *html.Node) []*html.Node {
+	if n.Type != html.DocumentNode && !(n.Type == html.ElementNode && n.Data == "html") {
+		return []*html.Node{n}
+	}
+	var nodes []*html.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		switch {
+		case c.Type == html.DoctypeNode:
+		case c.Type == html.ElementNode && (c.Data == "html" || c.Data == "head" || c.Data == "body"):
+			if c.Data == "html" {
+				nodes = append(nodes, fragmentNodes(c)...)
+				break
+			}
+			for gc := c.FirstChild; gc != nil; gc = gc.NextSibling {
+				nodes = append(nodes, gc)
+			}
+		default:
+			nodes = append(nodes, c)
+		}
+	}
+	return nodes
+}
+
+// minify removes the comments and whitespace below n, kept is set below the elements of keptWhitespace
+func minify(n *html.Node, kept bool) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		switch c.Type {
+		case html.CommentNode:
+			n.RemoveChild(c)
+		case html.TextNode:
+			if kept {
+				break
+			}
+			if strings.TrimSpace(c.Data) == "" && !inlineSibling(c.PrevSibling) && !inlineSibling(c.NextSibling) {
+				n.RemoveChild(c)
+				break
+			}
+			c.Data = collapseSpaces(c.Data)
+		case html.ElementNode:
+			minify(c, kept || keptWhitespace[c.Data])
+		}
+		c = next
+	}
+}
+
+// inlineSibling reports whether n is text or an inline element, which whitespace next to it separates
+func inlineSibling(n *html.Node) bool {
+	return n != nil && (n.Type == html.TextNode || n.Type == html.ElementNode && inlineElements[n.Data])
+}
+
+// collapseSpaces replaces every run of whitespace in s by a single space
+func collapseSpaces(s string) string {
+	var b strings.Builder
+	space := false
+	for _, c := range s {
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' {
+			space = true
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteRune(c)
+	}
+	if space {
+		b.WriteByte(' ')
+	}
+	return b.String()
+}
+
+// printer writes a tree with its block elements indented, see Pretty
+type printer struct {
+	w      *bufio.Writer
+	indent string
+	err    error
+}
+
+func (p *printer) node(n *html.Node, depth int) {
+	if p.err != nil {
+		return
+	}
+	switch {
+	case n.Type == html.DocumentNode:
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			p.node(c, depth)
+		}
+	case n.Type == html.TextNode && strings.TrimSpace(n.Data) == "" && n.Parent != nil:
+		// Whitespace between blocks is replaced by the line breaks
+	case n.Type == html.ElementNode && blockChildren(n):
+		var b strings.Builder
+		shallow := &html.Node{Type: n.Type, DataAtom: n.DataAtom, Data: n.Data, Namespace: n.Namespace, Attr: n.Attr}
+		if p.err = html.Render(&b, shallow); p.err != nil {
+			return
+		}
+		end := "</" + n.Data + ">"
+		p.line(depth, strings.TrimSuffix(b.String(), end))
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			p.node(c, depth+1)
+		}
+		p.line(depth, end)
+	default:
+		var b strings.Builder
+		if p.err = html.Render(&b, n); p.err != nil {
+			return
+		}
+		p.line(depth, b.String())
+	}
+}
+
+func (p *printer) line(depth int, s string) {
+	if p.err != nil {
+		return
+	}
+	p.w.WriteString(strings.Repeat(p.indent, depth))
+	p.w.WriteString(s)
+	_, p.err = p.w.WriteString("\n")
+}
+
+// blockChildren reports whether the children of n can go on lines of their own: n has element children,
+// which are all blocks, and no text but whitespace
+func blockChildren(n *html.Node) bool {
+	if keptWhitespace[n.Data] || n.FirstChild == nil {
+		return false
+	}
+	elements := false
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		switch c.Type {
+		case html.TextNode:
+			if strings.TrimSpace(c.Data) != "" {
+				return false
+			}
+		case html.ElementNode:
+			if inlineElements[c.Data] {
+				return false
+			}
+			elements = true
+		}
+	}
+	return elements
+}
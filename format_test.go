@@ -0,0 +1,88 @@
+package owl
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const formatHTML = `<body>
+  <!-- menu -->
+  <ul>
+    <li>One   <b>bold</b> <i>word</i></li>
+    <li><p>Two</p></li>
+  </ul>
+  <pre>  keep
+   this  </pre>
+  <p>text</p>
+</body>`
+
+func TestRenderPretty(t *testing.T) {
+	doc := HTMLParseFromString(formatHTML)
+	want := "<body>\n" +
+		"  <!-- menu -->\n" +
+		"  <ul>\n" +
+		"    <li>One   <b>bold</b> <i>word</i></li>\n" +
+		"    <li>\n" +
+		"      <p>Two</p>\n" +
+		"    </li>\n" +
+		"  </ul>\n" +
+		"  <pre>  keep\n   this  </pre>\n" +
+		"  <p>text</p>\n" +
+		"</body>\n"
+	require.Equal(t, want, string(doc.Find("body").Render(Pretty("  "))))
+
+	// The tree is left as it is
+	require.Contains(t, string(doc.Find("body").Render()), "<!-- menu -->\n  <ul>")
+}
+
+func TestRenderMinify(t *testing.T) {
+	doc := HTMLParseFromString(formatHTML)
+	require.Equal(t, "<body><ul><li>One <b>bold</b> <i>word</i></li><li><p>Two</p></li></ul><pre>  keep\n   this  </pre><p>text</p></body>",
+		string(doc.Find("body").Render(Minify())))
+
+	require.Equal(t, "<body>\n<ul>\n<li>One <b>bold</b> <i>word</i></li>\n<li>\n<p>Two</p>\n</li>\n</ul>\n<pre>  keep\n   this  </pre>\n<p>text</p>\n</body>\n",
+		string(doc.Find("body").Render(Minify(), Pretty(""))))
+}
+
+func TestRenderToOptions(t *testing.T) {
+	doc := HTMLParseFromString(`<p>a  b</p>`)
+	var buf bytes.Buffer
+	require.NoError(t, doc.Find("p").RenderTo(&buf, Minify()))
+	require.Equal(t, "<p>a b</p>", buf.String())
+
+	require.ErrorIs(t, doc.Find("nav").RenderTo(&buf), ErrNoNode)
+	require.Nil(t, doc.Find("nav").Render())
+}
+
+func TestRenderFragment(t *testing.T) {
+	fragments := []string{
+		`<p>Hello <b>owl</b></p>`,
+		`text only`,
+		`<title>Owls</title><p>a</p>`,
+		`<li>1</li><li>2</li>`,
+		`<!-- note --><img src="a.png"/> tail`,
+		`<table><tbody><tr><td>1</td></tr></tbody></table>`,
+		`<script>if (a < b) {}</script>`,
+	}
+	for _, s := range fragments {
+		root := HTMLParseFragmentFromString(s)
+		require.Nil(t, root.Error)
+		require.Equal(t, s, string(root.Render()), s)
+		require.Equal(t, s, root.InnerHTML(), s)
+		// Rendering again what was rendered gives the same markup
+		require.Equal(t, s, HTMLParseFragmentFromString(string(root.Render())).HTML(), s)
+	}
+
+	frag := HTMLParseFragmentFromString(`<ul><li>1</li></ul><p class="x">2</p>`)
+	require.Equal(t, "2", frag.Find("p").Text())
+	require.Len(t, frag.Select("li, p").Roots, 2)
+	require.Equal(t, "<ul>\n  <li>1</li>\n</ul>\n<p class=\"x\">2</p>\n", string(frag.Render(Pretty("  "))))
+
+	// Documents lose their wrappers
+	doc := HTMLParseFromString(`<!DOCTYPE html><title>Owls</title><p>a</p>`)
+	require.Equal(t, `<html><head><title>Owls</title></head><body><p>a</p></body></html>`, string(doc.Render()))
+	require.Equal(t, `<title>Owls</title><p>a</p>`, string(doc.Render(Fragment())))
+	require.Equal(t, `<p>a</p>`, string(doc.Find("p").Render(Fragment())))
+}
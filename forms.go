@@ -0,0 +1,513 @@
+package owl
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Form is a form element of the document along with its fields
+type Form struct {
+	Name string
+	ID   string
+	// Action is the action attribute as written in the document
+	Action string
+	// URL is Action resolved against the base of the document, the URL of the document when Action is empty.
+	// It is nil when the document has no URL and Action is relative
+	URL *url.URL
+	// Method is GET, POST or DIALOG, GET when the method attribute is missing or invalid
+	Method string
+	// Enctype is application/x-www-form-urlencoded, multipart/form-data or text/plain
+	Enctype string
+	// Fields are the input, select, textarea and button elements of the form in document order,
+	// including those outside of it that refer to it with a form attribute
+	Fields []*FormField
+}
+
+// FormField is an input, select, textarea or button element of a form
+type FormField struct {
+	// Tag is input, select, textarea or button
+	Tag  string
+	Name string
+	// Type is the lower-cased type attribute, text for inputs and submit for buttons when it is missing.
+	// Selects have the type select-one or select-multiple and textareas the type textarea
+	Type string
+	// Value is the current value of the field: the value attribute, the text of a textarea
+	// or the value of the first selected option of a select
+	Value string
+	// Checked is set for checked checkboxes and radio buttons
+	Checked bool
+	// Files are the files chosen for a file input with Form.SetFile
+	Files    []MultipartFile
+	Options  []FormOption
+	Multiple bool
+	Disabled bool
+	Required bool
+	ReadOnly bool
+}
+
+// FormOption is an option of a select element
+type FormOption struct {
+	// Value is the value attribute of the option, its text when it has none
+	Value    string
+	Text     string
+	Selected bool
+	Disabled bool
+}
+
+const (
+	formURLEncoded = "application/x-www-form-urlencoded"
+	formMultipart  = "multipart/form-data"
+	formTextPlain  = "text/plain"
+)
+
+// Forms returns every form element below the Node in document order
+func (r *Root) Forms() []*Form {
+	var nodes []*html.Node
+	walk(r.Node, func(n *html.Node) bool {
+		if n.Type == html.ElementNode && n.Data == "form" {
+			nodes = append(nodes, n)
+		}
+		return true
+	})
+	if len(nodes) == 0 {
+		return nil
+	}
+	top := r.Node
+	for top.Parent != nil {
+		top = top.Parent
+	}
+	forms := make([]*Form, 0, len(nodes))
+	for _, n := range nodes {
+		forms = append(forms, r.newForm(n, top))
+	}
+	return forms
+}
+
+// newForm returns the Form of the form element n, top is the root of its document
+func (r *Root) newForm(n, top *html.Node) *Form {
+	form := &Form{
+		Name:    attrValue(n, "name"),
+		ID:      attrValue(n, "id"),
+		Action:  attrValue(n, "action"),
+		Method:  strings.ToUpper(strings.TrimSpace(attrValue(n, "method"))),
+		Enctype: strings.ToLower(strings.TrimSpace(attrValue(n, "enctype"))),
+	}
+	if form.Method != "POST" && form.Method != "DIALOG" {
+		form.Method = "GET"
+	}
+	if form.Enctype != formMultipart && form.Enctype != formTextPlain {
+		form.Enctype = formURLEncoded
+	}
+	if strings.TrimSpace(form.Action) == "" {
+		form.URL = r.URL()
+	} else if u, err := r.ResolveURL(form.Action); err == nil {
+		form.URL = u
+	}
+
+	for _, c := range formFields(n, top, form.ID) {
+		form.Fields = append(form.Fields, newFormField(c))
+	}
+	return form
+}
+
+// formFields returns the input, select, textarea and button elements of the form element n with the id,
+// top is the root of its document
+func formFields(n, top *html.Node, id string) []*html.Node {
+	var fields []*html.Node
+	walk(top, func(c *html.Node) bool {
+		if c.Type != html.ElementNode {
+			return true
+		}
+		switch c.Data {
+		case "input", "select", "textarea", "button":
+			if owner, ok := attrLookup(c, "form"); ok {
+				if id == "" || owner != id {
+					return true
+				}
+			} else if !isAncestor(n, c) {
+				return true
+			}
+			fields = append(fields, c)
+			return false
+		}
+		return true
+	})
+	return fields
+}
+
+// newFormField returns the FormField of the input, select, textarea or button element n
+func newFormField(n *html.Node) *FormField {
+	field := &FormField{
+		Tag:      n.Data,
+		Name:     attrValue(n, "name"),
+		Type:     strings.ToLower(strings.TrimSpace(attrValue(n, "type"))),
+		Value:    attrValue(n, "value"),
+		Disabled: hasAttr(n, "disabled") || disabledByFieldset(n),
+		Required: hasAttr(n, "required"),
+		ReadOnly: hasAttr(n, "readonly"),
+		Multiple: hasAttr(n, "multiple"),
+	}
+	switch n.Data {
+	case "input":
+		if field.Type == "" {
+			field.Type = "text"
+		}
+		field.Checked = hasAttr(n, "checked")
+		if (field.Type == "checkbox" || field.Type == "radio") && !hasAttr(n, "value") {
+			field.Value = "on"
+		}
+	case "button":
+		if field.Type != "reset" && field.Type != "button" {
+			field.Type = "submit"
+		}
+	case "textarea":
+		field.Type = "textarea"
+		var text strings.Builder
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.TextNode {
+				text.WriteString(c.Data)
+			}
+		}
+		// A newline right after the start tag is dropped by the parser already
+		field.Value = text.String()
+	case "select":
+		field.Type = "select-one"
+		if field.Multiple {
+			field.Type = "select-multiple"
+		}
+		walk(n, func(c *html.Node) bool {
+			if c.Type != html.ElementNode || c.Data != "option" {
+				return true
+			}
+			option := FormOption{
+				Text:     collapsedText(c),
+				Selected: hasAttr(c, "selected"),
+				Disabled: hasAttr(c, "disabled") || (c.Parent.Data == "optgroup" && hasAttr(c.Parent, "disabled")),
+			}
+			option.Value = option.Text
+			if v, ok := attrLookup(c, "value"); ok {
+				option.Value = v
+			}
+			field.Options = append(field.Options, option)
+			return false
+		})
+		field.selectDefault()
+		field.Value = field.selectedValue()
+	}
+	return field
+}
+
+// selectDefault selects the first enabled option of a select-one without selected options,
+// as browsers display it
+func (f *FormField) selectDefault() {
+	if f.Multiple || len(f.Options) == 0 {
+		return
+	}
+	for _, o := range f.Options {
+		if o.Selected {
+			return
+		}
+	}
+	for i := range f.Options {
+		if !f.Options[i].Disabled {
+			f.Options[i].Selected = true
+			return
+		}
+	}
+}
+
+// selectedValue returns the value of the first selected option, an empty string when there is none
+func (f *FormField) selectedValue() string {
+	for _, o := range f.Options {
+		if o.Selected {
+			return o.Value
+		}
+	}
+	return ""
+}
+
+// disabledByFieldset reports whether n is in a disabled fieldset, outside of the first legend of that fieldset
+func disabledByFieldset(n *html.Node) bool {
+	child := n
+	for p := n.Parent; p != nil; child, p = p, p.Parent {
+		if p.Type != html.ElementNode || p.Data != "fieldset" || !hasAttr(p, "disabled") {
+			continue
+		}
+		legend := p.FirstChild
+		for legend != nil && (legend.Type != html.ElementNode || legend.Data != "legend") {
+			legend = legend.NextSibling
+		}
+		if legend == nil || legend != child {
+			return true
+		}
+	}
+	return false
+}
+
+// isAncestor reports whether a is an ancestor of n
+func isAncestor(a, n *html.Node) bool {
+	for p := n.Parent; p != nil; p = p.Parent {
+		if p == a {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// ErrNoFormField is returned when a form has no field by the given name or value
+	ErrNoFormField = errors.New("owl: no such form field")
+	// ErrNoFormOption is returned by Form.Select when the select has no option by the given value or text
+	ErrNoFormOption = errors.New("owl: no such option")
+	// ErrDialogForm is returned when submitting a form with method dialog, which closes a dialog instead
+	ErrDialogForm = errors.New("owl: dialog forms are not submitted")
+)
+
+// Set sets the value of the first field called name. Radio buttons and checkboxes called name
+// are checked when value is theirs, other radio buttons of the group are unchecked,
+// and for selects the option is chosen as with Select
+func (f *Form) Set(name, value string) error {
+	var found bool
+	for _, field := range f.Fields {
+		if field.Name != name {
+			continue
+		}
+		switch {
+		case field.Tag == "select":
+			return f.Select(name, value)
+		case field.Type == "radio":
+			field.Checked = field.Value == value
+			found = found || field.Checked
+		case field.Type == "checkbox":
+			if field.Value == value {
+				field.Checked, found = true, true
+			}
+		default:
+			field.Value = value
+			return nil
+		}
+	}
+	if !found {
+		return ErrNoFormField
+	}
+	return nil
+}
+
+// SetFile chooses a file for the file input called name, adding it to the files already chosen
+// when the input is multiple. The content type is guessed from the extension of filename
+func (f *Form) SetFile(name, filename string, content io.Reader) error {
+	for _, field := range f.Fields {
+		if field.Name != name || field.Type != "file" {
+			continue
+		}
+		file := MultipartFile{Field: name, Filename: filename, Content: content}
+		if field.Multiple {
+			field.Files = append(field.Files, file)
+		} else {
+			field.Files = []MultipartFile{file}
+		}
+		return nil
+	}
+	return ErrNoFormField
+}
+
+// Select chooses the option of the select called name with option as value or text.
+// Other options are deselected unless the select is multiple
+func (f *Form) Select(name, option string) error {
+	for _, field := range f.Fields {
+		if field.Name != name || field.Tag != "select" {
+			continue
+		}
+		i := field.option(option)
+		if i < 0 {
+			return ErrNoFormOption
+		}
+		if !field.Multiple {
+			for j := range field.Options {
+				field.Options[j].Selected = false
+			}
+		}
+		field.Options[i].Selected = true
+		field.Value = field.selectedValue()
+		return nil
+	}
+	return ErrNoFormField
+}
+
+// option returns the index of the option with the value, or else the text, option, -1 when there is none
+func (f *FormField) option(option string) int {
+	for i, o := range f.Options {
+		if o.Value == option {
+			return i
+		}
+	}
+	for i, o := range f.Options {
+		if o.Text == option {
+			return i
+		}
+	}
+	return -1
+}
+
+// Submit submits the form with client as if its first submit button was clicked and returns the response.
+// GET forms send their fields as the query of the action URL, POST forms as a body encoded as Enctype
+func (f *Form) Submit(client *Client) (*Response, error) {
+	var submitter *FormField
+	for _, field := range f.Fields {
+		if field.isSubmit() && !field.Disabled {
+			submitter = field
+			break
+		}
+	}
+	return f.submit(client, submitter)
+}
+
+// SubmitWith submits the form with client as if the submit button with the name or value button was clicked
+func (f *Form) SubmitWith(client *Client, button string) (*Response, error) {
+	for _, field := range f.Fields {
+		if field.isSubmit() && !field.Disabled && (field.Name == button || field.Value == button) {
+			return f.submit(client, field)
+		}
+	}
+	return nil, ErrNoFormField
+}
+
+func (f *FormField) isSubmit() bool {
+	return f.Type == "submit" || (f.Tag == "input" && f.Type == "image")
+}
+
+// formEntry is a name and value pair of the data set of a form, or a file of a file input
+type formEntry struct {
+	name, value string
+	file        *MultipartFile
+}
+
+// dataSet returns the entries a submission of the form sends in document order,
+// following the construction of the entry list of the HTML specification
+func (f *Form) dataSet(submitter *FormField) []formEntry {
+	var entries []formEntry
+	add := func(name, value string) {
+		// Line breaks are normalized to CRLF like browsers do
+		value = strings.ReplaceAll(strings.ReplaceAll(value, "\r\n", "\n"), "\n", "\r\n")
+		entries = append(entries, formEntry{name: name, value: value})
+	}
+	for _, field := range f.Fields {
+		if field.Disabled {
+			continue
+		}
+		if field.Tag == "input" && field.Type == "image" {
+			if field == submitter {
+				prefix := ""
+				if field.Name != "" {
+					prefix = field.Name + "."
+				}
+				add(prefix+"x", "0")
+				add(prefix+"y", "0")
+			}
+			continue
+		}
+		if field.Name == "" {
+			continue
+		}
+		switch {
+		case field.isSubmit():
+			if field == submitter {
+				add(field.Name, field.Value)
+			}
+		case field.Type == "reset" || field.Type == "button":
+		case field.Type == "file":
+			// Without files, multipart bodies hold an empty file and others an empty name
+			files := field.Files
+			if len(files) == 0 {
+				files = []MultipartFile{{Field: field.Name}}
+			}
+			for i := range files {
+				if f.Enctype == formMultipart {
+					entries = append(entries, formEntry{name: field.Name, file: &files[i]})
+				} else {
+					add(field.Name, files[i].Filename)
+				}
+			}
+		case field.Type == "checkbox" || field.Type == "radio":
+			if field.Checked {
+				add(field.Name, field.Value)
+			}
+		case field.Tag == "select":
+			for _, o := range field.Options {
+				if o.Selected && !o.Disabled {
+					add(field.Name, o.Value)
+				}
+			}
+		default:
+			add(field.Name, field.Value)
+		}
+	}
+	return entries
+}
+
+// submit submits the form as if submitter was clicked, with the RequestOptions opts
+func (f *Form) submit(client *Client, submitter *FormField, opts ...RequestOption) (*Response, error) {
+	if f.Method == "DIALOG" {
+		return nil, ErrDialogForm
+	}
+	if f.URL == nil {
+		return nil, ErrNoBaseURL
+	}
+	entries := f.dataSet(submitter)
+	action := *f.URL
+	if f.Method == "GET" {
+		action.RawQuery = urlEncode(entries)
+		return client.response(http.MethodGet, action.String(), nil, opts...)
+	}
+
+	var body bytes.Buffer
+	contentType := f.Enctype
+	switch f.Enctype {
+	case formMultipart:
+		w := multipart.NewWriter(&body)
+		for _, e := range entries {
+			var err error
+			if e.file != nil {
+				err = writeFilePart(w, *e.file)
+			} else {
+				err = w.WriteField(e.name, e.value)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		contentType = w.FormDataContentType()
+	case formTextPlain:
+		for _, e := range entries {
+			body.WriteString(e.name + "=" + e.value + "\r\n")
+		}
+	default:
+		body.WriteString(urlEncode(entries))
+	}
+	opts = append([]RequestOption{WithHeader("Content-Type", contentType)}, opts...)
+	return client.response(http.MethodPost, action.String(), &body, opts...)
+}
+
+// urlEncode encodes the entries as application/x-www-form-urlencoded, keeping their order
+func urlEncode(entries []formEntry) string {
+	var s strings.Builder
+	for i, e := range entries {
+		if i > 0 {
+			s.WriteByte('&')
+		}
+		s.WriteString(url.QueryEscape(e.name))
+		s.WriteByte('=')
+		s.WriteString(url.QueryEscape(e.value))
+	}
+	return s.String()
+}
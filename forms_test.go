@@ -0,0 +1,151 @@
+package owl
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const formsHTML = `<html><head><base href="/app/"></head><body>
+<form id="login" action="session" method="post">
+	<input type="hidden" name="csrf" value="t0k3n">
+	<input name="user" required>
+	<input type="password" name="pass">
+	<input type="checkbox" name="remember">
+	<fieldset disabled>
+		<legend><input name="legend"></legend>
+		<input name="locked" value="x">
+	</fieldset>
+	<textarea name="note">
+Hello</textarea>
+	<select name="lang">
+		<option disabled>Pick one</option>
+		<option value="en">English</option>
+		<option>Deutsch</option>
+	</select>
+	<select name="tags" multiple>
+		<optgroup label="Birds" disabled><option>owl</option></optgroup>
+		<option selected>bat</option>
+	</select>
+	<button>Log in</button>
+</form>
+<form method="dialog" enctype="multipart/form-data"><input name="q"></form>
+<input name="outside" form="login" readonly>
+</body></html>`
+
+func TestForms(t *testing.T) {
+	doc := HTMLParseFromString(formsHTML)
+	doc.SetURL(&url.URL{Scheme: "https", Host: "example.com", Path: "/login"})
+	forms := doc.Forms()
+	require.Len(t, forms, 2)
+
+	login := forms[0]
+	require.Equal(t, "login", login.ID)
+	require.Equal(t, "session", login.Action)
+	require.Equal(t, "https://example.com/app/session", login.URL.String())
+	require.Equal(t, "POST", login.Method)
+	require.Equal(t, "application/x-www-form-urlencoded", login.Enctype)
+
+	var names []string
+	for _, f := range login.Fields {
+		names = append(names, f.Name)
+	}
+	require.Equal(t, []string{"csrf", "user", "pass", "remember", "legend", "locked", "note", "lang", "tags", "", "outside"}, names)
+
+	fields := login.Fields
+	require.Equal(t, &FormField{Tag: "input", Name: "csrf", Type: "hidden", Value: "t0k3n"}, fields[0])
+	require.Equal(t, "text", fields[1].Type)
+	require.True(t, fields[1].Required)
+	require.Equal(t, "on", fields[3].Value)
+	require.False(t, fields[3].Checked)
+	require.False(t, fields[4].Disabled)
+	require.True(t, fields[5].Disabled)
+	require.Equal(t, "Hello", fields[6].Value)
+	require.Equal(t, "select-one", fields[7].Type)
+	require.Equal(t, "en", fields[7].Value)
+	require.Equal(t, []FormOption{
+		{Value: "Pick one", Text: "Pick one", Disabled: true},
+		{Value: "en", Text: "English", Selected: true},
+		{Value: "Deutsch", Text: "Deutsch"},
+	}, fields[7].Options)
+	require.Equal(t, "select-multiple", fields[8].Type)
+	require.Equal(t, "bat", fields[8].Value)
+	require.True(t, fields[8].Options[0].Disabled)
+	require.Equal(t, "button", fields[9].Tag)
+	require.Equal(t, "submit", fields[9].Type)
+	require.True(t, fields[10].ReadOnly)
+
+	dialog := forms[1]
+	require.Equal(t, "DIALOG", dialog.Method)
+	require.Equal(t, "multipart/form-data", dialog.Enctype)
+	require.Equal(t, "https://example.com/login", dialog.URL.String())
+	require.Len(t, dialog.Fields, 1)
+
+	require.Nil(t, HTMLParseFromString(`<p>No form</p>`).Forms())
+	require.Nil(t, HTMLParseFromString(`<form action="/search"></form>`).Forms()[0].URL)
+}
+
+func TestFormSubmit(t *testing.T) {
+	var method, query, contentType, body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		method, query, contentType, body = r.Method, r.URL.RawQuery, r.Header.Get("Content-Type"), string(data)
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, `<title>Welcome</title>`)
+	}))
+	defer srv.Close()
+	base, _ := url.Parse(srv.URL + "/login")
+	client := &Client{Client: srv.Client()}
+
+	login := HTMLParseFromString(formsHTML).SetURL(base).Forms()[0]
+	require.NoError(t, login.Set("user", "hedwig"))
+	require.NoError(t, login.Set("remember", "on"))
+	require.NoError(t, login.Set("note", "line\nbreak"))
+	require.NoError(t, login.Select("lang", "Deutsch"))
+	require.NoError(t, login.Select("tags", "owl"))
+	require.Equal(t, ErrNoFormField, login.Set("missing", "x"))
+	require.Equal(t, ErrNoFormOption, login.Select("lang", "Klingon"))
+
+	resp, err := login.Submit(client)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, srv.URL+"/app/session", resp.FinalURL.String())
+	require.Equal(t, "Welcome", resp.Parse().Title().Text())
+	require.Equal(t, "POST", method)
+	require.Equal(t, "application/x-www-form-urlencoded", contentType)
+	// The disabled option of tags is not sent, the button has no name
+	require.Equal(t, "csrf=t0k3n&user=hedwig&pass=&remember=on&legend=&note=line%0D%0Abreak&lang=Deutsch&tags=bat&outside=", body)
+
+	search := HTMLParseFromString(`<form action="/search?old=1#top">
+		<input name="q" value="owls">
+		<input type="radio" name="sort" value="new" checked><input type="radio" name="sort" value="top">
+		<input type="submit" name="go" value="Search"><input type="image" name="pic">
+	</form>`).SetURL(base).Forms()[0]
+	require.NoError(t, search.Set("sort", "top"))
+	_, err = search.SubmitWith(client, "pic")
+	require.NoError(t, err)
+	require.Equal(t, "GET", method)
+	require.Equal(t, "q=owls&sort=top&pic.x=0&pic.y=0", query)
+	_, err = search.Submit(client)
+	require.NoError(t, err)
+	require.Equal(t, "q=owls&sort=top&go=Search", query)
+	_, err = search.SubmitWith(client, "nothing")
+	require.Equal(t, ErrNoFormField, err)
+
+	upload := HTMLParseFromString(`<form method="post" enctype="multipart/form-data"><input name="title" value="Owl"></form>`).SetURL(base).Forms()[0]
+	_, err = upload.Submit(client)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(contentType, "multipart/form-data; boundary="))
+	require.Contains(t, body, "name=\"title\"\r\n\r\nOwl\r\n")
+
+	_, err = HTMLParseFromString(`<form method="dialog"></form>`).SetURL(base).Forms()[0].Submit(client)
+	require.Equal(t, ErrDialogForm, err)
+	_, err = HTMLParseFromString(`<form action="relative"></form>`).Forms()[0].Submit(client)
+	require.Equal(t, ErrNoBaseURL, err)
+}
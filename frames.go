@@ -0,0 +1,65 @@
+package owl
+
+import (
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Frame describes an iframe or frame element of the document
+type Frame struct {
+	// Tag is either "iframe" or "frame"
+	Tag   string
+	Name  string
+	Title string
+	// Src is the src attribute as written in the document
+	Src string
+	// URL is Src resolved against the base of the document, nil when Src is empty or invalid
+	URL *url.URL
+	// Root holds the parsed document of the frame, it is set for iframes with a srcdoc attribute
+	// and for the frames a Crawler fetches, see Crawler.FetchFrames
+	Root *Root
+}
+
+// Frames returns every iframe and frame below the Node in document order,
+// with their sources resolved against base, or against the document's <base href> when it has one.
+// A nil base stands for the URL the document was fetched from
+func (r *Root) Frames(base *url.URL) []Frame {
+	base = documentBase(r.Node, r.pageURL(base))
+	var frames []Frame
+	walk(r.Node, func(n *html.Node) bool {
+		if n.Type != html.ElementNode || (n.Data != "iframe" && n.Data != "frame") {
+			return true
+		}
+		attrs := getKeyValue(n.Attr)
+		f := Frame{
+			Tag:   n.Data,
+			Name:  attrs["name"],
+			Title: attrs["title"],
+			Src:   strings.TrimSpace(attrs["src"]),
+		}
+		if f.Src != "" {
+			if u, err := url.Parse(f.Src); err == nil {
+				if base != nil {
+					u = base.ResolveReference(u)
+				}
+				f.URL = u
+			}
+		}
+		if srcdoc, ok := attrs["srcdoc"]; ok && n.Data == "iframe" {
+			f.Root = HTMLParseFromString(srcdoc)
+		}
+		frames = append(frames, f)
+		return false
+	})
+	return frames
+}
+
+// SameOrigin reports whether the frame source has the same scheme, host and port as page
+func (f Frame) SameOrigin(page *url.URL) bool {
+	if f.URL == nil || page == nil || !f.URL.IsAbs() {
+		return false
+	}
+	return strings.EqualFold(f.URL.Scheme, page.Scheme) && strings.EqualFold(f.URL.Host, page.Host)
+}
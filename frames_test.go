@@ -0,0 +1,99 @@
+package owl
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const framesHTML = `
+<html>
+  <body>
+    <iframe name="ad" src="https://ads.example.net/banner"></iframe>
+    <iframe title="inline" srcdoc="<p>Inline frame</p>"></iframe>
+  </body>
+</html>
+`
+
+const framesetHTML = `
+<html>
+  <frameset cols="20%,80%">
+    <frame name="menu" src="menu.html">
+    <frame name="main" src="/main.html">
+  </frameset>
+</html>
+`
+
+func TestFrames(t *testing.T) {
+	page, _ := url.Parse("https://example.com/legacy/index.html")
+	frames := HTMLParseFromString(framesHTML).Frames(page)
+	require.Len(t, frames, 2)
+
+	require.Equal(t, "ad", frames[0].Name)
+	require.False(t, frames[0].SameOrigin(page))
+
+	require.Nil(t, frames[1].URL)
+	require.NotNil(t, frames[1].Root)
+	require.Equal(t, "Inline frame", frames[1].Root.Find("p").Text())
+
+	frames = HTMLParseFromString(framesetHTML).Frames(page)
+	require.Len(t, frames, 2)
+	require.Equal(t, "frame", frames[0].Tag)
+	require.Equal(t, "https://example.com/legacy/menu.html", frames[0].URL.String())
+	require.Equal(t, "https://example.com/main.html", frames[1].URL.String())
+	require.True(t, frames[1].SameOrigin(page))
+}
+
+func TestCrawlerFetchFrames(t *testing.T) {
+	var requests atomic.Int32
+	var referer atomic.Value
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch r.URL.Path {
+		case "/":
+			fmt.Fprint(w, `<frameset><frame name="menu" src="/menu"><frame name="main" src="main.html">`+
+				`<frame name="gone" src="/gone"><frame name="ad" src="https://ads.example.net/banner"></frameset>`)
+		case "/menu":
+			fmt.Fprint(w, `<a href="/a">A</a>`)
+		case "/main.html":
+			referer.Store(r.Referer())
+			fmt.Fprint(w, `<h1>Owls</h1>`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	crawler := NewCrawler(&Client{Client: srv.Client()})
+	crawler.FetchFrames = true
+	var frames []Frame
+	crawler.OnResponse(func(resp *Response, ctx *CrawlContext) {
+		frames = ctx.Frames()
+	})
+	var errs []error
+	crawler.OnError(func(err error, ctx *CrawlContext) { errs = append(errs, err) })
+	require.NoError(t, crawler.Start(srv.URL+"/"))
+
+	require.Len(t, frames, 4)
+	require.Equal(t, "A", frames[0].Root.Find("a").Text())
+	require.Equal(t, "Owls", frames[1].Root.Find("h1").Text())
+	require.Nil(t, frames[2].Root)
+	require.Nil(t, frames[3].Root)
+	require.Len(t, errs, 1)
+	require.EqualValues(t, 4, requests.Load())
+	require.Equal(t, srv.URL+"/", referer.Load())
+
+	// Without FetchFrames the frames are listed, not fetched
+	requests.Store(0)
+	crawler = NewCrawler(&Client{Client: srv.Client()})
+	crawler.OnHTML("frameset", func(e *Root, ctx *CrawlContext) { frames = ctx.Frames() })
+	require.NoError(t, crawler.Start(srv.URL+"/"))
+	require.Len(t, frames, 4)
+	require.Nil(t, frames[0].Root)
+	require.EqualValues(t, 1, requests.Load())
+}
@@ -0,0 +1,184 @@
+package owl
+
+import (
+	"container/heap"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Frontier is a Store in memory visiting the pages with the highest Priority first. Among pages
+// of the same priority, hosts take turns so a large site does not hold the crawl of the others back,
+// and the pages of a host are visited in the order they were queued
+type Frontier struct {
+	mu      sync.Mutex
+	visited map[string]bool
+	hosts   map[string]*frontierHost
+	// seq orders the requests and the turns of the hosts
+	seq  uint64
+	size int
+}
+
+// frontierHost is the queue of a host
+type frontierHost struct {
+	queue frontierQueue
+	// turn is when the host was first queued or last dequeued from, hosts with an earlier turn go first
+	turn uint64
+}
+
+type frontierItem struct {
+	req CrawlRequest
+	seq uint64
+}
+
+// frontierQueue is a heap of requests, the highest priority first and then the first queued
+type frontierQueue []frontierItem
+
+func (q frontierQueue) Len() int { return len(q) }
+func (q frontierQueue) Less(i, j int) bool {
+	if q[i].req.Priority != q[j].req.Priority {
+		return q[i].req.Priority > q[j].req.Priority
+	}
+	return q[i].seq < q[j].seq
+}
+func (q frontierQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *frontierQueue) Push(x interface{}) { *q = append(*q, x.(frontierItem)) }
+func (q *frontierQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
+
+var _ Store = (*Frontier)(nil)
+
+// NewFrontier returns an empty Frontier
+func NewFrontier() *Frontier {
+	return &Frontier{visited: make(map[string]bool), hosts: make(map[string]*frontierHost)}
+}
+
+// Visited implements Store
+func (f *Frontier) Visited(url string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.visited[url], nil
+}
+
+// MarkVisited implements Store
+func (f *Frontier) MarkVisited(url string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.visited[url] {
+		return false, nil
+	}
+	f.visited[url] = true
+	return true, nil
+}
+
+// Enqueue implements Store
+func (f *Frontier) Enqueue(req CrawlRequest) error {
+	var host string
+	if u, err := url.Parse(req.URL); err == nil {
+		host = strings.ToLower(u.Host)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.seq++
+	h := f.hosts[host]
+	if h == nil {
+		h = &frontierHost{turn: f.seq}
+		f.hosts[host] = h
+	}
+	heap.Push(&h.queue, frontierItem{req: req, seq: f.seq})
+	f.size++
+	return nil
+}
+
+// Dequeue implements Store
+func (f *Frontier) Dequeue() (CrawlRequest, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var best *frontierHost
+	for _, h := range f.hosts {
+		if len(h.queue) == 0 {
+			continue
+		}
+		if best == nil {
+			best = h
+			continue
+		}
+		p, bp := h.queue[0].req.Priority, best.queue[0].req.Priority
+		if p > bp || p == bp && h.turn < best.turn {
+			best = h
+		}
+	}
+	if best == nil {
+		return CrawlRequest{}, false, nil
+	}
+	item := heap.Pop(&best.queue).(frontierItem)
+	f.seq++
+	best.turn = f.seq
+	f.size--
+	return item.req, true, nil
+}
+
+// Len returns the number of queued requests
+func (f *Frontier) Len() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.size
+}
+
+// Glob returns the regular expression matching the URLs matched by the glob pattern, for the
+// AllowURLs and DenyURLs of a Crawler: * matches anything but a slash, ** anything and ? one character
+// other than a slash. Patterns starting with a slash match the path of the URLs along with their query,
+// such as /product/**, the others whole URLs, such as https://*.example.com/**.
+// Characters other than ASCII match themselves as well as their percent-encoding, as found in normalized URLs
+func Glob(pattern string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^")
+	if strings.HasPrefix(pattern, "/") {
+		b.WriteString(`[a-zA-Z][a-zA-Z0-9+.-]*://[^/?#]*`)
+	}
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		switch c := runes[i]; {
+		case c == '*' && i+1 < len(runes) && runes[i+1] == '*':
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c >= utf8.RuneSelf:
+			b.WriteString("(?:" + regexp.QuoteMeta(string(c)) + "|" + regexp.QuoteMeta(url.PathEscape(string(c))) + ")")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+// snapshot returns the visited URLs and the queue in the order it was queued, for Crawler.Checkpoint
+func (f *Frontier) snapshot() ([]string, []CrawlRequest) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	visited := make([]string, 0, len(f.visited))
+	for u := range f.visited {
+		visited = append(visited, u)
+	}
+	items := make([]frontierItem, 0, f.size)
+	for _, h := range f.hosts {
+		items = append(items, h.queue...)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].seq < items[j].seq })
+	queue := make([]CrawlRequest, len(items))
+	for i, item := range items {
+		queue[i] = item.req
+	}
+	return visited, queue
+}
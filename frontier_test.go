@@ -0,0 +1,117 @@
+package owl
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrontier(t *testing.T) {
+	frontier := NewFrontier()
+	for _, req := range []CrawlRequest{
+		{URL: "https://a.example/1"},
+		{URL: "https://a.example/2"},
+		{URL: "https://a.example/3"},
+		{URL: "https://b.example/1"},
+		{URL: "https://b.example/2"},
+		{URL: "https://c.example/1", Priority: -1},
+		{URL: "https://c.example/urgent", Priority: 5},
+	} {
+		require.NoError(t, frontier.Enqueue(req))
+	}
+	require.Equal(t, 7, frontier.Len())
+	var order []string
+	for {
+		req, ok, err := frontier.Dequeue()
+		require.NoError(t, err)
+		if !ok {
+			break
+		}
+		order = append(order, req.URL)
+	}
+	// The hosts take turns, in the order they were first queued
+	require.Equal(t, []string{
+		"https://c.example/urgent",
+		"https://a.example/1",
+		"https://b.example/1",
+		"https://a.example/2",
+		"https://b.example/2",
+		"https://a.example/3",
+		"https://c.example/1",
+	}, order)
+	require.Zero(t, frontier.Len())
+}
+
+func TestGlob(t *testing.T) {
+	for _, c := range []struct {
+		pattern, url string
+		match        bool
+	}{
+		{"/product/*", "https://shop.example/product/42", true},
+		{"/product/*", "https://shop.example/product/42?color=red", true},
+		{"/product/*", "https://shop.example/product/42/reviews", false},
+		{"/product/**", "https://shop.example/product/42/reviews", true},
+		{"/product/**", "https://shop.example/products", false},
+		{"/page?", "https://shop.example/page2", true},
+		{"/page?", "https://shop.example/page/", false},
+		{"/produkt/ü/*", "https://shop.example/produkt/ü/42", true},
+		{"/produkt/ü/*", "https://shop.example/produkt/%C3%BC/42", true},
+		{"/produkt/ü/*", "https://shop.example/produkt/u/42", false},
+		{"/日本?", "https://shop.example/日本語", true},
+		{"https://*.example.com/**", "https://www.example.com/a/b", true},
+		{"https://*.example.com/**", "https://example.org/a", false},
+	} {
+		require.Equal(t, c.match, Glob(c.pattern).MatchString(c.url), c.pattern+" "+c.url)
+	}
+}
+
+func TestCrawlerFocused(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<a href="/product/1">1</a><a href="/product/2">2</a><a href="/product/2/reviews">reviews</a>
+			<a href="/about">about</a><a href="/product/3">3</a><a href="/product/4?print=1">print</a>`)
+	}))
+	defer srv.Close()
+	crawler := NewCrawler(&Client{Client: srv.Client()})
+	crawler.AllowURLs = []*regexp.Regexp{Glob("/product/*"), Glob("/")}
+	crawler.DenyURLs = []*regexp.Regexp{regexp.MustCompile(`[?&]print=`)}
+	crawler.MaxPages = 3
+	crawler.Priority = func(req CrawlRequest) int {
+		// The highest product numbers first
+		return int(req.URL[len(req.URL)-1])
+	}
+	var visited []string
+	errs := make(map[string]error)
+	crawler.OnHTML("a[href]", func(e *Root, ctx *CrawlContext) {
+		href, _ := e.Attr("href")
+		if err := ctx.Visit(href); err != nil && !errors.Is(err, ErrAlreadyVisited) {
+			errs[href] = err
+		}
+	})
+	crawler.OnResponse(func(resp *Response, ctx *CrawlContext) {
+		visited = append(visited, resp.FinalURL.Path)
+	})
+	require.NoError(t, crawler.Start(srv.URL+"/"))
+	require.Equal(t, []string{"/", "/product/2", "/product/1"}, visited)
+	require.ErrorIs(t, errs["/product/2/reviews"], ErrFilteredURL)
+	require.ErrorIs(t, errs["/about"], ErrFilteredURL)
+	require.ErrorIs(t, errs["/product/4?print=1"], ErrFilteredURL)
+	require.ErrorIs(t, errs["/product/3"], ErrMaxPages)
+	require.True(t, strings.HasPrefix(errs["/about"].Error(), "owl: URL filtered out: "))
+
+	crawler = NewCrawler(&Client{Client: srv.Client()})
+	crawler.MaxHostPages = 2
+	crawler.OnHTML("a[href]", func(e *Root, ctx *CrawlContext) {
+		href, _ := e.Attr("href")
+		ctx.Visit(href)
+	})
+	pages := 0
+	crawler.OnResponse(func(resp *Response, ctx *CrawlContext) { pages++ })
+	require.NoError(t, crawler.Start(srv.URL+"/"))
+	require.Equal(t, 2, pages)
+}
@@ -1,17 +1,24 @@
 module github.com/Patrickmitech/owl
 
-go 1.17
+go 1.23
 
 require golang.org/x/net v0.0.0-20220403103023-749bd193bc2b
 
 require (
-	github.com/gobwas/glob v0.2.3
-	github.com/stretchr/testify v1.7.1
+	github.com/andybalholm/brotli v1.1.1
+	github.com/andybalholm/cascadia v1.3.1
+	github.com/klauspost/compress v1.17.11
+	github.com/stretchr/testify v1.9.0
+	go.opentelemetry.io/otel v1.31.0
+	go.opentelemetry.io/otel/trace v1.31.0
 )
 
 require (
-	github.com/davecgh/go-spew v1.1.0 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/go-logr/logr v1.4.2 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
+	go.opentelemetry.io/otel/metric v1.31.0 // indirect
 	golang.org/x/text v0.3.7 // indirect
-	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,364 @@
+// Package har exports the requests and responses of an owl.Client as HTTP Archive (HAR 1.2) files,
+// and reads HAR files, such as browser devtools captures, back into documents or replays them
+package har
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Patrickmitech/owl"
+)
+
+// HAR is an HTTP Archive
+type HAR struct {
+	Log Log `json:"log"`
+}
+
+// Log is the root of an HTTP Archive
+type Log struct {
+	Version string  `json:"version"`
+	Creator Creator `json:"creator"`
+	Entries []Entry `json:"entries"`
+}
+
+// Creator names the application that wrote the archive
+type Creator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// Entry is a request with its response
+type Entry struct {
+	StartedDateTime time.Time `json:"startedDateTime"`
+	// Time is the duration of the request in milliseconds
+	Time     float64  `json:"time"`
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+	Cache    struct{} `json:"cache"`
+	Timings  Timings  `json:"timings"`
+}
+
+// Request is a recorded request
+type Request struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []Cookie    `json:"cookies"`
+	Headers     []NameValue `json:"headers"`
+	QueryString []NameValue `json:"queryString"`
+	PostData    *PostData   `json:"postData,omitempty"`
+	HeadersSize int64       `json:"headersSize"`
+	BodySize    int64       `json:"bodySize"`
+}
+
+// Response is a recorded response
+type Response struct {
+	Status      int         `json:"status"`
+	StatusText  string      `json:"statusText"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []Cookie    `json:"cookies"`
+	Headers     []NameValue `json:"headers"`
+	Content     Content     `json:"content"`
+	RedirectURL string      `json:"redirectURL"`
+	HeadersSize int64       `json:"headersSize"`
+	BodySize    int64       `json:"bodySize"`
+}
+
+// Cookie is a cookie sent with a request or set by a response
+type Cookie struct {
+	Name     string     `json:"name"`
+	Value    string     `json:"value"`
+	Path     string     `json:"path,omitempty"`
+	Domain   string     `json:"domain,omitempty"`
+	Expires  *time.Time `json:"expires,omitempty"`
+	HTTPOnly bool       `json:"httpOnly,omitempty"`
+	Secure   bool       `json:"secure,omitempty"`
+}
+
+// NameValue is a header or a query parameter
+type NameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// PostData is the body of a request
+type PostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+// Content is the body of a response, decoded from its Content-Encoding
+type Content struct {
+	Size     int64  `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	// Encoding is "base64" when Text is encoded in base64
+	Encoding string `json:"encoding,omitempty"`
+}
+
+// Timings splits the time of an entry in milliseconds, -1 for the phases that do not apply
+type Timings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// Parse reads an HTTP Archive from r
+func Parse(r io.Reader) (*HAR, error) {
+	h := &HAR{}
+	if err := json.NewDecoder(r).Decode(h); err != nil {
+		return nil, fmt.Errorf("har: %w", err)
+	}
+	return h, nil
+}
+
+// Write writes the archive to w as indented JSON
+func (h *HAR) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(h)
+}
+
+// Body returns the decoded content of the response
+func (c *Content) Body() ([]byte, error) {
+	if c.Encoding == "base64" {
+		return base64.StdEncoding.DecodeString(c.Text)
+	}
+	return []byte(c.Text), nil
+}
+
+// Documents parses the HTML responses of the archive in order, every Root records the URL of its request
+func (h *HAR) Documents() []*owl.Root {
+	var docs []*owl.Root
+	for i := range h.Log.Entries {
+		entry := &h.Log.Entries[i]
+		mediaType, _, _ := mime.ParseMediaType(entry.Response.Content.MimeType)
+		if mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+			continue
+		}
+		body, err := entry.Response.Content.Body()
+		if err != nil {
+			continue
+		}
+		u, err := url.Parse(entry.Request.URL)
+		if err != nil {
+			continue
+		}
+		contentType := entry.Response.Content.MimeType
+		if entry.Response.Content.Encoding == "" {
+			// Text is already decoded to UTF-8
+			contentType = mediaType + "; charset=utf-8"
+		}
+		r := &owl.Response{StatusCode: entry.Response.Status, ContentType: contentType, FinalURL: u, Body: body}
+		docs = append(docs, r.Parse())
+	}
+	return docs
+}
+
+// Transport returns an http.RoundTripper answering the requests of the archive with their recorded responses,
+// matched by method and URL. Other requests get a 404 Not Found
+func (h *HAR) Transport() http.RoundTripper {
+	return replayer{h}
+}
+
+type replayer struct {
+	h *HAR
+}
+
+func (r replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	for i := range r.h.Log.Entries {
+		entry := &r.h.Log.Entries[i]
+		if entry.Request.Method != req.Method || entry.Request.URL != req.URL.String() {
+			continue
+		}
+		body, err := entry.Response.Content.Body()
+		if err != nil {
+			return nil, fmt.Errorf("har: %s %s: %w", req.Method, req.URL, err)
+		}
+		header := make(http.Header)
+		for _, h := range entry.Response.Headers {
+			header.Add(h.Name, h.Value)
+		}
+		// The content is recorded decoded
+		header.Del("Content-Encoding")
+		header.Del("Content-Length")
+		return newResponse(req, entry.Response.Status, header, body), nil
+	}
+	return newResponse(req, http.StatusNotFound, make(http.Header), nil), nil
+}
+
+func newResponse(req *http.Request, status int, header http.Header, body []byte) *http.Response {
+	return &http.Response{
+		Status:        strconv.Itoa(status) + " " + http.StatusText(status),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// Recorder is an http.RoundTripper recording the requests it sends. Response bodies are read whole
+// before they are returned
+type Recorder struct {
+	// Transport sends the requests, http.DefaultTransport when nil
+	Transport http.RoundTripper
+
+	mu      sync.Mutex
+	entries []Entry
+}
+
+var _ http.RoundTripper = (*Recorder)(nil)
+
+// Record makes the http.Client of c send its requests through a new Recorder and returns it.
+// Proxies and TLS options must be set on c before
+func Record(c *owl.Client) *Recorder {
+	if c.Client == nil {
+		c.Client = &http.Client{}
+	}
+	r := &Recorder{Transport: c.Client.Transport}
+	c.Client.Transport = r
+	return r
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	entry := Entry{StartedDateTime: time.Now(), Request: newRequest(req)}
+	if req.Body != nil && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			data, _ := io.ReadAll(body)
+			body.Close()
+			entry.Request.BodySize = int64(len(data))
+			entry.Request.PostData = &PostData{MimeType: req.Header.Get("Content-Type"), Text: string(data)}
+		}
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	waited := time.Now()
+	raw, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	received := time.Now()
+	resp.Body = io.NopCloser(bytes.NewReader(raw))
+
+	entry.Response = newResponseEntry(resp, raw)
+	entry.Timings = Timings{
+		Send:    0,
+		Wait:    milliseconds(waited.Sub(entry.StartedDateTime)),
+		Receive: milliseconds(received.Sub(waited)),
+	}
+	entry.Time = entry.Timings.Wait + entry.Timings.Receive
+	r.mu.Lock()
+	r.entries = append(r.entries, entry)
+	r.mu.Unlock()
+	return resp, nil
+}
+
+// HAR returns the archive of the requests recorded so far
+func (r *Recorder) HAR() *HAR {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return &HAR{Log: Log{
+		Version: "1.2",
+		Creator: Creator{Name: "owl", Version: "1"},
+		Entries: append([]Entry{}, r.entries...),
+	}}
+}
+
+func newRequest(req *http.Request) Request {
+	r := Request{
+		Method:      req.Method,
+		URL:         req.URL.String(),
+		HTTPVersion: "HTTP/1.1",
+		Cookies:     []Cookie{},
+		Headers:     nameValues(req.Header),
+		HeadersSize: -1,
+	}
+	for _, c := range req.Cookies() {
+		r.Cookies = append(r.Cookies, Cookie{Name: c.Name, Value: c.Value})
+	}
+	r.QueryString = nameValues(http.Header(req.URL.Query()))
+	return r
+}
+
+func newResponseEntry(resp *http.Response, raw []byte) Response {
+	r := Response{
+		Status:      resp.StatusCode,
+		StatusText:  http.StatusText(resp.StatusCode),
+		HTTPVersion: resp.Proto,
+		Cookies:     []Cookie{},
+		Headers:     nameValues(resp.Header),
+		RedirectURL: resp.Header.Get("Location"),
+		HeadersSize: -1,
+		BodySize:    int64(len(raw)),
+	}
+	if r.HTTPVersion == "" {
+		r.HTTPVersion = "HTTP/1.1"
+	}
+	for _, c := range resp.Cookies() {
+		cookie := Cookie{Name: c.Name, Value: c.Value, Path: c.Path, Domain: c.Domain, HTTPOnly: c.HttpOnly, Secure: c.Secure}
+		if !c.Expires.IsZero() {
+			expires := c.Expires
+			cookie.Expires = &expires
+		}
+		r.Cookies = append(r.Cookies, cookie)
+	}
+
+	// The content is recorded decoded, like browsers do
+	decoded := &http.Response{Header: resp.Header.Clone(), Body: io.NopCloser(bytes.NewReader(raw)), Request: resp.Request}
+	owl.DecodeContent(decoded)
+	body, err := io.ReadAll(decoded.Body)
+	if err != nil {
+		body = raw
+	}
+	r.Content = Content{Size: int64(len(body)), MimeType: resp.Header.Get("Content-Type")}
+	if utf8.Valid(body) {
+		r.Content.Text = string(body)
+	} else {
+		r.Content.Text = base64.StdEncoding.EncodeToString(body)
+		r.Content.Encoding = "base64"
+	}
+	return r
+}
+
+// nameValues lists the values of header, or of a query, sorted by name
+func nameValues(header http.Header) []NameValue {
+	list := []NameValue{}
+	for name, values := range header {
+		for _, value := range values {
+			list = append(list, NameValue{Name: name, Value: value})
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
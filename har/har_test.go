@@ -0,0 +1,111 @@
+package har
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Patrickmitech/owl"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecord(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/zipped":
+			w.Header().Set("Content-Type", "text/html")
+			w.Header().Set("Content-Encoding", "gzip")
+			gz := gzip.NewWriter(w)
+			gz.Write([]byte(`<html><title>Zipped</title><a href="/next">next</a></html>`))
+			gz.Close()
+		case "/login":
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc", Path: "/"})
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte("welcome"))
+		case "/image":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte{0x89, 'P', 'N', 'G', 0xff})
+		}
+	}))
+	defer srv.Close()
+
+	client := &owl.Client{Client: srv.Client()}
+	recorder := Record(client)
+	doc, err := client.GetDocument(srv.URL + "/zipped?page=2&lang=en")
+	require.NoError(t, err)
+	require.Equal(t, "Zipped", doc.Find("title").Text())
+	_, err = client.Post(srv.URL+"/login", "application/x-www-form-urlencoded", "user=owl")
+	require.NoError(t, err)
+	_, err = client.GetResponse(srv.URL + "/image")
+	require.NoError(t, err)
+
+	archive := recorder.HAR()
+	require.Equal(t, "1.2", archive.Log.Version)
+	require.Len(t, archive.Log.Entries, 3)
+	zipped := archive.Log.Entries[0]
+	require.Equal(t, "GET", zipped.Request.Method)
+	require.Equal(t, []NameValue{{Name: "lang", Value: "en"}, {Name: "page", Value: "2"}}, zipped.Request.QueryString)
+	require.Equal(t, 200, zipped.Response.Status)
+	require.Contains(t, zipped.Response.Content.Text, "<title>Zipped</title>")
+	require.Greater(t, zipped.Response.BodySize, int64(0))
+	require.NotEqual(t, zipped.Response.BodySize, zipped.Response.Content.Size)
+	login := archive.Log.Entries[1]
+	require.Equal(t, &PostData{MimeType: "application/x-www-form-urlencoded", Text: "user=owl"}, login.Request.PostData)
+	require.Equal(t, "session", login.Response.Cookies[0].Name)
+	image := archive.Log.Entries[2]
+	require.Equal(t, "base64", image.Response.Content.Encoding)
+	body, err := image.Response.Content.Body()
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x89, 'P', 'N', 'G', 0xff}, body)
+
+	var buf bytes.Buffer
+	require.NoError(t, archive.Write(&buf))
+	parsed, err := Parse(&buf)
+	require.NoError(t, err)
+	require.Len(t, parsed.Log.Entries, 3)
+
+	docs := parsed.Documents()
+	require.Len(t, docs, 1)
+	require.Equal(t, "Zipped", docs[0].Find("title").Text())
+	require.Equal(t, srv.URL+"/zipped?page=2&lang=en", docs[0].URL().String())
+
+	replayed := &owl.Client{Client: &http.Client{Transport: parsed.Transport()}}
+	doc, err = replayed.GetDocument(srv.URL + "/zipped?page=2&lang=en")
+	require.NoError(t, err)
+	require.Equal(t, "Zipped", doc.Find("title").Text())
+	resp, err := replayed.GetResponse(srv.URL + "/unknown")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestParse(t *testing.T) {
+	// A capture as written by browser devtools
+	capture := `{"log": {"version": "1.2", "creator": {"name": "WebInspector", "version": "537.36"},
+	"entries": [{
+		"startedDateTime": "2024-01-02T10:00:00.000Z", "time": 120.5,
+		"request": {"method": "GET", "url": "https://example.com/", "httpVersion": "http/2.0",
+			"headers": [{"name": ":authority", "value": "example.com"}], "queryString": [], "cookies": [],
+			"headersSize": -1, "bodySize": 0},
+		"response": {"status": 200, "statusText": "", "httpVersion": "http/2.0",
+			"headers": [{"name": "content-encoding", "value": "br"}], "cookies": [],
+			"content": {"size": 60, "mimeType": "text/html", "text": "<html><title>Café</title></html>"},
+			"redirectURL": "", "headersSize": -1, "bodySize": 40},
+		"cache": {}, "timings": {"blocked": 1, "dns": -1, "send": 0.2, "wait": 100, "receive": 20}
+	}]}}`
+	archive, err := Parse(strings.NewReader(capture))
+	require.NoError(t, err)
+	docs := archive.Documents()
+	require.Len(t, docs, 1)
+	require.Equal(t, "Café", docs[0].Find("title").Text())
+
+	client := &owl.Client{Client: &http.Client{Transport: archive.Transport()}}
+	doc, err := client.GetDocument("https://example.com/")
+	require.NoError(t, err)
+	require.Equal(t, "Café", doc.Find("title").Text())
+
+	_, err = Parse(strings.NewReader("{"))
+	require.Error(t, err)
+}
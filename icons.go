@@ -0,0 +1,221 @@
+package owl
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// ErrNoIcon is returned by Client.BestIcon when no icon of the page could be downloaded
+var ErrNoIcon = errors.New("owl: no icon found")
+
+// Icon is a favicon or app icon of a page
+type Icon struct {
+	URL *url.URL
+	// Rel is the rel of the link element, such as "icon" or "apple-touch-icon", or "manifest"
+	// for icons listed in the web app manifest
+	Rel  string
+	Type string
+	// Sizes lists the sizes of the icon, AnySize is set for scalable icons declared with sizes="any"
+	Sizes   []IconSize
+	AnySize bool
+}
+
+// IconSize is a size of an icon in pixels
+type IconSize struct {
+	Width  int
+	Height int
+}
+
+// iconRels are the rel values of links to icons
+var iconRels = []string{"icon", "apple-touch-icon", "apple-touch-icon-precomposed", "mask-icon", "fluid-icon"}
+
+// Icons returns the icons the document declares with link elements below the Node in document order,
+// with their URLs resolved against base or the document's <base href>. A nil base stands for the URL the document was fetched from.
+// Icons of the web app manifest are left out, see Client.BestIcon
+func (r *Root) Icons(base *url.URL) []Icon {
+	base = documentBase(r.Node, r.pageURL(base))
+	var icons []Icon
+	walk(r.Node, func(n *html.Node) bool {
+		if n.Type != html.ElementNode || n.Data != "link" {
+			return true
+		}
+		href := strings.TrimSpace(attrValue(n, "href"))
+		if href == "" {
+			return true
+		}
+		var rel string
+		for _, v := range strings.Fields(strings.ToLower(attrValue(n, "rel"))) {
+			if containsString(iconRels, v) {
+				rel = v
+				break
+			}
+		}
+		if rel == "" {
+			return true
+		}
+		u, err := url.Parse(href)
+		if err != nil {
+			return true
+		}
+		if base != nil {
+			u = base.ResolveReference(u)
+		}
+		icon := Icon{URL: u, Rel: rel, Type: strings.TrimSpace(attrValue(n, "type"))}
+		icon.Sizes, icon.AnySize = parseSizes(attrValue(n, "sizes"))
+		icons = append(icons, icon)
+		return true
+	})
+	return icons
+}
+
+// Width returns the largest width among the sizes of the icon, 0 when they are unknown
+func (i Icon) Width() int {
+	width := 0
+	for _, s := range i.Sizes {
+		if s.Width > width {
+			width = s.Width
+		}
+	}
+	return width
+}
+
+// parseSizes parses a sizes attribute such as "16x16 32x32" or "any"
+func parseSizes(v string) ([]IconSize, bool) {
+	var sizes []IconSize
+	anySize := false
+	for _, s := range strings.Fields(strings.ToLower(v)) {
+		if s == "any" {
+			anySize = true
+			continue
+		}
+		w, h, ok := strings.Cut(s, "x")
+		if !ok {
+			continue
+		}
+		if size := (IconSize{Width: atoi(w), Height: atoi(h)}); size.Width > 0 && size.Height > 0 {
+			sizes = append(sizes, size)
+		}
+	}
+	return sizes, anySize
+}
+
+// BestIcon downloads the icon of page best suited to display at size pixels: a scalable icon,
+// else the smallest icon at least that large, else the largest one. The icons of the web app manifest
+// of the page are considered too, and /favicon.ico is tried when the page declares no icon.
+// Candidates that fail to download are skipped
+func (c *Client) BestIcon(page *Root, base *url.URL, size int) (Icon, []byte, error) {
+	icons := page.Icons(base)
+	if manifest := page.Find("link", "rel", "manifest"); manifest.Error == nil {
+		if u, err := page.resolveIconURL(base, manifest.AttrOr("href", "")); err == nil {
+			if data, _, err := c.getBytes(u.String()); err == nil {
+				icons = append(icons, manifestIcons(data, u)...)
+			}
+		}
+	}
+	if len(icons) == 0 {
+		if u, err := page.resolveIconURL(base, "/favicon.ico"); err == nil {
+			icons = append(icons, Icon{URL: u, Rel: "icon"})
+		}
+	}
+	for _, icon := range rankIcons(icons, size) {
+		if !icon.URL.IsAbs() {
+			continue
+		}
+		data, contentType, err := c.getBytes(icon.URL.String())
+		if err != nil || len(data) == 0 {
+			continue
+		}
+		if icon.Type == "" {
+			icon.Type = contentType
+		}
+		return icon, data, nil
+	}
+	return Icon{}, nil, ErrNoIcon
+}
+
+// resolveIconURL resolves href like Icons resolves the href of links
+func (r *Root) resolveIconURL(base *url.URL, href string) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimSpace(href))
+	if err != nil {
+		return nil, err
+	}
+	if base = documentBase(r.Node, r.pageURL(base)); base != nil {
+		u = base.ResolveReference(u)
+	}
+	if !u.IsAbs() {
+		return nil, ErrNoBaseURL
+	}
+	return u, nil
+}
+
+// rankIcons orders icons from the best suited to display at size pixels to the least,
+// mask icons are left out as they are monochrome
+func rankIcons(icons []Icon, size int) []Icon {
+	score := func(i Icon) int {
+		switch w := i.Width(); {
+		case i.AnySize || strings.HasSuffix(i.URL.Path, ".svg"):
+			return 1 << 30
+		case w >= size:
+			// The smallest icon large enough is the best
+			return 1<<29 - w
+		case w > 0:
+			return w
+		}
+		return 0
+	}
+	var ranked []Icon
+	for _, icon := range icons {
+		if icon.Rel != "mask-icon" {
+			ranked = append(ranked, icon)
+		}
+	}
+	sort.SliceStable(ranked, func(a, b int) bool { return score(ranked[a]) > score(ranked[b]) })
+	return ranked
+}
+
+// manifestIcons returns the icons of a web app manifest, resolved against the URL of the manifest
+func manifestIcons(data []byte, manifest *url.URL) []Icon {
+	var m struct {
+		Icons []struct {
+			Src   string `json:"src"`
+			Sizes string `json:"sizes"`
+			Type  string `json:"type"`
+		} `json:"icons"`
+	}
+	if json.Unmarshal(data, &m) != nil {
+		return nil
+	}
+	var icons []Icon
+	for _, i := range m.Icons {
+		u, err := url.Parse(strings.TrimSpace(i.Src))
+		if err != nil || i.Src == "" {
+			continue
+		}
+		icon := Icon{URL: manifest.ResolveReference(u), Rel: "manifest", Type: i.Type}
+		icon.Sizes, icon.AnySize = parseSizes(i.Sizes)
+		icons = append(icons, icon)
+	}
+	return icons
+}
+
+// getBytes reads the body of a successful GET request to url along with its Content-Type
+func (c *Client) getBytes(url string) ([]byte, string, error) {
+	resp, release, err := c.do(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	defer release()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("owl: GET %s: %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	return data, resp.Header.Get("Content-Type"), err
+}
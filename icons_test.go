@@ -0,0 +1,87 @@
+package owl
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const iconsPage = `<html><head>
+	<link rel="shortcut icon" href="/favicon.ico">
+	<link rel="icon" type="image/png" sizes="16x16 32x32" href="icon-32.png">
+	<link rel="apple-touch-icon" sizes="180x180" href="/apple.png">
+	<link rel="mask-icon" href="/mask.svg" color="#000">
+	<link rel="manifest" href="/site.webmanifest">
+	<link rel="stylesheet" href="/style.css">
+</head><body></body></html>`
+
+func TestIcons(t *testing.T) {
+	base, _ := url.Parse("https://example.com/blog/")
+	icons := HTMLParseFromString(iconsPage).Icons(base)
+	require.Len(t, icons, 4)
+	require.Equal(t, "https://example.com/favicon.ico", icons[0].URL.String())
+	require.Equal(t, "icon", icons[0].Rel)
+	require.Equal(t, "https://example.com/blog/icon-32.png", icons[1].URL.String())
+	require.Equal(t, []IconSize{{16, 16}, {32, 32}}, icons[1].Sizes)
+	require.Equal(t, 32, icons[1].Width())
+	require.Equal(t, "apple-touch-icon", icons[2].Rel)
+	require.Equal(t, "mask-icon", icons[3].Rel)
+
+	sizes, anySize := parseSizes("any 48X48 bogus 0x0")
+	require.True(t, anySize)
+	require.Equal(t, []IconSize{{48, 48}}, sizes)
+}
+
+func TestBestIcon(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(iconsPage))
+	})
+	mux.HandleFunc("/site.webmanifest", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"icons": [{"src": "icons/192.png", "sizes": "192x192", "type": "image/png"},
+			{"src": "icons/512.png", "sizes": "512x512", "type": "image/png"}]}`))
+	})
+	mux.HandleFunc("/apple.png", func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("apple")) })
+	mux.HandleFunc("/icons/512.png", func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("512")) })
+	mux.HandleFunc("/favicon.ico", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/x-icon")
+		w.Write([]byte("ico"))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := HttpClientWrapper(srv.Client())
+	client.RequestTimeout = 5 * time.Second
+	page, err := client.GetDocument(srv.URL + "/")
+	require.NoError(t, err)
+
+	// 180x180 is the smallest icon large enough for 128 pixels
+	icon, data, err := client.BestIcon(page, nil, 128)
+	require.NoError(t, err)
+	require.Equal(t, "apple", string(data))
+	require.Equal(t, "apple-touch-icon", icon.Rel)
+
+	// 192.png is the best fit for 190 pixels but is missing, 512.png is next
+	icon, data, err = client.BestIcon(page, nil, 190)
+	require.NoError(t, err)
+	require.Equal(t, "512", string(data))
+	require.Equal(t, "manifest", icon.Rel)
+
+	// Without declared icons the favicon.ico of the site is used
+	bare := HTMLParseFromString(`<html><head></head></html>`).SetURL(page.URL())
+	icon, data, err = client.BestIcon(bare, nil, 16)
+	require.NoError(t, err)
+	require.Equal(t, "ico", string(data))
+	require.Equal(t, "image/x-icon", icon.Type)
+
+	_, _, err = client.BestIcon(HTMLParseFromString(`<html></html>`), nil, 16)
+	require.ErrorIs(t, err, ErrNoIcon)
+}
@@ -0,0 +1,177 @@
+package owl
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Image is an img element of the document
+type Image struct {
+	// Src is the src attribute as written in the document
+	Src string
+	// URL is the image source resolved against the base of the document. Lazy-loading attributes
+	// such as data-src take precedence over src, which usually holds a placeholder then
+	URL *url.URL
+	Alt string
+	// Width and Height come from the attributes of the element, 0 when missing
+	Width  int
+	Height int
+	// Srcset holds the candidates of srcset or data-srcset, followed by those of
+	// the source elements when the image is inside a picture element
+	Srcset []ImageCandidate
+	// Lazy is set when the image has loading="lazy" or a lazy-loading attribute
+	Lazy bool
+}
+
+// ImageCandidate is an image source of a srcset
+type ImageCandidate struct {
+	URL *url.URL
+	// Width is the w descriptor, 0 when the candidate has none
+	Width int
+	// Density is the x descriptor, 1 when the candidate has neither w nor x descriptors
+	Density float64
+	// Media and Type come from the source element holding the srcset
+	Media string
+	Type  string
+}
+
+// Images returns every img element below the Node in document order.
+// URLs are resolved against base, or against the document's <base href> when it has one.
+// A nil base stands for the URL the document was fetched from
+func (r *Root) Images(base *url.URL) []Image {
+	base = documentBase(r.Node, r.pageURL(base))
+	resolve := func(ref string) *url.URL {
+		u, err := url.Parse(strings.TrimSpace(ref))
+		if err != nil {
+			return nil
+		}
+		if base != nil {
+			u = base.ResolveReference(u)
+		}
+		return u
+	}
+
+	var images []Image
+	walk(r.Node, func(n *html.Node) bool {
+		if n.Type != html.ElementNode || n.Data != "img" {
+			return true
+		}
+		img := Image{
+			Src:    attrValue(n, "src"),
+			Alt:    attrValue(n, "alt"),
+			Width:  atoi(attrValue(n, "width")),
+			Height: atoi(attrValue(n, "height")),
+			Lazy:   strings.EqualFold(attrValue(n, "loading"), "lazy"),
+		}
+		src := strings.TrimSpace(img.Src)
+		if lazy := firstAttr(n, lazySrcAttributes); lazy != "" {
+			src, img.Lazy = lazy, true
+		}
+		if src != "" {
+			img.URL = resolve(src)
+		}
+		srcset := attrValue(n, "srcset")
+		if lazy := firstAttr(n, lazySrcsetAttributes); lazy != "" {
+			srcset, img.Lazy = lazy, true
+		}
+		img.Srcset = parseSrcset(srcset, resolve, "", "")
+		if n.Parent != nil && n.Parent.Type == html.ElementNode && n.Parent.Data == "picture" {
+			for s := n.Parent.FirstChild; s != nil; s = s.NextSibling {
+				if s.Type != html.ElementNode || s.Data != "source" {
+					continue
+				}
+				srcset := attrValue(s, "srcset")
+				if lazy := firstAttr(s, lazySrcsetAttributes); lazy != "" {
+					srcset = lazy
+				}
+				img.Srcset = append(img.Srcset, parseSrcset(srcset, resolve, attrValue(s, "media"), attrValue(s, "type"))...)
+			}
+		}
+		images = append(images, img)
+		return false
+	})
+	return images
+}
+
+// parseSrcset parses a srcset attribute following the HTML specification,
+// URLs may hold commas as long as they are not the last character
+func parseSrcset(srcset string, resolve func(string) *url.URL, media, typ string) []ImageCandidate {
+	var candidates []ImageCandidate
+	s := srcset
+	for {
+		s = strings.TrimLeft(s, " \t\n\r\f,")
+		if s == "" {
+			return candidates
+		}
+		end := strings.IndexAny(s, " \t\n\r\f")
+		if end < 0 {
+			end = len(s)
+		}
+		ref := s[:end]
+		s = s[end:]
+		var descriptors []string
+		if strings.HasSuffix(ref, ",") {
+			ref = strings.TrimRight(ref, ",")
+		} else {
+			// Descriptors run up to the next comma outside of parentheses
+			depth, i := 0, 0
+			for ; i < len(s); i++ {
+				if s[i] == '(' {
+					depth++
+				} else if s[i] == ')' && depth > 0 {
+					depth--
+				} else if s[i] == ',' && depth == 0 {
+					break
+				}
+			}
+			descriptors = strings.Fields(s[:i])
+			s = s[i:]
+		}
+		u := resolve(ref)
+		if u == nil {
+			continue
+		}
+		c := ImageCandidate{URL: u, Media: media, Type: typ}
+		valid := true
+		for _, d := range descriptors {
+			if len(d) < 2 {
+				valid = false
+				continue
+			}
+			value := d[:len(d)-1]
+			switch d[len(d)-1] {
+			case 'w':
+				w, err := strconv.Atoi(value)
+				valid = valid && err == nil && w > 0 && c.Width == 0
+				c.Width = w
+			case 'x':
+				x, err := strconv.ParseFloat(value, 64)
+				valid = valid && err == nil && x > 0 && c.Density == 0
+				c.Density = x
+			case 'h':
+				// Height descriptors are reserved for the future and only accompany w descriptors
+			default:
+				valid = false
+			}
+		}
+		if !valid || (c.Width > 0 && c.Density > 0) {
+			continue
+		}
+		if c.Width == 0 && c.Density == 0 {
+			c.Density = 1
+		}
+		candidates = append(candidates, c)
+	}
+}
+
+// atoi returns the integer value of s, 0 when s is not a number
+func atoi(s string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return 0
+	}
+	return n
+}
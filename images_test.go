@@ -0,0 +1,44 @@
+package owl
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestImages(t *testing.T) {
+	base, _ := url.Parse("https://example.com/gallery/")
+	images := HTMLParseFromString(`<html><body>
+		<img src="owl.jpg" alt="An owl" width="640" height="480"
+			srcset="owl-320.jpg 320w, owl-640.jpg 640w, /img/owl,large.jpg 1280w">
+		<img src="data:image/gif;base64,R0lGOD" data-src="/lazy.jpg" data-srcset="lazy.jpg, lazy@2x.jpg 2x" alt="">
+		<picture>
+			<source media="(min-width: 800px)" srcset="wide.webp 1x, wide@2x.webp 2x" type="image/webp">
+			<img src="narrow.jpg" loading="lazy">
+		</picture>
+	</body></html>`).Images(base)
+	require.Len(t, images, 3)
+
+	owl := images[0]
+	require.Equal(t, "https://example.com/gallery/owl.jpg", owl.URL.String())
+	require.Equal(t, "An owl", owl.Alt)
+	require.Equal(t, 640, owl.Width)
+	require.False(t, owl.Lazy)
+	require.Len(t, owl.Srcset, 3)
+	require.Equal(t, 320, owl.Srcset[0].Width)
+	require.Equal(t, "https://example.com/img/owl,large.jpg", owl.Srcset[2].URL.String())
+
+	lazy := images[1]
+	require.True(t, lazy.Lazy)
+	require.Equal(t, "https://example.com/lazy.jpg", lazy.URL.String())
+	require.Equal(t, 1.0, lazy.Srcset[0].Density)
+	require.Equal(t, 2.0, lazy.Srcset[1].Density)
+
+	picture := images[2]
+	require.True(t, picture.Lazy)
+	require.Len(t, picture.Srcset, 2)
+	require.Equal(t, "(min-width: 800px)", picture.Srcset[1].Media)
+	require.Equal(t, "image/webp", picture.Srcset[1].Type)
+	require.Equal(t, "https://example.com/gallery/wide@2x.webp", picture.Srcset[1].URL.String())
+}
@@ -0,0 +1,155 @@
+package owl
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// nodeIndex holds inverted indexes of the element nodes below root,
+// every list is kept in document order
+type nodeIndex struct {
+	root    *html.Node
+	tags    map[string][]*html.Node
+	ids     map[string][]*html.Node
+	classes map[string][]*html.Node
+	stale   bool
+}
+
+// BuildIndex builds id, class and tag indexes over the tree of the Node,
+// so subsequent Find and FindAll calls on the Root, and on Roots found from it,
+// are resolved by an index lookup instead of a full depth first search.
+// The index is dropped on mutation or by InvalidateIndex, call BuildIndex again to rebuild it
+func (r *Root) BuildIndex() *Root {
+	if r.Node == nil {
+		return r
+	}
+	if r.doc == nil {
+		r.doc = &document{}
+	}
+	r.doc.index = buildIndex(r.Node)
+	return r
+}
+
+// InvalidateIndex marks the index built by BuildIndex as stale,
+// it must be called after changing the tree through the Node directly
+func (r *Root) InvalidateIndex() {
+	if ix := r.docIndex(); ix != nil {
+		ix.stale = true
+	}
+}
+
+// Indexed reports whether Find and FindAll calls are served by a valid index
+func (r *Root) Indexed() bool {
+	ix := r.docIndex()
+	return ix != nil && !ix.stale && r.Node != nil && (r.Node == ix.root || isDescendant(r.Node, ix.root))
+}
+
+// docIndex returns the index of the document, nil when none was built
+func (r *Root) docIndex() *nodeIndex {
+	if r.doc == nil {
+		return nil
+	}
+	return r.doc.index
+}
+
+func buildIndex(root *html.Node) *nodeIndex {
+	ix := &nodeIndex{
+		root:    root,
+		tags:    make(map[string][]*html.Node),
+		ids:     make(map[string][]*html.Node),
+		classes: make(map[string][]*html.Node),
+	}
+	var f func(*html.Node)
+	f = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			ix.tags[n.Data] = append(ix.tags[n.Data], n)
+			for _, attr := range n.Attr {
+				switch attr.Key {
+				case "id":
+					addTokens(ix.ids, attr.Val, n)
+				case "class":
+					addTokens(ix.classes, attr.Val, n)
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			f(c)
+		}
+	}
+	f(root)
+	return ix
+}
+
+// addTokens indexes n under every whitespace separated value of val,
+// a value repeated inside val adds n only once
+func addTokens(m map[string][]*html.Node, val string, n *html.Node) {
+	for _, token := range strings.Fields(val) {
+		nodes := m[token]
+		if len(nodes) > 0 && nodes[len(nodes)-1] == n {
+			continue
+		}
+		m[token] = append(nodes, n)
+	}
+}
+
+// candidates returns the smallest indexed list that holds every possible match for args,
+// it reports false when no index applies to the given arguments
+func (ix *nodeIndex) candidates(args []string) ([]*html.Node, bool) {
+	if len(args) == 0 {
+		return nil, false
+	}
+	if len(args) == 3 {
+		if fields := strings.Fields(args[2]); len(fields) > 0 {
+			switch args[1] {
+			case "id":
+				return ix.ids[fields[0]], true
+			case "class":
+				return ix.classes[fields[0]], true
+			}
+		}
+	}
+	if args[0] != "" {
+		return ix.tags[args[0]], true
+	}
+	return nil, false
+}
+
+// lookup returns up to limit descendants of n matching args, a negative limit returns all of them.
+// It reports false when the index is missing, stale, or unable to answer the query
+func (ix *nodeIndex) lookup(n *html.Node, args []string, strict bool, limit int) ([]*html.Node, bool) {
+	if ix == nil || ix.stale || n == nil {
+		return nil, false
+	}
+	if n != ix.root && !isDescendant(n, ix.root) {
+		return nil, false
+	}
+	candidates, ok := ix.candidates(args)
+	if !ok {
+		return nil, false
+	}
+	var nodes []*html.Node
+	for _, c := range candidates {
+		if c == n || (n != ix.root && !isDescendant(c, n)) {
+			continue
+		}
+		if !matchNode(c, args, strict) {
+			continue
+		}
+		nodes = append(nodes, c)
+		if limit > 0 && len(nodes) == limit {
+			break
+		}
+	}
+	return nodes, true
+}
+
+// isDescendant reports whether n is below ancestor in the tree
+func isDescendant(n, ancestor *html.Node) bool {
+	for p := n.Parent; p != nil; p = p.Parent {
+		if p == ancestor {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package owl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildIndexMatchesSearch(t *testing.T) {
+	plain := HTMLParseFromString(HtmlRoot2HTML)
+	indexed := HTMLParseFromString(HtmlRoot2HTML).BuildIndex()
+	require.True(t, indexed.Indexed())
+	require.False(t, plain.Indexed())
+
+	queries := [][]string{
+		{"div"},
+		{"div", "class", "first"},
+		{"div", "class", "third"},
+		{"", "class", "second"},
+		{"title"},
+		{"span"},
+	}
+	for _, q := range queries {
+		require.Equal(t, plain.FindAll(q...).Len, indexed.FindAll(q...).Len, q)
+		require.Equal(t, plain.FindAllStrict(q...).Len, indexed.FindAllStrict(q...).Len, q)
+		require.Equal(t, plain.Find(q...).Node != nil, indexed.Find(q...).Node != nil, q)
+	}
+
+	// Roots found from an indexed Root share its index and only search their own subtree
+	found := indexed.Find("body").Find("div", "class", "third")
+	require.True(t, found.Indexed())
+	require.Equal(t, "Multiple classes inorder", found.Text())
+	wrapper := indexed.FindAll("div").Roots[3]
+	require.Equal(t, 4, wrapper.FindAll("div", "class", "first").Len)
+}
+
+func TestInvalidateIndex(t *testing.T) {
+	root := HTMLParseFromString(HtmlRoot2HTML).BuildIndex()
+	body := root.Find("body")
+	body.InvalidateIndex()
+	require.False(t, root.Indexed())
+	require.Equal(t, 7, root.FindAll("div", "class", "first").Len)
+}
@@ -0,0 +1,102 @@
+package owl
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/url"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"golang.org/x/net/html/charset"
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// decompress sniffs the first bytes of r and returns a reader decompressing gzip and zstd streams,
+// other input is returned unchanged. The returned function releases the decompressor
+func decompress(r io.Reader) (io.Reader, func(), error) {
+	br := bufio.NewReader(r)
+	magic, _ := br.Peek(len(zstdMagic))
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, nil, err
+		}
+		return zr, func() { zr.Close() }, nil
+	case bytes.HasPrefix(magic, zstdMagic):
+		zr, err := zstd.NewReader(br)
+		if err != nil {
+			return nil, nil, err
+		}
+		return zr, zr.Close, nil
+	}
+	return br, func() {}, nil
+}
+
+// HTMLParseDataURL parses the document embedded in a data URL such as data:text/html;base64,...,
+// both base64 and percent-encoded data are supported along with a charset parameter
+func HTMLParseDataURL(dataURL string) *Root {
+	r, err := dataURLReader(dataURL)
+	if err != nil {
+		return &Root{Node: nil, NodeValue: "", Error: newError(ErrUnableToParse, err)}
+	}
+	return HTMLParse(r)
+}
+
+func dataURLReader(dataURL string) (io.Reader, error) {
+	if !strings.HasPrefix(strings.ToLower(dataURL), "data:") {
+		return nil, errors.New("not a data URL")
+	}
+	comma := strings.IndexByte(dataURL, ',')
+	if comma < 0 {
+		return nil, errors.New("data URL has no data")
+	}
+	header, data := dataURL[len("data:"):comma], dataURL[comma+1:]
+
+	isBase64 := false
+	if strings.HasSuffix(strings.ToLower(header), ";base64") {
+		isBase64 = true
+		header = header[:len(header)-len(";base64")]
+	}
+	mediaType, params := "text/plain", map[string]string{}
+	if header != "" {
+		var err error
+		if mediaType, params, err = mime.ParseMediaType(header); err != nil {
+			return nil, err
+		}
+	}
+	if mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return nil, fmt.Errorf("data URL has media type %s, not an HTML document", mediaType)
+	}
+
+	var body []byte
+	if isBase64 {
+		decoded, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(data), ""))
+		if err != nil {
+			if decoded, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(data, "=")); err != nil {
+				return nil, err
+			}
+		}
+		body = decoded
+	} else {
+		unescaped, err := url.PathUnescape(data)
+		if err != nil {
+			return nil, err
+		}
+		body = []byte(unescaped)
+	}
+	if label, ok := params["charset"]; ok {
+		return charset.NewReaderLabel(label, bytes.NewReader(body))
+	}
+	return bytes.NewReader(body), nil
+}
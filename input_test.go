@@ -0,0 +1,48 @@
+package owl
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTMLParseCompressed(t *testing.T) {
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write([]byte(testHTML))
+	zw.Close()
+	root := HTMLParse(&gz)
+	require.Nil(t, root.Error)
+	require.Equal(t, "servlet", root.Find("a", "href", "hello").Text())
+
+	var zs bytes.Buffer
+	enc, err := zstd.NewWriter(&zs)
+	require.NoError(t, err)
+	enc.Write([]byte(testHTML))
+	enc.Close()
+	root = HTMLParse(&zs)
+	require.Nil(t, root.Error)
+	require.Equal(t, "servlet", root.Find("a", "href", "hello").Text())
+
+	root = HTMLParse(bytes.NewReader([]byte{0x1f, 0x8b, 0x00}))
+	require.NotNil(t, root.Error)
+	require.Equal(t, ErrUnableToParse, root.Error.Type)
+}
+
+func TestHTMLParseDataURL(t *testing.T) {
+	encoded := base64.StdEncoding.EncodeToString([]byte("<p>Embedded</p>"))
+	root := HTMLParseDataURL("data:text/html;base64," + encoded)
+	require.Nil(t, root.Error)
+	require.Equal(t, "Embedded", root.Find("p").Text())
+
+	root = HTMLParseDataURL("data:text/html;charset=iso-8859-1,%3Cp%3ECaf%E9%3C%2Fp%3E")
+	require.Nil(t, root.Error)
+	require.Equal(t, "Café", root.Find("p").Text())
+
+	require.NotNil(t, HTMLParseDataURL("data:image/png;base64,iVBORw0KGgo=").Error)
+	require.NotNil(t, HTMLParseDataURL("https://example.com").Error)
+}
@@ -0,0 +1,82 @@
+package owl
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"time"
+)
+
+// Finder looks elements up in a parsed document, it is implemented by *Root
+type Finder interface {
+	Find(args ...string) *Root
+	FindStrict(args ...string) *Root
+	FindAll(args ...string) Roots
+	FindAllStrict(args ...string) Roots
+}
+
+// Fetcher retrieves documents over the network, it is implemented by *Client
+type Fetcher interface {
+	Get(url string, opts ...RequestOption) (io.Reader, error)
+	Post(url string, contentType string, body interface{}, opts ...RequestOption) (io.Reader, error)
+}
+
+// RobotsPolicy decides whether a crawler may fetch a URL, see the robots package.
+// A Client with a RobotsPolicy refuses the requests it disallows with ErrDisallowedByRobots
+type RobotsPolicy interface {
+	Allowed(userAgent string, u *url.URL) bool
+}
+
+// RateLimiter decides when a Client may send a request to a host, see NewRateLimiter
+type RateLimiter interface {
+	// Wait blocks until a request to host may be sent, it returns the error of ctx when ctx is done first
+	Wait(ctx context.Context, host string) error
+}
+
+// Cache stores the responses of a Client by URL, see NewMemoryCache and NewDiskCache.
+// Implementations must be safe for concurrent use
+type Cache interface {
+	Get(url string) (*CacheEntry, bool)
+	Set(url string, entry *CacheEntry)
+}
+
+// Metrics collects measures of the requests of a Client, see the metrics package for a Prometheus one.
+// Implementations must be safe for concurrent use
+type Metrics interface {
+	// Request is called after every attempt of a request with the status of the response, 0 when it failed,
+	// and the time the response headers took to arrive
+	Request(method, host string, status int, duration time.Duration)
+	// Retry is called before a request is sent again
+	Retry(method, host string)
+	// Downloaded is called with the number of bytes read from a response body once it is closed
+	Downloaded(host string, bytes int64)
+	// CacheLookup is called for the requests going through the Cache, hit is true when the Cache answers them
+	CacheLookup(host string, hit bool)
+}
+
+// Logger receives the events of a Client: requests, retries, redirects and cache hits at debug level,
+// rate limit waits and parse failures too. *slog.Logger implements it
+type Logger interface {
+	Debug(msg string, args ...interface{})
+	Info(msg string, args ...interface{})
+}
+
+// Renderer loads pages in a browser so their JavaScript runs before they are parsed, see the owlchrome module.
+// A Client with a Renderer gets the documents of GetDocument and Visit from it
+type Renderer interface {
+	// Render returns the HTML of the page at url once rendered, it returns the error of ctx when ctx is done first
+	Render(ctx context.Context, url string, opts RendererOptions) (html string, err error)
+}
+
+// Sink receives the records scraped by a crawl, such as the structs filled by Unmarshal,
+// see CrawlContext.Emit, NewJSONLSink, NewCSVSink and NewChanSink. Implementations must be safe for concurrent use
+type Sink interface {
+	Write(record interface{}) error
+	// Close flushes the records written so far, no record is written after it
+	Close() error
+}
+
+var (
+	_ Finder  = (*Root)(nil)
+	_ Fetcher = (*Client)(nil)
+)
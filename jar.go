@@ -0,0 +1,211 @@
+package owl
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrJarNotSavable is returned by SaveJar and LoadJar when the http.Client of the Client has a cookie jar
+// other than a *Jar
+var ErrJarNotSavable = errors.New("owl: the cookie jar of the client can not be saved")
+
+// Jar is an http.CookieJar that respects the domains, paths and expirations of Set-Cookie headers
+// and can be saved and loaded, to keep sessions between runs
+type Jar struct {
+	jar *cookiejar.Jar
+
+	mu sync.Mutex
+	// cookies are the cookies set so far by name, domain and path, along with the URL that set them
+	cookies map[string]savedCookie
+}
+
+// savedCookie is a cookie as written by Jar.Save
+type savedCookie struct {
+	URL      string     `json:"url"`
+	Name     string     `json:"name"`
+	Value    string     `json:"value"`
+	Domain   string     `json:"domain,omitempty"`
+	Path     string     `json:"path,omitempty"`
+	Expires  *time.Time `json:"expires,omitempty"`
+	Secure   bool       `json:"secure,omitempty"`
+	HttpOnly bool       `json:"httpOnly,omitempty"`
+	SameSite string     `json:"sameSite,omitempty"`
+}
+
+// NewJar returns an empty Jar
+func NewJar() *Jar {
+	// cookiejar.New only fails on options it is not given
+	jar, _ := cookiejar.New(nil)
+	return &Jar{jar: jar, cookies: make(map[string]savedCookie)}
+}
+
+// SetCookies implements http.CookieJar
+func (j *Jar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.jar.SetCookies(u, cookies)
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	now := time.Now()
+	for _, c := range cookies {
+		key := cookieKey(u, c)
+		if c.MaxAge < 0 || (c.MaxAge == 0 && !c.Expires.IsZero() && !c.Expires.After(now)) {
+			delete(j.cookies, key)
+			continue
+		}
+		saved := savedCookie{
+			URL:      (&url.URL{Scheme: u.Scheme, Host: u.Host, Path: u.Path}).String(),
+			Name:     c.Name,
+			Value:    c.Value,
+			Domain:   c.Domain,
+			Path:     c.Path,
+			Secure:   c.Secure,
+			HttpOnly: c.HttpOnly,
+			SameSite: sameSiteNames[c.SameSite],
+		}
+		// Max-Age is relative to now, it is saved as the time it expires at
+		expires := c.Expires
+		if c.MaxAge > 0 {
+			expires = now.Add(time.Duration(c.MaxAge) * time.Second)
+		}
+		if !expires.IsZero() {
+			saved.Expires = &expires
+		}
+		j.cookies[key] = saved
+	}
+}
+
+// Cookies implements http.CookieJar
+func (j *Jar) Cookies(u *url.URL) []*http.Cookie {
+	return j.jar.Cookies(u)
+}
+
+// Save writes the cookies of the jar that have not expired as JSON,
+// session cookies without an expiration are written too
+func (j *Jar) Save(w io.Writer) error {
+	j.mu.Lock()
+	cookies := make([]savedCookie, 0, len(j.cookies))
+	now := time.Now()
+	for key, c := range j.cookies {
+		if c.Expires != nil && !c.Expires.After(now) {
+			delete(j.cookies, key)
+			continue
+		}
+		cookies = append(cookies, c)
+	}
+	j.mu.Unlock()
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(cookies)
+}
+
+// Load adds the cookies written by Save to the jar, cookies that expired in the meantime are dropped
+func (j *Jar) Load(r io.Reader) error {
+	var cookies []savedCookie
+	if err := json.NewDecoder(r).Decode(&cookies); err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, c := range cookies {
+		u, err := url.Parse(c.URL)
+		if err != nil {
+			return err
+		}
+		if c.Expires != nil && !c.Expires.After(now) {
+			continue
+		}
+		cookie := &http.Cookie{
+			Name:     c.Name,
+			Value:    c.Value,
+			Domain:   c.Domain,
+			Path:     c.Path,
+			Secure:   c.Secure,
+			HttpOnly: c.HttpOnly,
+		}
+		if c.Expires != nil {
+			cookie.Expires = *c.Expires
+		}
+		for mode, name := range sameSiteNames {
+			if name == c.SameSite && name != "" {
+				cookie.SameSite = mode
+			}
+		}
+		j.SetCookies(u, []*http.Cookie{cookie})
+	}
+	return nil
+}
+
+var sameSiteNames = map[http.SameSite]string{
+	http.SameSiteLaxMode:    "Lax",
+	http.SameSiteStrictMode: "Strict",
+	http.SameSiteNoneMode:   "None",
+}
+
+// cookieKey identifies c set by u by its name, domain and path, like a cookie store does
+func cookieKey(u *url.URL, c *http.Cookie) string {
+	domain := strings.TrimPrefix(strings.ToLower(c.Domain), ".")
+	if domain == "" {
+		// Host-only cookies are kept apart from domain cookies of the same host
+		domain = "host:" + strings.ToLower(u.Hostname())
+	}
+	path := c.Path
+	if path == "" || path[0] != '/' {
+		// The default path is the directory of the request path
+		path = "/"
+		if i := strings.LastIndex(u.Path, "/"); i > 0 {
+			path = u.Path[:i]
+		}
+	}
+	return c.Name + ";" + domain + ";" + path
+}
+
+// SaveJar writes the cookies of the client to the file at path, see Jar.Save
+func (c *Client) SaveJar(path string) error {
+	if c.Client == nil || c.Jar == nil {
+		return os.WriteFile(path, []byte("[]\n"), 0o600)
+	}
+	jar, ok := c.Jar.(*Jar)
+	if !ok {
+		return ErrJarNotSavable
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	if err := jar.Save(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// LoadJar adds the cookies saved by SaveJar in the file at path to the cookie jar of the client,
+// which gets a Jar when it has none
+func (c *Client) LoadJar(path string) error {
+	c.ensureJar()
+	jar, ok := c.Jar.(*Jar)
+	if !ok {
+		return ErrJarNotSavable
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return jar.Load(f)
+}
+
+// ensureJar gives the http.Client of c a Jar when it has no cookie jar
+func (c *Client) ensureJar() {
+	if c.Client == nil || c.Jar == nil {
+		// The Jar goes on a copy of the http.Client, which may be shared
+		c.ownClient()
+		c.Jar = NewJar()
+	}
+}
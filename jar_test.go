@@ -0,0 +1,60 @@
+package owl
+
+import (
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJar(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/set", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "42"})
+		http.SetCookie(w, &http.Cookie{Name: "admin", Value: "yes", Path: "/admin", MaxAge: 3600})
+		http.SetCookie(w, &http.Cookie{Name: "gone", Value: "x"})
+	})
+	mux.HandleFunc("/logout", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "gone", MaxAge: -1})
+	})
+	echo := func(w http.ResponseWriter, r *http.Request) {
+		var names []string
+		for _, c := range r.Cookies() {
+			names = append(names, c.Name+"="+c.Value)
+		}
+		sort.Strings(names)
+		io.WriteString(w, strings.Join(names, ","))
+	}
+	mux.HandleFunc("/echo", echo)
+	mux.HandleFunc("/admin/echo", echo)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	get := func(c *Client, path string) string {
+		resp, err := c.GetResponse(srv.URL + path)
+		require.NoError(t, err)
+		return string(resp.Body)
+	}
+	client := &Client{Client: &http.Client{Jar: NewJar()}}
+	get(client, "/set")
+	get(client, "/logout")
+	require.Equal(t, "session=42", get(client, "/echo"))
+	require.Equal(t, "admin=yes,session=42", get(client, "/admin/echo"))
+
+	path := filepath.Join(t.TempDir(), "cookies.json")
+	require.NoError(t, client.SaveJar(path))
+
+	restored := &Client{}
+	require.NoError(t, restored.LoadJar(path))
+	require.Equal(t, "session=42", get(restored, "/echo"))
+	require.Equal(t, "admin=yes,session=42", get(restored, "/admin/echo"))
+
+	std, _ := cookiejar.New(nil)
+	require.Equal(t, ErrJarNotSavable, (&Client{Client: &http.Client{Jar: std}}).SaveJar(path))
+}
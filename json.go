@@ -0,0 +1,57 @@
+package owl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// GetJSON fetches the JSON document at url and decodes it into v.
+// Responses with a status other than 2xx fail with an *HTTPError whatever the status errors say
+func (c *Client) GetJSON(url string, v interface{}, opts ...RequestOption) error {
+	return c.sendJSON(http.MethodGet, url, nil, v, opts...)
+}
+
+// PostJSON sends body marshaled to JSON to url, unless it is a []byte or a string already holding JSON,
+// and decodes the JSON response into v. The response is discarded when v is nil
+func (c *Client) PostJSON(url string, body, v interface{}, opts ...RequestOption) error {
+	var data []byte
+	switch body := body.(type) {
+	case []byte:
+		data = body
+	case string:
+		data = []byte(body)
+	default:
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+	opts = append([]RequestOption{WithHeader("Content-Type", "application/json")}, opts...)
+	return c.sendJSON(http.MethodPost, url, data, v, opts...)
+}
+
+func (c *Client) sendJSON(method, url string, body []byte, v interface{}, opts ...RequestOption) error {
+	opts = append([]RequestOption{WithHeader("Accept", "application/json")}, opts...)
+	var resp *Response
+	var err error
+	if body != nil {
+		resp, err = c.fetch(method, url, bytes.NewReader(body), opts...)
+	} else {
+		resp, err = c.fetch(method, url, nil, opts...)
+	}
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return newHTTPError(resp)
+	}
+	if v == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.Unmarshal(resp.Body, v); err != nil {
+		return fmt.Errorf("owl: %s: %w", resp.FinalURL, err)
+	}
+	return nil
+}
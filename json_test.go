@@ -0,0 +1,80 @@
+package owl
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type jsonItem struct {
+	Name  string `json:"name"`
+	Price int    `json:"price"`
+}
+
+func TestJSON(t *testing.T) {
+	var contentType, accept string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType, accept = r.Header.Get("Content-Type"), r.Header.Get("Accept")
+		switch r.URL.Path {
+		case "/item":
+			w.Write([]byte(`{"name":"owl","price":12}`))
+		case "/echo":
+			var item jsonItem
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&item))
+			item.Price++
+			json.NewEncoder(w).Encode(item)
+		case "/missing":
+			http.Error(w, `{"error":"not found"}`, http.StatusNotFound)
+		case "/html":
+			w.Write([]byte("<html></html>"))
+		}
+	}))
+	defer srv.Close()
+	client := &Client{Client: srv.Client()}
+
+	var item jsonItem
+	require.NoError(t, client.GetJSON(srv.URL+"/item", &item))
+	require.Equal(t, jsonItem{"owl", 12}, item)
+	require.Equal(t, "application/json", accept)
+
+	var echoed jsonItem
+	require.NoError(t, client.PostJSON(srv.URL+"/echo", item, &echoed))
+	require.Equal(t, jsonItem{"owl", 13}, echoed)
+	require.Equal(t, "application/json", contentType)
+	require.NoError(t, client.PostJSON(srv.URL+"/echo", `{"name":"raw"}`, nil))
+
+	err := client.GetJSON(srv.URL+"/missing", &item)
+	var httpErr *HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusNotFound, httpErr.StatusCode)
+	require.Contains(t, httpErr.Snippet, "not found")
+
+	require.Error(t, client.GetJSON(srv.URL+"/html", &item))
+}
+
+func TestPostStruct(t *testing.T) {
+	var contentType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		io.Copy(w, r.Body)
+	}))
+	defer srv.Close()
+	client := &Client{Client: srv.Client()}
+
+	body, err := client.Post(srv.URL, "", jsonItem{"owl", 12})
+	require.NoError(t, err)
+	data, _ := io.ReadAll(body)
+	require.JSONEq(t, `{"name":"owl","price":12}`, string(data))
+	require.Equal(t, "application/json", contentType)
+
+	_, err = client.Post(srv.URL, "application/vnd.api+json", []int{1, 2})
+	require.NoError(t, err)
+	require.Equal(t, "application/vnd.api+json", contentType)
+
+	_, err = client.Post(srv.URL, "", make(chan int))
+	require.Error(t, err)
+}
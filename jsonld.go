@@ -0,0 +1,119 @@
+package owl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// JSONLD returns the JSON-LD items of the scripts of type application/ld+json below the Node in document order.
+// Top-level arrays and @graph wrappers are flattened so every item is a single node, scripts holding invalid JSON are skipped
+func (r *Root) JSONLD() []json.RawMessage {
+	var items []json.RawMessage
+	walk(r.Node, func(n *html.Node) bool {
+		if n.Type != html.ElementNode || n.Data != "script" {
+			return true
+		}
+		typ, _, _ := strings.Cut(attrValue(n, "type"), ";")
+		if !strings.EqualFold(strings.TrimSpace(typ), "application/ld+json") {
+			return false
+		}
+		var text strings.Builder
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.TextNode {
+				text.WriteString(c.Data)
+			}
+		}
+		items = appendJSONLD(items, cleanJSONLD(text.String()))
+		return false
+	})
+	return items
+}
+
+// appendJSONLD appends the nodes of data to items, unwrapping arrays and @graph
+func appendJSONLD(items []json.RawMessage, data []byte) []json.RawMessage {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || !json.Valid(data) {
+		return items
+	}
+	switch data[0] {
+	case '[':
+		var list []json.RawMessage
+		if json.Unmarshal(data, &list) == nil {
+			for _, item := range list {
+				items = appendJSONLD(items, item)
+			}
+		}
+	case '{':
+		var wrapper struct {
+			Graph json.RawMessage `json:"@graph"`
+		}
+		if json.Unmarshal(data, &wrapper) == nil && len(wrapper.Graph) > 0 {
+			return appendJSONLD(items, wrapper.Graph)
+		}
+		items = append(items, json.RawMessage(data))
+	}
+	return items
+}
+
+// cleanJSONLD removes the comment and CDATA markers some sites wrap their JSON-LD in
+func cleanJSONLD(s string) []byte {
+	s = strings.TrimSpace(s)
+	for _, marker := range []string{"<!--", "//<![CDATA[", "<![CDATA["} {
+		s = strings.TrimSpace(strings.TrimPrefix(s, marker))
+	}
+	for _, marker := range []string{"-->", "//]]>", "]]>"} {
+		s = strings.TrimSpace(strings.TrimSuffix(s, marker))
+	}
+	return []byte(s)
+}
+
+// DecodeJSONLD unmarshals the JSON-LD items of r into values of type T.
+// When types are given only items whose @type matches one of them are decoded,
+// types match regardless of a schema.org prefix such as "https://schema.org/Product"
+func DecodeJSONLD[T any](r *Root, types ...string) ([]T, error) {
+	var values []T
+	for _, item := range r.JSONLD() {
+		if len(types) > 0 && !jsonLDTypeIs(item, types) {
+			continue
+		}
+		var v T
+		if err := json.Unmarshal(item, &v); err != nil {
+			return values, fmt.Errorf("owl: decoding JSON-LD: %w", err)
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// jsonLDTypeIs reports whether the @type of item, a string or an array of strings, is one of types
+func jsonLDTypeIs(item json.RawMessage, types []string) bool {
+	var node struct {
+		Type json.RawMessage `json:"@type"`
+	}
+	if json.Unmarshal(item, &node) != nil || len(node.Type) == 0 {
+		return false
+	}
+	var names []string
+	if json.Unmarshal(node.Type, &names) != nil {
+		var name string
+		if json.Unmarshal(node.Type, &name) != nil {
+			return false
+		}
+		names = []string{name}
+	}
+	for _, name := range names {
+		if i := strings.LastIndexAny(name, "/:#"); i >= 0 {
+			name = name[i+1:]
+		}
+		for _, t := range types {
+			if strings.EqualFold(name, t) {
+				return true
+			}
+		}
+	}
+	return false
+}
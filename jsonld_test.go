@@ -0,0 +1,48 @@
+package owl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONLD(t *testing.T) {
+	doc := HTMLParseFromString(`<html><head>
+		<script type="application/ld+json">
+		{"@context": "https://schema.org", "@type": "Product", "name": "Owl plush", "offers": {"price": "19.99"}}
+		</script>
+		<script type="application/ld+json">[
+			{"@type": "BreadcrumbList"},
+			{"@type": ["https://schema.org/Product", "Thing"], "name": "Owl mug"}
+		]</script>
+		<script type="application/ld+json">
+		<!--
+		{"@context": "https://schema.org", "@graph": [{"@type": "WebSite", "name": "Owls"}, {"@type": "schema:Product", "name": "Owl poster"}]}
+		-->
+		</script>
+		<script type="application/ld+json">{"@type": "Product", broken</script>
+		<script type="application/json">{"@type": "Product", "name": "Not JSON-LD"}</script>
+	</head><body></body></html>`)
+	require.Len(t, doc.JSONLD(), 5)
+
+	type product struct {
+		Name   string `json:"name"`
+		Offers struct {
+			Price string `json:"price"`
+		} `json:"offers"`
+	}
+	products, err := DecodeJSONLD[product](doc, "Product")
+	require.NoError(t, err)
+	require.Len(t, products, 3)
+	require.Equal(t, "Owl plush", products[0].Name)
+	require.Equal(t, "19.99", products[0].Offers.Price)
+	require.Equal(t, "Owl mug", products[1].Name)
+	require.Equal(t, "Owl poster", products[2].Name)
+
+	all, err := DecodeJSONLD[map[string]any](doc)
+	require.NoError(t, err)
+	require.Len(t, all, 5)
+
+	_, err = DecodeJSONLD[[]string](doc, "WebSite")
+	require.Error(t, err)
+}
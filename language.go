@@ -0,0 +1,239 @@
+package owl
+
+import (
+	"math"
+	"strings"
+	"sync"
+	"unicode"
+
+	"golang.org/x/net/html"
+)
+
+// maxLanguageText bounds the text the detector of Language looks at
+const maxLanguageText = 20000
+
+// Language returns the primary language subtag of the content below the Node, such as "en" or "pt",
+// along with a confidence between 0 and 1. It combines the lang attributes and the language meta tags
+// of the document with an n-gram detector run over the text. The empty string is returned when neither
+// the markup nor the text tell
+func (r *Root) Language() (string, float64) {
+	if r.Node == nil {
+		return "", 0
+	}
+	declared := declaredLanguage(r.Node)
+	text := r.PlainText()
+	if len(text) > maxLanguageText {
+		text = text[:maxLanguageText]
+	}
+	detected, confidence := detectLanguage(text)
+	switch {
+	case declared == "":
+		return detected, confidence
+	case detected == "" || detected == declared:
+		// The markup is trusted when the text is too short to tell, or when both agree
+		return declared, math.Max(0.8, confidence)
+	case confidence >= 0.9:
+		// Templates often keep the lang attribute of the site while the content is translated
+		return detected, confidence * 0.9
+	}
+	return declared, 0.5
+}
+
+// declaredLanguage returns the primary subtag of the language the markup declares for n:
+// the lang attribute of n or its closest ancestor having one, or the language meta tags of the document
+func declaredLanguage(n *html.Node) string {
+	for p := n; p != nil; p = p.Parent {
+		if p.Type != html.ElementNode {
+			continue
+		}
+		if lang, ok := attrLookup(p, "lang"); ok {
+			return primarySubtag(lang)
+		}
+		if lang, ok := attrLookup(p, "xml:lang"); ok {
+			return primarySubtag(lang)
+		}
+	}
+	top := n
+	for top.Parent != nil {
+		top = top.Parent
+	}
+	var lang string
+	walk(top, func(m *html.Node) bool {
+		if lang != "" {
+			return false
+		}
+		if m.Type == html.ElementNode && m.Data == "meta" {
+			key := strings.ToLower(first(attrValue(m, "http-equiv"), attrValue(m, "name"), attrValue(m, "property")))
+			switch key {
+			case "content-language", "language", "dc.language", "og:locale":
+				// Content-Language may list several languages, the first one is the main one
+				v, _, _ := strings.Cut(attrValue(m, "content"), ",")
+				lang = primarySubtag(v)
+			}
+		}
+		return lang == ""
+	})
+	return lang
+}
+
+// primarySubtag returns the lowercased primary subtag of a language tag such as "en-US" or "pt_BR"
+func primarySubtag(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	if len(tag) < 2 || len(tag) > 3 {
+		return ""
+	}
+	return tag
+}
+
+// detectLanguage guesses the language of text from its script, and with trigram profiles for the
+// languages written in the Latin script. It returns an empty string when text holds too few letters
+func detectLanguage(text string) (string, float64) {
+	scripts := make(map[string]int)
+	letters := 0
+	ukrainian := false
+	for _, c := range text {
+		if !unicode.IsLetter(c) {
+			continue
+		}
+		letters++
+		switch {
+		case unicode.Is(unicode.Latin, c):
+			scripts["latin"]++
+		case unicode.Is(unicode.Cyrillic, c):
+			scripts["ru"]++
+			ukrainian = ukrainian || strings.ContainsRune("іїєґІЇЄҐ", c)
+		case unicode.Is(unicode.Greek, c):
+			scripts["el"]++
+		case unicode.Is(unicode.Arabic, c):
+			scripts["ar"]++
+		case unicode.Is(unicode.Hebrew, c):
+			scripts["he"]++
+		case unicode.Is(unicode.Hiragana, c), unicode.Is(unicode.Katakana, c):
+			scripts["ja"]++
+		case unicode.Is(unicode.Han, c):
+			scripts["zh"]++
+		case unicode.Is(unicode.Hangul, c):
+			scripts["ko"]++
+		case unicode.Is(unicode.Thai, c):
+			scripts["th"]++
+		case unicode.Is(unicode.Devanagari, c):
+			scripts["hi"]++
+		}
+	}
+	if letters < 10 {
+		return "", 0
+	}
+	// Japanese mixes kana with Han characters
+	if scripts["ja"] > 0 && scripts["ja"]*10 >= scripts["ja"]+scripts["zh"] {
+		scripts["ja"] += scripts["zh"]
+		delete(scripts, "zh")
+	}
+	script, count := "", 0
+	for s, n := range scripts {
+		if n > count || (n == count && s < script) {
+			script, count = s, n
+		}
+	}
+	share := float64(count) / float64(letters)
+	switch script {
+	case "":
+		return "", 0
+	case "latin":
+		lang, confidence := detectLatin(text)
+		return lang, confidence * share
+	case "ru":
+		if ukrainian {
+			return "uk", share
+		}
+	}
+	return script, share
+}
+
+// trigramProfile holds the log probabilities of the trigrams of a language
+type trigramProfile struct {
+	lang     string
+	logProb  map[string]float64
+	fallback float64
+}
+
+var (
+	profilesOnce sync.Once
+	profiles     []trigramProfile
+)
+
+// latinProfiles builds the trigram profiles of languageSamples on first use
+func latinProfiles() []trigramProfile {
+	profilesOnce.Do(func() {
+		vocabulary := make(map[string]bool)
+		counts := make(map[string]map[string]int)
+		for lang, sample := range languageSamples {
+			counts[lang] = make(map[string]int)
+			for _, g := range trigrams(sample) {
+				counts[lang][g]++
+				vocabulary[g] = true
+			}
+		}
+		for lang, c := range counts {
+			total := 0
+			for _, n := range c {
+				total += n
+			}
+			// Laplace smoothing keeps unseen trigrams from ruling a language out
+			denominator := float64(total + len(vocabulary))
+			p := trigramProfile{lang: lang, logProb: make(map[string]float64, len(c)), fallback: math.Log(1 / denominator)}
+			for g, n := range c {
+				p.logProb[g] = math.Log(float64(n+1) / denominator)
+			}
+			profiles = append(profiles, p)
+		}
+	})
+	return profiles
+}
+
+// detectLatin scores text against the trigram profiles and returns the most likely language
+// with its probability among the profiled languages
+func detectLatin(text string) (string, float64) {
+	grams := trigrams(text)
+	if len(grams) < 3 {
+		return "", 0
+	}
+	if len(grams) > 3000 {
+		grams = grams[:3000]
+	}
+	ps := latinProfiles()
+	scores := make([]float64, len(ps))
+	best := 0
+	for i, p := range ps {
+		for _, g := range grams {
+			if lp, ok := p.logProb[g]; ok {
+				scores[i] += lp
+			} else {
+				scores[i] += p.fallback
+			}
+		}
+		if scores[i] > scores[best] || (scores[i] == scores[best] && p.lang < ps[best].lang) {
+			best = i
+		}
+	}
+	// Softmax over the log likelihoods, scaled down by the number of trigrams so short texts stay uncertain
+	sum := 0.0
+	for _, s := range scores {
+		sum += math.Exp((s - scores[best]) / math.Sqrt(float64(len(grams))))
+	}
+	return ps[best].lang, 1 / sum
+}
+
+// trigrams returns the letter trigrams of the words of text, lowercased and padded with spaces
+func trigrams(text string) []string {
+	var grams []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(c rune) bool { return !unicode.IsLetter(c) }) {
+		w := []rune(" " + word + " ")
+		for i := 0; i+3 <= len(w); i++ {
+			grams = append(grams, string(w[i:i+3]))
+		}
+	}
+	return grams
+}
@@ -0,0 +1,84 @@
+package owl
+
+// languageSamples hold text written in each language the n-gram detector of Language recognizes among
+// those written in the Latin script, its trigram profiles are built from them
+var languageSamples = map[string]string{
+	"en": `All human beings are born free and equal in dignity and rights. They are endowed with reason and conscience
+and should act towards one another in a spirit of brotherhood. Everyone is entitled to all the rights and freedoms
+set forth in this declaration, without distinction of any kind, such as race, colour, sex, language, religion,
+political or other opinion, national or social origin, property, birth or other status. The weather was nice
+yesterday so we went for a walk in the park with the children and then we had dinner at a restaurant near the
+river. This is the first time that I have seen such a beautiful house, and I think that they will sell it quickly.
+You can find more information about our products and services on the website, where there are also answers
+to the questions which people ask most often. What would you like to do this weekend with your friends?`,
+	"de": `Alle Menschen sind frei und gleich an Würde und Rechten geboren. Sie sind mit Vernunft und Gewissen begabt
+und sollen einander im Geist der Brüderlichkeit begegnen. Jeder hat Anspruch auf die in dieser Erklärung
+verkündeten Rechte und Freiheiten ohne irgendeinen Unterschied, etwa nach Rasse, Hautfarbe, Geschlecht, Sprache,
+Religion, politischer oder sonstiger Überzeugung, nationaler oder sozialer Herkunft, Vermögen, Geburt oder
+sonstigem Stand. Gestern war das Wetter schön, deshalb sind wir mit den Kindern im Park spazieren gegangen und
+haben danach in einem Restaurant am Fluss gegessen. Das ist das erste Mal, dass ich so ein schönes Haus gesehen
+habe, und ich glaube, dass sie es schnell verkaufen werden. Weitere Informationen über unsere Produkte und
+Dienstleistungen finden Sie auf der Webseite, wo es auch Antworten auf die häufigsten Fragen gibt. Was möchtest
+du am Wochenende mit deinen Freunden machen?`,
+	"fr": `Tous les êtres humains naissent libres et égaux en dignité et en droits. Ils sont doués de raison et de
+conscience et doivent agir les uns envers les autres dans un esprit de fraternité. Chacun peut se prévaloir de
+tous les droits et de toutes les libertés proclamés dans la présente déclaration, sans distinction aucune,
+notamment de race, de couleur, de sexe, de langue, de religion, d'opinion politique ou de toute autre opinion,
+d'origine nationale ou sociale, de fortune, de naissance ou de toute autre situation. Hier il faisait beau, alors
+nous sommes allés nous promener dans le parc avec les enfants, puis nous avons dîné dans un restaurant près de la
+rivière. C'est la première fois que je vois une maison aussi belle, et je pense qu'ils vont la vendre rapidement.
+Vous trouverez plus d'informations sur nos produits et nos services sur le site, où il y a aussi des réponses aux
+questions que les gens posent le plus souvent. Qu'est-ce que tu voudrais faire ce week-end avec tes amis?`,
+	"es": `Todos los seres humanos nacen libres e iguales en dignidad y derechos y, dotados como están de razón y
+conciencia, deben comportarse fraternalmente los unos con los otros. Toda persona tiene todos los derechos y
+libertades proclamados en esta declaración, sin distinción alguna de raza, color, sexo, idioma, religión, opinión
+política o de cualquier otra índole, origen nacional o social, posición económica, nacimiento o cualquier otra
+condición. Ayer hacía buen tiempo, así que fuimos a pasear por el parque con los niños y después cenamos en un
+restaurante cerca del río. Es la primera vez que veo una casa tan bonita, y creo que la van a vender muy rápido.
+Puede encontrar más información sobre nuestros productos y servicios en la página web, donde también hay
+respuestas a las preguntas que la gente hace con más frecuencia. ¿Qué te gustaría hacer este fin de semana con
+tus amigos?`,
+	"it": `Tutti gli esseri umani nascono liberi ed eguali in dignità e diritti. Essi sono dotati di ragione e di
+coscienza e devono agire gli uni verso gli altri in spirito di fratellanza. Ad ogni individuo spettano tutti i
+diritti e tutte le libertà enunciate nella presente dichiarazione, senza distinzione alcuna, per ragioni di
+razza, di colore, di sesso, di lingua, di religione, di opinione politica o di altro genere, di origine
+nazionale o sociale, di ricchezza, di nascita o di altra condizione. Ieri faceva bel tempo, quindi siamo andati a
+fare una passeggiata nel parco con i bambini e poi abbiamo cenato in un ristorante vicino al fiume. È la prima
+volta che vedo una casa così bella, e penso che la venderanno molto presto. Potete trovare maggiori informazioni
+sui nostri prodotti e servizi sul sito, dove ci sono anche le risposte alle domande che la gente fa più spesso.
+Che cosa vorresti fare questo fine settimana con i tuoi amici?`,
+	"pt": `Todos os seres humanos nascem livres e iguais em dignidade e em direitos. Dotados de razão e de
+consciência, devem agir uns para com os outros em espírito de fraternidade. Todos os seres humanos podem invocar
+os direitos e as liberdades proclamados na presente declaração, sem distinção alguma, nomeadamente de raça, de
+cor, de sexo, de língua, de religião, de opinião política ou outra, de origem nacional ou social, de fortuna, de
+nascimento ou de qualquer outra situação. Ontem o tempo estava bom, então fomos passear no parque com as
+crianças e depois jantamos num restaurante perto do rio. É a primeira vez que vejo uma casa tão bonita, e acho
+que eles vão vendê-la muito depressa. Você pode encontrar mais informações sobre os nossos produtos e serviços no
+site, onde também há respostas para as perguntas que as pessoas fazem com mais frequência. O que você gostaria
+de fazer neste fim de semana com os seus amigos?`,
+	"nl": `Alle mensen worden vrij en gelijk in waardigheid en rechten geboren. Zij zijn begiftigd met verstand en
+geweten, en behoren zich jegens elkander in een geest van broederschap te gedragen. Een ieder heeft aanspraak op
+alle rechten en vrijheden, in deze verklaring opgesomd, zonder enig onderscheid van welke aard ook, zoals ras,
+kleur, geslacht, taal, godsdienst, politieke of andere overtuiging, nationale of maatschappelijke afkomst,
+eigendom, geboorte of andere status. Gisteren was het mooi weer, dus zijn we met de kinderen in het park gaan
+wandelen en daarna hebben we gegeten in een restaurant bij de rivier. Het is de eerste keer dat ik zo een mooi
+huis zie, en ik denk dat ze het snel zullen verkopen. Meer informatie over onze producten en diensten vindt u op
+de website, waar ook antwoorden staan op de vragen die mensen het vaakst stellen. Wat wil je dit weekend met je
+vrienden doen?`,
+	"sv": `Alla människor är födda fria och lika i värde och rättigheter. De har utrustats med förnuft och samvete
+och bör handla gentemot varandra i en anda av broderskap. Var och en är berättigad till alla de rättigheter och
+friheter som uttalas i denna förklaring utan åtskillnad av något slag, såsom ras, hudfärg, kön, språk, religion,
+politisk eller annan uppfattning, nationellt eller socialt ursprung, egendom, börd eller ställning i övrigt. Igår
+var vädret fint, så vi gick på en promenad i parken med barnen och sedan åt vi middag på en restaurang nära
+floden. Det är första gången som jag ser ett så vackert hus, och jag tror att de kommer att sälja det snabbt. Mer
+information om våra produkter och tjänster hittar du på webbplatsen, där det också finns svar på de frågor som
+folk ställer oftast. Vad vill du göra i helgen med dina vänner?`,
+	"pl": `Wszyscy ludzie rodzą się wolni i równi pod względem swej godności i swych praw. Są oni obdarzeni rozumem i
+sumieniem i powinni postępować wobec innych w duchu braterstwa. Każdy człowiek posiada wszystkie prawa i
+wolności zawarte w niniejszej deklaracji bez względu na jakiekolwiek różnice rasy, koloru skóry, płci, języka,
+wyznania, poglądów politycznych i innych, narodowości, pochodzenia społecznego, majątku, urodzenia lub jakiegokolwiek
+innego stanu. Wczoraj była ładna pogoda, więc poszliśmy z dziećmi na spacer do parku, a potem zjedliśmy kolację w
+restauracji nad rzeką. To pierwszy raz, kiedy widzę taki piękny dom, i myślę, że szybko go sprzedadzą. Więcej
+informacji o naszych produktach i usługach znajdziesz na stronie internetowej, gdzie są też odpowiedzi na
+pytania, które ludzie zadają najczęściej. Co chciałbyś robić w ten weekend ze swoimi przyjaciółmi?`,
+}
@@ -0,0 +1,62 @@
+package owl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	for lang, text := range map[string]string{
+		"en": "The owl is a bird of prey that hunts mostly at night and sleeps during the day in old trees.",
+		"de": "Die Eule ist ein Greifvogel, der meistens in der Nacht jagt und tagsüber in alten Bäumen schläft.",
+		"fr": "La chouette est un oiseau de proie qui chasse surtout la nuit et dort pendant la journée dans les vieux arbres.",
+		"es": "El búho es un ave rapaz que caza sobre todo por la noche y duerme durante el día en los árboles viejos.",
+		"it": "Il gufo è un uccello rapace che caccia soprattutto di notte e dorme durante il giorno negli alberi vecchi.",
+		"pt": "A coruja é uma ave de rapina que caça sobretudo à noite e dorme durante o dia nas árvores velhas.",
+		"nl": "De uil is een roofvogel die vooral 's nachts jaagt en overdag in oude bomen slaapt.",
+		"sv": "Ugglan är en rovfågel som mest jagar på natten och sover i gamla träd under dagen.",
+		"pl": "Sowa jest ptakiem drapieżnym, który poluje głównie w nocy, a w dzień śpi w starych drzewach.",
+		"ru": "Сова — хищная птица, которая охотится в основном ночью.",
+		"uk": "Сова — хижий птах, який полює переважно вночі, а вдень спить у старих деревах.",
+		"ja": "フクロウは主に夜に狩りをする猛禽類です。",
+		"zh": "猫头鹰是一种主要在夜间捕猎的猛禽，白天在老树上睡觉。",
+		"el": "Η κουκουβάγια είναι αρπακτικό πουλί που κυνηγά κυρίως τη νύχτα.",
+	} {
+		detected, confidence := detectLanguage(text)
+		require.Equal(t, lang, detected, text)
+		require.Greater(t, confidence, 0.5, text)
+	}
+	detected, _ := detectLanguage("OK 42")
+	require.Empty(t, detected)
+}
+
+func TestLanguage(t *testing.T) {
+	text := "<p>The owl is a bird of prey that hunts mostly at night and sleeps during the day in old trees.</p>"
+
+	lang, confidence := HTMLParseFromString(`<html lang="en-GB"><body>` + text + `</body></html>`).Language()
+	require.Equal(t, "en", lang)
+	require.GreaterOrEqual(t, confidence, 0.8)
+
+	// A lang attribute closer to the content wins over the one of the document
+	doc := HTMLParseFromString(`<html lang="en"><body><div lang="de"><p>Kurz.</p></div></body></html>`)
+	lang, _ = doc.Find("p").Language()
+	require.Equal(t, "de", lang)
+
+	lang, confidence = HTMLParseFromString(`<html><head><meta http-equiv="Content-Language" content="fr-CA, en"></head><body></body></html>`).Language()
+	require.Equal(t, "fr", lang)
+	require.Equal(t, 0.8, confidence)
+
+	// Text clearly written in another language overrides the template's lang attribute
+	lang, _ = HTMLParseFromString(`<html lang="en"><body><p>Die Eule ist ein Greifvogel, der meistens in der Nacht jagt
+		und tagsüber in alten Bäumen schläft. Sie frisst Mäuse und andere kleine Tiere, die sie mit ihren Krallen fängt.</p></body></html>`).Language()
+	require.Equal(t, "de", lang)
+
+	lang, confidence = HTMLParseFromString(`<html><body>` + text + `</body></html>`).Language()
+	require.Equal(t, "en", lang)
+	require.Greater(t, confidence, 0.5)
+
+	lang, confidence = HTMLParseFromString(`<html><body></body></html>`).Language()
+	require.Empty(t, lang)
+	require.Zero(t, confidence)
+}
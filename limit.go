@@ -0,0 +1,49 @@
+package owl
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// BodyTooLargeError is returned when a response body exceeds the MaxBodySize of a Client
+type BodyTooLargeError struct {
+	URL   string
+	Limit int64
+}
+
+func (e *BodyTooLargeError) Error() string {
+	return fmt.Sprintf("owl: body of %s exceeds %d bytes", e.URL, e.Limit)
+}
+
+// limitedBody reads a response body up to a limit, reading more fails with a *BodyTooLargeError
+type limitedBody struct {
+	io.ReadCloser
+	r     io.Reader
+	read  int64
+	limit int64
+	url   string
+}
+
+// newLimitedBody limits the body of resp to limit bytes
+func newLimitedBody(resp *http.Response, limit int64) *limitedBody {
+	return &limitedBody{
+		ReadCloser: resp.Body,
+		// One byte more than the limit tells bodies of the limit size from longer ones
+		r:     io.LimitReader(resp.Body, limit+1),
+		limit: limit,
+		url:   resp.Request.URL.String(),
+	}
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.read > b.limit {
+		return 0, &BodyTooLargeError{URL: b.url, Limit: b.limit}
+	}
+	n, err := b.r.Read(p)
+	b.read += int64(n)
+	if b.read > b.limit {
+		return n - int(b.read-b.limit), &BodyTooLargeError{URL: b.url, Limit: b.limit}
+	}
+	return n, err
+}
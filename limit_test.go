@@ -0,0 +1,47 @@
+package owl
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxBodySize(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/small", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("0123456789"))
+	})
+	mux.HandleFunc("/streamed", func(w http.ResponseWriter, r *http.Request) {
+		// Flushing drops the Content-Length, the limit is found while reading
+		w.Write([]byte(strings.Repeat("x", 8)))
+		w.(http.Flusher).Flush()
+		w.Write([]byte(strings.Repeat("x", 8)))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	client := NewClient(&Parameters{MaxBodySize: 10, HttpClient: srv.Client()})
+
+	body, err := (&Root{}).Download(srv.URL+"/small", client)
+	require.NoError(t, err)
+	require.Equal(t, "0123456789", string(body))
+
+	client.MaxBodySize = 9
+	var tooLarge *BodyTooLargeError
+	_, err = (&Root{}).Download(srv.URL+"/small", client)
+	require.True(t, errors.As(err, &tooLarge))
+	require.Equal(t, &BodyTooLargeError{URL: srv.URL + "/small", Limit: 9}, tooLarge)
+
+	_, err = client.Get(srv.URL + "/streamed")
+	require.True(t, errors.As(err, &tooLarge))
+	_, err = client.GetDocument(srv.URL + "/streamed")
+	require.True(t, errors.As(err, &tooLarge))
+
+	client.MaxBodySize = 16
+	r, err := client.Get(srv.URL + "/streamed")
+	require.NoError(t, err)
+	require.NotNil(t, r)
+}
@@ -0,0 +1,123 @@
+package owl
+
+import (
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Link is a hyperlink of the document
+type Link struct {
+	// Href is the href attribute as written in the document
+	Href string
+	// URL is Href resolved against the base of the document, nil when Href is not a valid URL
+	URL *url.URL
+	// Text is the anchor text with whitespace collapsed
+	Text  string
+	Title string
+	Rel   []string
+	// Internal is set for links to the host of the document
+	Internal bool
+}
+
+// Links returns every a element with an href below the Node in document order.
+// URLs are resolved against base, the URL of the page, or against the document's <base href> when it has one.
+// A nil base stands for the URL the document was fetched from.
+// Links are internal when they point to the host of the page
+func (r *Root) Links(base *url.URL) []Link {
+	base = r.pageURL(base)
+	page := base
+	base = documentBase(r.Node, base)
+	if page == nil {
+		page = base
+	}
+	var links []Link
+	walk(r.Node, func(n *html.Node) bool {
+		if n.Type != html.ElementNode || n.Data != "a" {
+			return true
+		}
+		href, ok := attrLookup(n, "href")
+		if !ok {
+			return true
+		}
+		link := Link{
+			Href:  href,
+			Text:  strings.Join(strings.Fields((&Root{Node: n}).FullText()), " "),
+			Title: attrValue(n, "title"),
+			Rel:   strings.Fields(strings.ToLower(attrValue(n, "rel"))),
+		}
+		if u, err := url.Parse(strings.TrimSpace(href)); err == nil {
+			if base != nil {
+				u = base.ResolveReference(u)
+			}
+			link.URL = u
+			link.Internal = isInternal(u, page)
+		}
+		links = append(links, link)
+		return true
+	})
+	return links
+}
+
+// isInternal reports whether u points to the host of base, a leading www. is ignored.
+// Without a base only relative URLs are internal
+func isInternal(u, base *url.URL) bool {
+	if u.Scheme != "" && u.Scheme != "http" && u.Scheme != "https" {
+		return false
+	}
+	if base == nil {
+		return u.Host == ""
+	}
+	host := func(u *url.URL) string {
+		return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	}
+	return u.Host == "" || host(u) == host(base)
+}
+
+// documentBase returns the URL relative references of the document of n resolve against:
+// the href of its first base element resolved against base, or base itself
+func documentBase(n *html.Node, base *url.URL) *url.URL {
+	if n == nil {
+		return base
+	}
+	top := n
+	for top.Parent != nil {
+		top = top.Parent
+	}
+	var href string
+	found := false
+	walk(top, func(c *html.Node) bool {
+		if found {
+			return false
+		}
+		if c.Type == html.ElementNode && c.Data == "base" {
+			href, found = attrLookup(c, "href")
+		}
+		return !found
+	})
+	if !found {
+		return base
+	}
+	u, err := url.Parse(strings.TrimSpace(href))
+	if err != nil {
+		return base
+	}
+	if base != nil {
+		return base.ResolveReference(u)
+	}
+	if !u.IsAbs() {
+		return nil
+	}
+	return u
+}
+
+// attrLookup returns the value of the first attribute of n named key, reporting whether n has it
+func attrLookup(n *html.Node, key string) (string, bool) {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
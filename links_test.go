@@ -0,0 +1,41 @@
+package owl
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLinks(t *testing.T) {
+	base, _ := url.Parse("https://example.com/blog/post.html")
+	links := HTMLParseFromString(`<html><body>
+		<a href="../about">About <b>us</b></a>
+		<a href="https://www.example.com/contact" rel="Nofollow noopener" title="Contact">Contact</a>
+		<a href="https://other.org/">Other</a>
+		<a href="mailto:owl@example.com">Mail</a>
+		<a name="anchor">No href</a>
+	</body></html>`).Links(base)
+
+	require.Len(t, links, 4)
+	require.Equal(t, "https://example.com/about", links[0].URL.String())
+	require.Equal(t, "About us", links[0].Text)
+	require.True(t, links[0].Internal)
+	require.Equal(t, []string{"nofollow", "noopener"}, links[1].Rel)
+	require.True(t, links[1].Internal)
+	require.False(t, links[2].Internal)
+	require.False(t, links[3].Internal)
+}
+
+func TestLinksHonorBase(t *testing.T) {
+	page, _ := url.Parse("https://example.com/index.html")
+	root := HTMLParseFromString(`<html><head><base href="https://cdn.example.com/docs/"></head>
+		<body><a href="guide.html">Guide</a></body></html>`)
+
+	links := root.Find("body").Links(page)
+	require.Equal(t, "https://cdn.example.com/docs/guide.html", links[0].URL.String())
+	require.False(t, links[0].Internal)
+
+	// Without a page URL the absolute base href is used
+	require.Equal(t, "https://cdn.example.com/docs/guide.html", root.Links(nil)[0].URL.String())
+}
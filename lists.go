@@ -0,0 +1,120 @@
+package owl
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// ListItem is an item of a ul or ol element, with the items of the lists nested in it
+type ListItem struct {
+	// Text is the text of the item without its nested lists, with whitespace collapsed
+	Text     string
+	Children []ListItem
+}
+
+// List returns the text of the items of the ul or ol element the Node is, or of the first one below it.
+// The text of nested lists is left out, see ListTree
+func (r *Root) List() []string {
+	var items []string
+	for _, item := range r.ListTree() {
+		items = append(items, item.Text)
+	}
+	return items
+}
+
+// ListTree returns the items of the ul or ol element the Node is, or of the first one below it,
+// with nested lists as children of the item holding them
+func (r *Root) ListTree() []ListItem {
+	list := firstElement(r.Node, func(n *html.Node) bool { return isList(n) })
+	if list == nil {
+		return nil
+	}
+	return listItems(list)
+}
+
+func isList(n *html.Node) bool {
+	return n.Type == html.ElementNode && (n.Data == "ul" || n.Data == "ol" || n.Data == "menu")
+}
+
+// listItems returns the li children of list
+func listItems(list *html.Node) []ListItem {
+	var items []ListItem
+	for li := list.FirstChild; li != nil; li = li.NextSibling {
+		if li.Type != html.ElementNode || li.Data != "li" {
+			continue
+		}
+		var text strings.Builder
+		item := ListItem{}
+		walk(li, func(n *html.Node) bool {
+			switch {
+			case n != li && isList(n):
+				item.Children = append(item.Children, listItems(n)...)
+				return false
+			case n.Type == html.TextNode:
+				text.WriteString(n.Data)
+				text.WriteByte(' ')
+			case n.Type == html.ElementNode && hiddenElements[n.Data]:
+				return false
+			}
+			return true
+		})
+		item.Text = strings.Join(strings.Fields(text.String()), " ")
+		items = append(items, item)
+	}
+	return items
+}
+
+// DefinitionList returns the terms of the dl element the Node is, or of the first one below it,
+// mapped to their descriptions. Consecutive dt elements share the dd elements following them,
+// and dt and dd elements grouped in div elements are found too
+func (r *Root) DefinitionList() map[string][]string {
+	dl := firstElement(r.Node, func(n *html.Node) bool { return n.Type == html.ElementNode && n.Data == "dl" })
+	if dl == nil {
+		return nil
+	}
+	terms := make(map[string][]string)
+	var current []string
+	// A dt after a dd starts a new group of terms
+	afterDescription := false
+	var visit func(parent *html.Node)
+	visit = func(parent *html.Node) {
+		for c := parent.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode {
+				continue
+			}
+			switch c.Data {
+			case "dt":
+				if afterDescription {
+					current, afterDescription = nil, false
+				}
+				term := collapsedText(c)
+				current = append(current, term)
+				if _, ok := terms[term]; !ok {
+					terms[term] = nil
+				}
+			case "dd":
+				afterDescription = true
+				for _, term := range current {
+					terms[term] = append(terms[term], collapsedText(c))
+				}
+			case "div":
+				visit(c)
+			}
+		}
+	}
+	visit(dl)
+	return terms
+}
+
+// firstElement returns n or the first node below it for which match is true, nil when there is none
+func firstElement(n *html.Node, match func(*html.Node) bool) *html.Node {
+	var found *html.Node
+	walk(n, func(c *html.Node) bool {
+		if found == nil && match(c) {
+			found = c
+		}
+		return found == nil
+	})
+	return found
+}
@@ -0,0 +1,51 @@
+package owl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestList(t *testing.T) {
+	doc := HTMLParseFromString(`<html><body><div>
+		<ol>
+			<li>Strigidae
+				<ul>
+					<li>Snowy <em>owl</em></li>
+					<li>Little owl<ol><li>Athene noctua</li></ol></li>
+				</ul>
+			</li>
+			<li> Tytonidae </li>
+		</ol>
+		<ul><li>Other list</li></ul>
+	</div></body></html>`)
+
+	require.Equal(t, []string{"Strigidae", "Tytonidae"}, doc.List())
+	require.Equal(t, []ListItem{
+		{Text: "Strigidae", Children: []ListItem{
+			{Text: "Snowy owl"},
+			{Text: "Little owl", Children: []ListItem{{Text: "Athene noctua"}}},
+		}},
+		{Text: "Tytonidae"},
+	}, doc.ListTree())
+	require.Equal(t, []string{"Other list"}, doc.FindAll("ul").Roots[1].List())
+	require.Nil(t, HTMLParseFromString(`<p>No list</p>`).List())
+}
+
+func TestDefinitionList(t *testing.T) {
+	doc := HTMLParseFromString(`<dl>
+		<dt>Weight</dt><dd>500 g</dd>
+		<dt>Color</dt><dt>Colour</dt><dd>White</dd><dd>Brown</dd>
+		<div><dt>Material</dt><dd>Plush  <b>fabric</b></dd></div>
+		<dt>Weight</dt><dd>0.5 kg</dd>
+		<dt>Batteries</dt>
+	</dl>`)
+	require.Equal(t, map[string][]string{
+		"Weight":    {"500 g", "0.5 kg"},
+		"Color":     {"White", "Brown"},
+		"Colour":    {"White", "Brown"},
+		"Material":  {"Plush fabric"},
+		"Batteries": nil,
+	}, doc.DefinitionList())
+	require.Nil(t, HTMLParseFromString(`<p>No list</p>`).DefinitionList())
+}
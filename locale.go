@@ -0,0 +1,156 @@
+package owl
+
+import (
+	"net/url"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Locale tells how a site writes numbers and dates, see Pipeline.WithLocale, Pipeline.WithHostLocales,
+// Step.In and UnmarshalLocale
+type Locale struct {
+	// Decimal is the decimal separator, '.' or ','. The other one separates thousands.
+	// It is guessed from the number when it is zero, see ParseFloat
+	Decimal rune
+	// Months maps the lower case names and abbreviations of the months to them, for ParseTime.
+	// English names are always understood
+	Months map[string]time.Month
+}
+
+// Locales of common languages, see LocaleFor
+var (
+	LocaleEnglish = Locale{Decimal: '.'}
+	LocaleFrench  = Locale{Decimal: ',', Months: months(
+		"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre",
+		"janv", "févr", "", "avr", "", "", "juil", "", "sept", "oct", "nov", "déc")}
+	LocaleGerman = Locale{Decimal: ',', Months: months(
+		"januar", "februar", "märz", "april", "mai", "juni", "juli", "august", "september", "oktober", "november", "dezember",
+		"jan", "feb", "mär", "apr", "", "jun", "jul", "aug", "sep", "okt", "nov", "dez")}
+	LocaleSpanish = Locale{Decimal: ',', Months: months(
+		"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre",
+		"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sep", "oct", "nov", "dic")}
+	LocaleItalian = Locale{Decimal: ',', Months: months(
+		"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre",
+		"gen", "feb", "mar", "apr", "mag", "giu", "lug", "ago", "set", "ott", "nov", "dic")}
+	LocalePortuguese = Locale{Decimal: ',', Months: months(
+		"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro",
+		"jan", "fev", "mar", "abr", "mai", "jun", "jul", "ago", "set", "out", "nov", "dez")}
+	LocaleArabic = Locale{Decimal: '.', Months: months(
+		"يناير", "فبراير", "مارس", "أبريل", "مايو", "يونيو", "يوليو", "أغسطس", "سبتمبر", "أكتوبر", "نوفمبر", "ديسمبر")}
+)
+
+// months maps names, twelve at a time starting from January, to their month. Empty names are skipped
+func months(names ...string) map[string]time.Month {
+	m := make(map[string]time.Month, len(names))
+	for i, name := range names {
+		if name != "" {
+			m[name] = time.Month(i%12 + 1)
+		}
+	}
+	return m
+}
+
+// LocaleFor returns the Locale of the language subtag lang, such as "fr" or "pt-BR", as returned by
+// Root.Language to pick the Locale of a site. ok is false for unknown languages
+func LocaleFor(lang string) (l Locale, ok bool) {
+	switch primarySubtag(lang) {
+	case "en":
+		return LocaleEnglish, true
+	case "fr":
+		return LocaleFrench, true
+	case "de":
+		return LocaleGerman, true
+	case "es":
+		return LocaleSpanish, true
+	case "it":
+		return LocaleItalian, true
+	case "pt":
+		return LocalePortuguese, true
+	case "ar":
+		return LocaleArabic, true
+	}
+	return Locale{}, false
+}
+
+// HostLocales maps hosts to the Locale their sites are written in, such as {"shop.example.de": LocaleGerman},
+// for crawls spanning sites of several countries. A host matches its subdomains too
+type HostLocales map[string]Locale
+
+// Lookup returns the Locale of the host of u, or of its closest parent domain in locales.
+// ok is false when none is found or u is nil, such as for documents that were not fetched
+func (locales HostLocales) Lookup(u *url.URL) (l Locale, ok bool) {
+	if len(locales) == 0 || u == nil {
+		return Locale{}, false
+	}
+	for host := strings.ToLower(u.Hostname()); host != ""; {
+		if l, ok := locales[host]; ok {
+			return l, true
+		}
+		_, parent, found := strings.Cut(host, ".")
+		if !found {
+			break
+		}
+		host = parent
+	}
+	return Locale{}, false
+}
+
+// decimal returns the decimal separator of l, zero when l is nil
+func (l *Locale) decimal() rune {
+	if l == nil {
+		return 0
+	}
+	return l.Decimal
+}
+
+// englishMonths replaces the month names of l in s by their English name, or its abbreviation when short is set
+func (l *Locale) englishMonths(s string, short bool) string {
+	if l == nil || len(l.Months) == 0 {
+		return s
+	}
+	var b strings.Builder
+	for len(s) > 0 {
+		i := strings.IndexFunc(s, unicode.IsLetter)
+		if i < 0 {
+			b.WriteString(s)
+			break
+		}
+		b.WriteString(s[:i])
+		s = s[i:]
+		end := strings.IndexFunc(s, func(c rune) bool { return !unicode.IsLetter(c) })
+		if end < 0 {
+			end = len(s)
+		}
+		word := s[:end]
+		if month, ok := l.Months[strings.ToLower(word)]; ok {
+			word = month.String()
+			if short {
+				word = word[:3]
+			}
+		}
+		b.WriteString(word)
+		s = s[end:]
+	}
+	return b.String()
+}
+
+// normalizeDigits turns Arabic-Indic digits and separators into ASCII ones and drops the
+// bidirectional marks right-to-left sites put around numbers
+func normalizeDigits(s string) string {
+	return strings.Map(func(c rune) rune {
+		switch {
+		case c >= '٠' && c <= '٩':
+			return '0' + c - '٠'
+		case c >= '۰' && c <= '۹':
+			return '0' + c - '۰'
+		case c == '٫':
+			return '.'
+		case c == '٬':
+			return ','
+		case unicode.Is(unicode.Cf, c):
+			return -1
+		}
+		return c
+	}, s)
+}
@@ -0,0 +1,152 @@
+package owl
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocaleNumbers(t *testing.T) {
+	german := ParseFloat().In(LocaleGerman)
+	for in, want := range map[string]float64{"1.299": 1299, "1.299,50": 1299.5, "19,99": 19.99, "42": 42, "1 234,5": 1234.5} {
+		v, err := german.fn(in)
+		require.NoError(t, err, in)
+		require.Equal(t, want, v, in)
+	}
+	_, err := german.fn("1,299.50")
+	require.ErrorContains(t, err, "ambiguous number")
+
+	english := ParseFloat().In(LocaleEnglish)
+	v, err := english.fn("1,299")
+	require.NoError(t, err)
+	require.Equal(t, 1299.0, v)
+	_, err = english.fn("19,99")
+	require.Error(t, err)
+
+	// Arabic-Indic digits and separators, with the marks of right-to-left text
+	v, err = ParseFloat().fn("‏١٬٢٣٤٫٥")
+	require.NoError(t, err)
+	require.Equal(t, 1234.5, v)
+	v, err = ParseInt().In(LocaleArabic).fn("۱۲۳")
+	require.NoError(t, err)
+	require.Equal(t, 123, v)
+	v, err = ParseFloat().fn("1'299.50")
+	require.NoError(t, err)
+	require.Equal(t, 1299.5, v)
+}
+
+func TestLocaleTimes(t *testing.T) {
+	want := time.Date(2025, time.February, 3, 0, 0, 0, 0, time.UTC)
+	for in, l := range map[string]Locale{
+		"3 février 2025":       LocaleFrench,
+		"3 févr. 2025":         LocaleFrench,
+		"3. Februar 2025":      LocaleGerman,
+		"3 de febrero de 2025": LocaleSpanish,
+		"٣ فبراير ٢٠٢٥":        LocaleArabic,
+		"3 February 2025":      LocaleFrench,
+	} {
+		layouts := []string{"2 January 2006", "2 Jan. 2006", "2. January 2006", "2 de January de 2006"}
+		v, err := ParseTime(layouts...).In(l).fn(in)
+		require.NoError(t, err, in)
+		require.Equal(t, want, v, in)
+	}
+	_, err := ParseTime("2 January 2006").fn("3 février 2025")
+	require.Error(t, err)
+
+	l, ok := LocaleFor("pt-BR")
+	require.True(t, ok)
+	require.Equal(t, ',', l.Decimal)
+	_, ok = LocaleFor("xx")
+	require.False(t, ok)
+}
+
+func TestPipelineWithLocale(t *testing.T) {
+	doc := HTMLParse(strings.NewReader(`<span class="price">1.299,00 €</span><span class="count">1.200</span>
+		<time>12 mars 2024</time>`))
+	price := Pipe(Select(".price"), Text(), Regexp(`[\d.,]+`), ParseFloat()).WithLocale(LocaleFrench)
+	v, err := Extract[float64](doc, price)
+	require.NoError(t, err)
+	require.Equal(t, 1299.0, v)
+
+	// Steps added by Then get the Locale, steps with their own keep it
+	date := Pipe(Select("time")).WithLocale(LocaleFrench).Then(ParseTime("2 January 2006"))
+	published, err := Extract[time.Time](doc, date)
+	require.NoError(t, err)
+	require.Equal(t, time.March, published.Month())
+	count := Pipe(Select(".count"), ParseInt().In(LocaleEnglish)).WithLocale(LocaleFrench)
+	_, err = Extract[int](doc, count)
+	require.Error(t, err)
+
+	// The original Pipeline is left as it is
+	_, err = Extract[float64](doc, Pipe(Select(".price"), Text(), Regexp(`[\d.,]+`), ParseFloat()))
+	require.NoError(t, err)
+
+	// A later Locale replaces the former one
+	stock := Pipe(Select(".count"), ParseFloat())
+	v, err = Extract[float64](doc, stock.WithLocale(LocaleFrench).WithLocale(LocaleEnglish))
+	require.NoError(t, err)
+	require.Equal(t, 1.2, v)
+	v, err = Extract[float64](doc, Pipe(Select(".count")).WithLocale(LocaleEnglish).WithLocale(LocaleFrench).Then(Text(), ParseFloat()))
+	require.NoError(t, err)
+	require.Equal(t, 1200.0, v)
+}
+
+func TestPipelineHostLocales(t *testing.T) {
+	page := func(rawURL string) *Root {
+		u, err := url.Parse(rawURL)
+		require.NoError(t, err)
+		return HTMLParse(strings.NewReader(`<span class="price">1.299</span>`)).SetURL(u)
+	}
+	price := Pipe(Select(".price"), ParseFloat()).WithHostLocales(HostLocales{
+		"example.de":   LocaleGerman,
+		"shop.example": LocaleEnglish,
+	})
+	for rawURL, want := range map[string]float64{
+		"https://example.de/owl":           1299,
+		"https://www.EXAMPLE.de/owl":       1299,
+		"https://shop.example/owl":         1.299,
+		"https://other.example/owl":        1.299,
+		"https://shop.example.de.evil/owl": 1.299,
+	} {
+		v, err := Extract[float64](page(rawURL), price)
+		require.NoError(t, err, rawURL)
+		require.Equal(t, want, v, rawURL)
+	}
+	// Other hosts get the Locale of the Pipeline
+	v, err := Extract[float64](page("https://other.example/owl"), price.WithLocale(LocaleFrench))
+	require.NoError(t, err)
+	require.Equal(t, 1299.0, v)
+	v, err = Extract[float64](HTMLParse(strings.NewReader(`<span class="price">1.299</span>`)), price.WithLocale(LocaleFrench))
+	require.NoError(t, err)
+	require.Equal(t, 1299.0, v)
+
+	_, ok := HostLocales{"example.de": LocaleGerman}.Lookup(nil)
+	require.False(t, ok)
+}
+
+func TestUnmarshalLocale(t *testing.T) {
+	doc := HTMLParse(strings.NewReader(`<h1>Chouette</h1><span class="price">1 299,50</span><span class="stock">1.200</span>
+		<time>12 mars 2024</time>`))
+	type product struct {
+		Name      string    `owl:"h1"`
+		Price     float64   `owl:".price"`
+		Stock     int       `owl:".stock"`
+		Published time.Time `owl:"time" layout:"2 January 2006"`
+		Updated   time.Time `owl:".updated" layout:"2006-01-02" default:"2024-01-01"`
+	}
+	var p product
+	require.NoError(t, UnmarshalLocale(doc, &p, LocaleFrench))
+	require.Equal(t, product{
+		Name:      "Chouette",
+		Price:     1299.5,
+		Stock:     1200,
+		Published: time.Date(2024, time.March, 12, 0, 0, 0, 0, time.UTC),
+		Updated:   time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+	}, p)
+
+	// Without a Locale numbers are parsed strictly
+	require.ErrorContains(t, Unmarshal(doc, &p), "field Price")
+}
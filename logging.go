@@ -0,0 +1,15 @@
+package owl
+
+// debug logs an event at debug level when c has a Logger
+func (c *Client) debug(msg string, args ...interface{}) {
+	if c.Logger != nil {
+		c.Logger.Debug(msg, args...)
+	}
+}
+
+// info logs an event at info level when c has a Logger
+func (c *Client) info(msg string, args ...interface{}) {
+	if c.Logger != nil {
+		c.Logger.Info(msg, args...)
+	}
+}
@@ -0,0 +1,66 @@
+package owl
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogger(t *testing.T) {
+	attempts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/old":
+			http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+		case "/flaky":
+			attempts++
+			if attempts == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			fallthrough
+		default:
+			w.Header().Set("Cache-Control", "max-age=60")
+			w.Write([]byte("<title>logged</title>"))
+		}
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := NewClient(&Parameters{HttpClient: srv.Client(), Logger: logger})
+	client.Retry = &RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+	client.Cache = NewMemoryCache()
+	client.Limiter = NewRateLimiter(RateLimit{MinDelay: 5 * time.Millisecond})
+
+	_, err := client.GetDocument(srv.URL + "/flaky")
+	require.NoError(t, err)
+	_, err = client.GetDocument(srv.URL + "/flaky")
+	require.NoError(t, err)
+	_, err = client.GetDocument(srv.URL + "/old")
+	require.NoError(t, err)
+
+	logs := buf.String()
+	for _, want := range []string{
+		`level=DEBUG msg="owl: request" method=GET url=` + srv.URL + "/flaky attempt=1 status=503",
+		`level=INFO msg="owl: retry" method=GET url=` + srv.URL + "/flaky attempt=1",
+		`msg="owl: request" method=GET url=` + srv.URL + "/flaky attempt=2 status=200",
+		`level=DEBUG msg="owl: cache hit" url=` + srv.URL + "/flaky",
+		`level=DEBUG msg="owl: redirect" from=` + srv.URL + "/old to=" + srv.URL + "/new",
+		`level=DEBUG msg="owl: rate limit wait" host=127.0.0.1`,
+	} {
+		require.Contains(t, logs, want)
+	}
+
+	// Nothing is logged without a Logger
+	client.Logger = nil
+	buf.Reset()
+	_, err = client.GetDocument(srv.URL + "/old")
+	require.NoError(t, err)
+	require.Empty(t, buf.String())
+}
@@ -0,0 +1,124 @@
+package owl
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"golang.org/x/net/html"
+)
+
+var (
+	// ErrNoLoginForm is returned by Login when the login page has no login form
+	ErrNoLoginForm = errors.New("owl: no login form")
+	// ErrLoginFailed is returned by Login when the response to the login form does not look like a success
+	ErrLoginFailed = errors.New("owl: login failed")
+)
+
+// LoginOption configures Client.Login
+type LoginOption func(*loginOptions)
+
+type loginOptions struct {
+	form, button, success string
+}
+
+// WithLoginForm picks the login form with a CSS selector matching the form or an element inside it,
+// instead of the first form with a password field
+func WithLoginForm(selector string) LoginOption {
+	return func(o *loginOptions) { o.form = selector }
+}
+
+// WithLoginButton submits the login form with the submit button of the name or value button
+func WithLoginButton(button string) LoginOption {
+	return func(o *loginOptions) { o.button = button }
+}
+
+// WithLoginSuccess makes Login succeed only when the response has an element matching the CSS selector,
+// such as a logout link
+func WithLoginSuccess(selector string) LoginOption {
+	return func(o *loginOptions) { o.success = selector }
+}
+
+// Login fetches the login page at loginURL, fills its login form with credentials, keyed by field name,
+// and submits it. Hidden fields such as CSRF tokens are sent as they are in the page.
+// The cookies of the session are kept in the cookie jar of the http.Client of c,
+// which gets a Jar when it has none, see SaveJar. Without WithLoginSuccess the login succeeds when
+// the response is not an error and does not hold a password field anymore
+func (c *Client) Login(loginURL string, credentials map[string]string, opts ...LoginOption) (*Response, error) {
+	var o loginOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	c.ensureJar()
+
+	page, err := c.response(http.MethodGet, loginURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	form := loginForm(page.Parse(), o.form)
+	if form == nil {
+		return nil, ErrNoLoginForm
+	}
+	for name, value := range credentials {
+		if err := form.Set(name, value); err != nil {
+			return nil, fmt.Errorf("owl: login field %q: %w", name, err)
+		}
+	}
+
+	var resp *Response
+	if o.button != "" {
+		resp, err = form.SubmitWith(c, o.button)
+	} else {
+		resp, err = form.Submit(c)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return resp, fmt.Errorf("%w: %d %s", ErrLoginFailed, resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+	doc := resp.Parse()
+	if o.success != "" {
+		if doc.SelectOne(o.success).Error != nil {
+			return resp, fmt.Errorf("%w: nothing matches %s", ErrLoginFailed, o.success)
+		}
+	} else if loginForm(doc, "") != nil {
+		return resp, fmt.Errorf("%w: the response holds a login form", ErrLoginFailed)
+	}
+	return resp, nil
+}
+
+// loginForm returns the form of the element matching selector, or the first form
+// with a password field when selector is empty. It returns nil when there is none
+func loginForm(doc *Root, selector string) *Form {
+	if selector != "" {
+		return formAt(doc, selector)
+	}
+	for _, form := range doc.Forms() {
+		for _, field := range form.Fields {
+			if field.Type == "password" {
+				return form
+			}
+		}
+	}
+	return nil
+}
+
+// formAt returns the first form of the element matching selector: the form it is in,
+// or else the first form below it. It returns nil when there is none
+func formAt(doc *Root, selector string) *Form {
+	match := doc.SelectOne(selector)
+	if match.Error != nil {
+		return nil
+	}
+	for p := match.Node; p != nil; p = p.Parent {
+		if p.Type == html.ElementNode && p.Data == "form" {
+			match = &Root{Node: p, NodeValue: p.Data, doc: doc.doc}
+			break
+		}
+	}
+	if forms := match.Forms(); len(forms) > 0 {
+		return forms[0]
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package owl
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogin(t *testing.T) {
+	const loginPage = `<form action="/search"><input name="q"></form>
+		<form id="login" action="/session" method="post">
+			<input type="hidden" name="csrf" value="secret">
+			<input name="user"><input type="password" name="pass">
+			<button name="action" value="login">Log in</button>
+		</form>`
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "csrf", Value: "secret"})
+		fmt.Fprint(w, loginPage)
+	})
+	mux.HandleFunc("/session", func(w http.ResponseWriter, r *http.Request) {
+		csrf, err := r.Cookie("csrf")
+		if err != nil || csrf.Value != r.FormValue("csrf") || r.FormValue("action") != "login" ||
+			r.FormValue("user") != "hedwig" || r.FormValue("pass") != "letters" {
+			fmt.Fprint(w, loginPage)
+			return
+		}
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "42"})
+		http.Redirect(w, r, "/home", http.StatusSeeOther)
+	})
+	mux.HandleFunc("/home", func(w http.ResponseWriter, r *http.Request) {
+		if _, err := r.Cookie("session"); err != nil {
+			http.Error(w, "not logged in", http.StatusForbidden)
+			return
+		}
+		fmt.Fprint(w, `<a class="logout" href="/logout">Log out</a>`)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := &Client{RequestTimeout: time.Second}
+	_, err := client.Login(srv.URL+"/login", map[string]string{"user": "hedwig", "pass": "wrong"})
+	require.True(t, errors.Is(err, ErrLoginFailed))
+
+	resp, err := client.Login(srv.URL+"/login", map[string]string{"user": "hedwig", "pass": "letters"}, WithLoginSuccess("a.logout"))
+	require.NoError(t, err)
+	require.Equal(t, srv.URL+"/home", resp.FinalURL.String())
+	home, err := client.GetDocument(srv.URL + "/home")
+	require.NoError(t, err)
+	require.Equal(t, "Log out", home.SelectOne("a").Text())
+
+	_, err = client.Login(srv.URL+"/login", map[string]string{"q": "owls"}, WithLoginForm("input[name=q]"), WithLoginSuccess("a.logout"))
+	require.True(t, errors.Is(err, ErrLoginFailed))
+	_, err = client.Login(srv.URL+"/login", map[string]string{"email": "x"})
+	require.True(t, errors.Is(err, ErrNoFormField))
+	_, err = client.Login(srv.URL+"/home", nil)
+	require.Equal(t, ErrNoLoginForm, err)
+}
@@ -0,0 +1,399 @@
+package owl
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// MarkdownOption configures Markdown
+type MarkdownOption func(*markdownConfig)
+
+type markdownConfig struct {
+	base   *url.URL
+	bullet string
+	fence  string
+}
+
+// MarkdownBaseURL resolves the URLs of links and images against base
+func MarkdownBaseURL(base *url.URL) MarkdownOption {
+	return func(c *markdownConfig) {
+		c.base = base
+	}
+}
+
+// MarkdownBullet sets the marker of unordered list items, "-" by default
+func MarkdownBullet(marker string) MarkdownOption {
+	return func(c *markdownConfig) {
+		c.bullet = marker
+	}
+}
+
+// MarkdownFence sets the fence of code blocks, "```" by default
+func MarkdownFence(fence string) MarkdownOption {
+	return func(c *markdownConfig) {
+		c.fence = fence
+	}
+}
+
+// inlineElements are laid out inside a paragraph by Markdown, every other element is a block
+var inlineElements = map[string]bool{
+	"a": true, "abbr": true, "b": true, "bdi": true, "bdo": true, "br": true, "cite": true,
+	"code": true, "data": true, "del": true, "dfn": true, "em": true, "font": true, "i": true,
+	"img": true, "ins": true, "kbd": true, "label": true, "mark": true, "q": true, "s": true,
+	"samp": true, "small": true, "span": true, "strike": true, "strong": true, "sub": true,
+	"sup": true, "time": true, "u": true, "var": true, "wbr": true,
+}
+
+// Markdown converts the Node and everything below it to Markdown,
+// covering headings, paragraphs, links, images, emphasis, lists, code, blockquotes and tables
+func (r *Root) Markdown(opts ...MarkdownOption) string {
+	m := markdown{cfg: markdownConfig{bullet: "-", fence: "```"}}
+	for _, opt := range opts {
+		opt(&m.cfg)
+	}
+	return strings.Join(m.blocks([]*html.Node{r.Node}), "\n\n")
+}
+
+type markdown struct {
+	cfg markdownConfig
+}
+
+// blocks converts nodes into Markdown blocks, runs of inline nodes become paragraphs
+func (m *markdown) blocks(nodes []*html.Node) []string {
+	var out []string
+	var run strings.Builder
+	flush := func() {
+		if p := paragraph(run.String()); p != "" {
+			out = append(out, p)
+		}
+		run.Reset()
+	}
+	for _, n := range nodes {
+		if n.Type == html.ElementNode && !inlineElements[n.Data] {
+			flush()
+			if b := m.block(n); b != "" {
+				out = append(out, b)
+			}
+			continue
+		}
+		m.inline(&run, n)
+	}
+	flush()
+	return out
+}
+
+func (m *markdown) children(n *html.Node) []string {
+	var nodes []*html.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		nodes = append(nodes, c)
+	}
+	return m.blocks(nodes)
+}
+
+func (m *markdown) block(n *html.Node) string {
+	if hiddenElements[n.Data] {
+		return ""
+	}
+	switch n.Data {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		text := m.inlineText(n)
+		if text == "" {
+			return ""
+		}
+		return strings.Repeat("#", int(n.Data[1]-'0')) + " " + text
+	case "hr":
+		return "---"
+	case "ul", "ol":
+		return m.list(n)
+	case "pre":
+		return m.code(n)
+	case "blockquote":
+		inner := strings.Join(m.children(n), "\n\n")
+		if inner == "" {
+			return ""
+		}
+		lines := strings.Split(inner, "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimRight("> "+line, " ")
+		}
+		return strings.Join(lines, "\n")
+	case "table":
+		return m.table(n)
+	}
+	return strings.Join(m.children(n), "\n\n")
+}
+
+func (m *markdown) list(n *html.Node) string {
+	ordered := n.Data == "ol"
+	number := 1
+	if start, err := strconv.Atoi(attrValue(n, "start")); err == nil && ordered {
+		number = start
+	}
+	var items []string
+	for li := n.FirstChild; li != nil; li = li.NextSibling {
+		if li.Type != html.ElementNode || li.Data != "li" {
+			continue
+		}
+		marker := m.cfg.bullet
+		if ordered {
+			marker = strconv.Itoa(number) + "."
+			number++
+		}
+		// Items holding paragraphs make a loose list, separated by blank lines
+		sep := "\n"
+		for c := li.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode && c.Data == "p" {
+				sep = "\n\n"
+			}
+		}
+		content := strings.Join(m.children(li), sep)
+		indent := strings.Repeat(" ", len(marker)+1)
+		lines := strings.Split(content, "\n")
+		for i := 1; i < len(lines); i++ {
+			if lines[i] != "" {
+				lines[i] = indent + lines[i]
+			}
+		}
+		items = append(items, marker+" "+strings.Join(lines, "\n"))
+	}
+	return strings.Join(items, "\n")
+}
+
+func (m *markdown) code(n *html.Node) string {
+	var b strings.Builder
+	eachText(n, func(s string) bool {
+		b.WriteString(s)
+		return true
+	})
+	code := strings.TrimSuffix(b.String(), "\n")
+
+	lang := language(n)
+	for c := n.FirstChild; c != nil && lang == ""; c = c.NextSibling {
+		if c.Type == html.ElementNode && c.Data == "code" {
+			lang = language(c)
+		}
+	}
+	fence := m.cfg.fence
+	for strings.Contains(code, fence) {
+		fence += fence[:1]
+	}
+	return fence + lang + "\n" + code + "\n" + fence
+}
+
+// language returns the language of a code element from a language-* or lang-* class
+func language(n *html.Node) string {
+	for _, class := range strings.Fields(attrValue(n, "class")) {
+		for _, prefix := range []string{"language-", "lang-"} {
+			if strings.HasPrefix(class, prefix) {
+				return strings.TrimPrefix(class, prefix)
+			}
+		}
+	}
+	return ""
+}
+
+func (m *markdown) table(n *html.Node) string {
+	var rows [][]string
+	header := false
+	walk(n, func(c *html.Node) bool {
+		if c.Type != html.ElementNode {
+			return false
+		}
+		if c != n && c.Data == "table" {
+			return false
+		}
+		if c.Data != "tr" {
+			return true
+		}
+		var row []string
+		for cell := c.FirstChild; cell != nil; cell = cell.NextSibling {
+			if cell.Type != html.ElementNode || (cell.Data != "td" && cell.Data != "th") {
+				continue
+			}
+			if cell.Data == "th" && len(rows) == 0 {
+				header = true
+			}
+			row = append(row, strings.ReplaceAll(m.inlineText(cell), "|", "\\|"))
+		}
+		rows = append(rows, row)
+		return false
+	})
+	if len(rows) == 0 {
+		return ""
+	}
+	columns := 0
+	for _, row := range rows {
+		if len(row) > columns {
+			columns = len(row)
+		}
+	}
+	if columns == 0 {
+		return ""
+	}
+	// Markdown tables always have a header row, an empty one is used when the table has none
+	if !header {
+		rows = append([][]string{make([]string, columns)}, rows...)
+	}
+	var b strings.Builder
+	for i, row := range rows {
+		for len(row) < columns {
+			row = append(row, "")
+		}
+		b.WriteString("| " + strings.Join(row, " | ") + " |\n")
+		if i == 0 {
+			b.WriteString(strings.Repeat("| --- ", columns) + "|\n")
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// inlineText converts the children of n to inline Markdown on a single line
+func (m *markdown) inlineText(n *html.Node) string {
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		m.inline(&b, c)
+	}
+	return strings.Join(strings.Fields(strings.ReplaceAll(b.String(), hardBreak, " ")), " ")
+}
+
+func (m *markdown) inline(b *strings.Builder, n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		b.WriteString(escapeMarkdown(collapseWhitespace(n.Data)))
+		return
+	case html.ElementNode:
+	default:
+		return
+	}
+	if hiddenElements[n.Data] {
+		return
+	}
+	switch n.Data {
+	case "br":
+		b.WriteString(hardBreak)
+	case "strong", "b":
+		m.emphasis(b, n, "**")
+	case "em", "i":
+		m.emphasis(b, n, "*")
+	case "del", "s", "strike":
+		m.emphasis(b, n, "~~")
+	case "code", "kbd", "samp":
+		var text strings.Builder
+		eachText(n, func(s string) bool {
+			text.WriteString(s)
+			return true
+		})
+		code := collapseWhitespace(text.String())
+		if code == "" {
+			return
+		}
+		ticks := "`"
+		for strings.Contains(code, ticks) {
+			ticks += "`"
+		}
+		if strings.HasPrefix(code, "`") || strings.HasSuffix(code, "`") {
+			code = " " + code + " "
+		}
+		b.WriteString(ticks + code + ticks)
+	case "a":
+		text := m.inlineText(n)
+		href := strings.TrimSpace(attrValue(n, "href"))
+		if href == "" {
+			b.WriteString(text)
+			return
+		}
+		fmt.Fprintf(b, "[%s](%s%s)", text, m.resolve(href), title(n))
+	case "img":
+		src := strings.TrimSpace(attrValue(n, "src"))
+		if src == "" {
+			return
+		}
+		fmt.Fprintf(b, "![%s](%s%s)", escapeMarkdown(attrValue(n, "alt")), m.resolve(src), title(n))
+	default:
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode && !inlineElements[c.Data] {
+				// Blocks nested in inline elements are kept on the same paragraph
+				b.WriteString(" " + m.inlineText(c) + " ")
+				continue
+			}
+			m.inline(b, c)
+		}
+	}
+}
+
+// emphasis wraps the inline Markdown of n in marker, keeping surrounding whitespace outside of it
+func (m *markdown) emphasis(b *strings.Builder, n *html.Node, marker string) {
+	var inner strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		m.inline(&inner, c)
+	}
+	s := inner.String()
+	trimmed := strings.TrimSpace(s)
+	if trimmed == "" {
+		b.WriteString(s)
+		return
+	}
+	if strings.TrimLeft(s, " \t\n") != s {
+		b.WriteString(" ")
+	}
+	b.WriteString(marker + trimmed + marker)
+	if strings.TrimRight(s, " \t\n") != s {
+		b.WriteString(" ")
+	}
+}
+
+func (m *markdown) resolve(ref string) string {
+	if m.cfg.base != nil {
+		if u, err := url.Parse(ref); err == nil {
+			ref = m.cfg.base.ResolveReference(u).String()
+		}
+	}
+	return strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29").Replace(ref)
+}
+
+// title returns the Markdown link title for the title attribute of n
+func title(n *html.Node) string {
+	t := attrValue(n, "title")
+	if t == "" {
+		return ""
+	}
+	return ` "` + strings.ReplaceAll(t, `"`, `\"`) + `"`
+}
+
+// hardBreak marks a br in an inline run until the run is laid out,
+// the parser never leaves a NUL in text nodes
+const hardBreak = "\x00"
+
+// paragraph lays out an inline run: whitespace is collapsed, line breaks become
+// Markdown hard breaks and the whole run is dropped when blank
+func paragraph(s string) string {
+	var lines []string
+	for _, line := range strings.Split(s, hardBreak) {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\\\n")
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`,
+)
+
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+// attrValue returns the value of the first attribute of n named key
+func attrValue(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
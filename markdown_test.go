@@ -0,0 +1,57 @@
+package owl
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const markdownHTML = `<article>
+  <h1>Release <em>notes</em></h1>
+  <p>Owl is a <strong>small</strong> scraping library, see <a href="/docs" title="Docs">the docs</a>.<br>
+     Second line with <code>Find()</code> and a_snake.</p>
+  <img src="logo.png" alt="Owl logo">
+  <ul>
+    <li>One</li>
+    <li>Two
+      <ol start="3"><li>Three</li><li>Four</li></ol>
+    </li>
+  </ul>
+  <blockquote><p>Quoted</p><p>Twice</p></blockquote>
+  <pre><code class="language-go">func main() {
+	fmt.Println("hi")
+}
+</code></pre>
+  <table>
+    <thead><tr><th>Name</th><th>Price</th></tr></thead>
+    <tbody><tr><td>Tea | green</td><td>2.50</td></tr><tr><td>Coffee</td></tr></tbody>
+  </table>
+  <script>ignored()</script>
+</article>`
+
+func TestMarkdown(t *testing.T) {
+	base, _ := url.Parse("https://example.com/blog/")
+	article := HTMLParseFromString(markdownHTML).Find("article")
+
+	expected := "# Release *notes*\n\n" +
+		"Owl is a **small** scraping library, see [the docs](https://example.com/docs \"Docs\").\\\n" +
+		"Second line with `Find()` and a\\_snake.\n\n" +
+		"![Owl logo](https://example.com/blog/logo.png)\n\n" +
+		"- One\n" +
+		"- Two\n" +
+		"  3. Three\n" +
+		"  4. Four\n\n" +
+		"> Quoted\n>\n> Twice\n\n" +
+		"```go\nfunc main() {\n\tfmt.Println(\"hi\")\n}\n```\n\n" +
+		"| Name | Price |\n| --- | --- |\n| Tea \\| green | 2.50 |\n| Coffee |  |"
+	require.Equal(t, expected, article.Markdown(MarkdownBaseURL(base)))
+}
+
+func TestMarkdownOptions(t *testing.T) {
+	ul := HtmlRoot.Find("ul")
+	require.Equal(t, "* To a [JSP page](hello.jsp) right?\n* To a [servlet](hello)", ul.Markdown(MarkdownBullet("*")))
+
+	pre := HTMLParseFromString("<pre>a ``` b</pre>").Find("pre")
+	require.Equal(t, "````\na ``` b\n````", pre.Markdown())
+}
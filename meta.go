@@ -0,0 +1,119 @@
+package owl
+
+import (
+	"mime"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// MetaInfo holds the metadata of a document found in its title, meta and link elements
+type MetaInfo struct {
+	Title       string
+	Description string
+	// Canonical is the href of the canonical link, resolved against BaseURL when the document has one
+	Canonical string
+	// Robots holds the lowercased directives of the robots meta tag, such as "noindex"
+	Robots []string
+	// Charset is declared by a meta charset or a Content-Type http-equiv, empty when neither is present
+	Charset  string
+	Viewport string
+	// Alternates lists the rel=alternate links with an hreflang attribute
+	Alternates []Alternate
+	// Next and Prev are the rel=next and rel=prev links of paginated documents
+	Next string
+	Prev string
+}
+
+// Alternate is a translation of the document
+type Alternate struct {
+	Hreflang string
+	Href     string
+}
+
+// HasRobots reports whether the robots meta tag holds directive, "none" implies noindex and nofollow
+func (m MetaInfo) HasRobots(directive string) bool {
+	directive = strings.ToLower(directive)
+	for _, d := range m.Robots {
+		if d == directive || (d == "none" && (directive == "noindex" || directive == "nofollow")) {
+			return true
+		}
+	}
+	return false
+}
+
+// Meta collects the metadata of the document the Node belongs to,
+// the first occurrence of each element wins
+func (r *Root) Meta() MetaInfo {
+	var info MetaInfo
+	if r.Node == nil {
+		return info
+	}
+	top := r.Node
+	for top.Parent != nil {
+		top = top.Parent
+	}
+	walk(top, func(n *html.Node) bool {
+		if n.Type != html.ElementNode {
+			return true
+		}
+		switch n.Data {
+		case "title":
+			if info.Title == "" {
+				info.Title = strings.Join(strings.Fields((&Root{Node: n}).FullText()), " ")
+			}
+		case "meta":
+			content := strings.TrimSpace(attrValue(n, "content"))
+			if cs := strings.TrimSpace(attrValue(n, "charset")); cs != "" && info.Charset == "" {
+				info.Charset = strings.ToLower(cs)
+			}
+			if strings.EqualFold(attrValue(n, "http-equiv"), "content-type") && info.Charset == "" {
+				if _, params, err := mime.ParseMediaType(content); err == nil {
+					info.Charset = strings.ToLower(params["charset"])
+				}
+			}
+			switch strings.ToLower(attrValue(n, "name")) {
+			case "description":
+				if info.Description == "" {
+					info.Description = content
+				}
+			case "robots":
+				if info.Robots == nil {
+					for _, d := range strings.Split(strings.ToLower(content), ",") {
+						if d = strings.TrimSpace(d); d != "" {
+							info.Robots = append(info.Robots, d)
+						}
+					}
+				}
+			case "viewport":
+				if info.Viewport == "" {
+					info.Viewport = content
+				}
+			}
+		case "link":
+			href, ok := attrLookup(n, "href")
+			if !ok {
+				return true
+			}
+			rel := strings.Fields(strings.ToLower(attrValue(n, "rel")))
+			switch {
+			case containsString(rel, "canonical") && info.Canonical == "":
+				info.Canonical = r.resolveString(href)
+			case containsString(rel, "alternate") && attrValue(n, "hreflang") != "":
+				info.Alternates = append(info.Alternates, Alternate{
+					Hreflang: strings.TrimSpace(attrValue(n, "hreflang")),
+					Href:     r.resolveString(href),
+				})
+			case containsString(rel, "next") && info.Next == "":
+				info.Next = r.resolveString(href)
+			case containsString(rel, "prev") && info.Prev == "":
+				info.Prev = r.resolveString(href)
+			}
+		case "svg", "template":
+			// The title of an svg is not the title of the document
+			return false
+		}
+		return true
+	})
+	return info
+}
@@ -0,0 +1,46 @@
+package owl
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMeta(t *testing.T) {
+	doc := HTMLParseFromString(`<html><head>
+		<meta http-equiv="Content-Type" content="text/html; charset=ISO-8859-1">
+		<title> Owls |
+			Birds </title>
+		<meta name="Description" content=" All about owls ">
+		<meta name="robots" content="NOINDEX, follow">
+		<meta name="viewport" content="width=device-width, initial-scale=1">
+		<link rel="canonical" href="/owls">
+		<link rel="alternate" hreflang="de" href="https://example.de/eulen">
+		<link rel="alternate" hreflang="x-default" href="/owls">
+		<link rel="alternate" type="application/rss+xml" href="/feed">
+		<link rel="next" href="?page=2">
+	</head><body><svg><title>Icon</title></svg></body></html>`)
+	page, _ := url.Parse("https://example.com/birds/owls?page=1")
+	meta := doc.SetURL(page).Find("body").Meta()
+
+	require.Equal(t, "Owls | Birds", meta.Title)
+	require.Equal(t, "All about owls", meta.Description)
+	require.Equal(t, "iso-8859-1", meta.Charset)
+	require.Equal(t, []string{"noindex", "follow"}, meta.Robots)
+	require.True(t, meta.HasRobots("noindex"))
+	require.False(t, meta.HasRobots("nofollow"))
+	require.Equal(t, "width=device-width, initial-scale=1", meta.Viewport)
+	require.Equal(t, "https://example.com/owls", meta.Canonical)
+	require.Equal(t, []Alternate{
+		{Hreflang: "de", Href: "https://example.de/eulen"},
+		{Hreflang: "x-default", Href: "https://example.com/owls"},
+	}, meta.Alternates)
+	require.Equal(t, "https://example.com/birds/owls?page=2", meta.Next)
+	require.Empty(t, meta.Prev)
+
+	none := HTMLParseFromString(`<html><head><meta charset="UTF-8"><meta name="robots" content="none"><link rel="canonical" href="/owls"></head></html>`).Meta()
+	require.Equal(t, "utf-8", none.Charset)
+	require.True(t, none.HasRobots("nofollow"))
+	require.Equal(t, "/owls", none.Canonical)
+}
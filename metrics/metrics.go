@@ -0,0 +1,205 @@
+// Package metrics collects the measures of owl Clients and exposes them to Prometheus
+// in its text exposition format, without depending on the Prometheus client library:
+//
+//	m := metrics.NewPrometheus("crawler")
+//	client.Metrics = m
+//	http.Handle("/metrics", m)
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Patrickmitech/owl"
+)
+
+// DefaultBuckets are the upper bounds in seconds of the buckets of the request duration histogram
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Prometheus is an owl.Metrics counting requests by method and status class, retries, downloaded bytes
+// and cache lookups, with a histogram of request durations by method. It serves them over HTTP to Prometheus.
+// Hosts are left out of the labels, crawlers visit too many of them
+type Prometheus struct {
+	namespace string
+	buckets   []float64
+
+	mu         sync.Mutex
+	requests   map[[2]string]float64
+	durations  map[string]*histogram
+	retries    map[string]float64
+	downloaded float64
+	cache      map[string]float64
+}
+
+type histogram struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+var (
+	_ owl.Metrics  = (*Prometheus)(nil)
+	_ http.Handler = (*Prometheus)(nil)
+)
+
+// NewPrometheus returns a Prometheus whose metric names start with namespace, "owl" when it is empty
+func NewPrometheus(namespace string) *Prometheus {
+	if namespace == "" {
+		namespace = "owl"
+	}
+	return &Prometheus{
+		namespace: namespace,
+		buckets:   DefaultBuckets,
+		requests:  make(map[[2]string]float64),
+		durations: make(map[string]*histogram),
+		retries:   make(map[string]float64),
+		cache:     make(map[string]float64),
+	}
+}
+
+// Request implements owl.Metrics
+func (p *Prometheus) Request(method, host string, status int, duration time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.requests[[2]string{method, statusClass(status)}]++
+	h, ok := p.durations[method]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(p.buckets))}
+		p.durations[method] = h
+	}
+	seconds := duration.Seconds()
+	for i, bound := range p.buckets {
+		if seconds <= bound {
+			h.counts[i]++
+		}
+	}
+	h.sum += seconds
+	h.count++
+}
+
+// Retry implements owl.Metrics
+func (p *Prometheus) Retry(method, host string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.retries[method]++
+}
+
+// Downloaded implements owl.Metrics
+func (p *Prometheus) Downloaded(host string, bytes int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.downloaded += float64(bytes)
+}
+
+// CacheLookup implements owl.Metrics
+func (p *Prometheus) CacheLookup(host string, hit bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if hit {
+		p.cache["hit"]++
+	} else {
+		p.cache["miss"]++
+	}
+}
+
+// ServeHTTP serves the metrics in the Prometheus text format
+func (p *Prometheus) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	p.WriteTo(w)
+}
+
+// WriteTo writes the metrics to w in the Prometheus text format
+func (p *Prometheus) WriteTo(w io.Writer) (int64, error) {
+	p.mu.Lock()
+	var b strings.Builder
+	name := p.namespace + "_requests_total"
+	header(&b, name, "counter", "Requests sent by method and status class, retries included.")
+	for _, key := range sortedKeys(p.requests, func(k [2]string) string { return k[0] + " " + k[1] }) {
+		sample(&b, name, p.requests[key], "method", key[0], "class", key[1])
+	}
+
+	name = p.namespace + "_request_duration_seconds"
+	header(&b, name, "histogram", "Time until the response headers arrived by method.")
+	for _, method := range sortedKeys(p.durations, func(k string) string { return k }) {
+		h := p.durations[method]
+		for i, bound := range p.buckets {
+			sample(&b, name+"_bucket", float64(h.counts[i]), "method", method, "le", formatFloat(bound))
+		}
+		sample(&b, name+"_bucket", float64(h.count), "method", method, "le", "+Inf")
+		sample(&b, name+"_sum", h.sum, "method", method)
+		sample(&b, name+"_count", float64(h.count), "method", method)
+	}
+
+	name = p.namespace + "_retries_total"
+	header(&b, name, "counter", "Requests sent again by method.")
+	for _, method := range sortedKeys(p.retries, func(k string) string { return k }) {
+		sample(&b, name, p.retries[method], "method", method)
+	}
+
+	name = p.namespace + "_downloaded_bytes_total"
+	header(&b, name, "counter", "Bytes read from response bodies.")
+	sample(&b, name, p.downloaded)
+
+	name = p.namespace + "_cache_lookups_total"
+	header(&b, name, "counter", "Lookups of the cache by result.")
+	for _, result := range []string{"hit", "miss"} {
+		sample(&b, name, p.cache[result], "result", result)
+	}
+	p.mu.Unlock()
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// statusClass returns the class of status such as 2xx, or error for failed requests
+func statusClass(status int) string {
+	if status < 100 || status > 599 {
+		return "error"
+	}
+	return strconv.Itoa(status/100) + "xx"
+}
+
+func header(b *strings.Builder, name, kind, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// sample writes a sample of name with labels given as name and value pairs
+func sample(b *strings.Builder, name string, value float64, labels ...string) {
+	b.WriteString(name)
+	if len(labels) > 0 {
+		b.WriteByte('{')
+		for i := 0; i < len(labels); i += 2 {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			fmt.Fprintf(b, "%s=%q", labels[i], labels[i+1])
+		}
+		b.WriteByte('}')
+	}
+	b.WriteByte(' ')
+	b.WriteString(formatFloat(value))
+	b.WriteByte('\n')
+}
+
+func formatFloat(f float64) string {
+	if math.IsInf(f, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func sortedKeys[K comparable, V any](m map[K]V, key func(K) string) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return key(keys[i]) < key(keys[j]) })
+	return keys
+}
@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Patrickmitech/owl"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheus(t *testing.T) {
+	attempts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/flaky":
+			attempts++
+			if attempts == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+		case "/missing":
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Write([]byte("<title>owl</title>"))
+	}))
+	defer srv.Close()
+
+	m := NewPrometheus("")
+	client := &owl.Client{
+		Client:  srv.Client(),
+		Metrics: m,
+		Retry:   &owl.RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond},
+		Cache:   owl.NewMemoryCache(),
+	}
+	for _, path := range []string{"/flaky", "/missing", "/flaky"} {
+		_, err := client.GetDocument(srv.URL + path)
+		require.NoError(t, err)
+	}
+
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Contains(t, rec.Header().Get("Content-Type"), "version=0.0.4")
+	body := rec.Body.String()
+	for _, line := range []string{
+		"# TYPE owl_requests_total counter",
+		`owl_requests_total{method="GET",class="2xx"} 1`,
+		`owl_requests_total{method="GET",class="4xx"} 1`,
+		`owl_requests_total{method="GET",class="5xx"} 1`,
+		"# TYPE owl_request_duration_seconds histogram",
+		`owl_request_duration_seconds_bucket{method="GET",le="+Inf"} 3`,
+		`owl_request_duration_seconds_count{method="GET"} 3`,
+		`owl_retries_total{method="GET"} 1`,
+		"owl_downloaded_bytes_total 37",
+		`owl_cache_lookups_total{result="hit"} 1`,
+		`owl_cache_lookups_total{result="miss"} 2`,
+	} {
+		require.Contains(t, body, line+"\n")
+	}
+
+	m.Request(http.MethodPost, "example.com", 0, 20*time.Millisecond)
+	var b strings.Builder
+	_, err := m.WriteTo(&b)
+	require.NoError(t, err)
+	require.Contains(t, b.String(), `owl_requests_total{method="POST",class="error"} 1`)
+	require.Contains(t, b.String(), `owl_request_duration_seconds_bucket{method="POST",le="0.01"} 0`)
+	require.Contains(t, b.String(), `owl_request_duration_seconds_bucket{method="POST",le="0.025"} 1`)
+}
@@ -0,0 +1,566 @@
+// Package mf2 parses the microformats2 of a page parsed by owl into the canonical JSON structure
+// of the microformats2 parsing specification, see https://microformats.org/wiki/microformats2-parsing
+package mf2
+
+import (
+	"bytes"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Patrickmitech/owl"
+	"golang.org/x/net/html"
+)
+
+// Data is the result of parsing a document, it marshals to the canonical JSON
+type Data struct {
+	Items   []*Item             `json:"items"`
+	Rels    map[string][]string `json:"rels"`
+	RelURLs map[string]*RelURL  `json:"rel-urls"`
+}
+
+// Item is a microformat, an element with an h-* class.
+// Property values are strings, *Embedded for e-* properties, or *Item for nested microformats
+type Item struct {
+	Type       []string         `json:"type"`
+	Properties map[string][]any `json:"properties"`
+	ID         string           `json:"id,omitempty"`
+	Children   []*Item          `json:"children,omitempty"`
+	// Value is set on microformats that are the value of a property, it holds the value the property would have
+	Value any `json:"value,omitempty"`
+}
+
+// Embedded is the value of an e-* property
+type Embedded struct {
+	HTML  string `json:"html"`
+	Value string `json:"value"`
+}
+
+// RelURL describes a URL linked with a rel attribute
+type RelURL struct {
+	Rels     []string `json:"rels"`
+	Text     string   `json:"text,omitempty"`
+	Title    string   `json:"title,omitempty"`
+	Media    string   `json:"media,omitempty"`
+	Hreflang string   `json:"hreflang,omitempty"`
+	Type     string   `json:"type,omitempty"`
+}
+
+// String returns the first string value of the property name, the Value of a nested microformat counts.
+// It returns an empty string when there is none
+func (item *Item) String(name string) string {
+	for _, v := range item.Properties[name] {
+		switch v := v.(type) {
+		case string:
+			return v
+		case *Embedded:
+			return v.Value
+		case *Item:
+			if s, ok := v.Value.(string); ok {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+var (
+	rootClass     = regexp.MustCompile(`^h(-[a-z0-9]+)?(-[a-z]+)+$`)
+	propertyClass = regexp.MustCompile(`^(p|u|dt|e)-((?:[a-z0-9]+-)?[a-z]+(?:-[a-z]+)*)$`)
+)
+
+// Parse returns the microformats below the Node of root and the rel links of its document.
+// URLs are resolved against the BaseURL of root when it has one
+func Parse(root *owl.Root) *Data {
+	data := &Data{
+		Items:   []*Item{},
+		Rels:    map[string][]string{},
+		RelURLs: map[string]*RelURL{},
+	}
+	if root == nil || root.Node == nil {
+		return data
+	}
+	p := &parser{root: root}
+	var find func(n *html.Node)
+	find = func(n *html.Node) {
+		if n.Type == html.ElementNode && isRoot(n) {
+			data.Items = append(data.Items, p.item(n))
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			find(c)
+		}
+	}
+	find(root.Node)
+	p.rels(root.Node, data)
+	return data
+}
+
+type parser struct {
+	root *owl.Root
+}
+
+// found records which kinds of properties an item has, to decide on implied properties
+type found struct {
+	p, u, e, nested bool
+}
+
+// item parses the microformat of the root element n
+func (p *parser) item(n *html.Node) *Item {
+	roots, _ := classes(n)
+	item := &Item{Type: roots, Properties: map[string][]any{}}
+	if id := strings.TrimSpace(attr(n, "id")); id != "" {
+		item.ID = id
+	}
+	var f found
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		p.children(c, item, &f)
+	}
+	if _, ok := item.Properties["name"]; !ok && !f.p && !f.e && !f.nested {
+		item.Properties["name"] = []any{p.impliedName(n)}
+	}
+	if _, ok := item.Properties["photo"]; !ok && !f.u {
+		if photo, ok := p.impliedURL(n, photoSources); ok {
+			item.Properties["photo"] = []any{photo}
+		}
+	}
+	if _, ok := item.Properties["url"]; !ok && !f.u {
+		if u, ok := p.impliedURL(n, urlSources); ok {
+			item.Properties["url"] = []any{u}
+		}
+	}
+	return item
+}
+
+// children parses the properties and microformats of n and below it into item
+func (p *parser) children(n *html.Node, item *Item, f *found) {
+	if n.Type != html.ElementNode {
+		return
+	}
+	roots, props := classes(n)
+	if len(roots) > 0 {
+		child := p.item(n)
+		if len(props) == 0 {
+			item.Children = append(item.Children, child)
+			return
+		}
+		f.nested = true
+		for _, prop := range props {
+			nested := *child
+			switch prop.prefix {
+			case "p":
+				f.p = true
+				nested.Value = first(child.String("name"), p.text(n))
+			case "u":
+				f.u = true
+				nested.Value = first(child.String("url"), p.uValue(n))
+			case "dt":
+				nested.Value = p.dtValue(n)
+			case "e":
+				f.e = true
+				nested.Value = p.eValue(n).Value
+			}
+			item.Properties[prop.name] = append(item.Properties[prop.name], &nested)
+		}
+		return
+	}
+	for _, prop := range props {
+		var value any
+		switch prop.prefix {
+		case "p":
+			f.p = true
+			value = p.pValue(n)
+		case "u":
+			f.u = true
+			value = p.uValue(n)
+		case "dt":
+			value = p.dtValue(n)
+		case "e":
+			f.e = true
+			value = p.eValue(n)
+		}
+		item.Properties[prop.name] = append(item.Properties[prop.name], value)
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		p.children(c, item, f)
+	}
+}
+
+// pValue parses a p-* property
+func (p *parser) pValue(n *html.Node) string {
+	if v, ok := valueClass(n); ok {
+		return v
+	}
+	switch n.Data {
+	case "abbr", "link":
+		if v, ok := lookup(n, "title"); ok {
+			return v
+		}
+	case "data", "input":
+		if v, ok := lookup(n, "value"); ok {
+			return v
+		}
+	case "img", "area":
+		if v, ok := lookup(n, "alt"); ok {
+			return v
+		}
+	}
+	return p.text(n)
+}
+
+// uValue parses a u-* property
+func (p *parser) uValue(n *html.Node) string {
+	key := map[string]string{
+		"a": "href", "area": "href", "link": "href",
+		"img": "src", "audio": "src", "video": "src", "source": "src", "iframe": "src",
+		"object": "data",
+	}[n.Data]
+	if v, ok := lookup(n, key); ok && key != "" {
+		return p.resolve(v)
+	}
+	if v, ok := lookup(n, "poster"); ok && n.Data == "video" {
+		return p.resolve(v)
+	}
+	if v, ok := valueClass(n); ok {
+		return p.resolve(v)
+	}
+	switch n.Data {
+	case "abbr":
+		if v, ok := lookup(n, "title"); ok {
+			return p.resolve(v)
+		}
+	case "data", "input":
+		if v, ok := lookup(n, "value"); ok {
+			return p.resolve(v)
+		}
+	}
+	return p.resolve(p.text(n))
+}
+
+// dtValue parses a dt-* property
+func (p *parser) dtValue(n *html.Node) string {
+	if v, ok := valueClass(n); ok {
+		return v
+	}
+	switch n.Data {
+	case "time", "ins", "del":
+		if v, ok := lookup(n, "datetime"); ok {
+			return strings.TrimSpace(v)
+		}
+	case "abbr":
+		if v, ok := lookup(n, "title"); ok {
+			return strings.TrimSpace(v)
+		}
+	case "data", "input":
+		if v, ok := lookup(n, "value"); ok {
+			return strings.TrimSpace(v)
+		}
+	}
+	return p.text(n)
+}
+
+// eValue parses an e-* property
+func (p *parser) eValue(n *html.Node) *Embedded {
+	var b bytes.Buffer
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		html.Render(&b, c)
+	}
+	return &Embedded{HTML: strings.TrimSpace(b.String()), Value: strings.TrimSpace(textContent(n, false))}
+}
+
+// impliedName returns the name of a microformat without p-* and e-* properties
+func (p *parser) impliedName(n *html.Node) string {
+	named := func(n *html.Node) (string, bool) {
+		switch n.Data {
+		case "img", "area":
+			return lookup(n, "alt")
+		case "abbr":
+			return lookup(n, "title")
+		}
+		return "", false
+	}
+	if v, ok := named(n); ok {
+		return strings.TrimSpace(v)
+	}
+	if c := onlyChild(n); c != nil && !isRoot(c) {
+		if v, ok := named(c); ok && v != "" {
+			return strings.TrimSpace(v)
+		}
+		if g := onlyChild(c); g != nil && !isRoot(g) {
+			if v, ok := named(g); ok && v != "" {
+				return strings.TrimSpace(v)
+			}
+		}
+	}
+	return p.text(n)
+}
+
+var (
+	photoSources = [][2]string{{"img", "src"}, {"object", "data"}}
+	urlSources   = [][2]string{{"a", "href"}, {"area", "href"}}
+)
+
+// impliedURL looks for the photo or url implied by n: the attribute of n itself, of its only child
+// of a tag, or of the only child of a tag of its only child. sources pairs the tags with the attribute holding the URL
+func (p *parser) impliedURL(n *html.Node, sources [][2]string) (string, bool) {
+	for _, src := range sources {
+		if n.Data == src[0] {
+			if v, ok := lookup(n, src[1]); ok {
+				return p.resolve(v), true
+			}
+		}
+	}
+	parents := []*html.Node{n}
+	if c := onlyChild(n); c != nil && !isRoot(c) {
+		parents = append(parents, c)
+	}
+	for _, parent := range parents {
+		for _, src := range sources {
+			if c := onlyOfType(parent, src[0]); c != nil && !isRoot(c) {
+				if v, ok := lookup(c, src[1]); ok {
+					return p.resolve(v), true
+				}
+			}
+		}
+	}
+	return "", false
+}
+
+// rels collects the links with a rel attribute of the document of n
+func (p *parser) rels(n *html.Node, data *Data) {
+	top := n
+	for top.Parent != nil {
+		top = top.Parent
+	}
+	var visit func(n *html.Node)
+	visit = func(n *html.Node) {
+		if n.Type == html.ElementNode && (n.Data == "a" || n.Data == "area" || n.Data == "link") {
+			href, hasHref := lookup(n, "href")
+			rels := strings.Fields(attr(n, "rel"))
+			if hasHref && len(rels) > 0 {
+				u := p.resolve(href)
+				info := data.RelURLs[u]
+				if info == nil {
+					info = &RelURL{
+						Text:     strings.TrimSpace(textContent(n, false)),
+						Title:    attr(n, "title"),
+						Media:    attr(n, "media"),
+						Hreflang: attr(n, "hreflang"),
+						Type:     attr(n, "type"),
+					}
+					data.RelURLs[u] = info
+				}
+				for _, rel := range rels {
+					if !contains(data.Rels[rel], u) {
+						data.Rels[rel] = append(data.Rels[rel], u)
+					}
+					if !contains(info.Rels, rel) {
+						info.Rels = append(info.Rels, rel)
+					}
+				}
+				sort.Strings(info.Rels)
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			visit(c)
+		}
+	}
+	visit(top)
+}
+
+// resolve returns href resolved against the base URL of the document, or href trimmed
+func (p *parser) resolve(href string) string {
+	if u, err := p.root.ResolveURL(href); err == nil {
+		return u.String()
+	}
+	return strings.TrimSpace(href)
+}
+
+// text returns the text of n for p-* properties and implied names, images are replaced by their alt text
+func (p *parser) text(n *html.Node) string {
+	return strings.TrimSpace(textContent(n, true))
+}
+
+type property struct {
+	prefix, name string
+}
+
+// classes returns the sorted root classes and the property classes of n
+func classes(n *html.Node) ([]string, []property) {
+	var roots []string
+	var props []property
+	for _, class := range strings.Fields(attr(n, "class")) {
+		if rootClass.MatchString(class) {
+			if !contains(roots, class) {
+				roots = append(roots, class)
+			}
+		} else if m := propertyClass.FindStringSubmatch(class); m != nil {
+			prop := property{prefix: m[1], name: m[2]}
+			if !containsProperty(props, prop) {
+				props = append(props, prop)
+			}
+		}
+	}
+	sort.Strings(roots)
+	return roots, props
+}
+
+// valueClass joins the values of the elements with the class value below n,
+// following the value class pattern. It reports whether n has any
+func valueClass(n *html.Node) (string, bool) {
+	var parts []string
+	var visit func(n *html.Node)
+	visit = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode {
+				continue
+			}
+			roots, props := classes(c)
+			if len(roots) > 0 || len(props) > 0 {
+				continue
+			}
+			names := strings.Fields(attr(c, "class"))
+			switch {
+			case contains(names, "value-title"):
+				parts = append(parts, attr(c, "title"))
+			case contains(names, "value"):
+				switch c.Data {
+				case "img", "area":
+					parts = append(parts, attr(c, "alt"))
+				case "data":
+					if v, ok := lookup(c, "value"); ok {
+						parts = append(parts, v)
+					} else {
+						parts = append(parts, textContent(c, false))
+					}
+				case "abbr":
+					if v, ok := lookup(c, "title"); ok {
+						parts = append(parts, v)
+					} else {
+						parts = append(parts, textContent(c, false))
+					}
+				default:
+					parts = append(parts, textContent(c, false))
+				}
+			default:
+				visit(c)
+			}
+		}
+	}
+	visit(n)
+	if len(parts) == 0 {
+		return "", false
+	}
+	return strings.TrimSpace(strings.Join(parts, "")), true
+}
+
+// textContent returns the text below n leaving out scripts and styles,
+// with images replaced by their alt text when alt is set
+func textContent(n *html.Node, alt bool) string {
+	var b strings.Builder
+	var visit func(n *html.Node)
+	visit = func(n *html.Node) {
+		switch n.Type {
+		case html.TextNode:
+			b.WriteString(n.Data)
+			return
+		case html.ElementNode:
+			switch n.Data {
+			case "script", "style", "template":
+				return
+			case "img":
+				if alt {
+					if v, ok := lookup(n, "alt"); ok {
+						b.WriteString(" " + v + " ")
+					}
+				}
+				return
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			visit(c)
+		}
+	}
+	visit(n)
+	if alt {
+		return strings.Join(strings.Fields(b.String()), " ")
+	}
+	return b.String()
+}
+
+// onlyChild returns the only element child of n, nil when n has none or several
+func onlyChild(n *html.Node) *html.Node {
+	var only *html.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+		if only != nil {
+			return nil
+		}
+		only = c
+	}
+	return only
+}
+
+// onlyOfType returns the only element child of n with the tag, nil when n has none or several
+func onlyOfType(n *html.Node, tag string) *html.Node {
+	var only *html.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode || c.Data != tag {
+			continue
+		}
+		if only != nil {
+			return nil
+		}
+		only = c
+	}
+	return only
+}
+
+// isRoot reports whether n is the root element of a microformat
+func isRoot(n *html.Node) bool {
+	roots, _ := classes(n)
+	return len(roots) > 0
+}
+
+func attr(n *html.Node, key string) string {
+	v, _ := lookup(n, key)
+	return v
+}
+
+func lookup(n *html.Node, key string) (string, bool) {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func containsProperty(list []property, p property) bool {
+	for _, v := range list {
+		if v == p {
+			return true
+		}
+	}
+	return false
+}
+
+func first(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
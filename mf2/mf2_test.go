@@ -0,0 +1,77 @@
+package mf2
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/Patrickmitech/owl"
+	"github.com/stretchr/testify/require"
+)
+
+const entryPage = `<html><head><link rel="me authn" href="https://github.com/owl"></head><body>
+<article class="h-entry" id="post-1">
+	<h1 class="p-name">Owls at night</h1>
+	<a class="p-author h-card" href="/about"><img src="/me.jpg" alt="Olive Owl"> Olive</a>
+	<time class="dt-published" datetime="2024-05-01T22:00:00Z">May 1st</time>
+	<p class="p-summary">Notes from the <abbr>barn</abbr>.</p>
+	<div class="e-content"><p>They <em>hoot</em>.</p></div>
+	<a class="u-url u-uid" href="/posts/1">permalink</a>
+	<span class="p-category">birds</span><span class="p-category">night</span>
+	<p class="p-location">
+		<span class="value">Barn</span>, <span class="value">Yard</span>
+	</p>
+	<div class="h-cite"><a href="/posts/0">Earlier post</a></div>
+</article>
+<div class="h-card"><img src="photo.png" alt="Barn Owl"></div>
+<a class="h-card" href="https://example.org/">Example</a>
+</body></html>`
+
+func TestParse(t *testing.T) {
+	base, _ := url.Parse("https://blog.example.com/2024/")
+	data := Parse(owl.HTMLParseFromString(entryPage).SetURL(base))
+	require.Len(t, data.Items, 3)
+
+	entry := data.Items[0]
+	require.Equal(t, []string{"h-entry"}, entry.Type)
+	require.Equal(t, "post-1", entry.ID)
+	require.Equal(t, "Owls at night", entry.String("name"))
+	require.Equal(t, "2024-05-01T22:00:00Z", entry.String("published"))
+	require.Equal(t, "Notes from the barn.", entry.String("summary"))
+	require.Equal(t, "https://blog.example.com/posts/1", entry.String("url"))
+	require.Equal(t, "https://blog.example.com/posts/1", entry.String("uid"))
+	require.Equal(t, []any{"birds", "night"}, entry.Properties["category"])
+	require.Equal(t, "BarnYard", entry.String("location"))
+	require.Equal(t, &Embedded{HTML: "<p>They <em>hoot</em>.</p>", Value: "They hoot."}, entry.Properties["content"][0])
+	require.NotContains(t, entry.Properties, "photo")
+
+	author := entry.Properties["author"][0].(*Item)
+	require.Equal(t, []string{"h-card"}, author.Type)
+	require.Equal(t, "Olive Owl", author.Value)
+	require.Equal(t, "https://blog.example.com/about", author.String("url"))
+	require.Equal(t, "https://blog.example.com/me.jpg", author.String("photo"))
+
+	require.Len(t, entry.Children, 1)
+	require.Equal(t, []string{"h-cite"}, entry.Children[0].Type)
+	require.Equal(t, "https://blog.example.com/posts/0", entry.Children[0].String("url"))
+
+	card := data.Items[1]
+	require.Equal(t, "Barn Owl", card.String("name"))
+	require.Equal(t, "https://blog.example.com/2024/photo.png", card.String("photo"))
+	require.Equal(t, "https://example.org/", data.Items[2].String("url"))
+	require.Equal(t, "Example", data.Items[2].String("name"))
+
+	require.Equal(t, []string{"https://github.com/owl"}, data.Rels["me"])
+	require.Equal(t, []string{"authn", "me"}, data.RelURLs["https://github.com/owl"].Rels)
+
+	out, err := json.Marshal(data)
+	require.NoError(t, err)
+	require.Contains(t, string(out), `"rel-urls"`)
+}
+
+func TestParseEmpty(t *testing.T) {
+	data := Parse(owl.HTMLParseFromString(`<html><body><p>No microformats</p></body></html>`))
+	out, err := json.Marshal(data)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"items": [], "rels": {}, "rel-urls": {}}`, string(out))
+}
@@ -0,0 +1,190 @@
+package owl
+
+import (
+	"sort"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// MicrodataItem is an item annotated with itemscope, laid out like the JSON of the WHATWG microdata algorithm.
+// Property values are either strings or nested *MicrodataItem
+type MicrodataItem struct {
+	Type       []string         `json:"type,omitempty"`
+	ID         string           `json:"id,omitempty"`
+	Properties map[string][]any `json:"properties"`
+}
+
+// String returns the first string value of the property name, empty when there is none
+func (item *MicrodataItem) String(name string) string {
+	for _, v := range item.Properties[name] {
+		if s, ok := v.(string); ok {
+			return s
+		}
+	}
+	return ""
+}
+
+// Item returns the first nested item of the property name, nil when there is none
+func (item *MicrodataItem) Item(name string) *MicrodataItem {
+	for _, v := range item.Properties[name] {
+		if i, ok := v.(*MicrodataItem); ok {
+			return i
+		}
+	}
+	return nil
+}
+
+// Microdata returns the top-level microdata items below the Node in document order,
+// items that are the value of a property are nested in the item holding the property.
+// URL values are resolved against BaseURL when the document has one
+func (r *Root) Microdata() []*MicrodataItem {
+	if r.Node == nil {
+		return nil
+	}
+	top := r.Node
+	for top.Parent != nil {
+		top = top.Parent
+	}
+	ids := make(map[string]*html.Node)
+	walk(top, func(n *html.Node) bool {
+		if n.Type == html.ElementNode {
+			if id := attrValue(n, "id"); id != "" && ids[id] == nil {
+				ids[id] = n
+			}
+		}
+		return true
+	})
+	m := &microdata{root: r, ids: ids, inProgress: make(map[*html.Node]bool)}
+	var items []*MicrodataItem
+	walk(r.Node, func(n *html.Node) bool {
+		if n.Type == html.ElementNode && hasAttr(n, "itemscope") && !hasAttr(n, "itemprop") {
+			items = append(items, m.item(n))
+		}
+		return true
+	})
+	return items
+}
+
+// microdata holds the state of a Microdata extraction
+type microdata struct {
+	root *Root
+	ids  map[string]*html.Node
+	// inProgress guards against items that are properties of themselves through itemref
+	inProgress map[*html.Node]bool
+}
+
+// item builds the item of the itemscope element n
+func (m *microdata) item(n *html.Node) *MicrodataItem {
+	item := &MicrodataItem{
+		Type:       strings.Fields(attrValue(n, "itemtype")),
+		Properties: make(map[string][]any),
+	}
+	if id, ok := attrLookup(n, "itemid"); ok && len(item.Type) > 0 {
+		item.ID = m.root.resolveString(id)
+	}
+	m.inProgress[n] = true
+	defer delete(m.inProgress, n)
+
+	for _, prop := range m.properties(n) {
+		var value any
+		if hasAttr(prop, "itemscope") {
+			if m.inProgress[prop] {
+				value = "ERROR"
+			} else {
+				value = m.item(prop)
+			}
+		} else {
+			value = m.value(prop)
+		}
+		for _, name := range strings.Fields(attrValue(prop, "itemprop")) {
+			item.Properties[name] = append(item.Properties[name], value)
+		}
+	}
+	return item
+}
+
+// properties returns the elements with an itemprop belonging to the item n,
+// found below n and below the elements its itemref names, in tree order
+func (m *microdata) properties(n *html.Node) []*html.Node {
+	roots := []*html.Node{}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		roots = append(roots, c)
+	}
+	for _, id := range strings.Fields(attrValue(n, "itemref")) {
+		if ref := m.ids[id]; ref != nil {
+			roots = append(roots, ref)
+		}
+	}
+	seen := make(map[*html.Node]bool)
+	var props []*html.Node
+	for _, root := range roots {
+		walk(root, func(c *html.Node) bool {
+			if c.Type != html.ElementNode || seen[c] || c == n {
+				return false
+			}
+			seen[c] = true
+			if hasAttr(c, "itemprop") {
+				props = append(props, c)
+			}
+			return !hasAttr(c, "itemscope")
+		})
+	}
+	orderNodes(props)
+	return props
+}
+
+// value returns the property value of the element n without itemscope
+func (m *microdata) value(n *html.Node) string {
+	switch n.Data {
+	case "meta":
+		return attrValue(n, "content")
+	case "audio", "embed", "iframe", "img", "source", "track", "video":
+		return m.urlValue(n, "src")
+	case "a", "area", "link":
+		return m.urlValue(n, "href")
+	case "object":
+		return m.urlValue(n, "data")
+	case "data", "meter":
+		return attrValue(n, "value")
+	case "time":
+		if v, ok := attrLookup(n, "datetime"); ok {
+			return v
+		}
+	}
+	return (&Root{Node: n}).FullText()
+}
+
+// urlValue returns the attribute key of n resolved as a URL, empty when n does not have it
+func (m *microdata) urlValue(n *html.Node, key string) string {
+	v, ok := attrLookup(n, key)
+	if !ok {
+		return ""
+	}
+	return m.root.resolveString(v)
+}
+
+// orderNodes sorts nodes of the same tree in tree order
+func orderNodes(nodes []*html.Node) {
+	if len(nodes) < 2 {
+		return
+	}
+	top := nodes[0]
+	for top.Parent != nil {
+		top = top.Parent
+	}
+	position := make(map[*html.Node]int)
+	i := 0
+	walk(top, func(n *html.Node) bool {
+		position[n] = i
+		i++
+		return true
+	})
+	sort.SliceStable(nodes, func(a, b int) bool { return position[nodes[a]] < position[nodes[b]] })
+}
+
+// hasAttr reports whether n has the attribute key
+func hasAttr(n *html.Node, key string) bool {
+	_, ok := attrLookup(n, key)
+	return ok
+}
@@ -0,0 +1,49 @@
+package owl
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMicrodata(t *testing.T) {
+	doc := HTMLParseFromString(`<html><body>
+		<div itemscope itemtype="https://schema.org/Product" itemid="/products/owl" itemref="rating">
+			<h1 itemprop="name">Owl plush</h1>
+			<img itemprop="image" src="owl.jpg">
+			<div itemprop="offers" itemscope itemtype="https://schema.org/Offer">
+				<meta itemprop="priceCurrency" content="EUR">
+				<data itemprop="price" value="19.99">€19.99</data>
+				<span itemprop="name">Not the product name</span>
+			</div>
+			<time itemprop="releaseDate" datetime="2024-03-01">March</time>
+		</div>
+		<p id="rating" itemprop="ratingValue rating">4.5</p>
+		<div itemscope><span itemprop="note">second</span></div>
+	</body></html>`)
+	page, _ := url.Parse("https://shop.example.com/catalog/")
+	items := doc.SetURL(page).Microdata()
+	require.Len(t, items, 2)
+
+	product := items[0]
+	require.Equal(t, []string{"https://schema.org/Product"}, product.Type)
+	require.Equal(t, "https://shop.example.com/products/owl", product.ID)
+	require.Equal(t, "Owl plush", product.String("name"))
+	require.Equal(t, "https://shop.example.com/catalog/owl.jpg", product.String("image"))
+	require.Equal(t, "2024-03-01", product.String("releaseDate"))
+	require.Equal(t, "4.5", product.String("ratingValue"))
+	require.Equal(t, "4.5", product.String("rating"))
+
+	offer := product.Item("offers")
+	require.NotNil(t, offer)
+	require.Equal(t, "EUR", offer.String("priceCurrency"))
+	require.Equal(t, "19.99", offer.String("price"))
+	require.Equal(t, "Not the product name", offer.String("name"))
+
+	require.Equal(t, "second", items[1].String("note"))
+	data, err := json.Marshal(items[1])
+	require.NoError(t, err)
+	require.JSONEq(t, `{"properties": {"note": ["second"]}}`, string(data))
+}
@@ -0,0 +1,76 @@
+package owl
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// Multipart describes a multipart/form-data body for Client.Post
+type Multipart struct {
+	Fields url.Values
+	// Files are written after the fields, in order
+	Files []MultipartFile
+}
+
+// MultipartFile is a file part of a multipart/form-data body
+type MultipartFile struct {
+	// Field is the name of the form field the file is sent as
+	Field    string
+	Filename string
+	// ContentType defaults to the type of the extension of Filename, or application/octet-stream
+	ContentType string
+	Content     io.Reader
+}
+
+// encode writes the multipart body and returns it with its Content-Type, which holds the boundary
+func (m *Multipart) encode() (io.Reader, string, error) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	for name, values := range m.Fields {
+		for _, v := range values {
+			if err := w.WriteField(name, v); err != nil {
+				return nil, "", err
+			}
+		}
+	}
+	for _, f := range m.Files {
+		if err := writeFilePart(w, f); err != nil {
+			return nil, "", err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, "", err
+	}
+	return &body, w.FormDataContentType(), nil
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// writeFilePart writes f as a part of w, a nil Content is written as an empty file
+func writeFilePart(w *multipart.Writer, f MultipartFile) error {
+	contentType := f.ContentType
+	if contentType == "" {
+		contentType = mime.TypeByExtension(path.Ext(f.Filename))
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", `form-data; name="`+quoteEscaper.Replace(f.Field)+`"; filename="`+quoteEscaper.Replace(f.Filename)+`"`)
+	h.Set("Content-Type", contentType)
+	part, err := w.CreatePart(h)
+	if err != nil {
+		return err
+	}
+	if f.Content == nil {
+		return nil
+	}
+	_, err = io.Copy(part, f.Content)
+	return err
+}
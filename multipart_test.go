@@ -0,0 +1,68 @@
+package owl
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostMultipart(t *testing.T) {
+	type upload struct {
+		field, filename, contentType, content string
+	}
+	var fields url.Values
+	var uploads []upload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		fields = url.Values(r.MultipartForm.Value)
+		uploads = nil
+		for field, headers := range r.MultipartForm.File {
+			for _, h := range headers {
+				f, err := h.Open()
+				require.NoError(t, err)
+				data, _ := io.ReadAll(f)
+				f.Close()
+				uploads = append(uploads, upload{field, h.Filename, h.Header.Get("Content-Type"), string(data)})
+			}
+		}
+		io.WriteString(w, "ok")
+	}))
+	defer srv.Close()
+	client := &Client{Client: srv.Client(), RequestTimeout: time.Second}
+
+	_, err := client.Post(srv.URL, "", &Multipart{
+		Fields: url.Values{"title": {"Barn owl"}, "tags": {"bird", "night"}},
+		Files: []MultipartFile{
+			{Field: "photo", Filename: "owl.png", Content: strings.NewReader("PNG")},
+			{Field: "notes", Filename: `"notes".txt`, ContentType: "text/markdown", Content: strings.NewReader("# Owl")},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, url.Values{"title": {"Barn owl"}, "tags": {"bird", "night"}}, fields)
+	require.ElementsMatch(t, []upload{
+		{"photo", "owl.png", "image/png", "PNG"},
+		{"notes", `"notes".txt`, "text/markdown", "# Owl"},
+	}, uploads)
+
+	base, _ := url.Parse(srv.URL)
+	form := HTMLParseFromString(`<form method="post" enctype="multipart/form-data">
+		<input name="title" value="Owl"><input type="file" name="photos" multiple><input type="file" name="empty">
+	</form>`).SetURL(base).Forms()[0]
+	require.NoError(t, form.SetFile("photos", "a.jpg", strings.NewReader("A")))
+	require.NoError(t, form.SetFile("photos", "b.jpg", strings.NewReader("B")))
+	require.Equal(t, ErrNoFormField, form.SetFile("title", "c.jpg", nil))
+	_, err = form.Submit(client)
+	require.NoError(t, err)
+	// The empty file part of a file input without files is parsed as a value
+	require.Equal(t, url.Values{"title": {"Owl"}, "empty": {""}}, fields)
+	require.ElementsMatch(t, []upload{
+		{"photos", "a.jpg", "image/jpeg", "A"},
+		{"photos", "b.jpg", "image/jpeg", "B"},
+	}, uploads)
+}
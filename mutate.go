@@ -0,0 +1,296 @@
+package owl
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	// ErrNoNode is returned when changing the tree of a Root without a Node, such as a failed Find
+	ErrNoNode = errors.New("owl: root has no node")
+	// ErrNoParent is returned when inserting next to, or replacing, a node that is not in a tree
+	ErrNoParent = errors.New("owl: node has no parent")
+	// ErrHierarchy is returned when inserting a node into itself or below itself
+	ErrHierarchy = errors.New("owl: node inserted into itself")
+)
+
+// Append moves the nodes of children, in order, to the end of the children of the Node.
+// Nodes already in a tree are moved out of it, see Clone to insert copies.
+// The indexes built by BuildIndex on the trees changed are invalidated
+func (r *Root) Append(children ...*Root) error {
+	return r.insert(nodesOf(children), r.Node, nil)
+}
+
+// Prepend moves the nodes of children, in order, to the start of the children of the Node, see Append
+func (r *Root) Prepend(children ...*Root) error {
+	if r.Node == nil {
+		return ErrNoNode
+	}
+	return r.insert(nodesOf(children), r.Node, r.Node.FirstChild)
+}
+
+// InsertBefore moves the nodes of siblings, in order, before the Node, see Append
+func (r *Root) InsertBefore(siblings ...*Root) error {
+	if r.Node == nil {
+		return ErrNoNode
+	}
+	return r.insert(nodesOf(siblings), r.Node.Parent, r.Node)
+}
+
+// InsertAfter moves the nodes of siblings, in order, after the Node, see Append
+func (r *Root) InsertAfter(siblings ...*Root) error {
+	if r.Node == nil {
+		return ErrNoNode
+	}
+	return r.insert(nodesOf(siblings), r.Node.Parent, r.Node.NextSibling)
+}
+
+// ReplaceWith puts the nodes of replacements in place of the Node, which is removed from the tree
+// unless it is one of replacements, see Append
+func (r *Root) ReplaceWith(replacements ...*Root) error {
+	if r.Node == nil {
+		return ErrNoNode
+	}
+	if err := r.InsertBefore(replacements...); err != nil {
+		return err
+	}
+	for _, replacement := range replacements {
+		if replacement != nil && replacement.Node == r.Node {
+			return nil
+		}
+	}
+	r.Remove()
+	return nil
+}
+
+// Remove removes the Node, along with everything below it, from its tree and returns the Root,
+// so it can be inserted somewhere else. Nodes that are not in a tree are left as they are
+func (r *Root) Remove() *Root {
+	if r.Node != nil && r.Node.Parent != nil {
+		r.InvalidateIndex()
+		r.Node.Parent.RemoveChild(r.Node)
+	}
+	return r
+}
+
+// Empty removes every child of the Node
+func (r *Root) Empty() *Root {
+	if r.Node == nil {
+		return r
+	}
+	for c := r.Node.FirstChild; c != nil; c = r.Node.FirstChild {
+		r.Node.RemoveChild(c)
+	}
+	r.InvalidateIndex()
+	return r
+}
+
+// AppendHTML parses the HTML fragment s in the context of the Node and appends its nodes, see Append
+func (r *Root) AppendHTML(s string) error {
+	if r.Node == nil {
+		return ErrNoNode
+	}
+	nodes, err := parseFragment(s, r.Node)
+	if err != nil {
+		return err
+	}
+	return r.insert(nodes, r.Node, nil)
+}
+
+// PrependHTML parses the HTML fragment s in the context of the Node and prepends its nodes, see Prepend
+func (r *Root) PrependHTML(s string) error {
+	if r.Node == nil {
+		return ErrNoNode
+	}
+	nodes, err := parseFragment(s, r.Node)
+	if err != nil {
+		return err
+	}
+	return r.insert(nodes, r.Node, r.Node.FirstChild)
+}
+
+// InsertBeforeHTML parses the HTML fragment s in the context of the parent of the Node and inserts
+// its nodes before the Node, see InsertBefore
+func (r *Root) InsertBeforeHTML(s string) error {
+	if r.Node == nil {
+		return ErrNoNode
+	}
+	nodes, err := parseFragment(s, r.Node.Parent)
+	if err != nil {
+		return err
+	}
+	return r.insert(nodes, r.Node.Parent, r.Node)
+}
+
+// InsertAfterHTML parses the HTML fragment s in the context of the parent of the Node and inserts
+// its nodes after the Node, see InsertAfter
+func (r *Root) InsertAfterHTML(s string) error {
+	if r.Node == nil {
+		return ErrNoNode
+	}
+	nodes, err := parseFragment(s, r.Node.Parent)
+	if err != nil {
+		return err
+	}
+	return r.insert(nodes, r.Node.Parent, r.Node.NextSibling)
+}
+
+// ReplaceWithHTML parses the HTML fragment s in the context of the parent of the Node and puts its nodes
+// in place of the Node, see ReplaceWith
+func (r *Root) ReplaceWithHTML(s string) error {
+	if err := r.InsertBeforeHTML(s); err != nil {
+		return err
+	}
+	r.Remove()
+	return nil
+}
+
+// SetText replaces the children of the Node with a text node holding s, escaped when rendered.
+// The text of script and style elements is rendered as is
+func (r *Root) SetText(s string) error {
+	if r.Node == nil {
+		return ErrNoNode
+	}
+	r.Empty()
+	r.Node.AppendChild(&html.Node{Type: html.TextNode, Data: s})
+	return nil
+}
+
+// SetInnerHTML replaces the children of the Node with the nodes of the HTML fragment s,
+// parsed in the context of the Node as browsers do, so <td> fragments set into a tr are cells
+func (r *Root) SetInnerHTML(s string) error {
+	if r.Node == nil {
+		return ErrNoNode
+	}
+	nodes, err := parseFragment(s, r.Node)
+	if err != nil {
+		return err
+	}
+	r.Empty()
+	return r.insert(nodes, r.Node, nil)
+}
+
+// Wrap puts a new element with the tag name and the attributes given as key and value pairs
+// in place of the Node, and moves the Node into it. It returns the new element
+func (r *Root) Wrap(tag string, attrs ...string) (*Root, error) {
+	if r.Node == nil {
+		return nil, ErrNoNode
+	}
+	if r.Node.Parent == nil {
+		return nil, ErrNoParent
+	}
+	if len(attrs)%2 != 0 {
+		return nil, fmt.Errorf("owl: attribute %q has no value", attrs[len(attrs)-1])
+	}
+	contents := make([]ElementContent, 0, len(attrs)/2)
+	for i := 0; i < len(attrs); i += 2 {
+		contents = append(contents, Attribute(attrs[i], attrs[i+1]))
+	}
+	wrapper := NewElement(tag, contents...)
+	wrapper.doc = r.doc
+	if err := r.InsertBefore(wrapper); err != nil {
+		return nil, err
+	}
+	if err := wrapper.Append(r); err != nil {
+		return nil, err
+	}
+	return wrapper, nil
+}
+
+// Unwrap puts the children of the Node in its place and removes it from the tree
+func (r *Root) Unwrap() error {
+	if r.Node == nil {
+		return ErrNoNode
+	}
+	if r.Node.Parent == nil {
+		return ErrNoParent
+	}
+	unwrap(r.Node)
+	r.InvalidateIndex()
+	return nil
+}
+
+// Clone returns a deep copy of the Node, outside of any tree, sharing the document of the Root
+func (r *Root) Clone() *Root {
+	if r.Node == nil {
+		return &Root{Error: r.Error}
+	}
+	n := cloneNode(r.Node)
+	return &Root{Node: n, NodeValue: n.Data, doc: r.doc}
+}
+
+func cloneNode(n *html.Node) *html.Node {
+	c := &html.Node{
+		Type:      n.Type,
+		DataAtom:  n.DataAtom,
+		Data:      n.Data,
+		Namespace: n.Namespace,
+		Attr:      append([]html.Attribute(nil), n.Attr...),
+	}
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		c.AppendChild(cloneNode(child))
+	}
+	return c
+}
+
+// insert moves nodes under parent before the child before, at the end when before is nil,
+// and invalidates the indexes of the trees changed
+func (r *Root) insert(nodes []*html.Node, parent, before *html.Node) error {
+	if r.Node == nil {
+		return ErrNoNode
+	}
+	if parent == nil {
+		return ErrNoParent
+	}
+	for _, n := range nodes {
+		if n == nil {
+			return ErrNoNode
+		}
+		for p := parent; p != nil; p = p.Parent {
+			if p == n {
+				return ErrHierarchy
+			}
+		}
+	}
+	for _, n := range nodes {
+		if n == before {
+			// The node goes before itself, it stays in place and the next nodes go before it
+			before = n.NextSibling
+			continue
+		}
+		if n.Parent != nil {
+			n.Parent.RemoveChild(n)
+		}
+		parent.InsertBefore(n, before)
+	}
+	r.InvalidateIndex()
+	return nil
+}
+
+// nodesOf returns the Nodes of roots, invalidating the indexes of the trees they are moved out of
+func nodesOf(roots []*Root) []*html.Node {
+	nodes := make([]*html.Node, len(roots))
+	for i, root := range roots {
+		if root == nil {
+			continue
+		}
+		if root.Node != nil && root.Node.Parent != nil {
+			root.InvalidateIndex()
+		}
+		nodes[i] = root.Node
+	}
+	return nodes
+}
+
+// parseFragment parses the HTML fragment s as the children of an element like context,
+// the body when context is not an element
+func parseFragment(s string, context *html.Node) ([]*html.Node, error) {
+	if context == nil || context.Type != html.ElementNode {
+		context = &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	}
+	return html.ParseFragment(strings.NewReader(s), context)
+}
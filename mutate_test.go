@@ -0,0 +1,141 @@
+package owl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func body(r *Root) string {
+	return string(r.Find("body").Render())
+}
+
+func TestMutation(t *testing.T) {
+	doc := HTMLParseFromString(`<ul id="list"><li>1</li><li id="two">2</li><li>3</li></ul><p id="note">note</p>`)
+	list, two := doc.Find("ul"), doc.Find("li", "id", "two")
+
+	require.NoError(t, list.AppendHTML(`<li>4</li>`))
+	require.NoError(t, list.PrependHTML(`<li>0</li>`))
+	require.NoError(t, two.InsertBeforeHTML(`<li>1.5</li>`))
+	require.NoError(t, two.InsertAfterHTML(`<li>2.5</li>`))
+	require.Equal(t, `<body><ul id="list"><li>0</li><li>1</li><li>1.5</li><li id="two">2</li><li>2.5</li><li>3</li><li>4</li></ul><p id="note">note</p></body>`, body(doc))
+
+	// Moving nodes keeps the parent and sibling pointers right
+	note := doc.Find("p")
+	require.NoError(t, list.Prepend(note))
+	require.Equal(t, list.Node, note.Node.Parent)
+	require.Nil(t, note.Node.PrevSibling)
+	require.Equal(t, "0", note.Node.NextSibling.FirstChild.Data)
+	require.Equal(t, note.Node, list.Node.FirstChild)
+	require.Nil(t, list.Node.NextSibling)
+
+	require.NoError(t, two.ReplaceWithHTML(`<li class="new">two</li><li>2.2</li>`))
+	require.Nil(t, two.Node.Parent)
+	require.NoError(t, doc.Find("li", "class", "new").ReplaceWith(two))
+	require.Equal(t, "2", doc.Find("li", "id", "two").Text())
+
+	first := doc.Find("li")
+	require.Equal(t, first, first.Remove())
+	require.Nil(t, first.Node.Parent)
+	first.Remove()
+	require.NoError(t, list.InsertAfter(first, first.Clone()))
+	require.Equal(t, `<body><ul id="list"><p id="note">note</p><li>1</li><li>1.5</li><li id="two">2</li><li>2.2</li><li>2.5</li><li>3</li><li>4</li></ul><li>0</li><li>0</li></body>`, body(doc))
+
+	// Nodes inserted before themselves keep their place
+	three := doc.FindAll("li").Roots[5]
+	require.NoError(t, three.InsertBefore(doc.FindAll("li").Roots[0], three, doc.Find("p")))
+	require.Equal(t, `<ul id="list"><li>1.5</li><li id="two">2</li><li>2.2</li><li>2.5</li><li>1</li><li>3</li><p id="note">note</p><li>4</li></ul>`, string(list.Render()))
+
+	// Nodes replaced with themselves and other nodes stay in the tree
+	require.NoError(t, three.ReplaceWith(doc.Find("p"), three, first))
+	require.Equal(t, list.Node, three.Node.Parent)
+	require.Equal(t, `<ul id="list"><li>1.5</li><li id="two">2</li><li>2.2</li><li>2.5</li><li>1</li><p id="note">note</p><li>3</li><li>0</li><li>4</li></ul>`, string(list.Render()))
+	require.NoError(t, three.ReplaceWith(three))
+	require.Equal(t, list.Node, three.Node.Parent)
+
+	list.Empty()
+	require.Nil(t, list.Node.FirstChild)
+}
+
+func TestMutationErrors(t *testing.T) {
+	doc := HTMLParseFromString(`<div><p><b>x</b></p></div>`)
+	missing := doc.Find("table")
+	require.ErrorIs(t, missing.Append(doc.Find("b")), ErrNoNode)
+	require.ErrorIs(t, missing.AppendHTML("x"), ErrNoNode)
+	require.ErrorIs(t, doc.Find("b").Append(missing), ErrNoNode)
+	require.ErrorIs(t, doc.Find("b").Append(doc.Find("div")), ErrHierarchy)
+	require.ErrorIs(t, doc.Find("p").Append(doc.Find("p")), ErrHierarchy)
+	detached := doc.Find("b").Remove()
+	require.ErrorIs(t, detached.InsertAfter(doc.Find("p")), ErrNoParent)
+	require.ErrorIs(t, detached.ReplaceWithHTML("<i>y</i>"), ErrNoParent)
+	require.Equal(t, missing, missing.Remove().Empty())
+	require.Nil(t, missing.Clone().Node)
+}
+
+func TestMutationInvalidatesIndex(t *testing.T) {
+	doc := HTMLParseFromString(`<div class="a">1</div><div class="b">2</div>`).BuildIndex()
+	other := HTMLParseFromString(`<span class="a">moved</span>`).BuildIndex()
+	span := other.Find("span")
+	require.True(t, other.Indexed())
+
+	require.NoError(t, doc.Find("div", "class", "b").Append(span))
+	require.False(t, doc.Indexed())
+	require.False(t, other.Indexed())
+	require.Equal(t, 2, len(doc.FindAll("", "class", "a").Roots))
+	require.True(t, other.Find("span").Error != nil)
+
+	doc.BuildIndex()
+	require.NoError(t, doc.Find("body").AppendHTML(`<p class="a">3</p>`))
+	require.False(t, doc.Indexed())
+	require.Equal(t, "3", doc.FindAll("", "class", "a").Roots[2].Text())
+}
+
+func TestSetText(t *testing.T) {
+	doc := HTMLParseFromString(`<p id="a">Call <b>555-0100</b> now</p><table><tr id="row"><td>old</td></tr></table>`).BuildIndex()
+	p := doc.Find("p")
+	require.NoError(t, p.SetText(`<b>redacted</b> & "more"`))
+	require.Equal(t, `<p id="a">&lt;b&gt;redacted&lt;/b&gt; &amp; &#34;more&#34;</p>`, string(p.Render()))
+	require.Equal(t, `<b>redacted</b> & "more"`, p.Text())
+	require.False(t, doc.Indexed())
+	require.Nil(t, doc.Find("b").Node)
+
+	row := doc.Find("tr")
+	require.NoError(t, row.SetInnerHTML(`<td>1</td><td>2</td>`))
+	require.Equal(t, `<tr id="row"><td>1</td><td>2</td></tr>`, string(row.Render()))
+	require.NoError(t, p.SetInnerHTML(`Hello <em>world</em>`))
+	require.Equal(t, "world", doc.Find("em").Text())
+	require.NoError(t, p.SetInnerHTML(""))
+	require.Nil(t, p.Node.FirstChild)
+
+	missing := doc.Find("table", "id", "missing")
+	require.ErrorIs(t, missing.SetText("x"), ErrNoNode)
+	require.ErrorIs(t, missing.SetInnerHTML("x"), ErrNoNode)
+}
+
+func TestWrap(t *testing.T) {
+	doc := HTMLParseFromString(`<article><img src="owl.png"><p>An <span>owl</span>.</p></article>`).BuildIndex()
+	img := doc.Find("img")
+	figure, err := img.Wrap("figure", "class", "wrapper", "ID", "fig")
+	require.NoError(t, err)
+	require.Equal(t, figure.Node, img.Node.Parent)
+	require.False(t, doc.Indexed())
+	require.NoError(t, figure.AppendHTML("<figcaption>An owl</figcaption>"))
+	require.Equal(t, "An owl", doc.Find("figure", "id", "fig").Find("figcaption").Text())
+
+	require.NoError(t, doc.Find("span").Unwrap())
+	require.Equal(t, `<article><figure class="wrapper" id="fig"><img src="owl.png"/><figcaption>An owl</figcaption></figure><p>An owl.</p></article>`,
+		string(doc.Find("article").Render()))
+	require.NoError(t, figure.Unwrap())
+	require.Equal(t, `<article><img src="owl.png"/><figcaption>An owl</figcaption><p>An owl.</p></article>`,
+		string(doc.Find("article").Render()))
+
+	_, err = doc.Find("p").Wrap("div", "class")
+	require.ErrorContains(t, err, `attribute "class" has no value`)
+	_, err = NewElement("p").Wrap("div")
+	require.ErrorIs(t, err, ErrNoParent)
+	require.ErrorIs(t, NewElement("p").Unwrap(), ErrNoParent)
+	missing := doc.Find("table")
+	_, err = missing.Wrap("div")
+	require.ErrorIs(t, err, ErrNoNode)
+	require.ErrorIs(t, missing.Unwrap(), ErrNoNode)
+}
@@ -0,0 +1,217 @@
+package owl
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// NormalizeOption configures NormalizeURL
+type NormalizeOption func(*normalizeConfig)
+
+type normalizeConfig struct {
+	keepFragment  bool
+	keepOrder     bool
+	keepTracking  bool
+	stripWWW      bool
+	trailingSlash bool
+	strip         []string
+}
+
+// trackingParams are the query parameters NormalizeURL strips, those ending with * are prefixes
+var trackingParams = []string{
+	"utm_*", "fbclid", "gclid", "gclsrc", "dclid", "gbraid", "wbraid", "msclkid", "yclid", "twclid",
+	"igshid", "mc_cid", "mc_eid", "_ga", "_gl", "_hsenc", "_hsmi", "mkt_tok", "vero_id", "oly_enc_id", "oly_anon_id",
+}
+
+// defaultPorts are the ports NormalizeURL strips for their scheme
+var defaultPorts = map[string]string{"http": "80", "https": "443", "ws": "80", "wss": "443", "ftp": "21"}
+
+// KeepFragment keeps the fragment of the URL
+func KeepFragment() NormalizeOption {
+	return func(c *normalizeConfig) {
+		c.keepFragment = true
+	}
+}
+
+// KeepQueryOrder keeps the query parameters in their order instead of sorting them
+func KeepQueryOrder() NormalizeOption {
+	return func(c *normalizeConfig) {
+		c.keepOrder = true
+	}
+}
+
+// KeepTrackingParams keeps the tracking query parameters, such as utm_source
+func KeepTrackingParams() NormalizeOption {
+	return func(c *normalizeConfig) {
+		c.keepTracking = true
+	}
+}
+
+// StripQueryParams strips these query parameters too, names ending with * are prefixes such as sess_*
+func StripQueryParams(names ...string) NormalizeOption {
+	return func(c *normalizeConfig) {
+		c.strip = append(c.strip, names...)
+	}
+}
+
+// StripWWW removes the www. subdomain of the host
+func StripWWW() NormalizeOption {
+	return func(c *normalizeConfig) {
+		c.stripWWW = true
+	}
+}
+
+// StripTrailingSlash removes the trailing slash of the path, other than the root one
+func StripTrailingSlash() NormalizeOption {
+	return func(c *normalizeConfig) {
+		c.trailingSlash = true
+	}
+}
+
+// NormalizeURL returns u in a canonical form, so the URLs of the same page compare equal:
+// the scheme and host are lowercased, default ports, dot segments and the fragment are removed,
+// percent escapes are uppercased, the empty path of http URLs becomes / and the query parameters
+// are sorted by name, without the tracking ones such as utm_source and fbclid.
+// The Crawler deduplicates the pages it visits with it
+func NormalizeURL(u string, opts ...NormalizeOption) (string, error) {
+	var cfg normalizeConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	parsed, err := url.Parse(strings.TrimSpace(u))
+	if err != nil {
+		return "", err
+	}
+	normalizeURL(parsed, &cfg)
+	return parsed.String(), nil
+}
+
+func normalizeURL(u *url.URL, cfg *normalizeConfig) {
+	u.Scheme = strings.ToLower(u.Scheme)
+	if u.Opaque != "" {
+		return
+	}
+	host, port := strings.ToLower(u.Hostname()), u.Port()
+	if cfg.stripWWW {
+		host = strings.TrimPrefix(host, "www.")
+	}
+	host = strings.TrimSuffix(host, ".")
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	if port != "" && port != defaultPorts[u.Scheme] {
+		host += ":" + port
+	}
+	u.Host = host
+
+	path := upperEscapes(u.EscapedPath())
+	if strings.HasPrefix(path, "/") {
+		path = removeDotSegments(path)
+	}
+	if path == "" && u.Host != "" && (u.Scheme == "http" || u.Scheme == "https") {
+		path = "/"
+	}
+	if cfg.trailingSlash && len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+	if unescaped, err := url.PathUnescape(path); err == nil {
+		u.Path, u.RawPath = unescaped, path
+	}
+	u.RawQuery = normalizeQuery(u.RawQuery, cfg)
+	u.ForceQuery = false
+	if !cfg.keepFragment {
+		u.Fragment, u.RawFragment = "", ""
+	}
+}
+
+// normalizeQuery removes the empty and stripped parameters of the raw query and sorts the others
+// by name, keeping the order of the values of a parameter and their escaping
+func normalizeQuery(query string, cfg *normalizeConfig) string {
+	if query == "" {
+		return ""
+	}
+	type param struct{ name, raw string }
+	var params []param
+	for _, raw := range strings.Split(query, "&") {
+		if raw == "" {
+			continue
+		}
+		name, _, _ := strings.Cut(raw, "=")
+		if unescaped, err := url.QueryUnescape(name); err == nil {
+			name = unescaped
+		}
+		if !cfg.keepTracking && matchParam(trackingParams, name) || matchParam(cfg.strip, name) {
+			continue
+		}
+		params = append(params, param{name: name, raw: upperEscapes(raw)})
+	}
+	if !cfg.keepOrder {
+		sort.SliceStable(params, func(i, j int) bool { return params[i].name < params[j].name })
+	}
+	raws := make([]string, len(params))
+	for i, p := range params {
+		raws[i] = p.raw
+	}
+	return strings.Join(raws, "&")
+}
+
+// matchParam reports whether the query parameter name is one of names, ignoring case,
+// those ending with * being prefixes
+func matchParam(names []string, name string) bool {
+	name = strings.ToLower(name)
+	for _, n := range names {
+		n = strings.ToLower(n)
+		if prefix, ok := strings.CutSuffix(n, "*"); ok && strings.HasPrefix(name, prefix) || n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// upperEscapes uppercases the hexadecimal digits of the percent escapes of s
+func upperEscapes(s string) string {
+	if !strings.Contains(s, "%") {
+		return s
+	}
+	b := []byte(s)
+	for i := 0; i+2 < len(b); i++ {
+		if b[i] == '%' {
+			b[i+1], b[i+2] = upperHex(b[i+1]), upperHex(b[i+2])
+			i += 2
+		}
+	}
+	return string(b)
+}
+
+func upperHex(c byte) byte {
+	if c >= 'a' && c <= 'f' {
+		return c - 'a' + 'A'
+	}
+	return c
+}
+
+// removeDotSegments removes the . and .. segments of the absolute path as RFC 3986 section 5.2.4 does
+func removeDotSegments(path string) string {
+	segments := strings.Split(path[1:], "/")
+	out := make([]string, 0, len(segments))
+	for i, s := range segments {
+		last := i == len(segments)-1
+		switch s {
+		case ".":
+			if last {
+				out = append(out, "")
+			}
+		case "..":
+			if len(out) > 0 {
+				out = out[:len(out)-1]
+			}
+			if last {
+				out = append(out, "")
+			}
+		default:
+			out = append(out, s)
+		}
+	}
+	return "/" + strings.Join(out, "/")
+}
@@ -0,0 +1,45 @@
+package owl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeURL(t *testing.T) {
+	for _, c := range []struct {
+		in, out string
+		opts    []NormalizeOption
+	}{
+		{in: "HTTP://Example.COM:80", out: "http://example.com/"},
+		{in: "https://example.com:443/a/./b/../c/?", out: "https://example.com/a/c/"},
+		{in: "https://example.com:8443/a/..", out: "https://example.com:8443/"},
+		{in: "https://example.com/%7e%2fowl#top", out: "https://example.com/%7E%2Fowl"},
+		{in: "https://example.com/?z=1&utm_source=news&a=2&fbclid=x&a=1&&UTM_Medium=mail", out: "https://example.com/?a=2&a=1&z=1"},
+		{in: "https://example.com/?z=1&a=2", out: "https://example.com/?z=1&a=2", opts: []NormalizeOption{KeepQueryOrder()}},
+		{in: "https://example.com/?utm_source=news#top", out: "https://example.com/?utm_source=news#top",
+			opts: []NormalizeOption{KeepTrackingParams(), KeepFragment()}},
+		{in: "https://example.com/?sess_id=1&id=2&SID=3", out: "https://example.com/?id=2",
+			opts: []NormalizeOption{StripQueryParams("sess_*", "sid")}},
+		{in: "https://WWW.example.com./a/", out: "https://example.com/a", opts: []NormalizeOption{StripWWW(), StripTrailingSlash()}},
+		{in: "https://[::1]:443/", out: "https://[::1]/"},
+		{in: "mailto:Owl@Example.com", out: "mailto:Owl@Example.com"},
+		{in: "../a/./b", out: "../a/./b"},
+	} {
+		out, err := NormalizeURL(c.in, c.opts...)
+		require.NoError(t, err, c.in)
+		require.Equal(t, c.out, out, c.in)
+	}
+	_, err := NormalizeURL("http://%zz")
+	require.Error(t, err)
+}
+
+func TestCrawlerNormalize(t *testing.T) {
+	crawler := NewCrawler(nil)
+	require.NoError(t, crawler.Visit("https://Example.com/a?utm_source=x&b=1&a=2"))
+	require.ErrorIs(t, crawler.Visit("https://example.com:443/b/../a?a=2&b=1#top"), ErrAlreadyVisited)
+	req, ok, err := crawler.Store.Dequeue()
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "https://example.com/a?a=2&b=1", req.URL)
+}
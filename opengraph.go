@@ -0,0 +1,189 @@
+package owl
+
+import (
+	"errors"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+var (
+	// ErrNoOpenGraph is returned by OpenGraph when the document has no og: properties
+	ErrNoOpenGraph = errors.New("owl: no Open Graph properties")
+	// ErrNoTwitterCard is returned by TwitterCard when the document has no twitter: properties
+	ErrNoTwitterCard = errors.New("owl: no Twitter Card properties")
+)
+
+// OpenGraph holds the Open Graph properties of a document, see https://ogp.me
+type OpenGraph struct {
+	Title       string
+	Type        string
+	URL         string
+	Description string
+	SiteName    string
+	Determiner  string
+	Locale      string
+	// LocaleAlternates lists the og:locale:alternate properties
+	LocaleAlternates []string
+	Images           []OpenGraphMedia
+	Videos           []OpenGraphMedia
+	Audios           []OpenGraphMedia
+}
+
+// OpenGraphMedia is an og:image, og:video or og:audio along with its structured properties
+type OpenGraphMedia struct {
+	URL       string
+	SecureURL string
+	Type      string
+	Width     int
+	Height    int
+	Alt       string
+}
+
+// TwitterCard holds the twitter: properties of a document
+type TwitterCard struct {
+	Card        string
+	Site        string
+	Creator     string
+	Title       string
+	Description string
+	Image       string
+	ImageAlt    string
+	Player      string
+}
+
+// OpenGraph decodes the og: meta properties of the document the Node belongs to.
+// Structured properties such as og:image:width apply to the og:image before them.
+// URLs are resolved against BaseURL when the document has one
+func (r *Root) OpenGraph() (*OpenGraph, error) {
+	og := &OpenGraph{}
+	found := false
+	media := func(kind string) *[]OpenGraphMedia {
+		switch kind {
+		case "image":
+			return &og.Images
+		case "video":
+			return &og.Videos
+		case "audio":
+			return &og.Audios
+		}
+		return nil
+	}
+	r.metaProperties("og:", func(key, content string) {
+		found = true
+		kind, sub, _ := strings.Cut(key, ":")
+		if list := media(kind); list != nil {
+			if sub == "" || (sub == "url" && (len(*list) == 0 || (*list)[len(*list)-1].URL != "")) {
+				*list = append(*list, OpenGraphMedia{URL: r.resolveString(content)})
+				return
+			}
+			if len(*list) == 0 {
+				*list = append(*list, OpenGraphMedia{})
+			}
+			m := &(*list)[len(*list)-1]
+			switch sub {
+			case "url":
+				m.URL = r.resolveString(content)
+			case "secure_url":
+				m.SecureURL = r.resolveString(content)
+			case "type":
+				m.Type = content
+			case "width":
+				m.Width = atoi(content)
+			case "height":
+				m.Height = atoi(content)
+			case "alt":
+				m.Alt = content
+			}
+			return
+		}
+		switch key {
+		case "title":
+			og.Title = first(og.Title, content)
+		case "type":
+			og.Type = first(og.Type, content)
+		case "url":
+			og.URL = first(og.URL, r.resolveString(content))
+		case "description":
+			og.Description = first(og.Description, content)
+		case "site_name":
+			og.SiteName = first(og.SiteName, content)
+		case "determiner":
+			og.Determiner = first(og.Determiner, content)
+		case "locale":
+			og.Locale = first(og.Locale, content)
+		case "locale:alternate":
+			og.LocaleAlternates = append(og.LocaleAlternates, content)
+		}
+	})
+	if !found {
+		return nil, ErrNoOpenGraph
+	}
+	return og, nil
+}
+
+// TwitterCard decodes the twitter: meta properties of the document the Node belongs to
+func (r *Root) TwitterCard() (*TwitterCard, error) {
+	card := &TwitterCard{}
+	found := false
+	r.metaProperties("twitter:", func(key, content string) {
+		found = true
+		switch key {
+		case "card":
+			card.Card = first(card.Card, content)
+		case "site":
+			card.Site = first(card.Site, content)
+		case "creator":
+			card.Creator = first(card.Creator, content)
+		case "title":
+			card.Title = first(card.Title, content)
+		case "description":
+			card.Description = first(card.Description, content)
+		case "image", "image:src":
+			card.Image = first(card.Image, r.resolveString(content))
+		case "image:alt":
+			card.ImageAlt = first(card.ImageAlt, content)
+		case "player":
+			card.Player = first(card.Player, r.resolveString(content))
+		}
+	})
+	if !found {
+		return nil, ErrNoTwitterCard
+	}
+	return card, nil
+}
+
+// metaProperties calls f in document order for every meta element of the document whose property
+// or name starts with prefix, with the rest of the lowercased key and the trimmed content
+func (r *Root) metaProperties(prefix string, f func(key, content string)) {
+	if r.Node == nil {
+		return
+	}
+	top := r.Node
+	for top.Parent != nil {
+		top = top.Parent
+	}
+	walk(top, func(n *html.Node) bool {
+		if n.Type != html.ElementNode || n.Data != "meta" {
+			return true
+		}
+		key := strings.ToLower(strings.TrimSpace(attrValue(n, "property")))
+		if !strings.HasPrefix(key, prefix) {
+			key = strings.ToLower(strings.TrimSpace(attrValue(n, "name")))
+		}
+		if rest, ok := strings.CutPrefix(key, prefix); ok && rest != "" {
+			f(rest, strings.TrimSpace(attrValue(n, "content")))
+		}
+		return true
+	})
+}
+
+// first returns the first non empty string of values
+func first(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
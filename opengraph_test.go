@@ -0,0 +1,59 @@
+package owl
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenGraph(t *testing.T) {
+	doc := HTMLParseFromString(`<html><head>
+		<meta property="og:title" content="The Barn Owl">
+		<meta property="og:type" content="article">
+		<meta property="og:url" content="/barn-owl">
+		<meta property="og:locale" content="en_GB">
+		<meta property="og:locale:alternate" content="fr_FR">
+		<meta property="og:locale:alternate" content="de_DE">
+		<meta property="og:image" content="https://cdn.example.com/owl.jpg">
+		<meta property="og:image:width" content="1200">
+		<meta property="og:image:height" content="630">
+		<meta property="og:image:alt" content="A barn owl">
+		<meta property="og:image" content="/owl-small.jpg">
+		<meta property="og:image:type" content="image/jpeg">
+		<meta property="og:video:url" content="https://example.com/owl.mp4">
+		<meta property="og:video:secure_url" content="https://secure.example.com/owl.mp4">
+		<meta property="og:title" content="Ignored">
+		<meta name="twitter:card" content="summary_large_image">
+		<meta property="twitter:site" content="@owls">
+		<meta name="twitter:image" content="/card.png">
+	</head><body></body></html>`)
+	page, _ := url.Parse("https://example.com/birds/")
+	doc.SetURL(page)
+
+	og, err := doc.OpenGraph()
+	require.NoError(t, err)
+	require.Equal(t, "The Barn Owl", og.Title)
+	require.Equal(t, "article", og.Type)
+	require.Equal(t, "https://example.com/barn-owl", og.URL)
+	require.Equal(t, "en_GB", og.Locale)
+	require.Equal(t, []string{"fr_FR", "de_DE"}, og.LocaleAlternates)
+	require.Equal(t, []OpenGraphMedia{
+		{URL: "https://cdn.example.com/owl.jpg", Width: 1200, Height: 630, Alt: "A barn owl"},
+		{URL: "https://example.com/owl-small.jpg", Type: "image/jpeg"},
+	}, og.Images)
+	require.Equal(t, []OpenGraphMedia{
+		{URL: "https://example.com/owl.mp4", SecureURL: "https://secure.example.com/owl.mp4"},
+	}, og.Videos)
+
+	card, err := doc.TwitterCard()
+	require.NoError(t, err)
+	require.Equal(t, "summary_large_image", card.Card)
+	require.Equal(t, "@owls", card.Site)
+	require.Equal(t, "https://example.com/card.png", card.Image)
+
+	_, err = HtmlRoot.OpenGraph()
+	require.ErrorIs(t, err, ErrNoOpenGraph)
+	_, err = HtmlRoot.TwitterCard()
+	require.ErrorIs(t, err, ErrNoTwitterCard)
+}
@@ -0,0 +1,249 @@
+package owl
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestOption customizes a single request of a Client without changing the Client,
+// see WithHeader and WithQuery
+type RequestOption interface {
+	applyRequest(*requestConfig)
+}
+
+// requestConfig holds what the RequestOptions of a request set
+type requestConfig struct {
+	header       http.Header
+	query        [][2]string
+	statusErrors *bool
+	tracer       trace.Tracer
+	bandwidth    int64
+	timeout      *time.Duration
+	ctx          context.Context
+	waitSelector string
+	noRender     bool
+}
+
+type requestOptionFunc func(*requestConfig)
+
+func (f requestOptionFunc) applyRequest(r *requestConfig) { f(r) }
+
+// WithHeader sets the header key of the request to value, replacing the value the Client would send
+func WithHeader(key, value string) RequestOption {
+	return requestOptionFunc(func(r *requestConfig) {
+		if r.header == nil {
+			r.header = make(http.Header)
+		}
+		r.header.Set(key, value)
+	})
+}
+
+// WithQuery adds the query parameter key with value to the parameters the URL of the request already has
+func WithQuery(key, value string) RequestOption {
+	return requestOptionFunc(func(r *requestConfig) {
+		r.query = append(r.query, [2]string{key, value})
+	})
+}
+
+// WithStatusErrors overrides the StatusErrors of the Client for the request
+func WithStatusErrors(enabled bool) RequestOption {
+	return requestOptionFunc(func(r *requestConfig) { r.statusErrors = &enabled })
+}
+
+// WithContext sends the request within ctx, canceling ctx cancels the request and its retries
+func WithContext(ctx context.Context) RequestOption {
+	return requestOptionFunc(func(r *requestConfig) { r.ctx = ctx })
+}
+
+// newRequestConfig applies opts in order
+func newRequestConfig(opts []RequestOption) *requestConfig {
+	r := &requestConfig{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt.applyRequest(r)
+		}
+	}
+	return r
+}
+
+// context returns the parent context of the request
+func (r *requestConfig) context() context.Context {
+	if r.ctx == nil {
+		return context.Background()
+	}
+	return r.ctx
+}
+
+// requestTimeout returns the timeout of each attempt of a request
+func (c *Client) requestTimeout(cfg *requestConfig) time.Duration {
+	if cfg.timeout != nil {
+		return *cfg.timeout
+	}
+	return c.RequestTimeout
+}
+
+// requestClient returns the http.Client sending a request. The Timeout of the http.Client
+// gives way to the timeout of WithTimeout, which covers the same ground
+func (c *Client) requestClient(cfg *requestConfig) *http.Client {
+	hc := c.httpClient()
+	if cfg.timeout == nil || hc.Timeout == 0 {
+		return hc
+	}
+	copied := *hc
+	copied.Timeout = 0
+	return &copied
+}
+
+// apply sets the headers and query parameters of r on req
+func (r *requestConfig) apply(req *http.Request) {
+	for key, values := range r.header {
+		req.Header[key] = values
+	}
+	if len(r.query) > 0 {
+		query := req.URL.Query()
+		for _, kv := range r.query {
+			query.Add(kv[0], kv[1])
+		}
+		req.URL.RawQuery = query.Encode()
+	}
+}
+
+// Option configures the Client built by NewClient, see WithTimeout and WithHTTPClient
+type Option interface {
+	applyClient(*clientConfig)
+}
+
+// clientConfig holds what the Options of NewClient set
+type clientConfig struct {
+	Parameters
+	retry *RetryPolicy
+	cache Cache
+}
+
+type optionFunc func(*clientConfig)
+
+func (f optionFunc) applyClient(c *clientConfig) { f(c) }
+
+// applyClient replaces the configuration with p, DefaultParameters when p is nil
+func (p *Parameters) applyClient(c *clientConfig) {
+	if p == nil {
+		p = &DefaultParameters
+	}
+	c.Parameters = *p
+}
+
+// WithHTTPClient sends the requests with hc, the timeout of WithTimeout then only applies to each attempt
+func WithHTTPClient(hc *http.Client) Option {
+	return optionFunc(func(c *clientConfig) { c.HttpClient = hc })
+}
+
+// SharedOption is both an Option of NewClient and a RequestOption,
+// it applies to every request of the Client or to a single one
+type SharedOption interface {
+	Option
+	RequestOption
+}
+
+type sharedOption struct {
+	optionFunc
+	requestOptionFunc
+}
+
+// WithTimeout limits every attempt of a request to d, reading the body included. It is 10s by default.
+// As a RequestOption it overrides the RequestTimeout of the Client, 0 sends the request without timeout
+func WithTimeout(d time.Duration) SharedOption {
+	return sharedOption{
+		optionFunc:        func(c *clientConfig) { c.RequestTimeout, c.Timeout = d, d },
+		requestOptionFunc: func(r *requestConfig) { r.timeout = &d },
+	}
+}
+
+// WithHeaders adds header to the headers sent with every request, replacing the defaults of the same name
+func WithHeaders(header map[string]string) Option {
+	return optionFunc(func(c *clientConfig) {
+		// The map is copied, it may be shared with DefaultParameters or a Parameters
+		merged := make(map[string]string, len(c.Header)+len(header))
+		for key, value := range c.Header {
+			merged[http.CanonicalHeaderKey(key)] = value
+		}
+		for key, value := range header {
+			merged[http.CanonicalHeaderKey(key)] = value
+		}
+		c.Header = merged
+	})
+}
+
+// WithUserAgent sets the User-Agent sent with every request
+func WithUserAgent(userAgent string) Option {
+	return WithHeaders(map[string]string{"User-Agent": userAgent})
+}
+
+// WithRateLimit limits the requests to each host, see NewRateLimiter
+func WithRateLimit(limit RateLimit) Option {
+	return optionFunc(func(c *clientConfig) { c.RateLimit = &limit })
+}
+
+// WithCircuitBreaker stops the requests to the hosts that keep failing, see NewCircuitBreaker
+func WithCircuitBreaker(settings BreakerSettings) Option {
+	return optionFunc(func(c *clientConfig) { c.Breaker = &settings })
+}
+
+// WithRetry retries the requests that failed for reasons that may not last, see DefaultRetryPolicy
+func WithRetry(policy RetryPolicy) Option {
+	return optionFunc(func(c *clientConfig) { c.retry = &policy })
+}
+
+// WithCache stores the responses to GET requests in cache, see Client.Cache
+func WithCache(cache Cache) Option {
+	return optionFunc(func(c *clientConfig) { c.cache = cache })
+}
+
+// WithMaxBodySize limits the size in bytes of response bodies, see Client.MaxBodySize
+func WithMaxBodySize(size int64) Option {
+	return optionFunc(func(c *clientConfig) { c.MaxBodySize = size })
+}
+
+// WithMaxRefreshes follows up to n meta refreshes and JavaScript redirects, see Client.MaxRefreshes
+func WithMaxRefreshes(n int) Option {
+	return optionFunc(func(c *clientConfig) { c.MaxRefreshes = n })
+}
+
+// WithBandwidthLimit limits how fast all the response bodies are read together in bytes per second,
+// see WithBandwidth for a single request
+func WithBandwidthLimit(bytesPerSecond int64) Option {
+	return optionFunc(func(c *clientConfig) { c.Bandwidth = bytesPerSecond })
+}
+
+// WithProxy chooses the proxy of each request, see ParseProxies
+func WithProxy(proxy Proxy) Option {
+	return optionFunc(func(c *clientConfig) { c.Proxy = proxy })
+}
+
+// WithTLS configures the TLS connections, see TLSOptions
+func WithTLS(opts TLSOptions) Option {
+	return optionFunc(func(c *clientConfig) { c.TLS = &opts })
+}
+
+// WithTransportOptions tunes the connections, see TransportOptions
+func WithTransportOptions(opts TransportOptions) Option {
+	return optionFunc(func(c *clientConfig) { c.Transport = &opts })
+}
+
+// WithLogger sends the events of the requests to logger, see Client.Logger
+func WithLogger(logger Logger) Option {
+	return optionFunc(func(c *clientConfig) { c.Logger = logger })
+}
+
+// newClientConfig applies opts in order to DefaultParameters
+func newClientConfig(opts []Option) *clientConfig {
+	c := &clientConfig{Parameters: DefaultParameters}
+	for _, opt := range opts {
+		if opt != nil {
+			opt.applyClient(c)
+		}
+	}
+	return c
+}
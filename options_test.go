@@ -0,0 +1,113 @@
+package owl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestOptions(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s|%s|%s|%s", r.URL.RawQuery, r.Header.Get("X-Api-Key"), r.Header.Get("User-Agent"), r.Header.Get("Content-Type"))
+	}))
+	defer srv.Close()
+	client := &Client{Client: srv.Client(), Header: map[string]string{"User-Agent": "owl", "X-Api-Key": "shared"}}
+
+	read := func(r io.Reader, err error) string {
+		require.NoError(t, err)
+		data, err := io.ReadAll(r)
+		require.NoError(t, err)
+		return string(data)
+	}
+	require.Equal(t, "page=2&q=barn+owl&q=snowy|secret|owl|",
+		read(client.Get(srv.URL+"/?q=barn+owl", WithHeader("X-Api-Key", "secret"), WithQuery("page", "2"), WithQuery("q", "snowy"))))
+	require.Equal(t, "|shared|owl|", read(client.Get(srv.URL)))
+
+	require.Equal(t, "|shared|agent|application/json",
+		read(client.Post(srv.URL, "application/json", "{}", WithHeader("User-Agent", "agent"))))
+	// Post leaves the headers of the client alone
+	require.Equal(t, map[string]string{"User-Agent": "owl", "X-Api-Key": "shared"}, client.Header)
+
+	resp, err := client.GetResponse(srv.URL, WithQuery("a", "b"))
+	require.NoError(t, err)
+	require.Equal(t, "a=b|shared|owl|", string(resp.Body))
+}
+
+func TestClientOptions(t *testing.T) {
+	hc := &http.Client{}
+	client := NewClient(
+		WithHTTPClient(hc),
+		WithTimeout(3*time.Second),
+		WithHeaders(map[string]string{"accept": "application/json", "X-Api-Key": "secret"}),
+		WithUserAgent("owl-test"),
+		WithRateLimit(RateLimit{Every: time.Second}),
+		WithRetry(DefaultRetryPolicy),
+		WithMaxBodySize(1024),
+	)
+	require.Same(t, hc, client.Client)
+	require.Equal(t, 3*time.Second, client.RequestTimeout)
+	require.Equal(t, map[string]string{
+		"User-Agent":    "owl-test",
+		"Accept":        "application/json",
+		"Cache-Control": "max-age=0",
+		"X-Api-Key":     "secret",
+	}, client.Header)
+	require.NotNil(t, client.Limiter)
+	require.Equal(t, DefaultRetryPolicy.MaxAttempts, client.Retry.MaxAttempts)
+	require.Equal(t, int64(1024), client.MaxBodySize)
+	// The defaults are left alone
+	require.Equal(t, "Owl Mozilla/5.0 Firefox/96.0", DefaultParameters.Header["User-Agent"])
+	require.NotContains(t, DefaultParameters.Header, "X-Api-Key")
+
+	client = NewClient(WithTimeout(time.Second))
+	require.Equal(t, time.Second, client.Timeout)
+	require.Equal(t, DefaultParameters.Header, client.Header)
+
+	// Options apply on top of Parameters
+	client = NewClient(&Parameters{MaxRefreshes: 2}, WithMaxBodySize(10))
+	require.Equal(t, 2, client.MaxRefreshes)
+	require.Equal(t, int64(10), client.MaxBodySize)
+	require.Empty(t, client.Header)
+	var para *Parameters
+	client = NewClient(para)
+	require.Equal(t, DefaultParameters.RequestTimeout, client.RequestTimeout)
+}
+
+func TestRequestTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(100 * time.Millisecond):
+		case <-r.Context().Done():
+		}
+		w.Write([]byte("slow"))
+	}))
+	defer srv.Close()
+	hc := srv.Client()
+	hc.Timeout = 20 * time.Millisecond
+	client := NewClient(WithHTTPClient(hc), WithTimeout(20*time.Millisecond))
+
+	_, err := client.Get(srv.URL)
+	require.Error(t, err)
+	// The timeout of the request replaces both the RequestTimeout and the http.Client Timeout
+	_, err = client.Get(srv.URL, WithTimeout(5*time.Second))
+	require.NoError(t, err)
+	_, err = client.Get(srv.URL, WithTimeout(0))
+	require.NoError(t, err)
+
+	// The request context derives from the one of WithContext
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.Get(srv.URL, WithContext(ctx), WithTimeout(5*time.Second))
+	require.ErrorIs(t, err, context.Canceled)
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = client.Get(srv.URL, WithContext(ctx), WithTimeout(5*time.Second))
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
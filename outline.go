@@ -0,0 +1,84 @@
+package owl
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Heading is an h1 to h6 element of the document along with the headings of its section
+type Heading struct {
+	Level int
+	// Text is the text of the heading with whitespace collapsed
+	Text string
+	// ID is the id of the heading, or the id or name of an anchor inside it, empty when there is none
+	ID       string
+	Children []Heading
+}
+
+// Outline returns the headings below the Node as a tree, each heading holding the following
+// headings of a deeper level. Skipped levels are tolerated: an h4 after an h2 is a child of the h2
+func (r *Root) Outline() []Heading {
+	var headings []Heading
+	walk(r.Node, func(n *html.Node) bool {
+		if n.Type != html.ElementNode {
+			return true
+		}
+		if hiddenElements[n.Data] {
+			return false
+		}
+		level := headingLevel(n.Data)
+		if level == 0 {
+			return true
+		}
+		headings = append(headings, Heading{
+			Level: level,
+			Text:  strings.Join(strings.Fields((&Root{Node: n}).FullText()), " "),
+			ID:    headingID(n),
+		})
+		return false
+	})
+	outline, _ := nestHeadings(headings, 0)
+	return outline
+}
+
+// nestHeadings builds the tree of the headings deeper than level at the start of flat,
+// returning it along with the headings left
+func nestHeadings(flat []Heading, level int) ([]Heading, []Heading) {
+	var tree []Heading
+	for len(flat) > 0 && flat[0].Level > level {
+		h := flat[0]
+		h.Children, flat = nestHeadings(flat[1:], h.Level)
+		tree = append(tree, h)
+	}
+	return tree, flat
+}
+
+// headingLevel returns the level of the heading tag, 0 when tag is not one
+func headingLevel(tag string) int {
+	if len(tag) == 2 && tag[0] == 'h' && tag[1] >= '1' && tag[1] <= '6' {
+		return int(tag[1] - '0')
+	}
+	return 0
+}
+
+// headingID returns the fragment linking to the heading n
+func headingID(n *html.Node) string {
+	if id := strings.TrimSpace(attrValue(n, "id")); id != "" {
+		return id
+	}
+	var id string
+	walk(n, func(c *html.Node) bool {
+		if id != "" {
+			return false
+		}
+		if c.Type == html.ElementNode && c.Data == "a" {
+			id = strings.TrimSpace(attrValue(c, "id"))
+			if id == "" {
+				id = strings.TrimSpace(attrValue(c, "name"))
+			}
+		}
+		return id == ""
+	})
+	return id
+}
@@ -0,0 +1,35 @@
+package owl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutline(t *testing.T) {
+	outline := HTMLParseFromString(`<html><body>
+		<h1 id="owls">Owls</h1>
+		<h2>  Barn   owls </h2>
+		<h4><a name="diet"></a>Diet</h4>
+		<h3>Habitat</h3>
+		<h2 id="snowy">Snowy owls</h2>
+		<template><h2>Hidden</h2></template>
+		<h1>Appendix</h1>
+	</body></html>`).Outline()
+
+	require.Len(t, outline, 2)
+	owls := outline[0]
+	require.Equal(t, Heading{Level: 1, Text: "Owls", ID: "owls"}, Heading{Level: owls.Level, Text: owls.Text, ID: owls.ID})
+	require.Len(t, owls.Children, 2)
+
+	barn := owls.Children[0]
+	require.Equal(t, "Barn owls", barn.Text)
+	require.Len(t, barn.Children, 2)
+	require.Equal(t, 4, barn.Children[0].Level)
+	require.Equal(t, "diet", barn.Children[0].ID)
+	require.Equal(t, "Habitat", barn.Children[1].Text)
+
+	require.Equal(t, "snowy", owls.Children[1].ID)
+	require.Empty(t, owls.Children[1].Children)
+	require.Equal(t, "Appendix", outline[1].Text)
+}
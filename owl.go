@@ -1,15 +1,13 @@
 package owl
 
 import (
-	"bytes"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
-	"regexp"
+	"net/url"
 	"strings"
 
-	"github.com/gobwas/glob"
 	"golang.org/x/net/html"
 )
 
@@ -17,16 +15,63 @@ type Root struct {
 	Node      *html.Node
 	NodeValue string
 	Error     *Error
+
+	doc *document
+}
+
+// document holds the state shared by every Root of the same parsed document
+type document struct {
+	index *nodeIndex
+	// raw maps text nodes to their source text, see HTMLParseKeepRaw
+	raw map[*html.Node]string
+	// url is the final URL of the response the document was read from
+	url *url.URL
 }
 
+// HTMLParse parses the document read from r,
+// gzip and zstd compressed input is recognized by its first bytes and decompressed
 func HTMLParse(r io.Reader) *Root {
-	return htmlparsing(r)
+	dr, release, err := decompress(r)
+	if err != nil {
+		return &Root{Node: nil, NodeValue: "", Error: newError(ErrUnableToParse, err)}
+	}
+	defer release()
+	return htmlparsing(dr)
 }
 
 func HTMLParseFromString(s string) *Root {
 	return htmlparsing(strings.NewReader(s))
 }
 
+// HTMLParseFragment parses the HTML fragment read from r as the content of a body element,
+// without the html, head and body elements a document gets. The Node is a document node holding
+// the nodes of the fragment, so Render returns the fragment as it was parsed
+func HTMLParseFragment(r io.Reader) *Root {
+	dr, release, err := decompress(r)
+	if err != nil {
+		return &Root{Node: nil, NodeValue: "", Error: newError(ErrUnableToParse, err)}
+	}
+	defer release()
+	data, err := io.ReadAll(dr)
+	if err != nil {
+		return &Root{Node: nil, NodeValue: "", Error: newError(ErrUnableToParse, err)}
+	}
+	nodes, err := parseFragment(string(data), nil)
+	if err != nil {
+		return &Root{Node: nil, NodeValue: "", Error: newError(ErrUnableToParse, err)}
+	}
+	root := &html.Node{Type: html.DocumentNode}
+	for _, n := range nodes {
+		root.AppendChild(n)
+	}
+	return &Root{Node: root, NodeValue: root.Data, Error: nil, doc: &document{}}
+}
+
+// HTMLParseFragmentFromString parses the HTML fragment s, see HTMLParseFragment
+func HTMLParseFragmentFromString(s string) *Root {
+	return HTMLParseFragment(strings.NewReader(s))
+}
+
 func htmlparsing(r io.Reader) *Root {
 	root, err := html.Parse(r)
 	if err != nil {
@@ -45,7 +90,7 @@ func htmlparsing(r io.Reader) *Root {
 			root = root.NextSibling
 		}
 	}
-	return &Root{Node: root, NodeValue: root.Data, Error: nil}
+	return &Root{Node: root, NodeValue: root.Data, Error: nil, doc: &document{}}
 }
 
 // Find finds the first occurrence of the given tag name,
@@ -53,7 +98,7 @@ func htmlparsing(r io.Reader) *Root {
 // and returns a struct with a Node to it
 
 func (r *Root) Find(args ...string) *Root {
-	temp, ok := findOnce(r.Node, args, false, false)
+	temp, ok := r.findOnce(args, false)
 	if !ok {
 		return &Root{Node: nil, NodeValue: "", Error: &Error{
 			Type: ErrElementNotFound,
@@ -61,13 +106,13 @@ func (r *Root) Find(args ...string) *Root {
 		},
 		}
 	}
-	return &Root{Node: temp, NodeValue: temp.Data, Error: nil}
+	return &Root{Node: temp, NodeValue: temp.Data, Error: nil, doc: r.doc}
 }
 
 // FindStrict finds the first occurrence of the given tag name
 // only if all the values of the provided attribute are an exact match
 func (r *Root) FindStrict(args ...string) *Root {
-	temp, ok := findOnce(r.Node, args, false, true)
+	temp, ok := r.findOnce(args, true)
 	if !ok {
 		return &Root{Node: nil, NodeValue: "", Error: &Error{
 			Type: ErrElementNotFound,
@@ -76,12 +121,12 @@ func (r *Root) FindStrict(args ...string) *Root {
 		}
 	}
 
-	return &Root{Node: temp, NodeValue: temp.Data, Error: nil}
+	return &Root{Node: temp, NodeValue: temp.Data, Error: nil, doc: r.doc}
 }
 
 func (r *Root) Title() *Root {
 	var slic []string = []string{"title"}
-	re, exits := findOnce(r.Node, slic, false, true)
+	re, exits := r.findOnce(slic, true)
 	if !exits {
 		return &Root{Node: nil, NodeValue: "", Error: &Error{
 			Type: ErrElementNotFound,
@@ -89,7 +134,7 @@ func (r *Root) Title() *Root {
 		},
 		}
 	}
-	return &Root{Node: re, NodeValue: re.Data, Error: nil}
+	return &Root{Node: re, NodeValue: re.Data, Error: nil, doc: r.doc}
 }
 
 // FindNextSibling finds the next sibling of the Node in the DOM
@@ -99,7 +144,7 @@ func (r *Root) FindNextSibling() *Root {
 	if nextSibling == nil {
 		return &Root{Node: nil, NodeValue: "", Error: newError(ErrNoNextSibling, errors.New("no next sibling found"))}
 	}
-	return &Root{Node: nextSibling, NodeValue: nextSibling.Data, Error: nil}
+	return &Root{Node: nextSibling, NodeValue: nextSibling.Data, Error: nil, doc: r.doc}
 }
 
 func (r *Root) FindPrevSibling() *Root {
@@ -108,7 +153,7 @@ func (r *Root) FindPrevSibling() *Root {
 		return &Root{Node: nil, NodeValue: "", Error: newError(ErrNoNextSibling, errors.New("no previous sibling found"))}
 
 	}
-	return &Root{Node: prevSibling, NodeValue: prevSibling.Data, Error: nil}
+	return &Root{Node: prevSibling, NodeValue: prevSibling.Data, Error: nil, doc: r.doc}
 }
 
 // FindNextElementSibling finds the next element sibling of the pointer in the DOM
@@ -119,9 +164,9 @@ func (r Root) FindNextElementSibling() *Root {
 		return &Root{Node: nil, NodeValue: "", Error: newError(ErrNoNextSibling, errors.New("no next element sibling found"))}
 	}
 	if nextSibling.Type == html.ElementNode {
-		return &Root{Node: nextSibling, NodeValue: nextSibling.Data, Error: nil}
+		return &Root{Node: nextSibling, NodeValue: nextSibling.Data, Error: nil, doc: r.doc}
 	}
-	p := &Root{Node: nextSibling, NodeValue: nextSibling.Data}
+	p := &Root{Node: nextSibling, NodeValue: nextSibling.Data, doc: r.doc}
 	return p.FindNextElementSibling()
 }
 
@@ -133,44 +178,83 @@ func (r Root) FindPrevElementSibling() *Root {
 		return &Root{Node: nil, NodeValue: "", Error: newError(ErrNoNextSibling, errors.New("no previous element sibling found"))}
 	}
 	if prevSibling.Type == html.ElementNode {
-		return &Root{Node: prevSibling, NodeValue: prevSibling.Data, Error: nil}
+		return &Root{Node: prevSibling, NodeValue: prevSibling.Data, Error: nil, doc: r.doc}
 	}
-	p := Root{Node: prevSibling, NodeValue: prevSibling.Data}
+	p := Root{Node: prevSibling, NodeValue: prevSibling.Data, doc: r.doc}
 	return p.FindPrevElementSibling()
 }
 
 // FullText returns the string inside even a nested element
 func (r Root) FullText() string {
-	var buf bytes.Buffer
+	buf := getBuffer()
+	defer putBuffer(buf)
 
-	var f func(*html.Node)
-	f = func(n *html.Node) {
-		if n == nil {
-			return
-		}
-		if n.Type == html.TextNode {
-			buf.WriteString(n.Data)
-		}
-		if n.Type == html.ElementNode {
-			f(n.FirstChild)
-		}
-		if n.NextSibling != nil {
-			f(n.NextSibling)
+	r.FullTextTo(buf)
+	return buf.String()
+}
+
+// FullTextTo writes the string inside even a nested element to w
+func (r Root) FullTextTo(w io.Writer) error {
+	var f func(*html.Node) error
+	f = func(n *html.Node) error {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			switch c.Type {
+			case html.TextNode:
+				if _, err := io.WriteString(w, c.Data); err != nil {
+					return err
+				}
+			case html.ElementNode:
+				if err := f(c); err != nil {
+					return err
+				}
+			}
 		}
+		return nil
 	}
+	return f(r.Node)
+}
 
-	f(r.Node.FirstChild)
+// Render returns the HTML code for the specific element, see Pretty and Minify for the options
+func (r Root) Render(opts ...RenderOption) []byte {
+	buf := getBuffer()
+	defer putBuffer(buf)
 
-	return buf.String()
+	if err := r.RenderTo(buf, opts...); err != nil {
+		return nil
+	}
+	return append([]byte(nil), buf.Bytes()...)
 }
 
-// HTML returns the HTML code for the specific element
-func (r Root) Render() []byte {
-	var buf bytes.Buffer
-	if err := html.Render(&buf, r.Node); err != nil {
-		return nil
+// RenderTo writes the HTML code for the specific element to w, without buffering it whole
+func (r Root) RenderTo(w io.Writer, opts ...RenderOption) error {
+	if r.Node == nil {
+		return ErrNoNode
+	}
+	return renderNode(w, r.Node, opts)
+}
+
+// HTML returns the HTML code of the element along with its tags, Render as a string
+func (r Root) HTML() string {
+	if r.Node == nil {
+		return ""
 	}
-	return buf.Bytes()
+	return string(r.Render())
+}
+
+// InnerHTML returns the HTML code of the children of the element, without its own tags
+func (r Root) InnerHTML() string {
+	if r.Node == nil {
+		return ""
+	}
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	for c := r.Node.FirstChild; c != nil; c = c.NextSibling {
+		if err := html.Render(buf, c); err != nil {
+			return ""
+		}
+	}
+	return buf.String()
 }
 
 type Roots struct {
@@ -180,14 +264,14 @@ type Roots struct {
 }
 
 func (r *Root) FindAll(args ...string) Roots {
-	temp := findAllofem(r.Node, args, false)
+	temp := r.findAll(args, false)
 	length := len(temp)
 	if length == 0 {
 		return Roots{Roots: nil, Error: newError(ErrElementsNotFound, errors.New("no elements or attriabutes found"))}
 	}
 	Nodes := make([](*Root), 0, length)
 	for i := 0; i < length; i++ {
-		Nodes = append(Nodes, &Root{Node: temp[i], NodeValue: temp[i].Data})
+		Nodes = append(Nodes, &Root{Node: temp[i], NodeValue: temp[i].Data, doc: r.doc})
 	}
 	return Roots{Roots: Nodes, Len: length, Error: nil}
 }
@@ -202,14 +286,14 @@ func (rs Roots) Last() *Root {
 // FindAllStrict finds all occurrences of the given tag name
 // only if all the values of the provided attribute are an exact match
 func (r Root) FindAllStrict(args ...string) Roots {
-	temp := findAllofem(r.Node, args, true)
+	temp := r.findAll(args, true)
 	length := len(temp)
 	if length == 0 {
 		return Roots{Roots: nil, Len: 0, Error: newError(ErrElementNotFound, fmt.Errorf("element `%s` with attributes `%s` not found", args[0], strings.Join(args[1:], " ")))}
 	}
 	Nodes := make([](*Root), 0, length)
 	for i := 0; i < length; i++ {
-		Nodes = append(Nodes, &Root{Node: temp[i], NodeValue: temp[i].Data})
+		Nodes = append(Nodes, &Root{Node: temp[i], NodeValue: temp[i].Data, doc: r.doc})
 	}
 	return Roots{Roots: Nodes, Len: length, Error: nil}
 }
@@ -225,55 +309,109 @@ func (rs Roots) ForEach(f func(int, *Root)) *Root {
 	return r
 }
 
-// Text returns the string inside a non-nested element
+// Text returns the string inside a non-nested element,
+// that is the first child text node which is not only whitespace
 func (r *Root) Text() string {
-	var f func(*html.Node) string
-	k := r.Node.FirstChild
-
-	f = func(n *html.Node) string {
-		if n != nil && n.Type != html.TextNode {
-			if n = n.NextSibling; n == nil {
-				return ""
-			}
-			f(n)
-		}
-		if k != nil {
-			r, _ := regexp.Compile(`^\s+$`)
-			if ok := r.MatchString(k.Data); ok {
-				if n = n.NextSibling; n == nil {
-					return ""
-				}
-				f(n)
-			}
-			return k.Data
+	for c := r.Node.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.TextNode && !isBlank(c.Data) {
+			return c.Data
 		}
-		return ""
 	}
-	return f(k)
+	return ""
+}
+
+// isBlank reports whether s is empty or made of whitespace only
+func isBlank(s string) bool {
+	return strings.TrimSpace(s) == ""
 }
 
 // Attrs() returns a map containing all attributes
 func (r *Root) Attrs() map[string]string {
-	if (r.Node.Type != html.ElementNode) && (len(r.Node.Attr) == 0) {
+	if !r.hasAttrs() {
 		return nil
 	}
 	return getKeyValue(r.Node.Attr)
 }
 
-// Attrs just like Atr
+// AttrsAll returns every value of every attribute, unlike Attrs() which keeps the first of repeated attributes.
+// Values are in the order they appear on the element, use AttrKeys for the order of the keys
+func (r *Root) AttrsAll() map[string][]string {
+	if !r.hasAttrs() {
+		return nil
+	}
+	all := make(map[string][]string, len(r.Node.Attr))
+	for _, a := range r.Node.Attr {
+		all[a.Key] = append(all[a.Key], a.Val)
+	}
+	return all
+}
+
+// AttrKeys returns the attribute names of the element in the order they first appear
+func (r *Root) AttrKeys() []string {
+	if !r.hasAttrs() {
+		return nil
+	}
+	keys := make([]string, 0, len(r.Node.Attr))
+	for _, a := range r.Node.Attr {
+		if !containsString(keys, a.Key) {
+			keys = append(keys, a.Key)
+		}
+	}
+	return keys
+}
 
-func (r *Root) Attr(s string) (string, bool) {
-	if (r.Node.Type != html.ElementNode) && (len(r.Node.Attr) == 0) {
-		return " ", false
+// Attr returns the value of a single attribute without building the Attrs() map,
+// reporting whether the element has it
+func (r *Root) Attr(key string) (string, bool) {
+	if !r.hasAttrs() {
+		return "", false
 	}
 	for _, a := range r.Node.Attr {
-		if a.Key == s {
+		if a.Key == key {
 			return a.Val, true
 		}
 	}
 	return "", false
 }
 
+// AttrOr returns the value of the attribute key, or def when the element does not have it
+func (r *Root) AttrOr(key, def string) string {
+	if val, ok := r.Attr(key); ok {
+		return val
+	}
+	return def
+}
+
+// Classes returns the classes of the element in the order of its class attribute, without duplicates
+func (r *Root) Classes() []string {
+	val, ok := r.Attr("class")
+	if !ok {
+		return nil
+	}
+	fields := strings.Fields(val)
+	classes := fields[:0]
+	for _, class := range fields {
+		if !containsString(classes, class) {
+			classes = append(classes, class)
+		}
+	}
+	return classes
+}
+
+// HasClass reports whether name is one of the classes of the element
+func (r *Root) HasClass(name string) bool {
+	val, ok := r.Attr("class")
+	if !ok {
+		return false
+	}
+	return attributeContainsValue(html.Attribute{Key: "class", Val: val}, "class", name)
+}
+
+// hasAttrs reports whether the Node is an element with at least one attribute
+func (r *Root) hasAttrs() bool {
+	return r.Node != nil && r.Node.Type == html.ElementNode && len(r.Node.Attr) > 0
+}
+
 func (r Root) Children() Roots {
 	childNode := r.Node.FirstChild
 	var (
@@ -281,7 +419,7 @@ func (r Root) Children() Roots {
 		rootNode     [](*Root)
 	)
 	for childNode != nil {
-		rootNode = append(rootNode, &Root{Node: childNode, NodeValue: childNode.Data})
+		rootNode = append(rootNode, &Root{Node: childNode, NodeValue: childNode.Data, doc: r.doc})
 		childrenNode.Roots = rootNode
 		childrenNode.Len = len(rootNode)
 
@@ -290,40 +428,41 @@ func (r Root) Children() Roots {
 	return childrenNode
 }
 
-// This is for Scraping HTML documents for a Visited Link
+// This is for Scraping HTML documents for a Visited Link. Relative links resolve against the base URL
+// of the document, see ResolveURL, and links to other schemes than http and https are refused.
+// The returned Root records the final URL of the response, see URL and ResolveURL
 func (r *Root) Visit(str string, client *Client) (*Root, error) {
-	var c *Client
-	g := glob.MustCompile("https://*, http://*, /*")
-	if !g.Match(str) {
+	c := client
+	target, err := r.ResolveURL(str)
+	if err != nil {
+		return nil, fmt.Errorf("string %s is not a link: %w", str, err)
+	}
+	if target.Scheme != "http" && target.Scheme != "https" {
 		return nil, fmt.Errorf("string %s is not a link", str)
 	}
-	if client == nil {
-		c = NewClient(nil)
+	if c == nil {
+		c = NewClient()
 	}
-	reader, err := c.Get(str)
-	return HTMLParse(reader), err
+	return c.GetDocument(target.String())
 }
 
-// This Download files, this is different from Visit
+// This Download files, this is different from Visit.
+// The MaxBodySize of the client applies, downloads are not limited without a client.
+//
+// Deprecated: Download holds the whole file in memory, use Client.DownloadTo or Client.DownloadFile
 func (r *Root) Download(url string, client *Client) ([]byte, error) {
-	var (
-		body []byte
-		err  error
-	)
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, err
+	c := client
+	if c == nil {
+		c = NewClient()
 	}
-	defer resp.Body.Close()
-
-	body, err = io.ReadAll(resp.Body)
+	resp, err := c.response(http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
 	}
-	if len(body) == 0 {
+	if len(resp.Body) == 0 {
 		return nil, errors.New("file is corrupted and sothing else happened")
 	}
-	return body, nil
+	return resp.Body, nil
 }
 
 func matchElementName(n *html.Node, name string) bool {
@@ -349,26 +488,56 @@ func attributeContainsValue(attr html.Attribute, attribute, value string) bool {
 	return false
 }
 
+// matchNode reports when n is an element with the tag name given in args[0],
+// and when attribute key and value are given, an attribute matching them
+func matchNode(n *html.Node, args []string, strict bool) bool {
+	if n.Type != html.ElementNode || !matchElementName(n, args[0]) {
+		return false
+	}
+	if len(args) == 1 {
+		return true
+	}
+	if len(args) > 3 {
+		return false
+	}
+	for i := 0; i < len(n.Attr); i++ {
+		attr := n.Attr[i]
+		searchAttrName := args[1]
+		searchAttrVal := args[2]
+		if (strict && attributeAndValueEquals(attr, searchAttrName, searchAttrVal)) ||
+			(!strict && attributeContainsValue(attr, searchAttrName, searchAttrVal)) {
+			return true
+		}
+	}
+	return false
+}
+
+// findOnce looks the first match up in the index when one was built,
+// falling back to a depth first search of the tree
+func (r *Root) findOnce(args []string, strict bool) (*html.Node, bool) {
+	if nodes, ok := r.docIndex().lookup(r.Node, args, strict, 1); ok {
+		if len(nodes) == 0 {
+			return nil, false
+		}
+		return nodes[0], true
+	}
+	return findOnce(r.Node, args, false, strict)
+}
+
+// findAll looks all matches up in the index when one was built,
+// falling back to a depth first search of the tree
+func (r *Root) findAll(args []string, strict bool) []*html.Node {
+	if nodes, ok := r.docIndex().lookup(r.Node, args, strict, -1); ok {
+		return nodes
+	}
+	return findAllofem(r.Node, args, strict)
+}
+
 // Using depth first search to find the first occurrence and return
 func findOnce(n *html.Node, args []string, uni bool, strict bool) (*html.Node, bool) {
-	if uni {
-		if n.Type == html.ElementNode && matchElementName(n, args[0]) {
-			if len(args) > 1 && len(args) < 4 {
-				for i := 0; i < len(n.Attr); i++ {
-					attr := n.Attr[i]
-					searchAttrName := args[1]
-					searchAttrVal := args[2]
-					if (strict && attributeAndValueEquals(attr, searchAttrName, searchAttrVal)) ||
-						(!strict && attributeContainsValue(attr, searchAttrName, searchAttrVal)) {
-						return n, true
-					}
-				}
-			} else if len(args) == 1 {
-				return n, true
-			}
-		}
+	if uni && matchNode(n, args, strict) {
+		return n, true
 	}
-	uni = true
 	for c := n.FirstChild; c != nil; c = c.NextSibling {
 		p, q := findOnce(c, args, true, strict)
 		if q {
@@ -381,34 +550,34 @@ func findOnce(n *html.Node, args []string, uni bool, strict bool) (*html.Node, b
 // Using depth first search to find all occurrences and return
 func findAllofem(n *html.Node, args []string, strict bool) []*html.Node {
 	var nodeLinks = make([]*html.Node, 0, 10)
-	var f func(*html.Node, []string, bool)
-	f = func(n *html.Node, args []string, uni bool) {
-		if uni {
-			if n.Type == html.ElementNode && matchElementName(n, args[0]) {
-				if len(args) > 1 && len(args) < 4 {
-					for i := 0; i < len(n.Attr); i++ {
-						attr := n.Attr[i]
-						searchAttrName := args[1]
-						searchAttrVal := args[2]
-						if (strict && attributeAndValueEquals(attr, searchAttrName, searchAttrVal)) ||
-							(!strict && attributeContainsValue(attr, searchAttrName, searchAttrVal)) {
-							nodeLinks = append(nodeLinks, n)
-						}
-					}
-				} else if len(args) == 1 {
-					nodeLinks = append(nodeLinks, n)
-				}
-			}
-		}
-		uni = true
-		for c := n.FirstChild; c != nil; c = c.NextSibling {
-			f(c, args, true)
-		}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		nodeLinks = appendMatches(nodeLinks, c, args, strict)
 	}
-	f(n, args, false)
 	return nodeLinks
 }
 
+// appendMatches appends n and its descendants matching args to nodes in document order
+func appendMatches(nodes []*html.Node, n *html.Node, args []string, strict bool) []*html.Node {
+	if matchNode(n, args, strict) {
+		nodes = append(nodes, n)
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		nodes = appendMatches(nodes, c, args, strict)
+	}
+	return nodes
+}
+
+// walk visits n and every node below it in document order,
+// the children of a node are skipped when f returns false
+func walk(n *html.Node, f func(*html.Node) bool) {
+	if n == nil || !f(n) {
+		return
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		walk(c, f)
+	}
+}
+
 // Returns a key pair value (like a dictionary) for each attribute
 func getKeyValue(attributes []html.Attribute) map[string]string {
 	length := len(attributes)
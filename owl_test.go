@@ -2,6 +2,9 @@ package owl
 
 import (
 	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"strconv"
 	"strings"
 	"testing"
@@ -171,6 +174,15 @@ func TestText(t *testing.T) {
 	require.Equal(t, "To a ", li.Text())
 }
 
+func TestTextSkipsElementsAndBlankNodes(t *testing.T) {
+	root := HTMLParseFromString(`<p id="a"><b>bold</b>after</p><p id="b">
+	<i>x</i>
+	last</p><p id="c"><span>only nested</span></p>`)
+	require.Equal(t, "after", root.Find("p", "id", "a").Text())
+	require.Equal(t, "\n\tlast", root.Find("p", "id", "b").Text())
+	require.Equal(t, "", root.Find("p", "id", "c").Text())
+}
+
 func TestFullText(t *testing.T) {
 	// <li>To a <a href="hello.jsp">JSP page</a> right?</li>
 	li := HtmlRoot.Find("ul").Find("li")
@@ -183,6 +195,57 @@ func TestFullTextEmpty(t *testing.T) {
 	require.Empty(t, h1.FullText())
 }
 
+func TestHTML(t *testing.T) {
+	doc := HTMLParseFromString(`<div id="post"><h1>Owls</h1> <p class="lead">Night &amp; day</p></div>`)
+	post := doc.Find("div")
+	require.Equal(t, `<div id="post"><h1>Owls</h1> <p class="lead">Night &amp; day</p></div>`, post.HTML())
+	require.Equal(t, `<h1>Owls</h1> <p class="lead">Night &amp; day</p>`, post.InnerHTML())
+	require.Equal(t, "Owls", post.Find("h1").InnerHTML())
+	require.Equal(t, "", doc.Find("p").Find("br").InnerHTML())
+	require.Equal(t, "", doc.Find("nav").HTML())
+}
+
+func TestAttr(t *testing.T) {
+	img := HtmlRoot.Find("img")
+	src, ok := img.Attr("src")
+	require.True(t, ok)
+	require.Equal(t, "images/springsource.png", src)
+
+	_, ok = img.Attr("alt")
+	require.False(t, ok)
+	require.Equal(t, "none", img.AttrOr("alt", "none"))
+	require.Equal(t, "images/springsource.png", img.AttrOr("src", "none"))
+
+	// Elements without attributes and text nodes have none to return
+	li := HtmlRoot.Find("li")
+	_, ok = li.Attr("id")
+	require.False(t, ok)
+	require.Nil(t, li.Attrs())
+	_, ok = li.Children().First().Attr("id")
+	require.False(t, ok)
+}
+
+func TestAttrsAll(t *testing.T) {
+	p := HTMLParseFromString(`<p data-x="1" class="a" data-x="2" id="p">`).Find("p")
+	require.Equal(t, map[string][]string{"data-x": {"1", "2"}, "class": {"a"}, "id": {"p"}}, p.AttrsAll())
+	require.Equal(t, []string{"data-x", "class", "id"}, p.AttrKeys())
+	require.Equal(t, "1", p.Attrs()["data-x"])
+	require.Nil(t, HtmlRoot.Find("li").AttrsAll())
+}
+
+func TestClasses(t *testing.T) {
+	div := HtmlRoot2.Find("div", "class", "third")
+	require.Equal(t, []string{"second", "first", "third"}, div.Classes())
+	require.True(t, div.HasClass("first"))
+	require.False(t, div.HasClass("fir"))
+	require.False(t, div.HasClass("second first"))
+
+	dup := HTMLParseFromString(`<span class=" a b  a "></span>`).Find("span")
+	require.Equal(t, []string{"a", "b"}, dup.Classes())
+	require.Nil(t, HtmlRoot.Find("li").Classes())
+	require.False(t, HtmlRoot.Find("li").HasClass("a"))
+}
+
 func TestNewErrorReturnsInspectableError(t *testing.T) {
 	err := newError(ErrElementNotFound, errors.New("element not found"))
 	require.NotNil(t, err)
@@ -196,3 +259,40 @@ func TestNewErrorReturnsInspectableError(t *testing.T) {
 // 	require.Equal(t, "element `bogus` with attributes `thing` not found", r.Error.Error())
 // 	require.Equal(t, ErrElementNotFound, r.Error.(Error).Type)
 // }
+
+func TestVisit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/blog/":
+			w.Write([]byte(`<a href="post.html">Post</a><a href="/about">About</a><a href="mailto:owl@example.com">Mail</a>`))
+		default:
+			fmt.Fprintf(w, "<title>%s</title>", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+	client := &Client{Client: srv.Client()}
+
+	doc, err := client.GetDocument(srv.URL + "/blog/")
+	require.NoError(t, err)
+	links := doc.FindAll("a")
+	post, err := doc.Visit("post.html", client)
+	require.NoError(t, err)
+	require.Equal(t, "/blog/post.html", post.Find("title").Text())
+	require.Equal(t, srv.URL+"/blog/post.html", post.URL().String())
+
+	about, err := links.Roots[1].Visit("/about", client)
+	require.NoError(t, err)
+	require.Equal(t, "/about", about.Find("title").Text())
+
+	_, err = doc.Visit("mailto:owl@example.com", client)
+	require.Error(t, err)
+	_, err = doc.Visit("javascript:void(0)", client)
+	require.Error(t, err)
+
+	// Relative links need the URL of the document
+	_, err = HTMLParse(strings.NewReader(`<a href="post.html">Post</a>`)).Visit("post.html", client)
+	require.ErrorIs(t, err, ErrNoBaseURL)
+	abs, err := HTMLParse(strings.NewReader("")).Visit(srv.URL+"/abs", client)
+	require.NoError(t, err)
+	require.Equal(t, "/abs", abs.Find("title").Text())
+}
@@ -0,0 +1,135 @@
+// Package owlbolt keeps the state of an owl.Crawler in a BoltDB file, so a crawl stopped or crashed
+// resumes from its queue without fetching the pages it already visited again:
+//
+//	store, err := owlbolt.Open("crawl.db")
+//	if err != nil {
+//		return err
+//	}
+//	defer store.Close()
+//	crawler.Store = store
+//
+// It is a module of its own so owl does not depend on bbolt
+package owlbolt
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"time"
+
+	"github.com/Patrickmitech/owl"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	visitedBucket = []byte("visited")
+	queueBucket   = []byte("queue")
+)
+
+// Store is an owl.Store in a BoltDB file, visiting pages in the order they were queued
+type Store struct {
+	db *bolt.DB
+}
+
+var _ owl.Store = (*Store)(nil)
+
+// Open opens the Store in the BoltDB file at path, created if needed
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{visitedBucket, queueBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+// DB returns the BoltDB database of s
+func (s *Store) DB() *bolt.DB {
+	return s.db
+}
+
+// Close closes the database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Visited implements owl.Store
+func (s *Store) Visited(url string) (bool, error) {
+	var visited bool
+	err := s.db.View(func(tx *bolt.Tx) error {
+		visited = tx.Bucket(visitedBucket).Get([]byte(url)) != nil
+		return nil
+	})
+	return visited, err
+}
+
+// MarkVisited implements owl.Store
+func (s *Store) MarkVisited(url string) (bool, error) {
+	var marked bool
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(visitedBucket)
+		if b.Get([]byte(url)) != nil {
+			return nil
+		}
+		marked = true
+		return b.Put([]byte(url), []byte{})
+	})
+	return marked, err
+}
+
+// Enqueue implements owl.Store
+func (s *Store) Enqueue(req owl.CrawlRequest) error {
+	value, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(queueBucket)
+		seq, err := b.NextSequence()
+		if err != nil {
+			return err
+		}
+		// Big endian keys keep the requests in the order they were queued
+		key := binary.BigEndian.AppendUint64(nil, seq)
+		return b.Put(key, value)
+	})
+}
+
+// Dequeue implements owl.Store
+func (s *Store) Dequeue() (owl.CrawlRequest, bool, error) {
+	var req owl.CrawlRequest
+	var ok bool
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(queueBucket).Cursor()
+		key, value := cursor.First()
+		if key == nil {
+			return nil
+		}
+		if err := json.Unmarshal(value, &req); err != nil {
+			return err
+		}
+		ok = true
+		return cursor.Delete()
+	})
+	return req, ok, err
+}
+
+// Len returns the number of queued requests
+func (s *Store) Len() (int, error) {
+	var n int
+	err := s.db.View(func(tx *bolt.Tx) error {
+		n = tx.Bucket(queueBucket).Stats().KeyN
+		return nil
+	})
+	return n, err
+}
@@ -0,0 +1,46 @@
+package owlbolt
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/Patrickmitech/owl"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "crawl.db")
+	store, err := Open(path)
+	require.NoError(t, err)
+
+	marked, err := store.MarkVisited("https://example.com/")
+	require.NoError(t, err)
+	require.True(t, marked)
+	marked, err = store.MarkVisited("https://example.com/")
+	require.NoError(t, err)
+	require.False(t, marked)
+	require.NoError(t, store.Enqueue(owl.CrawlRequest{URL: "https://example.com/a", Depth: 1}))
+	require.NoError(t, store.Enqueue(owl.CrawlRequest{URL: "https://example.com/b", Depth: 1, Referer: "https://example.com/"}))
+	require.NoError(t, store.Close())
+
+	// The state survives the Store
+	store, err = Open(path)
+	require.NoError(t, err)
+	defer store.Close()
+	visited, err := store.Visited("https://example.com/")
+	require.NoError(t, err)
+	require.True(t, visited)
+	n, err := store.Len()
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+
+	req, ok, err := store.Dequeue()
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, owl.CrawlRequest{URL: "https://example.com/a", Depth: 1}, req)
+	req, _, _ = store.Dequeue()
+	require.Equal(t, "https://example.com/", req.Referer)
+	_, ok, err = store.Dequeue()
+	require.NoError(t, err)
+	require.False(t, ok)
+}
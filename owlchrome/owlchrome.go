@@ -0,0 +1,100 @@
+// Package owlchrome renders pages with Chrome through the DevTools protocol, it is an owl.Renderer
+// for the pages whose content is built by JavaScript:
+//
+//	renderer, err := owlchrome.New(context.Background())
+//	if err != nil {
+//		return err
+//	}
+//	defer renderer.Close()
+//	client.Renderer = renderer
+//
+// It is a module of its own so owl does not depend on chromedp
+package owlchrome
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Patrickmitech/owl"
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+// Renderer renders every page in a new tab of a Chrome browser it starts, safe for concurrent use
+type Renderer struct {
+	browser context.Context
+	cancel  context.CancelFunc
+}
+
+var _ owl.Renderer = (*Renderer)(nil)
+
+// New starts a headless Chrome found on the system with chromedp.DefaultExecAllocatorOptions
+// followed by opts, such as chromedp.ExecPath. The browser stops when ctx is done or Close is called
+func New(ctx context.Context, opts ...chromedp.ExecAllocatorOption) (*Renderer, error) {
+	opts = append(chromedp.DefaultExecAllocatorOptions[:], opts...)
+	allocator, cancelAllocator := chromedp.NewExecAllocator(ctx, opts...)
+	browser, cancelBrowser := chromedp.NewContext(allocator)
+	r := &Renderer{browser: browser, cancel: func() {
+		cancelBrowser()
+		cancelAllocator()
+	}}
+	// Running nothing starts the browser, so a missing Chrome fails here
+	if err := chromedp.Run(browser); err != nil {
+		r.cancel()
+		return nil, err
+	}
+	return r, nil
+}
+
+// Render implements owl.Renderer, the page is loaded with the headers and cookies of opts
+// and its HTML is returned once the WaitSelector of opts matches an element, or once it is loaded
+func (r *Renderer) Render(ctx context.Context, url string, opts owl.RendererOptions) (string, error) {
+	tab, cancel := chromedp.NewContext(r.browser)
+	defer cancel()
+	if opts.Timeout > 0 {
+		tab, cancel = context.WithTimeout(tab, opts.Timeout)
+		defer cancel()
+	}
+	stop := context.AfterFunc(ctx, cancel)
+	defer stop()
+
+	var html string
+	actions := []chromedp.Action{network.Enable()}
+	headers := make(network.Headers)
+	for name, values := range opts.Header {
+		switch strings.ToLower(name) {
+		case "user-agent":
+			actions = append(actions, emulation.SetUserAgentOverride(values[0]))
+		case "accept-encoding":
+			// Chrome negotiates the encodings it decodes
+		default:
+			headers[name] = strings.Join(values, ", ")
+		}
+	}
+	if len(headers) > 0 {
+		actions = append(actions, network.SetExtraHTTPHeaders(headers))
+	}
+	for _, c := range opts.Cookies {
+		actions = append(actions, network.SetCookie(c.Name, c.Value).WithURL(url))
+	}
+	actions = append(actions, chromedp.Navigate(url))
+	if opts.WaitSelector != "" {
+		actions = append(actions, chromedp.WaitReady(opts.WaitSelector, chromedp.ByQuery))
+	}
+	actions = append(actions, chromedp.OuterHTML("html", &html, chromedp.ByQuery))
+	if err := chromedp.Run(tab, actions...); err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return "", err
+	}
+	return html, nil
+}
+
+// Close stops the browser
+func (r *Renderer) Close() error {
+	err := chromedp.Cancel(r.browser)
+	r.cancel()
+	return err
+}
@@ -0,0 +1,43 @@
+package owlchrome
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/Patrickmitech/owl"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderer(t *testing.T) {
+	renderer, err := New(context.Background())
+	if err != nil {
+		t.Skipf("Chrome is not available: %v", err)
+	}
+	defer renderer.Close()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		session := ""
+		if c, err := r.Cookie("session"); err == nil {
+			session = c.Value
+		}
+		fmt.Fprintf(w, `<div id="app"></div><script>
+			setTimeout(function() {
+				document.getElementById("app").innerHTML = '<p class="ready">%s %s</p>';
+			}, 50);
+		</script>`, r.UserAgent(), session)
+	}))
+	defer srv.Close()
+
+	jar := owl.NewJar()
+	u, _ := url.Parse(srv.URL)
+	jar.SetCookies(u, []*http.Cookie{{Name: "session", Value: "hoot"}})
+	client := owl.NewClient(owl.WithHTTPClient(&http.Client{Jar: jar}), owl.WithUserAgent("owl-test"))
+	client.Renderer = renderer
+	doc, err := client.GetDocument(srv.URL, owl.WithWaitSelector("p.ready"))
+	require.NoError(t, err)
+	require.Equal(t, "owl-test hoot", doc.Find("p").Text())
+}
@@ -0,0 +1,341 @@
+// Package owlmock provides mock implementations of the owl interfaces,
+// so code depending on owl can be unit tested without network access or real documents.
+// Every mock records its calls and answers with the function set for the method,
+// or with a zero value when none is set
+package owlmock
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Patrickmitech/owl"
+)
+
+// ErrNotMocked is returned by mocks whose method has no function set
+var ErrNotMocked = errors.New("owlmock: method not mocked")
+
+// Call is a recorded call of a mock method
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// recorder keeps the calls made to a mock, safe for concurrent use
+type recorder struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+func (r *recorder) record(method string, args ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, Call{Method: method, Args: args})
+}
+
+// Calls returns the calls made so far in order
+func (r *recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call(nil), r.calls...)
+}
+
+// CallCount returns how many times method was called
+func (r *recorder) CallCount(method string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	count := 0
+	for _, c := range r.calls {
+		if c.Method == method {
+			count++
+		}
+	}
+	return count
+}
+
+// Finder is a mock owl.Finder
+type Finder struct {
+	recorder
+	FindFunc          func(args ...string) *owl.Root
+	FindStrictFunc    func(args ...string) *owl.Root
+	FindAllFunc       func(args ...string) owl.Roots
+	FindAllStrictFunc func(args ...string) owl.Roots
+}
+
+var _ owl.Finder = (*Finder)(nil)
+
+func (m *Finder) Find(args ...string) *owl.Root {
+	m.record("Find", strs(args)...)
+	if m.FindFunc == nil {
+		return &owl.Root{}
+	}
+	return m.FindFunc(args...)
+}
+
+func (m *Finder) FindStrict(args ...string) *owl.Root {
+	m.record("FindStrict", strs(args)...)
+	if m.FindStrictFunc == nil {
+		return &owl.Root{}
+	}
+	return m.FindStrictFunc(args...)
+}
+
+func (m *Finder) FindAll(args ...string) owl.Roots {
+	m.record("FindAll", strs(args)...)
+	if m.FindAllFunc == nil {
+		return owl.Roots{}
+	}
+	return m.FindAllFunc(args...)
+}
+
+func (m *Finder) FindAllStrict(args ...string) owl.Roots {
+	m.record("FindAllStrict", strs(args)...)
+	if m.FindAllStrictFunc == nil {
+		return owl.Roots{}
+	}
+	return m.FindAllStrictFunc(args...)
+}
+
+// Fetcher is a mock owl.Fetcher
+type Fetcher struct {
+	recorder
+	GetFunc  func(url string, opts ...owl.RequestOption) (io.Reader, error)
+	PostFunc func(url string, contentType string, body interface{}, opts ...owl.RequestOption) (io.Reader, error)
+}
+
+var _ owl.Fetcher = (*Fetcher)(nil)
+
+// NewPages returns a Fetcher answering Get requests with the documents of pages by URL,
+// unknown URLs fail with ErrNotMocked
+func NewPages(pages map[string]string) *Fetcher {
+	return &Fetcher{
+		GetFunc: func(url string, opts ...owl.RequestOption) (io.Reader, error) {
+			page, ok := pages[url]
+			if !ok {
+				return nil, ErrNotMocked
+			}
+			return strings.NewReader(page), nil
+		},
+	}
+}
+
+func (m *Fetcher) Get(url string, opts ...owl.RequestOption) (io.Reader, error) {
+	m.record("Get", url)
+	if m.GetFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetFunc(url, opts...)
+}
+
+func (m *Fetcher) Post(url string, contentType string, body interface{}, opts ...owl.RequestOption) (io.Reader, error) {
+	m.record("Post", url, contentType, body)
+	if m.PostFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.PostFunc(url, contentType, body, opts...)
+}
+
+// RobotsPolicy is a mock owl.RobotsPolicy
+type RobotsPolicy struct {
+	recorder
+	AllowedFunc func(userAgent string, u *url.URL) bool
+}
+
+var _ owl.RobotsPolicy = (*RobotsPolicy)(nil)
+
+func (m *RobotsPolicy) Allowed(userAgent string, u *url.URL) bool {
+	m.record("Allowed", userAgent, u)
+	if m.AllowedFunc == nil {
+		return false
+	}
+	return m.AllowedFunc(userAgent, u)
+}
+
+// RateLimiter is a mock owl.RateLimiter, requests are not delayed when WaitFunc is not set
+type RateLimiter struct {
+	recorder
+	WaitFunc func(ctx context.Context, host string) error
+}
+
+var _ owl.RateLimiter = (*RateLimiter)(nil)
+
+func (m *RateLimiter) Wait(ctx context.Context, host string) error {
+	m.record("Wait", host)
+	if m.WaitFunc == nil {
+		return nil
+	}
+	return m.WaitFunc(ctx, host)
+}
+
+// Cache is a mock owl.Cache, it misses every lookup when GetFunc is not set
+type Cache struct {
+	recorder
+	GetFunc func(url string) (*owl.CacheEntry, bool)
+	SetFunc func(url string, entry *owl.CacheEntry)
+}
+
+var _ owl.Cache = (*Cache)(nil)
+
+func (m *Cache) Get(url string) (*owl.CacheEntry, bool) {
+	m.record("Get", url)
+	if m.GetFunc == nil {
+		return nil, false
+	}
+	return m.GetFunc(url)
+}
+
+func (m *Cache) Set(url string, entry *owl.CacheEntry) {
+	m.record("Set", url, entry)
+	if m.SetFunc != nil {
+		m.SetFunc(url, entry)
+	}
+}
+
+// Metrics is a mock owl.Metrics, it only records its calls when no function is set
+type Metrics struct {
+	recorder
+	RequestFunc     func(method, host string, status int, duration time.Duration)
+	RetryFunc       func(method, host string)
+	DownloadedFunc  func(host string, bytes int64)
+	CacheLookupFunc func(host string, hit bool)
+}
+
+var _ owl.Metrics = (*Metrics)(nil)
+
+func (m *Metrics) Request(method, host string, status int, duration time.Duration) {
+	m.record("Request", method, host, status, duration)
+	if m.RequestFunc != nil {
+		m.RequestFunc(method, host, status, duration)
+	}
+}
+
+func (m *Metrics) Retry(method, host string) {
+	m.record("Retry", method, host)
+	if m.RetryFunc != nil {
+		m.RetryFunc(method, host)
+	}
+}
+
+func (m *Metrics) Downloaded(host string, bytes int64) {
+	m.record("Downloaded", host, bytes)
+	if m.DownloadedFunc != nil {
+		m.DownloadedFunc(host, bytes)
+	}
+}
+
+func (m *Metrics) CacheLookup(host string, hit bool) {
+	m.record("CacheLookup", host, hit)
+	if m.CacheLookupFunc != nil {
+		m.CacheLookupFunc(host, hit)
+	}
+}
+
+// Renderer is a mock owl.Renderer
+type Renderer struct {
+	recorder
+	RenderFunc func(ctx context.Context, url string, opts owl.RendererOptions) (string, error)
+}
+
+var _ owl.Renderer = (*Renderer)(nil)
+
+// NewRenderer returns a Renderer answering with the rendered documents of pages by URL,
+// unknown URLs fail with ErrNotMocked
+func NewRenderer(pages map[string]string) *Renderer {
+	return &Renderer{
+		RenderFunc: func(ctx context.Context, url string, opts owl.RendererOptions) (string, error) {
+			page, ok := pages[url]
+			if !ok {
+				return "", ErrNotMocked
+			}
+			return page, nil
+		},
+	}
+}
+
+func (m *Renderer) Render(ctx context.Context, url string, opts owl.RendererOptions) (string, error) {
+	m.record("Render", url, opts)
+	if m.RenderFunc == nil {
+		return "", ErrNotMocked
+	}
+	return m.RenderFunc(ctx, url, opts)
+}
+
+// Store is a mock owl.Store
+type Store struct {
+	recorder
+	VisitedFunc     func(url string) (bool, error)
+	MarkVisitedFunc func(url string) (bool, error)
+	EnqueueFunc     func(req owl.CrawlRequest) error
+	DequeueFunc     func() (owl.CrawlRequest, bool, error)
+}
+
+var _ owl.Store = (*Store)(nil)
+
+func (m *Store) Visited(url string) (bool, error) {
+	m.record("Visited", url)
+	if m.VisitedFunc == nil {
+		return false, ErrNotMocked
+	}
+	return m.VisitedFunc(url)
+}
+
+func (m *Store) MarkVisited(url string) (bool, error) {
+	m.record("MarkVisited", url)
+	if m.MarkVisitedFunc == nil {
+		return false, ErrNotMocked
+	}
+	return m.MarkVisitedFunc(url)
+}
+
+func (m *Store) Enqueue(req owl.CrawlRequest) error {
+	m.record("Enqueue", req)
+	if m.EnqueueFunc == nil {
+		return ErrNotMocked
+	}
+	return m.EnqueueFunc(req)
+}
+
+func (m *Store) Dequeue() (owl.CrawlRequest, bool, error) {
+	m.record("Dequeue")
+	if m.DequeueFunc == nil {
+		return owl.CrawlRequest{}, false, ErrNotMocked
+	}
+	return m.DequeueFunc()
+}
+
+// Sink is a mock owl.Sink
+type Sink struct {
+	recorder
+	WriteFunc func(record interface{}) error
+	CloseFunc func() error
+}
+
+var _ owl.Sink = (*Sink)(nil)
+
+func (m *Sink) Write(record interface{}) error {
+	m.record("Write", record)
+	if m.WriteFunc == nil {
+		return ErrNotMocked
+	}
+	return m.WriteFunc(record)
+}
+
+func (m *Sink) Close() error {
+	m.record("Close")
+	if m.CloseFunc == nil {
+		return ErrNotMocked
+	}
+	return m.CloseFunc()
+}
+
+func strs(args []string) []interface{} {
+	out := make([]interface{}, len(args))
+	for i, a := range args {
+		out[i] = a
+	}
+	return out
+}
@@ -0,0 +1,124 @@
+package owlmock
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Patrickmitech/owl"
+	"github.com/stretchr/testify/require"
+)
+
+// titleOf is code under test depending on owl through its interfaces
+func titleOf(f owl.Fetcher, url string) (string, error) {
+	r, err := f.Get(url)
+	if err != nil {
+		return "", err
+	}
+	return owl.HTMLParse(r).Find("title").Text(), nil
+}
+
+func TestFetcher(t *testing.T) {
+	f := NewPages(map[string]string{
+		"https://example.com/": "<html><head><title>Example</title></head></html>",
+	})
+	title, err := titleOf(f, "https://example.com/")
+	require.NoError(t, err)
+	require.Equal(t, "Example", title)
+
+	_, err = titleOf(f, "https://example.com/missing")
+	require.ErrorIs(t, err, ErrNotMocked)
+	require.Equal(t, 2, f.CallCount("Get"))
+	require.Equal(t, "https://example.com/missing", f.Calls()[1].Args[0])
+}
+
+func TestFinder(t *testing.T) {
+	doc := owl.HTMLParseFromString("<p>mocked</p>")
+	f := &Finder{FindFunc: func(args ...string) *owl.Root { return doc.Find(args...) }}
+	var finder owl.Finder = f
+	require.Equal(t, "mocked", finder.Find("p").Text())
+	require.Equal(t, []Call{{Method: "Find", Args: []interface{}{"p"}}}, f.Calls())
+}
+
+func TestRobotsPolicy(t *testing.T) {
+	client := owl.HttpClientWrapper(nil)
+	client.Robots = &RobotsPolicy{}
+	_, err := client.GetDocument("https://example.com/")
+	require.ErrorIs(t, err, owl.ErrDisallowedByRobots)
+	require.Equal(t, 1, client.Robots.(*RobotsPolicy).CallCount("Allowed"))
+}
+
+func TestRateLimiter(t *testing.T) {
+	client := owl.HttpClientWrapper(nil)
+	limiter := &RateLimiter{WaitFunc: func(ctx context.Context, host string) error { return errors.New("slow down") }}
+	client.Limiter = limiter
+	_, err := client.GetDocument("https://example.com/")
+	require.EqualError(t, err, "slow down")
+	require.Equal(t, []Call{{Method: "Wait", Args: []interface{}{"example.com"}}}, limiter.Calls())
+}
+
+func TestCache(t *testing.T) {
+	cache := &Cache{GetFunc: func(url string) (*owl.CacheEntry, bool) {
+		return &owl.CacheEntry{
+			URL:        url,
+			StatusCode: 200,
+			Header:     http.Header{"Cache-Control": {"max-age=60"}},
+			Body:       []byte("<title>cached</title>"),
+			Stored:     time.Now(),
+		}, true
+	}}
+	client := owl.HttpClientWrapper(nil)
+	client.Cache = cache
+	doc, err := client.GetDocument("https://example.com/")
+	require.NoError(t, err)
+	require.Equal(t, "cached", doc.Title().Text())
+	require.Equal(t, 1, cache.CallCount("Get"))
+}
+
+func TestMetrics(t *testing.T) {
+	metrics := &Metrics{}
+	client := owl.HttpClientWrapper(nil)
+	client.Metrics = metrics
+	client.Cache = &Cache{GetFunc: func(url string) (*owl.CacheEntry, bool) {
+		return &owl.CacheEntry{
+			URL:        url,
+			StatusCode: 200,
+			Header:     http.Header{"Cache-Control": {"max-age=60"}},
+			Body:       []byte("<title>cached</title>"),
+			Stored:     time.Now(),
+		}, true
+	}}
+	_, err := client.GetDocument("https://example.com/")
+	require.NoError(t, err)
+	require.Equal(t, []Call{{Method: "CacheLookup", Args: []interface{}{"example.com", true}}}, metrics.Calls())
+}
+
+func TestRenderer(t *testing.T) {
+	renderer := NewRenderer(map[string]string{"https://example.com/": `<div id="app">rendered</div>`})
+	client := owl.HttpClientWrapper(nil)
+	client.Renderer = renderer
+	doc, err := client.GetDocument("https://example.com/", owl.WithWaitSelector("#app"))
+	require.NoError(t, err)
+	require.Equal(t, "rendered", doc.Find("div").Text())
+	require.Equal(t, 1, renderer.CallCount("Render"))
+	require.Equal(t, "#app", renderer.Calls()[0].Args[1].(owl.RendererOptions).WaitSelector)
+
+	_, err = client.GetDocument("https://example.com/missing")
+	require.ErrorIs(t, err, ErrNotMocked)
+}
+
+func TestStore(t *testing.T) {
+	store := &Store{
+		MarkVisitedFunc: func(url string) (bool, error) { return true, nil },
+		EnqueueFunc:     func(req owl.CrawlRequest) error { return errors.New("store down") },
+	}
+	crawler := owl.NewCrawler(owl.HttpClientWrapper(nil))
+	crawler.Store = store
+	require.EqualError(t, crawler.Visit("https://example.com/"), "store down")
+	require.Equal(t, []Call{
+		{Method: "MarkVisited", Args: []interface{}{"https://example.com/"}},
+		{Method: "Enqueue", Args: []interface{}{owl.CrawlRequest{URL: "https://example.com/"}}},
+	}, store.Calls())
+}
@@ -0,0 +1,86 @@
+// Package owlredis keeps the state of an owl.Crawler in Redis, so a crawl resumes after a crash
+// and several crawlers, on one machine or more, share its queue:
+//
+//	store := owlredis.New(redis.NewClient(&redis.Options{Addr: "localhost:6379"}), "crawl:")
+//	crawler.Store = store
+//
+// It is a module of its own so owl does not depend on go-redis
+package owlredis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/Patrickmitech/owl"
+	"github.com/redis/go-redis/v9"
+)
+
+// Store is an owl.Store in Redis: a set of the visited URLs and a list of the queued requests
+// as JSON, visiting pages in the order they were queued
+type Store struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+var _ owl.Store = (*Store)(nil)
+
+// New returns a Store using client, keeping its keys under prefix so crawls can share a database
+func New(client redis.UniversalClient, prefix string) *Store {
+	return &Store{client: client, prefix: prefix}
+}
+
+func (s *Store) visitedKey() string {
+	return s.prefix + "visited"
+}
+
+func (s *Store) queueKey() string {
+	return s.prefix + "queue"
+}
+
+// Visited implements owl.Store
+func (s *Store) Visited(url string) (bool, error) {
+	return s.client.SIsMember(context.Background(), s.visitedKey(), url).Result()
+}
+
+// MarkVisited implements owl.Store
+func (s *Store) MarkVisited(url string) (bool, error) {
+	added, err := s.client.SAdd(context.Background(), s.visitedKey(), url).Result()
+	return added == 1, err
+}
+
+// Enqueue implements owl.Store
+func (s *Store) Enqueue(req owl.CrawlRequest) error {
+	value, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	return s.client.RPush(context.Background(), s.queueKey(), value).Err()
+}
+
+// Dequeue implements owl.Store
+func (s *Store) Dequeue() (owl.CrawlRequest, bool, error) {
+	var req owl.CrawlRequest
+	value, err := s.client.LPop(context.Background(), s.queueKey()).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return req, false, nil
+	}
+	if err != nil {
+		return req, false, err
+	}
+	if err := json.Unmarshal(value, &req); err != nil {
+		return req, false, err
+	}
+	return req, true, nil
+}
+
+// Len returns the number of queued requests
+func (s *Store) Len() (int, error) {
+	n, err := s.client.LLen(context.Background(), s.queueKey()).Result()
+	return int(n), err
+}
+
+// Clear deletes the visited URLs and the queue, to start the crawl over
+func (s *Store) Clear() error {
+	return s.client.Del(context.Background(), s.visitedKey(), s.queueKey()).Err()
+}
@@ -0,0 +1,50 @@
+package owlredis
+
+import (
+	"testing"
+
+	"github.com/Patrickmitech/owl"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+	store := New(client, "crawl:")
+
+	marked, err := store.MarkVisited("https://example.com/")
+	require.NoError(t, err)
+	require.True(t, marked)
+	marked, err = store.MarkVisited("https://example.com/")
+	require.NoError(t, err)
+	require.False(t, marked)
+	visited, err := store.Visited("https://example.com/")
+	require.NoError(t, err)
+	require.True(t, visited)
+	require.True(t, server.Exists("crawl:visited"))
+
+	require.NoError(t, store.Enqueue(owl.CrawlRequest{URL: "https://example.com/a", Depth: 1}))
+	require.NoError(t, store.Enqueue(owl.CrawlRequest{URL: "https://example.com/b", Depth: 1}))
+	n, err := store.Len()
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+
+	// Another crawler sharing the keys gets the next request
+	req, ok, err := New(client, "crawl:").Dequeue()
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, owl.CrawlRequest{URL: "https://example.com/a", Depth: 1}, req)
+	req, _, _ = store.Dequeue()
+	require.Equal(t, "https://example.com/b", req.URL)
+	_, ok, err = store.Dequeue()
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	require.NoError(t, store.Clear())
+	visited, err = store.Visited("https://example.com/")
+	require.NoError(t, err)
+	require.False(t, visited)
+}
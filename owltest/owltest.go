@@ -0,0 +1,234 @@
+// Package owltest helps unit testing code built on owl. A Server serves HTML fixtures from a directory,
+// a Stub answers the requests of an owl.Client from pages set by URL without any network access,
+// and both record the requests they receive so tests can assert on them
+package owltest
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/Patrickmitech/owl"
+)
+
+// Request is a request received by a Server or a Stub
+type Request struct {
+	Method string
+	// URL is the absolute URL of the request
+	URL    string
+	Header http.Header
+	Body   []byte
+}
+
+// recorder keeps the requests received, safe for concurrent use
+type recorder struct {
+	mu       sync.Mutex
+	requests []Request
+}
+
+func (r *recorder) record(req *http.Request, absoluteURL string) {
+	var body []byte
+	if req.Body != nil {
+		body, _ = io.ReadAll(req.Body)
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, Request{Method: req.Method, URL: absoluteURL, Header: req.Header.Clone(), Body: body})
+}
+
+// Requests returns the requests received so far in order
+func (r *recorder) Requests() []Request {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Request(nil), r.requests...)
+}
+
+// Requested reports whether url was requested
+func (r *recorder) Requested(url string) bool {
+	return len(r.requestsTo(url)) > 0
+}
+
+// Reset forgets the requests received so far
+func (r *recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = nil
+}
+
+func (r *recorder) requestsTo(url string) []Request {
+	var matched []Request
+	for _, req := range r.Requests() {
+		if req.URL == url {
+			matched = append(matched, req)
+		}
+	}
+	return matched
+}
+
+// AssertRequested fails t unless every one of urls was requested
+func (r *recorder) AssertRequested(t testing.TB, urls ...string) {
+	t.Helper()
+	for _, url := range urls {
+		if !r.Requested(url) {
+			t.Errorf("owltest: %s was not requested, requests: %v", url, r.urls())
+		}
+	}
+}
+
+// AssertNotRequested fails t when any of urls was requested
+func (r *recorder) AssertNotRequested(t testing.TB, urls ...string) {
+	t.Helper()
+	for _, url := range urls {
+		if r.Requested(url) {
+			t.Errorf("owltest: %s was requested", url)
+		}
+	}
+}
+
+// AssertHeader fails t unless a request to url had the header key set to value
+func (r *recorder) AssertHeader(t testing.TB, url, key, value string) {
+	t.Helper()
+	requests := r.requestsTo(url)
+	if len(requests) == 0 {
+		t.Errorf("owltest: %s was not requested", url)
+		return
+	}
+	var got []string
+	for _, req := range requests {
+		if req.Header.Get(key) == value {
+			return
+		}
+		got = append(got, req.Header.Get(key))
+	}
+	t.Errorf("owltest: %s was requested with %s %q, want %q", url, key, got, value)
+}
+
+func (r *recorder) urls() []string {
+	var urls []string
+	for _, req := range r.Requests() {
+		urls = append(urls, req.URL)
+	}
+	return urls
+}
+
+// Server is a test HTTP server serving fixture files and the pages set with Page
+type Server struct {
+	*httptest.Server
+	recorder
+
+	mu    sync.Mutex
+	pages map[string]string
+	files http.Handler
+}
+
+// NewServer starts a Server serving the files of dir, where directories are served by their index.html.
+// dir may be empty to serve only the pages set with Page. The server is closed when the test ends
+func NewServer(t testing.TB, dir string) *Server {
+	s := &Server{pages: make(map[string]string), files: http.NotFoundHandler()}
+	if dir != "" {
+		s.files = http.FileServer(http.Dir(dir))
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Page serves html as the page at path, before the fixture files
+func (s *Server) Page(path, html string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pages[path] = html
+}
+
+// Client returns an owl.Client sending its requests to the server
+func (s *Server) Client() *owl.Client {
+	return &owl.Client{Client: s.Server.Client()}
+}
+
+func (s *Server) serve(w http.ResponseWriter, req *http.Request) {
+	s.record(req, s.URL+req.URL.RequestURI())
+	s.mu.Lock()
+	html, ok := s.pages[req.URL.Path]
+	s.mu.Unlock()
+	if !ok {
+		s.files.ServeHTTP(w, req)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	io.WriteString(w, html)
+}
+
+// Stub answers the requests of an owl.Client from the responses set by URL, without any network access.
+// Requests to other URLs get a 404 Not Found
+type Stub struct {
+	recorder
+
+	mu        sync.Mutex
+	responses map[string]stubResponse
+}
+
+type stubResponse struct {
+	status int
+	header http.Header
+	body   string
+}
+
+var _ http.RoundTripper = (*Stub)(nil)
+
+// NewStub makes c send its requests to a new Stub answering with the HTML pages by URL
+func NewStub(c *owl.Client, pages map[string]string) *Stub {
+	s := &Stub{responses: make(map[string]stubResponse)}
+	for url, html := range pages {
+		s.Page(url, html)
+	}
+	if c.Client == nil {
+		c.Client = &http.Client{}
+	}
+	c.Client.Transport = s
+	return s
+}
+
+// Page answers the requests to url with html
+func (s *Stub) Page(url, html string) {
+	s.Respond(url, http.StatusOK, http.Header{"Content-Type": {"text/html; charset=utf-8"}}, html)
+}
+
+// Respond answers the requests to url with a response of status, header and body
+func (s *Stub) Respond(url string, status int, header http.Header, body string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.responses[url] = stubResponse{status: status, header: header.Clone(), body: body}
+}
+
+// RoundTrip implements http.RoundTripper
+func (s *Stub) RoundTrip(req *http.Request) (*http.Response, error) {
+	url := req.URL.String()
+	s.record(req, url)
+	s.mu.Lock()
+	r, ok := s.responses[url]
+	s.mu.Unlock()
+	if !ok {
+		r = stubResponse{status: http.StatusNotFound, body: "404 page not found"}
+	}
+	header := r.header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        strconv.Itoa(r.status) + " " + http.StatusText(r.status),
+		StatusCode:    r.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader([]byte(r.body))),
+		ContentLength: int64(len(r.body)),
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,101 @@
+package owltest
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Patrickmitech/owl"
+	"github.com/stretchr/testify/require"
+)
+
+// spy is a testing.TB recording failures instead of failing the test
+type spy struct {
+	testing.TB
+	failures []string
+}
+
+func (s *spy) Helper() {}
+
+func (s *spy) Errorf(format string, args ...interface{}) {
+	s.failures = append(s.failures, fmt.Sprintf(format, args...))
+}
+
+// titles is code under test following the links of a page
+func titles(c *owl.Client, start string) ([]string, error) {
+	doc, err := c.GetDocument(start)
+	if err != nil {
+		return nil, err
+	}
+	titles := []string{doc.Find("title").Text()}
+	for _, link := range doc.Links(nil) {
+		page, err := c.GetDocument(link.URL.String())
+		if err != nil {
+			return nil, err
+		}
+		titles = append(titles, page.Find("title").Text())
+	}
+	return titles, nil
+}
+
+func TestServer(t *testing.T) {
+	srv := NewServer(t, "testdata")
+	srv.Page("/about", "<html><title>About</title></html>")
+	client := srv.Client()
+	client.Header = map[string]string{"User-Agent": "owlbot"}
+
+	got, err := titles(client, srv.URL+"/")
+	require.NoError(t, err)
+	require.Equal(t, []string{"Fixture home", "Fixture blog"}, got)
+	doc, err := client.GetDocument(srv.URL + "/about")
+	require.NoError(t, err)
+	require.Equal(t, "About", doc.Find("title").Text())
+
+	srv.AssertRequested(t, srv.URL+"/", srv.URL+"/blog/", srv.URL+"/about")
+	srv.AssertNotRequested(t, srv.URL+"/missing")
+	srv.AssertHeader(t, srv.URL+"/blog/", "User-Agent", "owlbot")
+	require.Len(t, srv.Requests(), 3)
+
+	s := &spy{TB: t}
+	srv.AssertRequested(s, srv.URL+"/missing")
+	srv.AssertNotRequested(s, srv.URL+"/")
+	srv.AssertHeader(s, srv.URL+"/", "User-Agent", "other")
+	srv.AssertHeader(s, srv.URL+"/missing", "User-Agent", "owlbot")
+	require.Len(t, s.failures, 4)
+
+	srv.Reset()
+	require.Empty(t, srv.Requests())
+}
+
+func TestStub(t *testing.T) {
+	client := &owl.Client{}
+	stub := NewStub(client, map[string]string{
+		"https://example.com/":      `<html><title>Home</title><a href="/news">News</a></html>`,
+		"https://example.com/news":  "<html><title>News</title></html>",
+		"https://example.com/other": "<html><title>Other</title></html>",
+	})
+	got, err := titles(client, "https://example.com/")
+	require.NoError(t, err)
+	require.Equal(t, []string{"Home", "News"}, got)
+	stub.AssertRequested(t, "https://example.com/", "https://example.com/news")
+	stub.AssertNotRequested(t, "https://example.com/other")
+
+	stub.Respond("https://example.com/api", http.StatusTeapot, http.Header{"X-Owl": {"hoot"}}, "short and stout")
+	resp, err := client.GetResponse("https://example.com/api")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusTeapot, resp.StatusCode)
+	require.Equal(t, "hoot", resp.Header.Get("X-Owl"))
+	require.Equal(t, "short and stout", string(resp.Body))
+
+	resp, err = client.GetResponse("https://example.com/unknown")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	_, err = client.Post("https://example.com/form", "application/x-www-form-urlencoded", "q=owl")
+	require.NoError(t, err)
+	requests := stub.Requests()
+	last := requests[len(requests)-1]
+	require.Equal(t, http.MethodPost, last.Method)
+	require.Equal(t, "q=owl", string(last.Body))
+	stub.AssertHeader(t, "https://example.com/form", "Content-Type", "application/x-www-form-urlencoded")
+}
@@ -0,0 +1,236 @@
+package owl
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+var (
+	// ErrNotClickable is returned by Page.Click when the element is neither in a link nor a submit button
+	ErrNotClickable = errors.New("owl: element not clickable")
+	// ErrNoLink is returned by Page.FollowLink when the page has no link with the text
+	ErrNoLink = errors.New("owl: no such link")
+	// ErrNoForm is returned by Page.SubmitForm when the page has no form for the selector
+	ErrNoForm = errors.New("owl: no such form")
+	// ErrNoHistory is returned by Page.Back on the first page of a session
+	ErrNoHistory = errors.New("owl: no previous page")
+)
+
+// Page browses documents with a Client like a browser tab: links are followed and forms submitted
+// from the current document, with the cookies of the session and the Referer of the current page
+type Page struct {
+	client   *Client
+	response *Response
+	document *Root
+	// history are the previous responses, the last one is the page before the current one
+	history []*Response
+}
+
+// Open fetches the document at url and returns the Page showing it. The cookies of the session are kept
+// in the cookie jar of the http.Client of c, which gets a Jar when it has none.
+// With status errors on, the Page is returned showing the error page along with the *HTTPError
+func (c *Client) Open(url string) (*Page, error) {
+	c.ensureJar()
+	p := &Page{client: c}
+	err := p.Open(url)
+	if p.response == nil {
+		return nil, err
+	}
+	return p, err
+}
+
+// Response returns the response of the current page
+func (p *Page) Response() *Response {
+	return p.response
+}
+
+// Document returns the parsed document of the current page
+func (p *Page) Document() *Root {
+	return p.document
+}
+
+// URL returns the URL of the current page after redirects, nil before the first page
+func (p *Page) URL() *url.URL {
+	if p.response == nil {
+		return nil
+	}
+	return p.response.FinalURL
+}
+
+// Open fetches the document at url, resolved against the base URL of the current page, and shows it
+func (p *Page) Open(url string) error {
+	target, err := p.resolve(url)
+	if err != nil {
+		return err
+	}
+	return p.navigate(p.client.response(http.MethodGet, target.String(), nil, p.referer(target)...))
+}
+
+// Click clicks the first element matching the CSS selector: links are followed and submit buttons
+// submit their form. Elements inside a link, such as images, follow the link
+func (p *Page) Click(selector string) error {
+	match := p.document.SelectOne(selector)
+	if match.Error != nil {
+		return match.Error.Err()
+	}
+	for n := match.Node; n != nil; n = n.Parent {
+		if n.Type != html.ElementNode {
+			continue
+		}
+		if href, ok := attrLookup(n, "href"); ok && (n.Data == "a" || n.Data == "area") {
+			return p.Open(href)
+		}
+		if form, button := p.submitter(n); form != nil {
+			return p.submit(form, button)
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrNotClickable, selector)
+}
+
+// FollowLink follows the first link whose text is text, ignoring case and extra whitespace,
+// or else the first link whose text contains it
+func (p *Page) FollowLink(text string) error {
+	text = strings.Join(strings.Fields(text), " ")
+	links := p.document.Links(nil)
+	for _, link := range links {
+		if strings.EqualFold(link.Text, text) {
+			return p.Open(link.Href)
+		}
+	}
+	lower := strings.ToLower(text)
+	for _, link := range links {
+		if strings.Contains(strings.ToLower(link.Text), lower) {
+			return p.Open(link.Href)
+		}
+	}
+	return fmt.Errorf("%w: %q", ErrNoLink, text)
+}
+
+// SubmitForm fills the form of the element matching the CSS selector with values, keyed by field name,
+// and submits it as if its first submit button was clicked, see Form.Set. An empty selector picks
+// the first form of the page
+func (p *Page) SubmitForm(selector string, values map[string]string) error {
+	var form *Form
+	if selector == "" {
+		if forms := p.document.Forms(); len(forms) > 0 {
+			form = forms[0]
+		}
+	} else {
+		form = formAt(p.document, selector)
+	}
+	if form == nil {
+		return fmt.Errorf("%w: %s", ErrNoForm, selector)
+	}
+	for name, value := range values {
+		if err := form.Set(name, value); err != nil {
+			return fmt.Errorf("owl: form field %q: %w", name, err)
+		}
+	}
+	var button *FormField
+	for _, field := range form.Fields {
+		if field.isSubmit() && !field.Disabled {
+			button = field
+			break
+		}
+	}
+	return p.submit(form, button)
+}
+
+// Back shows the previous page again without fetching it
+func (p *Page) Back() error {
+	if len(p.history) == 0 {
+		return ErrNoHistory
+	}
+	last := len(p.history) - 1
+	p.response, p.history = p.history[last], p.history[:last]
+	p.document = p.response.Parse()
+	return nil
+}
+
+// submitter returns the form and the FormField of n when it is an enabled submit button
+func (p *Page) submitter(n *html.Node) (*Form, *FormField) {
+	if n.Data != "button" && n.Data != "input" {
+		return nil, nil
+	}
+	top := n
+	for top.Parent != nil {
+		top = top.Parent
+	}
+	// The form of a field is the one named by its form attribute, or else the form it is in
+	var owner *html.Node
+	if id, ok := attrLookup(n, "form"); ok {
+		walk(top, func(c *html.Node) bool {
+			if owner == nil && c.Type == html.ElementNode && c.Data == "form" && attrValue(c, "id") == id {
+				owner = c
+			}
+			return owner == nil
+		})
+	} else {
+		for c := n.Parent; c != nil && owner == nil; c = c.Parent {
+			if c.Type == html.ElementNode && c.Data == "form" {
+				owner = c
+			}
+		}
+	}
+	if owner == nil {
+		return nil, nil
+	}
+	form := p.document.newForm(owner, top)
+	for i, c := range formFields(owner, top, form.ID) {
+		if field := form.Fields[i]; c == n && field.isSubmit() && !field.Disabled {
+			return form, field
+		}
+	}
+	return nil, nil
+}
+
+func (p *Page) submit(form *Form, button *FormField) error {
+	var opts []RequestOption
+	if form.URL != nil {
+		opts = p.referer(form.URL)
+	}
+	return p.navigate(form.submit(p.client, button, opts...))
+}
+
+// navigate shows the response, the current page goes to the history. Error pages returned
+// along with an *HTTPError are shown too
+func (p *Page) navigate(resp *Response, err error) error {
+	if resp == nil {
+		return err
+	}
+	if p.response != nil {
+		p.history = append(p.history, p.response)
+	}
+	p.response, p.document = resp, resp.Parse()
+	return err
+}
+
+func (p *Page) resolve(href string) (*url.URL, error) {
+	if p.document == nil {
+		return url.Parse(strings.TrimSpace(href))
+	}
+	return p.document.ResolveURL(href)
+}
+
+// referer returns the Referer header of a request from the current page to target as browsers send it
+// by default: the whole URL to the same origin, the origin to other ones and nothing from https to http
+func (p *Page) referer(target *url.URL) []RequestOption {
+	from := p.URL()
+	if from == nil || (from.Scheme != "http" && from.Scheme != "https") {
+		return nil
+	}
+	if from.Scheme == "https" && target.Scheme != "https" {
+		return nil
+	}
+	if from.Scheme == target.Scheme && strings.EqualFold(from.Host, target.Host) {
+		referer := *from
+		referer.User, referer.Fragment, referer.RawFragment = nil, "", ""
+		return []RequestOption{WithHeader("Referer", referer.String())}
+	}
+	return []RequestOption{WithHeader("Referer", from.Scheme+"://"+from.Host+"/")}
+}
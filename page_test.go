@@ -0,0 +1,98 @@
+package owl
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPage(t *testing.T) {
+	referers := make(map[string]string)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		referers[r.URL.Path] = r.Referer()
+		switch r.URL.Path {
+		case "/":
+			fmt.Fprint(w, `<title>Home</title>
+				<a href="/login">Sign in</a>
+				<a id="about" href="about"><img src="owl.png"></a>`)
+		case "/about":
+			fmt.Fprint(w, `<title>About</title>`)
+		case "/login":
+			fmt.Fprint(w, `<title>Login</title>
+				<form method="post" action="/session">
+					<input name="user"><input name="password" type="password">
+					<button name="remember" value="no">Login</button>
+					<button name="remember" value="yes" id="remember">Stay logged in</button>
+				</form>`)
+		case "/session":
+			r.ParseForm()
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: r.PostForm.Get("user") + ":" + r.PostForm.Get("remember")})
+			http.Redirect(w, r, "/account", http.StatusSeeOther)
+		case "/account":
+			cookie, err := r.Cookie("session")
+			if err != nil {
+				http.Error(w, "<title>Forbidden</title>", http.StatusForbidden)
+				return
+			}
+			fmt.Fprintf(w, `<title>%s</title>`, cookie.Value)
+		}
+	}))
+	defer srv.Close()
+	client := NewClient(WithHTTPClient(srv.Client()))
+
+	page, err := client.Open(srv.URL + "/")
+	require.NoError(t, err)
+	require.Equal(t, "Home", page.Document().Find("title").Text())
+	require.Empty(t, referers["/"])
+
+	require.NoError(t, page.Click("#about img"))
+	require.Equal(t, "About", page.Document().Find("title").Text())
+	require.Equal(t, srv.URL+"/", referers["/about"])
+
+	require.NoError(t, page.Back())
+	require.Equal(t, srv.URL+"/", page.URL().String())
+	require.NoError(t, page.FollowLink("sign IN"))
+	require.Equal(t, "Login", page.Document().Find("title").Text())
+
+	require.NoError(t, page.SubmitForm("form", map[string]string{"user": "owl", "password": "hoot"}))
+	require.Equal(t, "owl:no", page.Document().Find("title").Text())
+	require.Equal(t, srv.URL+"/account", page.URL().String())
+	require.Equal(t, srv.URL+"/login", referers["/session"])
+
+	// Back shows the login page as it was served, with empty fields
+	require.NoError(t, page.Back())
+	require.NoError(t, page.Click("#remember"))
+	require.Equal(t, ":yes", page.Document().Find("title").Text())
+
+	require.ErrorIs(t, page.Click("title"), ErrNotClickable)
+	require.ErrorIs(t, page.FollowLink("missing"), ErrNoLink)
+	require.ErrorIs(t, page.SubmitForm("", nil), ErrNoForm)
+
+	// Error pages are shown along with the error
+	fresh := NewClient(WithHTTPClient(&http.Client{}))
+	fresh.StatusErrors = true
+	page, err = fresh.Open(srv.URL + "/account")
+	var httpErr *HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, "Forbidden", page.Document().Find("title").Text())
+}
+
+func TestReferer(t *testing.T) {
+	page := &Page{response: &Response{}}
+	for _, c := range []struct{ from, to, referer string }{
+		{"https://example.com/a?q=1#top", "https://example.com/b", "https://example.com/a?q=1"},
+		{"https://example.com/a", "https://other.com/b", "https://example.com/"},
+		{"https://example.com/a", "http://example.com/b", ""},
+		{"http://example.com/a", "https://example.com/b", "http://example.com/"},
+	} {
+		from, _ := url.Parse(c.from)
+		to, _ := url.Parse(c.to)
+		page.response.FinalURL = from
+		cfg := newRequestConfig(page.referer(to))
+		require.Equal(t, c.referer, cfg.header.Get("Referer"), c.from+" -> "+c.to)
+	}
+}
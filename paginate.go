@@ -0,0 +1,146 @@
+package owl
+
+import (
+	"iter"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// pageParams are the query parameters holding the page number that NextPage increments
+var pageParams = []string{"page", "p", "pg", "paged"}
+
+// nextTexts are the texts of the links to the next page, compared ignoring case
+var nextTexts = []string{"next", "next page", "next »", "next ›", "next >", "»", "›", ">", "→", "older posts"}
+
+// Paginate fetches startURL and then the page next finds in every document, NextPage when next is nil,
+// until next finds none. URLs found by next resolve against the base URL of their document,
+// and pages already fetched end the iteration, so cyclic listings stop.
+// A failed fetch is yielded with its error and ends the iteration, error pages are yielded along with their error
+func (c *Client) Paginate(startURL string, next func(*Root) (string, bool), opts ...RequestOption) iter.Seq2[*Root, error] {
+	if next == nil {
+		next = NextPage
+	}
+	return func(yield func(*Root, error) bool) {
+		seen := make(map[string]bool)
+		target := startURL
+		for {
+			doc, err := c.GetDocument(target, opts...)
+			seen[target] = true
+			if doc != nil && doc.URL() != nil {
+				seen[doc.URL().String()] = true
+			}
+			if !yield(doc, err) || err != nil {
+				return
+			}
+			href, ok := next(doc)
+			if !ok {
+				return
+			}
+			u, err := doc.ResolveURL(href)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			u.Fragment, u.RawFragment = "", ""
+			if target = u.String(); seen[target] {
+				return
+			}
+		}
+	}
+}
+
+// NextPage finds the link to the next page of a listing: a link or a element with rel=next,
+// then an a element whose text, title or aria-label says next, and then a link to the URL of the document
+// with its page number query parameter, such as ?page=2, incremented
+func NextPage(doc *Root) (string, bool) {
+	var byText string
+	var rel *html.Node
+	walk(doc.Node, func(n *html.Node) bool {
+		if rel != nil {
+			return false
+		}
+		if n.Type != html.ElementNode || (n.Data != "a" && n.Data != "link") {
+			return true
+		}
+		href, ok := attrLookup(n, "href")
+		if !ok || strings.TrimSpace(href) == "" {
+			return true
+		}
+		for _, r := range strings.Fields(strings.ToLower(attrValue(n, "rel"))) {
+			if r == "next" {
+				rel = n
+				return false
+			}
+		}
+		if byText == "" && n.Data == "a" && isNextLink(n) {
+			byText = href
+		}
+		return true
+	})
+	if rel != nil {
+		return attrValue(rel, "href"), true
+	}
+	if byText != "" {
+		return byText, true
+	}
+	return nextPageNumber(doc)
+}
+
+// isNextLink reports whether the text, title or aria-label of the a element n says it leads to the next page
+func isNextLink(n *html.Node) bool {
+	labels := []string{
+		(&Root{Node: n}).FullText(),
+		attrValue(n, "title"),
+		attrValue(n, "aria-label"),
+	}
+	for _, label := range labels {
+		label = strings.ToLower(strings.Join(strings.Fields(label), " "))
+		for _, text := range nextTexts {
+			if label == text {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// nextPageNumber returns the link of doc to the URL of doc with its page number incremented,
+// the first page of listings without a page number being 1
+func nextPageNumber(doc *Root) (string, bool) {
+	current := doc.URL()
+	if current == nil {
+		return "", false
+	}
+	query := current.Query()
+	for _, param := range pageParams {
+		page := 1
+		if value := query.Get(param); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				continue
+			}
+			page = n
+		}
+		for _, link := range doc.Links(nil) {
+			if link.URL == nil || !strings.EqualFold(link.URL.Host, current.Host) || link.URL.Path != current.Path {
+				continue
+			}
+			if linkPage, err := strconv.Atoi(link.URL.Query().Get(param)); err == nil && linkPage == page+1 &&
+				sameQueryBut(link.URL, current, param) {
+				return link.Href, true
+			}
+		}
+	}
+	return "", false
+}
+
+// sameQueryBut reports whether the queries of a and b hold the same parameters other than param
+func sameQueryBut(a, b *url.URL, param string) bool {
+	qa, qb := a.Query(), b.Query()
+	qa.Del(param)
+	qb.Del(param)
+	return qa.Encode() == qb.Encode()
+}
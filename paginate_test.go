@@ -0,0 +1,83 @@
+package owl
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaginate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rel":
+			page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+			fmt.Fprintf(w, `<h1>%d</h1>`, page)
+			if page < 3 {
+				fmt.Fprintf(w, `<link rel="next" href="?page=%d">`, page+1)
+			}
+		case "/cycle":
+			fmt.Fprint(w, `<h1>cycle</h1><a rel="next" href="/cycle#top">Next</a>`)
+		case "/missing":
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	client := &Client{Client: srv.Client(), StatusErrors: true}
+
+	var pages []string
+	for doc, err := range client.Paginate(srv.URL+"/rel?page=1", nil) {
+		require.NoError(t, err)
+		pages = append(pages, doc.Find("h1").Text())
+	}
+	require.Equal(t, []string{"1", "2", "3"}, pages)
+
+	// Breaking out of the loop stops the fetches
+	pages = nil
+	for doc := range client.Paginate(srv.URL+"/rel?page=1", nil) {
+		pages = append(pages, doc.Find("h1").Text())
+		break
+	}
+	require.Equal(t, []string{"1"}, pages)
+
+	count := 0
+	for _, err := range client.Paginate(srv.URL+"/cycle", nil) {
+		require.NoError(t, err)
+		count++
+	}
+	require.Equal(t, 1, count)
+
+	var errs []error
+	for _, err := range client.Paginate(srv.URL+"/missing", func(*Root) (string, bool) { return "/rel", true }) {
+		errs = append(errs, err)
+	}
+	require.Len(t, errs, 1)
+	var httpErr *HTTPError
+	require.ErrorAs(t, errs[0], &httpErr)
+}
+
+func TestNextPage(t *testing.T) {
+	u, _ := url.Parse("https://example.com/list?sort=new&page=2")
+	for _, c := range []struct{ page, next string }{
+		{`<a href="/a">1</a><a rel="nofollow next" href="/list?page=3">3</a>`, "/list?page=3"},
+		{`<link rel="next" href="/feed?page=3"><a href="/other">Next</a>`, "/feed?page=3"},
+		{`<a href="/prev">Previous</a><a href="/list/3"> Next  Page </a>`, "/list/3"},
+		{`<a href="/list/3" aria-label="Next"><svg></svg></a>`, "/list/3"},
+		{`<a href="?sort=old&page=3">3</a><a href="?sort=new&page=1">1</a><a href="?sort=new&page=3">3</a>`, "?sort=new&page=3"},
+		{`<a href="?sort=new&page=4">4</a>`, ""},
+	} {
+		next, ok := NextPage(HTMLParse(strings.NewReader(c.page)).SetURL(u))
+		require.Equal(t, c.next != "", ok, c.page)
+		require.Equal(t, c.next, next, c.page)
+	}
+
+	first, _ := url.Parse("https://example.com/list")
+	next, ok := NextPage(HTMLParse(strings.NewReader(`<a href="/list?p=2">2</a>`)).SetURL(first))
+	require.True(t, ok)
+	require.Equal(t, "/list?p=2", next)
+}
@@ -0,0 +1,95 @@
+package owl
+
+import (
+	"errors"
+	"sync"
+
+	"golang.org/x/net/html"
+)
+
+// part is a unit of work for FindAllParallel, either the whole subtree of node
+// or, when self is true, only the node itself
+type part struct {
+	node *html.Node
+	self bool
+}
+
+// FindAllParallel works like FindAll but searches the subtrees of the Node with up to workers goroutines,
+// results are merged back in document order. It only pays off on very large documents,
+// an indexed Root or a workers count below 2 falls back to FindAll
+func (r *Root) FindAllParallel(workers int, args ...string) Roots {
+	if workers < 2 || r.Indexed() {
+		return r.FindAll(args...)
+	}
+	parts := partition(r.Node, workers*4)
+	if len(parts) < 2 {
+		return r.FindAll(args...)
+	}
+
+	// Every worker takes a contiguous run of parts so results stay in document order
+	results := make([][]*html.Node, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		from, to := w*len(parts)/workers, (w+1)*len(parts)/workers
+		wg.Add(1)
+		go func(w int, parts []part) {
+			defer wg.Done()
+			var nodes []*html.Node
+			for _, p := range parts {
+				if p.self {
+					if matchNode(p.node, args, false) {
+						nodes = append(nodes, p.node)
+					}
+					continue
+				}
+				nodes = appendMatches(nodes, p.node, args, false)
+			}
+			results[w] = nodes
+		}(w, parts[from:to])
+	}
+	wg.Wait()
+
+	length := 0
+	for _, nodes := range results {
+		length += len(nodes)
+	}
+	if length == 0 {
+		return Roots{Roots: nil, Error: newError(ErrElementsNotFound, errors.New("no elements or attriabutes found"))}
+	}
+	Nodes := make([](*Root), 0, length)
+	for _, nodes := range results {
+		for _, n := range nodes {
+			Nodes = append(Nodes, &Root{Node: n, NodeValue: n.Data, doc: r.doc})
+		}
+	}
+	return Roots{Roots: Nodes, Len: length, Error: nil}
+}
+
+// partition splits the descendants of n into parts in document order,
+// nodes are split into themselves and their children until there are at least want parts
+func partition(n *html.Node, want int) []part {
+	var parts []part
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		parts = append(parts, part{node: c})
+	}
+	for len(parts) < want {
+		expanded := make([]part, 0, len(parts)*2)
+		split := false
+		for _, p := range parts {
+			if p.self || p.node.FirstChild == nil {
+				expanded = append(expanded, p)
+				continue
+			}
+			split = true
+			expanded = append(expanded, part{node: p.node, self: true})
+			for c := p.node.FirstChild; c != nil; c = c.NextSibling {
+				expanded = append(expanded, part{node: c})
+			}
+		}
+		if !split {
+			break
+		}
+		parts = expanded
+	}
+	return parts
+}
@@ -0,0 +1,59 @@
+package owl
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func largeHTML(sections int) string {
+	var b strings.Builder
+	b.WriteString("<html><head><title>Large</title></head><body>")
+	for i := 0; i < sections; i++ {
+		fmt.Fprintf(&b, `<section id="s%d"><div class="item"><p>Item %d</p><ul>`, i, i)
+		for j := 0; j < 10; j++ {
+			fmt.Fprintf(&b, `<li class="entry"><a href="/item/%d/%d">link</a></li>`, i, j)
+		}
+		b.WriteString("</ul></div></section>")
+	}
+	b.WriteString("</body></html>")
+	return b.String()
+}
+
+func TestFindAllParallel(t *testing.T) {
+	root := HTMLParseFromString(largeHTML(50))
+	for _, q := range [][]string{{"li"}, {"", "class", "item"}, {"section"}, {"body"}, {"footer"}} {
+		want := root.FindAll(q...)
+		got := root.FindAllParallel(4, q...)
+		require.Equal(t, want.Len, got.Len, q)
+		require.Equal(t, want.Error == nil, got.Error == nil, q)
+		for i := range want.Roots {
+			require.Same(t, want.Roots[i].Node, got.Roots[i].Node, q)
+		}
+	}
+
+	// Parallel search keeps the semantics of FindAll on smaller documents too
+	require.Equal(t, HtmlRoot.FindAll("div").Len, HtmlRoot.FindAllParallel(8, "div").Len)
+}
+
+// benchRoot parses the document of the benchmarks the first time it is called, so tests do not pay for it
+var benchRoot = sync.OnceValue(func() *Root { return HTMLParseFromString(largeHTML(5000)) })
+
+func BenchmarkFindAll(b *testing.B) {
+	root := benchRoot()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		root.FindAll("a", "href", "/item/42/7")
+	}
+}
+
+func BenchmarkFindAllParallel(b *testing.B) {
+	root := benchRoot()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		root.FindAllParallel(8, "a", "href", "/item/42/7")
+	}
+}
@@ -0,0 +1,591 @@
+package owl
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Step is a stage of a Pipeline, it turns the value of the previous stage into a new one.
+// When the value is a list, as produced by SelectAll, element-wise steps are applied to each element
+type Step struct {
+	// Name identifies the step in the errors of the Pipeline
+	Name string
+	fn   func(any) (any, error)
+	// whole steps receive lists as they are instead of each of their elements
+	whole bool
+	// hits counts the elements the step chooses from in its input, see RunScored
+	hits func(any) int
+	// localize returns the step parsing with a Locale, see In
+	localize func(Locale) Step
+}
+
+// StepFunc returns an element-wise Step applying fn, to plug custom cleanup into a Pipeline
+func StepFunc(name string, fn func(any) (any, error)) Step {
+	return Step{Name: name, fn: fn}
+}
+
+// In returns the step parsing numbers and dates as written in the Locale l, such as ParseFloat().In(owl.LocaleFrench).
+// Steps that do not parse are returned as they are. The Locale of the Pipeline does not override l
+func (s Step) In(l Locale) Step {
+	if s.localize == nil {
+		return s
+	}
+	localized := s.localize(l)
+	localized.localize = nil
+	return localized
+}
+
+// Pipeline extracts a value from a document by running steps in order, see Pipe
+type Pipeline struct {
+	// steps are the steps given to Pipe and Then, they are put in the Locale of the document when they run
+	steps       []Step
+	locale      *Locale
+	hostLocales HostLocales
+	preprocess  []Preprocessor
+}
+
+// PipelineError tells which step of a Pipeline failed and on which value
+type PipelineError struct {
+	// Step is the index of the failed step
+	Step  int
+	Name  string
+	Input any
+	Err   error
+}
+
+func (e *PipelineError) Error() string {
+	input := fmt.Sprint(e.Input)
+	if r, ok := e.Input.(*Root); ok && r != nil {
+		input = "<" + r.NodeValue + ">"
+	}
+	return fmt.Sprintf("owl: pipeline step %d (%s) on %q: %v", e.Step, e.Name, input, e.Err)
+}
+
+func (e *PipelineError) Unwrap() error {
+	return e.Err
+}
+
+// Pipe returns the Pipeline running steps in order:
+//
+//	price := owl.Pipe(owl.Select(".price"), owl.Text(), owl.Regexp(`[\d.]+`), owl.ParseFloat())
+//	v, err := owl.Extract[float64](doc, price)
+func Pipe(steps ...Step) *Pipeline {
+	return &Pipeline{steps: steps}
+}
+
+// Then returns a Pipeline running the steps of p followed by steps
+func (p *Pipeline) Then(steps ...Step) *Pipeline {
+	next := *p
+	next.steps = append(append([]Step(nil), p.steps...), steps...)
+	return &next
+}
+
+// WithLocale returns a Pipeline whose steps parse numbers and dates as written in the Locale l,
+// including the steps added by Then, see Step.In. It replaces the Locale p was given before, so a Pipeline
+// can be reused for sites written in other languages:
+//
+//	price := owl.Pipe(owl.Select(".price"), owl.ParseFloat())
+//	german, french := price.WithLocale(owl.LocaleGerman), price.WithLocale(owl.LocaleFrench)
+func (p *Pipeline) WithLocale(l Locale) *Pipeline {
+	next := *p
+	next.locale = &l
+	return &next
+}
+
+// WithHostLocales returns a Pipeline parsing the documents of the hosts of locales in their Locale,
+// see HostLocales.Lookup. Documents of other hosts are parsed in the Locale set by WithLocale
+func (p *Pipeline) WithHostLocales(locales HostLocales) *Pipeline {
+	next := *p
+	next.hostLocales = locales
+	return &next
+}
+
+// stepsFor returns the steps of p in the Locale of root
+func (p *Pipeline) stepsFor(root *Root) []Step {
+	l := p.locale
+	if root != nil {
+		if hl, ok := p.hostLocales.Lookup(root.URL()); ok {
+			l = &hl
+		}
+	}
+	if l == nil {
+		return p.steps
+	}
+	steps := make([]Step, len(p.steps))
+	for i, step := range p.steps {
+		steps[i] = step.In(*l)
+	}
+	return steps
+}
+
+// Preprocess returns a Pipeline running steps on the document before the steps of p, such as
+// removing the boilerplate of a site before extracting from it, see Root.Preprocess.
+// The steps change a copy of the document, the Root given to Run is left as it is
+func (p *Pipeline) Preprocess(steps ...Preprocessor) *Pipeline {
+	next := p.Then()
+	next.preprocess = append(append([]Preprocessor(nil), p.preprocess...), steps...)
+	return next
+}
+
+// Run runs the pipeline on root, lists are returned as []any
+func (p *Pipeline) Run(root *Root) (any, error) {
+	return p.run(root, nil)
+}
+
+// run runs the pipeline on root, adding the steps choosing among several elements to the issues of c when it is set
+func (p *Pipeline) run(root *Root, c *Confidence) (any, error) {
+	if len(p.preprocess) > 0 && root != nil && root.Node != nil {
+		root = root.Clone().Preprocess(p.preprocess...)
+	}
+	var value any = root
+	for i, step := range p.stepsFor(root) {
+		list, isList := value.([]any)
+		if !isList || step.whole {
+			step.score(i, value, c)
+			v, err := step.fn(value)
+			if err != nil {
+				return nil, &PipelineError{Step: i, Name: step.Name, Input: value, Err: err}
+			}
+			value = v
+			continue
+		}
+		out := make([]any, len(list))
+		for j, elem := range list {
+			step.score(i, elem, c)
+			v, err := step.fn(elem)
+			if err != nil {
+				return nil, &PipelineError{Step: i, Name: step.Name, Input: elem, Err: err}
+			}
+			out[j] = v
+		}
+		value = out
+	}
+	return value, nil
+}
+
+// score adds an issue to c when the step i chooses the first of several elements of v, see RunScored
+func (s Step) score(i int, v any, c *Confidence) {
+	if c == nil || s.hits == nil {
+		return
+	}
+	c.choose(fmt.Sprintf("step %d (%s)", i, s.Name), s.hits(v))
+}
+
+// Extract runs p on root and returns its result as a T.
+// Lists convert to slices of their element type, such as []string or []float64
+func Extract[T any](root *Root, p *Pipeline) (T, error) {
+	value, err := p.Run(root)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return convertResult[T](value)
+}
+
+// convertResult returns the result of a Pipeline as a T, see Extract
+func convertResult[T any](value any) (T, error) {
+	var zero T
+	if v, ok := value.(T); ok {
+		return v, nil
+	}
+	if list, ok := value.([]any); ok {
+		err := convertList(list, &zero)
+		return zero, err
+	}
+	return zero, fmt.Errorf("owl: pipeline result is %T, not %T", value, zero)
+}
+
+// convertList stores list into the slice dst points to
+func convertList[T any](list []any, dst *T) error {
+	switch d := any(dst).(type) {
+	case *[]string:
+		return fill(list, d)
+	case *[]float64:
+		return fill(list, d)
+	case *[]int:
+		return fill(list, d)
+	case *[]int64:
+		return fill(list, d)
+	case *[]bool:
+		return fill(list, d)
+	case *[]time.Time:
+		return fill(list, d)
+	case *[]*Root:
+		return fill(list, d)
+	case *[]Price:
+		return fill(list, d)
+	}
+	return fmt.Errorf("owl: pipeline result is a list, not %T", *dst)
+}
+
+func fill[E any](list []any, dst *[]E) error {
+	out := make([]E, len(list))
+	for i, v := range list {
+		e, ok := v.(E)
+		if !ok {
+			return fmt.Errorf("owl: pipeline result element %d is %T, not %T", i, v, e)
+		}
+		out[i] = e
+	}
+	*dst = out
+	return nil
+}
+
+var errNotElement = errors.New("value is not an element")
+
+// rootStep returns an element-wise Step for elements
+func rootStep(name string, fn func(*Root) (any, error)) Step {
+	return StepFunc(name, func(v any) (any, error) {
+		r, ok := v.(*Root)
+		if !ok || r == nil || r.Node == nil {
+			return nil, errNotElement
+		}
+		return fn(r)
+	})
+}
+
+// stringStep returns an element-wise Step for strings, elements are turned into their text first
+func stringStep(name string, fn func(string) (any, error)) Step {
+	return StepFunc(name, func(v any) (any, error) {
+		switch v := v.(type) {
+		case string:
+			return fn(v)
+		case *Root:
+			if v != nil && v.Node != nil {
+				return fn(elementText(v))
+			}
+		}
+		return nil, fmt.Errorf("value of type %T is not a string", v)
+	})
+}
+
+func elementText(r *Root) string {
+	return collapsedText(r.Node)
+}
+
+// Select finds the first element matching the CSS selector
+func Select(selector string) Step {
+	step := rootStep("Select", func(r *Root) (any, error) {
+		found := r.SelectOne(selector)
+		if found.Error != nil {
+			return nil, fmt.Errorf("%q: %w", selector, found.Error.Err())
+		}
+		return found, nil
+	})
+	step.hits = func(v any) int {
+		if r, ok := v.(*Root); ok && r != nil && r.Node != nil {
+			return len(r.Select(selector).Roots)
+		}
+		return 0
+	}
+	return step
+}
+
+// SelectAll finds every element matching the CSS selector, the following steps apply to each of them.
+// No match results in an empty list
+func SelectAll(selector string) Step {
+	return rootStep("SelectAll", func(r *Root) (any, error) {
+		found := r.Select(selector)
+		if found.Error != nil && found.Error.Type == ErrInvalidSelector {
+			return nil, found.Error.Err()
+		}
+		list := make([]any, len(found.Roots))
+		for i, root := range found.Roots {
+			list[i] = root
+		}
+		return list, nil
+	})
+}
+
+// Text takes the text of elements with whitespace trimmed and collapsed
+func Text() Step {
+	return rootStep("Text", func(r *Root) (any, error) {
+		return elementText(r), nil
+	})
+}
+
+// Attr takes the value of the attribute key, failing when the element does not have it
+func Attr(key string) Step {
+	return rootStep("Attr", func(r *Root) (any, error) {
+		v, ok := r.Attr(key)
+		if !ok {
+			return nil, fmt.Errorf("no attribute %q", key)
+		}
+		return v, nil
+	})
+}
+
+// HTML renders elements
+func HTML() Step {
+	return rootStep("HTML", func(r *Root) (any, error) {
+		return string(r.Render()), nil
+	})
+}
+
+// Trim removes the leading and trailing whitespace of strings
+func Trim() Step {
+	return stringStep("Trim", func(s string) (any, error) {
+		return strings.TrimSpace(s), nil
+	})
+}
+
+// Replace replaces every occurrence of old by new in strings
+func Replace(old, new string) Step {
+	return stringStep("Replace", func(s string) (any, error) {
+		return strings.ReplaceAll(s, old, new), nil
+	})
+}
+
+// Regexp takes the first match of pattern in strings, or its first capturing group when it has one.
+// It fails when the pattern does not compile or does not match
+func Regexp(pattern string) Step {
+	re, err := regexp.Compile(pattern)
+	return stringStep("Regexp", func(s string) (any, error) {
+		if err != nil {
+			return nil, err
+		}
+		m := re.FindStringSubmatch(s)
+		if m == nil {
+			return nil, fmt.Errorf("no match for %s", pattern)
+		}
+		if len(m) > 1 {
+			return m[1], nil
+		}
+		return m[0], nil
+	})
+}
+
+// ParseFloat parses strings as float64, ignoring whitespace and thousands separators.
+// When a string has both commas and periods, the last of them is the decimal separator, as in
+// "1,299.50" and "1.299,50". A single period is a decimal separator, so is a single comma unless
+// three digits follow it, as in "19,99" but not "1,299". The decimal separator of a Locale is not guessed, see Step.In.
+// Arabic-Indic digits are understood
+func ParseFloat() Step {
+	return parseFloat(nil)
+}
+
+func parseFloat(l *Locale) Step {
+	step := stringStep("ParseFloat", func(s string) (any, error) {
+		number, err := cleanNumber(s, l.decimal())
+		if err != nil {
+			return nil, err
+		}
+		return strconv.ParseFloat(number, 64)
+	})
+	step.localize = func(l Locale) Step { return parseFloat(&l) }
+	return step
+}
+
+// ParseInt parses strings as int, ignoring whitespace and thousands separators, see ParseFloat.
+// Strings with a decimal separator fail
+func ParseInt() Step {
+	return parseInt(nil)
+}
+
+func parseInt(l *Locale) Step {
+	step := stringStep("ParseInt", func(s string) (any, error) {
+		number, err := cleanNumber(s, l.decimal())
+		if err != nil {
+			return nil, err
+		}
+		if strings.Contains(number, ".") {
+			return nil, fmt.Errorf("%q is not an integer", s)
+		}
+		return strconv.Atoi(number)
+	})
+	step.localize = func(l Locale) Step { return parseInt(&l) }
+	return step
+}
+
+// cleanNumber removes whitespace and thousands separators from s and turns its decimal separator
+// into a period, see ParseFloat. The decimal separator is guessed when decimal is zero.
+// Thousands separators must separate groups of three digits
+func cleanNumber(s string, decimalSeparator rune) (string, error) {
+	if strings.ContainsAny(s, "٫٬") {
+		// The Arabic separators are turned into a period and a comma
+		decimalSeparator = '.'
+	}
+	s = strings.Map(func(c rune) rune {
+		if unicode.IsSpace(c) || c == '\'' || c == '’' {
+			return -1
+		}
+		return c
+	}, normalizeDigits(s))
+	commas, periods := strings.Count(s, ","), strings.Count(s, ".")
+	lastComma, lastPeriod := strings.LastIndex(s, ","), strings.LastIndex(s, ".")
+	var decimal, thousands byte
+	switch {
+	case decimalSeparator == ',':
+		decimal, thousands = ',', '.'
+	case decimalSeparator == '.':
+		decimal, thousands = '.', ','
+	case commas > 0 && periods > 0:
+		decimal, thousands = '.', ','
+		if lastComma > lastPeriod {
+			decimal, thousands = ',', '.'
+		}
+	case commas > 1:
+		thousands = ','
+	case periods > 1:
+		thousands = '.'
+	case periods == 1:
+		decimal = '.'
+	case commas == 1 && len(s)-lastComma-1 == 3:
+		thousands = ','
+	case commas == 1:
+		decimal = ','
+	}
+	integer, fraction, hasDecimal := s, "", false
+	if i := strings.LastIndexByte(s, decimal); decimal != 0 && i >= 0 {
+		integer, fraction, hasDecimal = s[:i], s[i+1:], true
+		if strings.IndexByte(integer, decimal) >= 0 || thousands != 0 && strings.IndexByte(fraction, thousands) >= 0 {
+			return "", fmt.Errorf("ambiguous number %q", s)
+		}
+	}
+	if thousands != 0 && strings.IndexByte(integer, thousands) >= 0 {
+		groups := strings.Split(integer, string(thousands))
+		if first := len(strings.TrimLeft(groups[0], "+-")); first == 0 || first > 3 {
+			return "", fmt.Errorf("ambiguous number %q", s)
+		}
+		for _, group := range groups[1:] {
+			if len(group) != 3 {
+				return "", fmt.Errorf("ambiguous number %q", s)
+			}
+		}
+		integer = strings.Join(groups, "")
+	}
+	if hasDecimal {
+		return integer + "." + fraction, nil
+	}
+	return integer, nil
+}
+
+// ParseTime parses strings as time.Time with the first of layouts that fits.
+// Month names are read in English unless the step has a Locale, see Step.In
+func ParseTime(layouts ...string) Step {
+	return parseTime(nil, layouts)
+}
+
+func parseTime(l *Locale, layouts []string) Step {
+	step := stringStep("ParseTime", func(s string) (any, error) {
+		return parseTimeIn(l, s, layouts)
+	})
+	step.localize = func(l Locale) Step { return parseTime(&l, layouts) }
+	return step
+}
+
+// parseTimeIn parses s with the first of layouts that fits, the month names of l are turned into English ones first
+func parseTimeIn(l *Locale, s string, layouts []string) (time.Time, error) {
+	s = normalizeDigits(strings.TrimSpace(s))
+	long, short := l.englishMonths(s, false), l.englishMonths(s, true)
+	for _, layout := range layouts {
+		for _, v := range []string{long, short} {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t, nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("no layout fits %q", s)
+}
+
+// Price is an amount of money
+type Price struct {
+	Amount float64
+	// Currency is an ISO 4217 code, empty when the text does not tell
+	Currency string
+}
+
+// currencySymbols maps currency symbols to their ISO 4217 code
+var currencySymbols = map[string]string{
+	"€": "EUR", "$": "USD", "£": "GBP", "¥": "JPY", "₹": "INR", "₽": "RUB", "₩": "KRW", "₺": "TRY", "₴": "UAH", "zł": "PLN",
+}
+
+var priceAmount = regexp.MustCompile(`\d[\d\s,.]*`)
+
+// ParsePrice parses strings such as "$1,299.00" or "19,99 €" into a Price. The last comma or period
+// followed by one or two digits is the decimal separator, the others separate thousands
+func ParsePrice() Step {
+	return stringStep("ParsePrice", func(s string) (any, error) {
+		number := strings.TrimRight(priceAmount.FindString(s), " ,.")
+		if number == "" {
+			return nil, fmt.Errorf("no amount in %q", s)
+		}
+		digits := strings.Map(func(c rune) rune {
+			if unicode.IsSpace(c) {
+				return -1
+			}
+			return c
+		}, number)
+		decimal := -1
+		if i := strings.LastIndexAny(digits, ",."); i >= 0 && len(digits)-i-1 <= 2 {
+			decimal = i
+		}
+		var b strings.Builder
+		for i, c := range digits {
+			switch {
+			case c >= '0' && c <= '9':
+				b.WriteRune(c)
+			case i == decimal:
+				b.WriteByte('.')
+			}
+		}
+		amount, err := strconv.ParseFloat(b.String(), 64)
+		if err != nil {
+			return nil, err
+		}
+		p := Price{Amount: amount}
+		for symbol, code := range currencySymbols {
+			if strings.Contains(s, symbol) && (p.Currency == "" || symbol != "$") {
+				p.Currency = code
+			}
+		}
+		for _, word := range strings.FieldsFunc(s, func(c rune) bool { return !unicode.IsLetter(c) }) {
+			if len(word) == 3 && strings.ToUpper(word) == word {
+				p.Currency = word
+				break
+			}
+		}
+		return p, nil
+	})
+}
+
+// First keeps the first element of lists, failing on empty ones
+func First() Step {
+	return Step{Name: "First", whole: true, fn: func(v any) (any, error) {
+		list, ok := v.([]any)
+		if !ok {
+			return v, nil
+		}
+		if len(list) == 0 {
+			return nil, errors.New("empty list")
+		}
+		return list[0], nil
+	}}
+}
+
+// Join joins lists of strings with sep, elements are turned into their text first
+func Join(sep string) Step {
+	return Step{Name: "Join", whole: true, fn: func(v any) (any, error) {
+		list, ok := v.([]any)
+		if !ok {
+			list = []any{v}
+		}
+		parts := make([]string, 0, len(list))
+		for _, elem := range list {
+			switch elem := elem.(type) {
+			case string:
+				parts = append(parts, elem)
+			case *Root:
+				parts = append(parts, elementText(elem))
+			default:
+				return nil, fmt.Errorf("value of type %T is not a string", elem)
+			}
+		}
+		return strings.Join(parts, sep), nil
+	}}
+}
@@ -0,0 +1,105 @@
+package owl
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const shopPage = `<html><body>
+	<div class="product"><h2> Owl   plush </h2><span class="price">Price: $1,299.50</span>
+		<time datetime="2024-05-01">May 1st</time><a href="/owl">more</a></div>
+	<div class="product"><h2>Owl mug</h2><span class="price">19,99 €</span></div>
+	<div class="product"><h2>Owl poster</h2><span class="price">EUR 5</span></div>
+</body></html>`
+
+func TestPipe(t *testing.T) {
+	doc := HTMLParseFromString(shopPage)
+
+	price, err := Extract[float64](doc, Pipe(Select(".price"), Text(), Regexp(`[\d,.]+`), ParseFloat()))
+	require.NoError(t, err)
+	require.Equal(t, 1299.5, price)
+
+	names, err := Extract[[]string](doc, Pipe(SelectAll(".product h2"), Text()))
+	require.NoError(t, err)
+	require.Equal(t, []string{"Owl plush", "Owl mug", "Owl poster"}, names)
+
+	prices, err := Extract[[]Price](doc, Pipe(SelectAll(".price"), ParsePrice()))
+	require.NoError(t, err)
+	require.Equal(t, []Price{{1299.5, "USD"}, {19.99, "EUR"}, {5, "EUR"}}, prices)
+
+	published, err := Extract[time.Time](doc, Pipe(Select("time"), Attr("datetime"), ParseTime(time.RFC3339, "2006-01-02")))
+	require.NoError(t, err)
+	require.Equal(t, 2024, published.Year())
+
+	joined, err := Extract[string](doc, Pipe(SelectAll("h2"), Join(" | ")))
+	require.NoError(t, err)
+	require.Equal(t, "Owl plush | Owl mug | Owl poster", joined)
+
+	upper := StepFunc("Upper", func(v any) (any, error) { return strings.ToUpper(v.(string)), nil })
+	base := Pipe(SelectAll("h2"), Text())
+	first, err := Extract[string](doc, base.Then(upper, First()))
+	require.NoError(t, err)
+	require.Equal(t, "OWL PLUSH", first)
+
+	count, err := Extract[int](doc, Pipe(Select("span.price"), Replace("Price: $", ""), Regexp(`^(\d+),`), ParseInt()))
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+}
+
+func TestPipeErrors(t *testing.T) {
+	doc := HTMLParseFromString(shopPage)
+
+	_, err := Extract[float64](doc, Pipe(SelectAll(".product"), Select("a"), Attr("href")))
+	var perr *PipelineError
+	require.True(t, errors.As(err, &perr))
+	require.Equal(t, 1, perr.Step)
+	require.Equal(t, "Select", perr.Name)
+	require.Contains(t, err.Error(), `owl: pipeline step 1 (Select) on "<div>"`)
+
+	_, err = Extract[float64](doc, Pipe(Select("h2"), Text(), ParseFloat()))
+	require.ErrorAs(t, err, &perr)
+	require.Equal(t, "Owl plush", perr.Input)
+
+	_, err = Extract[int](doc, Pipe(Select("h2"), Text()))
+	require.EqualError(t, err, "owl: pipeline result is string, not int")
+
+	_, err = Extract[string](doc, Pipe(Select("h2"), Regexp(`(`)))
+	require.Error(t, err)
+
+	_, err = Extract[string](doc, Pipe(SelectAll("table"), First()))
+	require.ErrorContains(t, err, "empty list")
+}
+
+func TestParseNumbers(t *testing.T) {
+	floats := map[string]float64{
+		"1,299.50":   1299.5,
+		"1.299,50":   1299.5,
+		"19,99":      19.99,
+		"1,5":        1.5,
+		"3.14159":    3.14159,
+		"1,299":      1299,
+		"1 234 567":  1234567,
+		"1.234.567":  1234567,
+		"-2,000,000": -2000000,
+		"42":         42,
+	}
+	for in, want := range floats {
+		v, err := ParseFloat().fn(in)
+		require.NoError(t, err, in)
+		require.Equal(t, want, v, in)
+	}
+	for _, in := range []string{"1,2,3", "12,34.5", "1.2.3,4", "1,234.5.6", ",123"} {
+		_, err := ParseFloat().fn(in)
+		require.Error(t, err, in)
+	}
+
+	v, err := ParseInt().fn("1,234,567")
+	require.NoError(t, err)
+	require.Equal(t, 1234567, v)
+	_, err = ParseInt().fn("19,99")
+	require.ErrorContains(t, err, "not an integer")
+}
@@ -0,0 +1,113 @@
+package owl
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Preprocessor changes a parsed document before anything is extracted from it,
+// such as removing boilerplate or fixing up markup of a specific site
+type Preprocessor func(*Root)
+
+// Preprocess applies steps to the tree of the Node in order and returns the Root,
+// the index built by BuildIndex is invalidated
+func (r *Root) Preprocess(steps ...Preprocessor) *Root {
+	if r.Node == nil {
+		return r
+	}
+	for _, step := range steps {
+		step(r)
+	}
+	r.InvalidateIndex()
+	return r
+}
+
+// RemoveElements removes every element FindAll(args...) matches, along with everything below it
+func RemoveElements(args ...string) Preprocessor {
+	return func(r *Root) {
+		for _, n := range findAllofem(r.Node, args, false) {
+			if n.Parent != nil {
+				n.Parent.RemoveChild(n)
+			}
+		}
+	}
+}
+
+// UnwrapTags replaces every element with one of the given tag names by its children
+func UnwrapTags(tags ...string) Preprocessor {
+	return func(r *Root) {
+		var nodes []*html.Node
+		walk(r.Node, func(n *html.Node) bool {
+			if n != r.Node && n.Type == html.ElementNode && containsString(tags, n.Data) {
+				nodes = append(nodes, n)
+			}
+			return true
+		})
+		for _, n := range nodes {
+			unwrap(n)
+		}
+	}
+}
+
+// unwrap moves the children of n in its place and removes n
+func unwrap(n *html.Node) {
+	if n.Parent == nil {
+		return
+	}
+	for c := n.FirstChild; c != nil; c = n.FirstChild {
+		n.RemoveChild(c)
+		n.Parent.InsertBefore(c, n)
+	}
+	n.Parent.RemoveChild(n)
+}
+
+// lazySrcAttributes and lazySrcsetAttributes hold the attributes lazy-loading scripts
+// commonly read the real source of an image or frame from
+var (
+	lazySrcAttributes    = []string{"data-src", "data-lazy-src", "data-original", "data-lazy"}
+	lazySrcsetAttributes = []string{"data-srcset", "data-lazy-srcset"}
+)
+
+// DecodeLazyLoad copies the sources lazy-loading scripts would set from attributes such as data-src
+// and data-srcset into the src and srcset attributes of img, iframe, source, video and audio elements
+func DecodeLazyLoad() Preprocessor {
+	return func(r *Root) {
+		walk(r.Node, func(n *html.Node) bool {
+			if n.Type != html.ElementNode {
+				return true
+			}
+			switch n.Data {
+			case "img", "iframe", "source", "video", "audio":
+				if v := firstAttr(n, lazySrcAttributes); v != "" {
+					setAttr(n, "src", v)
+				}
+				if v := firstAttr(n, lazySrcsetAttributes); v != "" {
+					setAttr(n, "srcset", v)
+				}
+			}
+			return true
+		})
+	}
+}
+
+// firstAttr returns the value of the first non empty attribute of n among keys
+func firstAttr(n *html.Node, keys []string) string {
+	for _, key := range keys {
+		if v := strings.TrimSpace(attrValue(n, key)); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// setAttr sets the attribute key of n to val, adding it when n does not have it
+func setAttr(n *html.Node, key, val string) {
+	for i := range n.Attr {
+		if n.Attr[i].Key == key {
+			n.Attr[i].Val = val
+			return
+		}
+	}
+	n.Attr = append(n.Attr, html.Attribute{Key: key, Val: val})
+}
@@ -0,0 +1,73 @@
+package owl
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreprocess(t *testing.T) {
+	root := HTMLParseFromString(`<html><body>
+		<div class="ad banner">Buy now</div>
+		<article><font color="red"><span>Story</span> text</font>
+			<img src="data:image/gif;base64,R0lGOD" data-src="/photo.jpg" data-srcset="/photo@2x.jpg 2x">
+		</article>
+		<div class="ad">More ads</div>
+	</body></html>`).BuildIndex()
+
+	root.Preprocess(
+		RemoveElements("div", "class", "ad"),
+		UnwrapTags("font"),
+		DecodeLazyLoad(),
+	)
+	require.False(t, root.Indexed())
+	require.Equal(t, 0, root.FindAll("div", "class", "ad").Len)
+
+	article := root.Find("article")
+	require.Equal(t, "span", article.Children().Roots[0].NodeValue)
+	require.Equal(t, "Story text", article.FullTextOpts(TextOptions{Trim: true, CollapseWhitespace: true, Separator: " "}))
+
+	img := root.Find("img")
+	require.Equal(t, "/photo.jpg", img.Attrs()["src"])
+	require.Equal(t, "/photo@2x.jpg 2x", img.Attrs()["srcset"])
+}
+
+func TestPipelinePreprocess(t *testing.T) {
+	doc := HTMLParseFromString(`<article><div class="ad">Buy now</div><p>Owls hunt at <font>night</font>.</p>
+		<img data-src="/owl.jpg"></article>`)
+	body := Pipe(Select("article"), Text()).Preprocess(RemoveElements("div", "class", "ad"))
+	text, err := Extract[string](doc, body)
+	require.NoError(t, err)
+	require.Equal(t, "Owls hunt at night.", text)
+
+	// Preprocessors are kept by Then and WithLocale, and added to by Preprocess
+	image := Pipe().Preprocess(RemoveElements("p")).Preprocess(DecodeLazyLoad()).WithLocale(LocaleFrench).
+		Then(Select("article"), Select("img"), Attr("src"))
+	src, err := Extract[string](doc, image)
+	require.NoError(t, err)
+	require.Equal(t, "/owl.jpg", src)
+
+	// The document given to the Pipeline is left as it is
+	require.Equal(t, 1, doc.FindAll("div", "class", "ad").Len)
+	_, ok := doc.Find("img").Attrs()["src"]
+	require.False(t, ok)
+}
+
+func TestCrawlerPreprocess(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<nav><a href="/nav">Home</a></nav><article><a href="/story">Story</a></article>`)
+	}))
+	defer srv.Close()
+	crawler := NewCrawler(&Client{Client: srv.Client()})
+	crawler.Preprocess = []Preprocessor{RemoveElements("nav")}
+	var links []string
+	crawler.OnHTML("a[href]", func(e *Root, ctx *CrawlContext) {
+		href, _ := e.Attr("href")
+		links = append(links, href)
+	})
+	require.NoError(t, crawler.Start(srv.URL+"/"))
+	require.Equal(t, []string{"/story"}, links)
+}
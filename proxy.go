@@ -0,0 +1,89 @@
+package owl
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+)
+
+// ErrNoTransport is returned when the http.Client of a Client has a Transport other than *http.Transport,
+// which can not be configured
+var ErrNoTransport = errors.New("owl: the transport of the client is not an *http.Transport")
+
+// Proxy returns the URL of the proxy a request goes through, nil for a direct connection.
+// Proxies may be HTTP, HTTPS or SOCKS5 with socks5:// URLs
+type Proxy func(*http.Request) (*url.URL, error)
+
+// ProxyURL sends every request through the proxy at u
+func ProxyURL(u *url.URL) Proxy {
+	return http.ProxyURL(u)
+}
+
+// RotateProxies sends the requests through each proxy in turn
+func RotateProxies(proxies ...*url.URL) Proxy {
+	var next atomic.Uint64
+	return func(*http.Request) (*url.URL, error) {
+		if len(proxies) == 0 {
+			return nil, nil
+		}
+		return proxies[(next.Add(1)-1)%uint64(len(proxies))], nil
+	}
+}
+
+// ParseProxies parses the proxy URLs, a single proxy is used for every request and several are rotated.
+// URLs without a scheme are HTTP proxies
+func ParseProxies(rawURLs ...string) (Proxy, error) {
+	proxies := make([]*url.URL, 0, len(rawURLs))
+	for _, raw := range rawURLs {
+		raw = strings.TrimSpace(raw)
+		if !strings.Contains(raw, "://") {
+			raw = "http://" + raw
+		}
+		u, err := url.Parse(raw)
+		if err != nil {
+			return nil, err
+		}
+		if u.Host == "" {
+			return nil, errors.New("owl: proxy URL without a host: " + raw)
+		}
+		proxies = append(proxies, u)
+	}
+	if len(proxies) == 1 {
+		return ProxyURL(proxies[0]), nil
+	}
+	return RotateProxies(proxies...), nil
+}
+
+// SetProxy sends the requests of c through proxy, or directly when proxy is nil
+func (c *Client) SetProxy(proxy Proxy) error {
+	t, err := c.transport()
+	if err != nil {
+		return err
+	}
+	t.Proxy = proxy
+	return nil
+}
+
+// transport returns the *http.Transport of c to be configured. It is a clone of the one c had,
+// http.DefaultTransport when it had none, set on a copy of the http.Client of c,
+// so transports and clients shared with other code are left alone
+func (c *Client) transport() (*http.Transport, error) {
+	if c.Client != nil && c.owned != nil && c.Client == c.ownedClient && c.Transport == c.owned {
+		return c.owned, nil
+	}
+	var owned *http.Transport
+	switch t := c.httpClient().Transport.(type) {
+	case nil:
+		owned = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		owned = t.Clone()
+	default:
+		return nil, ErrNoTransport
+	}
+	c.ownClient()
+	c.owned = owned
+	c.Transport = c.owned
+	return c.owned, nil
+}
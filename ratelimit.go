@@ -0,0 +1,79 @@
+package owl
+
+import (
+	"context"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimit limits how often a Client sends requests to each host
+type RateLimit struct {
+	// Every is the time between two requests to the same host once the burst is spent,
+	// requests are not limited when it is 0
+	Every time.Duration
+	// Burst is the number of requests sent to a host without waiting, 1 when 0
+	Burst int
+	// MinDelay and MaxDelay add a random delay between them before every request, on top of the limit
+	MinDelay time.Duration
+	MaxDelay time.Duration
+}
+
+// HostLimiter is a RateLimiter enforcing a RateLimit with a token bucket per host
+type HostLimiter struct {
+	limit RateLimit
+
+	mu    sync.Mutex
+	hosts map[string]*bucket
+}
+
+// bucket holds the tokens of a host, which can go negative for the requests waiting for one
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+var _ RateLimiter = (*HostLimiter)(nil)
+
+// NewRateLimiter returns a HostLimiter enforcing limit
+func NewRateLimiter(limit RateLimit) *HostLimiter {
+	if limit.Burst < 1 {
+		limit.Burst = 1
+	}
+	return &HostLimiter{limit: limit, hosts: make(map[string]*bucket)}
+}
+
+// Wait blocks until a request to host may be sent, it returns the error of ctx when ctx is done first
+func (l *HostLimiter) Wait(ctx context.Context, host string) error {
+	return sleep(ctx, l.reserve(strings.ToLower(host), time.Now())+l.delay())
+}
+
+// reserve takes a token of host and returns how long to wait for it
+func (l *HostLimiter) reserve(host string, now time.Time) time.Duration {
+	if l.limit.Every <= 0 {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b, ok := l.hosts[host]
+	if !ok {
+		b = &bucket{tokens: float64(l.limit.Burst), last: now}
+		l.hosts[host] = b
+	}
+	b.tokens = min(float64(l.limit.Burst), b.tokens+float64(now.Sub(b.last))/float64(l.limit.Every))
+	b.last = now
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens * float64(l.limit.Every))
+}
+
+// delay returns a random delay between MinDelay and MaxDelay
+func (l *HostLimiter) delay() time.Duration {
+	if l.limit.MaxDelay <= l.limit.MinDelay {
+		return max(l.limit.MinDelay, 0)
+	}
+	return l.limit.MinDelay + rand.N(l.limit.MaxDelay-l.limit.MinDelay)
+}
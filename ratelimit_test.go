@@ -0,0 +1,47 @@
+package owl
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiterReserve(t *testing.T) {
+	l := NewRateLimiter(RateLimit{Every: 2 * time.Second, Burst: 2})
+	now := time.Now()
+	require.Equal(t, time.Duration(0), l.reserve("a", now))
+	require.Equal(t, time.Duration(0), l.reserve("a", now))
+	require.Equal(t, 2*time.Second, l.reserve("a", now))
+	require.Equal(t, 4*time.Second, l.reserve("a", now))
+	// Hosts have buckets of their own
+	require.Equal(t, time.Duration(0), l.reserve("b", now))
+	// Tokens come back over time
+	require.Equal(t, 3*time.Second, l.reserve("a", now.Add(3*time.Second)))
+	require.Equal(t, time.Duration(0), l.reserve("b", now.Add(time.Second)))
+
+	require.Equal(t, time.Duration(0), NewRateLimiter(RateLimit{}).reserve("a", now))
+	for i := 0; i < 20; i++ {
+		d := NewRateLimiter(RateLimit{MinDelay: time.Second, MaxDelay: 2 * time.Second}).delay()
+		require.True(t, d >= time.Second && d < 2*time.Second, d)
+	}
+}
+
+func TestRateLimiterWait(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	client := &Client{Client: srv.Client(), Limiter: NewRateLimiter(RateLimit{Every: 50 * time.Millisecond})}
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		_, err := client.GetResponse(srv.URL)
+		require.NoError(t, err)
+	}
+	require.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, NewRateLimiter(RateLimit{MinDelay: time.Hour}).Wait(ctx, "example.com"), context.Canceled)
+}
@@ -0,0 +1,190 @@
+package owl
+
+import (
+	"bytes"
+	"io"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// rawWindow bounds how many source text tokens are skipped looking for the match of a text node,
+// the parser drops or moves some text, such as whitespace between head and body
+const rawWindow = 8
+
+// HTMLParseKeepRaw parses like HTMLParse and also keeps the source text of every text node,
+// with character references left encoded, for RawText, RawFullText and RenderRaw
+func HTMLParseKeepRaw(r io.Reader) *Root {
+	dr, release, err := decompress(r)
+	if err != nil {
+		return &Root{Node: nil, NodeValue: "", Error: newError(ErrUnableToParse, err)}
+	}
+	defer release()
+	src, err := io.ReadAll(dr)
+	if err != nil {
+		return &Root{Node: nil, NodeValue: "", Error: newError(ErrUnableToParse, err)}
+	}
+	root := htmlparsing(bytes.NewReader(src))
+	if root.Error != nil {
+		return root
+	}
+	top := root.Node
+	for top.Parent != nil {
+		top = top.Parent
+	}
+	root.doc.raw = rawTextNodes(top, src)
+	return root
+}
+
+func HTMLParseKeepRawFromString(s string) *Root {
+	return HTMLParseKeepRaw(strings.NewReader(s))
+}
+
+// RawText returns the source text of the string Text returns, with its character references still encoded.
+// It reports false when the document was not parsed by HTMLParseKeepRaw or the source could not be matched
+func (r *Root) RawText() (string, bool) {
+	for c := r.Node.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.TextNode && !isBlank(c.Data) {
+			return r.rawText(c)
+		}
+	}
+	return "", r.doc != nil && r.doc.raw != nil
+}
+
+// RawFullText returns the source text of the string FullText returns, with its character references still encoded.
+// It reports false when the source of any of its text nodes is unknown
+func (r *Root) RawFullText() (string, bool) {
+	var b strings.Builder
+	ok := r.doc != nil && r.doc.raw != nil
+	var f func(*html.Node)
+	f = func(n *html.Node) {
+		for c := n.FirstChild; c != nil && ok; c = c.NextSibling {
+			switch c.Type {
+			case html.TextNode:
+				var s string
+				s, ok = r.rawText(c)
+				b.WriteString(s)
+			case html.ElementNode:
+				f(c)
+			}
+		}
+	}
+	f(r.Node)
+	if !ok {
+		return "", false
+	}
+	return b.String(), true
+}
+
+func (r *Root) rawText(n *html.Node) (string, bool) {
+	if r.doc == nil || r.doc.raw == nil {
+		return "", false
+	}
+	s, ok := r.doc.raw[n]
+	return s, ok
+}
+
+// RenderRaw returns the HTML code for the specific element like Render,
+// but writes text nodes as they were found in the source instead of re-encoding them.
+// Text nodes with an unknown source are encoded the way Render does
+func (r Root) RenderRaw() []byte {
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	if err := r.RenderRawTo(buf); err != nil {
+		return nil
+	}
+	return append([]byte(nil), buf.Bytes()...)
+}
+
+// RenderRawTo writes the HTML code for the specific element to w like RenderRaw
+func (r Root) RenderRawTo(w io.Writer) error {
+	if r.doc == nil || r.doc.raw == nil {
+		return r.RenderTo(w)
+	}
+	return html.Render(w, rawCopy(r.Node, r.doc.raw))
+}
+
+// rawCopy copies the tree of n turning text nodes with a known source into raw nodes
+func rawCopy(n *html.Node, raw map[*html.Node]string) *html.Node {
+	c := &html.Node{
+		Type:      n.Type,
+		DataAtom:  n.DataAtom,
+		Data:      n.Data,
+		Namespace: n.Namespace,
+		Attr:      n.Attr,
+	}
+	if s, ok := raw[n]; ok && n.Type == html.TextNode {
+		c.Type = html.RawNode
+		c.Data = s
+	}
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		c.AppendChild(rawCopy(child, raw))
+	}
+	return c
+}
+
+// textToken is a text token of the source, text is decoded the way the parser sees it
+type textToken struct {
+	text string
+	raw  string
+}
+
+// rawTextNodes matches the text nodes below top to the text tokens of src, both are in document order
+func rawTextNodes(top *html.Node, src []byte) map[*html.Node]string {
+	var tokens []textToken
+	z := html.NewTokenizer(bytes.NewReader(src))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+		if tt == html.TextToken {
+			// Text unescapes in place, the source must be copied first
+			raw := string(z.Raw())
+			tokens = append(tokens, textToken{raw: raw, text: string(z.Text())})
+		}
+	}
+
+	raw := make(map[*html.Node]string)
+	next := 0
+	walk(top, func(n *html.Node) bool {
+		if n.Type != html.TextNode {
+			return true
+		}
+		for i := next; i < len(tokens) && i < next+rawWindow; i++ {
+			if s, end, ok := matchTokens(n.Data, tokens, i); ok {
+				raw[n] = s
+				next = end
+				break
+			}
+		}
+		return false
+	})
+	return raw
+}
+
+// matchTokens reports whether text is made of the tokens starting at i,
+// it returns their source and the index of the first token after them
+func matchTokens(text string, tokens []textToken, i int) (string, int, bool) {
+	t := tokens[i]
+	// The parser drops a newline right after the start tag of pre, listing and textarea
+	if t.text != text && strings.HasPrefix(t.text, "\n") && t.text[1:] == text {
+		raw := strings.TrimPrefix(strings.TrimPrefix(t.raw, "\r"), "\n")
+		return raw, i + 1, true
+	}
+	// The parser merges text split by tags it ignores into a single node
+	var s strings.Builder
+	rest := text
+	for j := i; j < len(tokens) && strings.HasPrefix(rest, tokens[j].text); j++ {
+		if tokens[j].text == "" {
+			break
+		}
+		s.WriteString(tokens[j].raw)
+		rest = rest[len(tokens[j].text):]
+		if rest == "" {
+			return s.String(), j + 1, true
+		}
+	}
+	return "", i, false
+}
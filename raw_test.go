@@ -0,0 +1,51 @@
+package owl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const rawHTML = `<html><head><title>Smith &amp; Sons</title></head>
+<body>
+<p id="clause">Section&nbsp;2 &#8212; &quot;Terms&quot; <b>&lt;void&gt;</b> apply</p>
+<pre>
+  indented &amp; kept</pre>
+<script>if (a < b && c) {}</script>
+</body></html>`
+
+func TestRawText(t *testing.T) {
+	root := HTMLParseKeepRawFromString(rawHTML)
+	require.Nil(t, root.Error)
+
+	p := root.Find("p", "id", "clause")
+	require.Equal(t, "Section 2 — \"Terms\" ", p.Text())
+	raw, ok := p.RawText()
+	require.True(t, ok)
+	require.Equal(t, "Section&nbsp;2 &#8212; &quot;Terms&quot; ", raw)
+
+	full, ok := p.RawFullText()
+	require.True(t, ok)
+	require.Equal(t, "Section&nbsp;2 &#8212; &quot;Terms&quot; &lt;void&gt; apply", full)
+
+	raw, ok = root.Find("title").RawText()
+	require.True(t, ok)
+	require.Equal(t, "Smith &amp; Sons", raw)
+
+	raw, ok = root.Find("pre").RawText()
+	require.True(t, ok)
+	require.Equal(t, "  indented &amp; kept", raw)
+
+	// Documents parsed without keeping the source have no raw text
+	_, ok = HTMLParseFromString(rawHTML).Find("p").RawText()
+	require.False(t, ok)
+}
+
+func TestRenderRaw(t *testing.T) {
+	p := HTMLParseKeepRawFromString(rawHTML).Find("p", "id", "clause")
+	require.Equal(t, "<p id=\"clause\">Section\u00a02 \u2014 &#34;Terms&#34; <b>&lt;void&gt;</b> apply</p>", string(p.Render()))
+	require.Equal(t, `<p id="clause">Section&nbsp;2 &#8212; &quot;Terms&quot; <b>&lt;void&gt;</b> apply</p>`, string(p.RenderRaw()))
+
+	script := HTMLParseKeepRawFromString(rawHTML).Find("script")
+	require.Equal(t, string(script.Render()), string(script.RenderRaw()))
+}
@@ -0,0 +1,106 @@
+package readability
+
+import (
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+// publishedLayouts are tried in order to parse publication dates
+var publishedLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	time.RFC1123Z,
+	time.RFC1123,
+	"January 2, 2006",
+	"2 January 2006",
+}
+
+// readMetadata fills title, byline, publication time, excerpt and site name
+// from the meta tags, title and time elements of the document
+func readMetadata(doc *html.Node, article *Article) {
+	meta := make(map[string]string)
+	var title, h1, published string
+	walkElements(doc, func(n *html.Node) {
+		switch n.Data {
+		case "meta":
+			key := strings.ToLower(attr(n, "property"))
+			if key == "" {
+				key = strings.ToLower(attr(n, "name"))
+			}
+			if key == "" {
+				key = strings.ToLower(attr(n, "itemprop"))
+			}
+			if _, ok := meta[key]; !ok && key != "" {
+				meta[key] = strings.TrimSpace(attr(n, "content"))
+			}
+		case "title":
+			if title == "" {
+				title = collapse(textOf(n))
+			}
+		case "h1":
+			if h1 == "" {
+				h1 = collapse(textOf(n))
+			}
+		case "time":
+			if published == "" && attr(n, "datetime") != "" {
+				published = attr(n, "datetime")
+			}
+		}
+	})
+
+	article.Title = first(meta["og:title"], meta["twitter:title"], meta["title"], titleOf(title, h1))
+	article.Byline = first(meta["author"], meta["article:author"], meta["dc.creator"])
+	article.Excerpt = first(meta["description"], meta["og:description"], meta["twitter:description"])
+	article.SiteName = meta["og:site_name"]
+	for _, v := range []string{meta["article:published_time"], meta["datepublished"], meta["date"],
+		meta["pubdate"], meta["publish-date"], meta["dc.date"], published} {
+		if t, ok := parseTime(v); ok {
+			article.Published = t
+			break
+		}
+	}
+}
+
+// titleOf drops the site name commonly appended to the title of the page,
+// when the first heading of the page is a better match
+func titleOf(title, h1 string) string {
+	if title == "" {
+		return h1
+	}
+	for _, sep := range []string{" | ", " - ", " – ", " — ", " :: ", " » "} {
+		if i := strings.LastIndex(title, sep); i > 0 {
+			head := title[:i]
+			if h1 == "" || strings.EqualFold(head, h1) || len(strings.Fields(head)) >= 3 {
+				return head
+			}
+		}
+	}
+	return title
+}
+
+func parseTime(v string) (time.Time, bool) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range publishedLayouts {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func first(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
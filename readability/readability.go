@@ -0,0 +1,385 @@
+// Package readability extracts the main article of a page parsed by owl,
+// leaving out navigation, advertisements and other boilerplate.
+// It follows the text density heuristics of Mozilla's Readability
+package readability
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Patrickmitech/owl"
+	"golang.org/x/net/html"
+)
+
+// ErrNoArticle is returned when no part of the page looks like an article
+var ErrNoArticle = errors.New("readability: no article content found")
+
+// Article is the main content of a page along with its metadata
+type Article struct {
+	Title  string
+	Byline string
+	// Published is the zero time when the page does not tell when it was published
+	Published time.Time
+	Excerpt   string
+	SiteName  string
+	// Content is a div holding the article elements, detached from the original document
+	Content *owl.Root
+	// Text is the plain text of Content
+	Text string
+}
+
+var (
+	unlikelyCandidates = regexp.MustCompile(`(?i)-ad-|ai2html|banner|breadcrumbs|combx|comment|community|cover-wrap|disqus|extra|footer|gdpr|header|legends|menu|related|remark|replies|rss|shoutbox|sidebar|skyscraper|social|sponsor|supplemental|ad-break|agegate|pagination|pager|popup|yom-remote|cookie|newsletter|share`)
+	maybeCandidate     = regexp.MustCompile(`(?i)and|article|body|column|content|main|shadow`)
+	positiveClass      = regexp.MustCompile(`(?i)article|body|content|entry|hentry|h-entry|main|page|pagination|post|text|blog|story`)
+	negativeClass      = regexp.MustCompile(`(?i)-ad-|hidden|^hid$| hid$| hid |^hid |banner|combx|comment|com-|contact|foot|footer|footnote|gdpr|masthead|media|meta|outbrain|promo|related|scroll|share|shoutbox|sidebar|skyscraper|sponsor|shopping|tags|tool|widget`)
+	bylineClass        = regexp.MustCompile(`(?i)byline|author|dateline|writtenby|p-author`)
+)
+
+// removedTags never hold article content
+var removedTags = map[string]bool{
+	"script": true, "style": true, "noscript": true, "template": true, "iframe": true,
+	"form": true, "button": true, "input": true, "select": true, "textarea": true,
+	"nav": true, "aside": true, "footer": true, "svg": true, "object": true, "embed": true,
+}
+
+// scoredTags hold the paragraphs whose text is scored
+var scoredTags = map[string]bool{
+	"p": true, "pre": true, "td": true, "section": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+}
+
+// minParagraphLength is the text length below which paragraphs are not scored
+const minParagraphLength = 25
+
+// ExtractArticle finds the main article of root. The document of root is left untouched,
+// the article is extracted from a copy of it
+func ExtractArticle(root *owl.Root) (*Article, error) {
+	if root == nil || root.Node == nil {
+		return nil, ErrNoArticle
+	}
+	doc := owl.HTMLParse(bytes.NewReader(root.Render()))
+	if doc.Error != nil {
+		return nil, doc.Error.Err()
+	}
+
+	article := &Article{}
+	readMetadata(doc.Node, article)
+
+	prune(doc.Node, article, new(bool))
+	top := topCandidate(doc.Node)
+	if top == nil {
+		return nil, ErrNoArticle
+	}
+	content := gather(top)
+	clean(content)
+
+	article.Content = &owl.Root{Node: content, NodeValue: content.Data}
+	article.Text = article.Content.PlainText()
+	if strings.TrimSpace(article.Text) == "" {
+		return nil, ErrNoArticle
+	}
+	if article.Excerpt == "" {
+		if p := article.Content.Find("p"); p.Error == nil {
+			article.Excerpt = strings.TrimSpace(p.FullTextOpts(owl.TextOptions{CollapseWhitespace: true}))
+		}
+	}
+	return article, nil
+}
+
+// prune removes elements that never belong to an article and takes the first byline out of the tree,
+// it is used when the metadata had no author
+func prune(n *html.Node, article *Article, byline *bool) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		if c.Type == html.CommentNode {
+			n.RemoveChild(c)
+			c = next
+			continue
+		}
+		if c.Type != html.ElementNode {
+			c = next
+			continue
+		}
+		match := attr(c, "class") + " " + attr(c, "id")
+		switch {
+		case removedTags[c.Data]:
+			n.RemoveChild(c)
+		case !*byline && isByline(c, match):
+			*byline = true
+			if article.Byline == "" {
+				article.Byline = collapse(textOf(c))
+			}
+			n.RemoveChild(c)
+		case c.Data != "body" && c.Data != "a" && c.Data != "article" &&
+			unlikelyCandidates.MatchString(match) && !maybeCandidate.MatchString(match):
+			n.RemoveChild(c)
+		default:
+			prune(c, article, byline)
+		}
+		c = next
+	}
+}
+
+func isByline(n *html.Node, match string) bool {
+	if attr(n, "rel") == "author" || strings.Contains(attr(n, "itemprop"), "author") || bylineClass.MatchString(match) {
+		text := strings.TrimSpace(textOf(n))
+		return text != "" && len(text) < 100
+	}
+	return false
+}
+
+// topCandidate scores the ancestors of every paragraph by the amount of text below them
+// and returns the one with the best score once link density is accounted for
+func topCandidate(body *html.Node) *html.Node {
+	scores := make(map[*html.Node]float64)
+	var candidates []*html.Node
+	initialize := func(n *html.Node) {
+		if _, ok := scores[n]; ok {
+			return
+		}
+		scores[n] = tagWeight(n) + classWeight(n)
+		candidates = append(candidates, n)
+	}
+
+	var paragraphs []*html.Node
+	walkElements(body, func(n *html.Node) {
+		if scoredTags[n.Data] || (n.Data == "div" && !hasBlockChildren(n)) {
+			paragraphs = append(paragraphs, n)
+		}
+	})
+	for _, p := range paragraphs {
+		text := collapse(textOf(p))
+		if len(text) < minParagraphLength || p.Parent == nil || p.Parent.Type != html.ElementNode {
+			continue
+		}
+		score := 1 + float64(strings.Count(text, ",")) + float64(min(len(text)/100, 3))
+		// Every ancestor up to three levels gets a share of the score that shrinks with the distance
+		level := 0
+		for a := p.Parent; a != nil && a.Type == html.ElementNode && level < 3; a = a.Parent {
+			initialize(a)
+			divider := 1.0
+			if level == 1 {
+				divider = 2
+			} else if level > 1 {
+				divider = float64(level * 3)
+			}
+			scores[a] += score / divider
+			level++
+		}
+	}
+
+	var top *html.Node
+	best := 0.0
+	for _, c := range candidates {
+		scores[c] *= 1 - linkDensity(c)
+		if top == nil || scores[c] > best {
+			top, best = c, scores[c]
+		}
+	}
+	if top == nil {
+		return nil
+	}
+	// A lone element wrapping the candidate holds the same content, keep the wider one
+	for top.Parent != nil && top.Parent.Type == html.ElementNode && top.Parent.Data != "body" &&
+		top.Parent.FirstChild == top.Parent.LastChild {
+		top = top.Parent
+	}
+	scores[top] = best
+	return withSiblings(top, scores)
+}
+
+// withSiblings returns top or, when siblings of top look like part of the article too,
+// their common parent trimmed down to top and those siblings
+func withSiblings(top *html.Node, scores map[*html.Node]float64) *html.Node {
+	if top.Parent == nil || top.Data == "body" {
+		return top
+	}
+	threshold := scores[top] * 0.2
+	if threshold < 10 {
+		threshold = 10
+	}
+	topClass := attr(top, "class")
+	var keep []*html.Node
+	for s := top.Parent.FirstChild; s != nil; s = s.NextSibling {
+		if s.Type != html.ElementNode {
+			continue
+		}
+		if s == top {
+			keep = append(keep, s)
+			continue
+		}
+		bonus := 0.0
+		if topClass != "" && attr(s, "class") == topClass {
+			bonus = scores[top] * 0.2
+		}
+		if score, ok := scores[s]; ok && score+bonus >= threshold {
+			keep = append(keep, s)
+			continue
+		}
+		if s.Data == "p" {
+			text := collapse(textOf(s))
+			density := linkDensity(s)
+			if (len(text) > 80 && density < 0.25) ||
+				(len(text) > 0 && len(text) <= 80 && density == 0 && strings.Contains(text, ". ")) {
+				keep = append(keep, s)
+			}
+		}
+	}
+	if len(keep) == 1 {
+		return top
+	}
+	wrapper := &html.Node{Type: html.ElementNode, Data: "div"}
+	for _, s := range keep {
+		s.Parent.RemoveChild(s)
+		wrapper.AppendChild(s)
+	}
+	return wrapper
+}
+
+// gather detaches n into a div of its own
+func gather(n *html.Node) *html.Node {
+	if n.Parent != nil {
+		n.Parent.RemoveChild(n)
+	}
+	if n.Data == "div" && len(n.Attr) == 0 {
+		return n
+	}
+	wrapper := &html.Node{Type: html.ElementNode, Data: "div"}
+	wrapper.AppendChild(n)
+	return wrapper
+}
+
+// clean removes lists, tables and divs inside the content that are mostly links or have little text
+func clean(content *html.Node) {
+	var suspects []*html.Node
+	walkElements(content, func(n *html.Node) {
+		switch n.Data {
+		case "ul", "ol", "table", "div", "section", "header":
+			suspects = append(suspects, n)
+		}
+	})
+	// Deepest elements are checked first so their parents are judged on what remains
+	for i := len(suspects) - 1; i >= 0; i-- {
+		n := suspects[i]
+		if n == content || n.Parent == nil {
+			continue
+		}
+		if classWeight(n) < 0 {
+			n.Parent.RemoveChild(n)
+			continue
+		}
+		text := collapse(textOf(n))
+		density := linkDensity(n)
+		images := countTags(n, "img")
+		paragraphs := countTags(n, "p")
+		switch {
+		case text == "" && images == 0:
+			n.Parent.RemoveChild(n)
+		case density > 0.5 && len(text) < 1000:
+			n.Parent.RemoveChild(n)
+		case n.Data != "ul" && n.Data != "ol" && images > 1 && paragraphs < images/2:
+			n.Parent.RemoveChild(n)
+		case strings.Count(text, ",") < 10 && len(text) < minParagraphLength && images == 0 && n.Data != "table":
+			n.Parent.RemoveChild(n)
+		}
+	}
+}
+
+func tagWeight(n *html.Node) float64 {
+	switch n.Data {
+	case "div", "article":
+		return 5
+	case "pre", "td", "blockquote":
+		return 3
+	case "address", "ol", "ul", "dl", "dd", "dt", "li", "form":
+		return -3
+	case "h1", "h2", "h3", "h4", "h5", "h6", "th":
+		return -5
+	}
+	return 0
+}
+
+// classWeight rewards class and id names hinting at content and penalizes those hinting at boilerplate
+func classWeight(n *html.Node) float64 {
+	weight := 0.0
+	for _, v := range []string{attr(n, "class"), attr(n, "id")} {
+		if v == "" {
+			continue
+		}
+		if negativeClass.MatchString(v) {
+			weight -= 25
+		}
+		if positiveClass.MatchString(v) {
+			weight += 25
+		}
+	}
+	return weight
+}
+
+// linkDensity is the share of the text of n inside links
+func linkDensity(n *html.Node) float64 {
+	total := len(collapse(textOf(n)))
+	if total == 0 {
+		return 0
+	}
+	links := 0
+	walkElements(n, func(a *html.Node) {
+		if a.Data == "a" {
+			links += len(collapse(textOf(a)))
+		}
+	})
+	return float64(links) / float64(total)
+}
+
+func hasBlockChildren(n *html.Node) bool {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+		switch c.Data {
+		case "a", "blockquote", "dl", "div", "img", "ol", "p", "pre", "table", "ul", "section", "article":
+			return true
+		}
+	}
+	return false
+}
+
+func countTags(n *html.Node, tag string) int {
+	count := 0
+	walkElements(n, func(c *html.Node) {
+		if c.Data == tag {
+			count++
+		}
+	})
+	return count
+}
+
+// walkElements calls f for every element below n in document order
+func walkElements(n *html.Node, f func(*html.Node)) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode {
+			f(c)
+			walkElements(c, f)
+		}
+	}
+}
+
+func textOf(n *html.Node) string {
+	return (&owl.Root{Node: n}).FullText()
+}
+
+func collapse(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
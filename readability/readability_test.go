@@ -0,0 +1,62 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Patrickmitech/owl"
+	"github.com/stretchr/testify/require"
+)
+
+const articleHTML = `<!DOCTYPE html>
+<html>
+<head>
+  <title>Owls hunt at night | Nature Weekly</title>
+  <meta name="description" content="Why owls are so good at hunting in the dark.">
+  <meta property="article:published_time" content="2022-04-03T10:00:00Z">
+  <script>track()</script>
+</head>
+<body>
+  <nav class="menu"><a href="/">Home</a> <a href="/birds">Birds</a> <a href="/about">About</a></nav>
+  <div id="sidebar-ads" class="sidebar">
+    <p>Subscribe now, get three months free, limited offer for all our readers!</p>
+  </div>
+  <main>
+    <article class="post">
+      <h1>Owls hunt at night</h1>
+      <p class="byline">By Jane Roe</p>
+      <p>Owls are birds from the order Strigiformes, which includes over 200 species of mostly solitary and nocturnal birds of prey.</p>
+      <p>They have large forward-facing eyes, a hawk-like beak, a flat face, and usually a conspicuous circle of feathers, a facial disc, around each eye.</p>
+      <p>Their hearing is so good that, even in complete darkness, they can catch prey by sound alone, tilting their heads to locate it.</p>
+      <ul class="share"><li><a href="/share/twitter">Tweet</a></li><li><a href="/share/fb">Share</a></li></ul>
+    </article>
+  </main>
+  <footer><p>Copyright Nature Weekly, all rights reserved, 2022, everywhere.</p></footer>
+</body>
+</html>`
+
+func TestExtractArticle(t *testing.T) {
+	root := owl.HTMLParseFromString(articleHTML)
+	article, err := ExtractArticle(root)
+	require.NoError(t, err)
+
+	require.Equal(t, "Owls hunt at night", article.Title)
+	require.Equal(t, "By Jane Roe", article.Byline)
+	require.Equal(t, time.Date(2022, 4, 3, 10, 0, 0, 0, time.UTC), article.Published)
+	require.Equal(t, "Why owls are so good at hunting in the dark.", article.Excerpt)
+
+	require.Contains(t, article.Text, "Strigiformes")
+	require.Contains(t, article.Text, "catch prey by sound alone")
+	for _, boilerplate := range []string{"Subscribe", "Copyright", "Tweet", "Home", "track()", "Jane Roe"} {
+		require.False(t, strings.Contains(article.Text, boilerplate), boilerplate)
+	}
+
+	// The original document is left untouched
+	require.NotNil(t, root.Find("nav").Node)
+}
+
+func TestExtractArticleEmpty(t *testing.T) {
+	_, err := ExtractArticle(owl.HTMLParseFromString(`<html><body><nav><a href="/">Home</a></nav></body></html>`))
+	require.ErrorIs(t, err, ErrNoArticle)
+}
@@ -0,0 +1,116 @@
+package owl
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// jsLocation matches the trivial JavaScript redirects of inline scripts, such as
+// window.location = "/new" or location.replace('/new')
+var jsLocation = regexp.MustCompile(`(?:\b(?:window|document|self|top)\.)?\blocation(?:\.href)?\s*=\s*(["'])([^"']+)["']` +
+	`|(?:\b(?:window|document|self|top)\.)?\blocation\.(?:replace|assign)\(\s*(["'])([^"']+)["']\s*\)`)
+
+// Redirect returns the URL the document redirects to with a meta refresh, or else with a trivial
+// JavaScript location change in an inline script. Refreshes without a URL, which reload the page, are ignored
+func (r *Root) Redirect() (*url.URL, bool) {
+	var meta, script string
+	walk(r.Node, func(n *html.Node) bool {
+		if n.Type != html.ElementNode {
+			return true
+		}
+		switch {
+		case n.Data == "meta" && meta == "" && strings.EqualFold(strings.TrimSpace(attrValue(n, "http-equiv")), "refresh"):
+			meta = parseRefresh(attrValue(n, "content"))
+		case n.Data == "script" && script == "" && !hasAttr(n, "src") && n.FirstChild != nil:
+			if m := jsLocation.FindStringSubmatch(n.FirstChild.Data); m != nil {
+				script = m[2] + m[4]
+			}
+		case n.Data == "noscript":
+			return false
+		}
+		return true
+	})
+	for _, target := range []string{meta, script} {
+		if target == "" {
+			continue
+		}
+		if u, err := r.ResolveURL(target); err == nil {
+			return u, true
+		}
+	}
+	return nil, false
+}
+
+// parseRefresh returns the URL of the content of a meta refresh or of a Refresh header,
+// such as 5; url='/new', an empty string when it has none
+func parseRefresh(content string) string {
+	s := strings.TrimLeft(content, " \t\n\r\f")
+	s = strings.TrimLeft(s, "0123456789.")
+	s = strings.TrimLeft(s, " \t\n\r\f")
+	if s == "" || (s[0] != ';' && s[0] != ',') {
+		return ""
+	}
+	s = strings.TrimLeft(s[1:], " \t\n\r\f")
+	if len(s) >= 3 && strings.EqualFold(s[:3], "url") {
+		if rest := strings.TrimLeft(s[3:], " \t\n\r\f"); strings.HasPrefix(rest, "=") {
+			s = strings.TrimLeft(rest[1:], " \t\n\r\f")
+		}
+	}
+	if s != "" && (s[0] == '"' || s[0] == '\'') {
+		if end := strings.IndexByte(s[1:], s[0]); end >= 0 {
+			s = s[1 : end+1]
+		} else {
+			s = s[1:]
+		}
+	}
+	return strings.TrimSpace(s)
+}
+
+// refreshTarget returns the URL r redirects to with a Refresh header, a meta refresh or a JavaScript redirect
+func (r *Response) refreshTarget() (*url.URL, bool) {
+	if target := parseRefresh(r.Header.Get("Refresh")); target != "" && r.FinalURL != nil {
+		if u, err := r.FinalURL.Parse(target); err == nil {
+			return u, true
+		}
+	}
+	// Documents are often served as text/plain by mistake, other types are not parsed
+	if !isHTML(r.ContentType) {
+		return nil, false
+	}
+	return r.Parse().Redirect()
+}
+
+// followRefreshes follows the refreshes of r up to MaxRefreshes times with GET requests carrying the headers,
+// context and timeout of cfg
+func (c *Client) followRefreshes(r *Response, cfg *requestConfig) (*Response, error) {
+	var opts []RequestOption
+	for key, values := range cfg.header {
+		opts = append(opts, WithHeader(key, values[0]))
+	}
+	if cfg.ctx != nil {
+		opts = append(opts, WithContext(cfg.ctx))
+	}
+	if cfg.timeout != nil {
+		opts = append(opts, WithTimeout(*cfg.timeout))
+	}
+	for hops := 0; hops < c.MaxRefreshes; hops++ {
+		if r.StatusCode < 200 || r.StatusCode > 299 {
+			break
+		}
+		target, ok := r.refreshTarget()
+		if !ok || (r.FinalURL != nil && sameURL(target, r.FinalURL)) {
+			break
+		}
+		c.debug("owl: refresh", "from", r.FinalURL, "to", target.String())
+		next, err := c.fetch(http.MethodGet, target.String(), nil, opts...)
+		if err != nil {
+			return nil, err
+		}
+		r = next
+	}
+	return r, nil
+}
@@ -0,0 +1,87 @@
+package owl
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRefresh(t *testing.T) {
+	for content, want := range map[string]string{
+		"0; url=http://example.com/":     "http://example.com/",
+		"5;URL='/next page'":             "/next page",
+		` 3 , url = "/quoted" `:          "/quoted",
+		"0;/bare":                        "/bare",
+		"1.5; url=/fraction":             "/fraction",
+		"30":                             "",
+		"":                               "",
+		"url=/missing-delay-is-accepted": "",
+	} {
+		require.Equal(t, want, parseRefresh(content), content)
+	}
+}
+
+func TestRedirect(t *testing.T) {
+	base, _ := url.Parse("https://example.com/dir/page")
+	for doc, want := range map[string]string{
+		`<meta http-equiv="Refresh" content="0; url=../moved">`:                            "https://example.com/moved",
+		`<script>window.location.href = "/js";</script>`:                                   "https://example.com/js",
+		`<script>if (old) { location.replace('https://new.example/') }</script>`:           "https://new.example/",
+		`<meta http-equiv="refresh" content="0;url=/meta"><script>location="/js"</script>`: "https://example.com/meta",
+	} {
+		u, ok := HTMLParseFromString(doc).SetURL(base).Redirect()
+		require.True(t, ok, doc)
+		require.Equal(t, want, u.String(), doc)
+	}
+	for _, doc := range []string{
+		`<meta http-equiv="refresh" content="60">`,
+		`<script src="/app.js"></script>`,
+		`<script>if (location.hash == "#x") {}</script>`,
+		`<noscript><meta http-equiv="refresh" content="0; url=/nojs"></noscript>`,
+	} {
+		_, ok := HTMLParseFromString(doc).SetURL(base).Redirect()
+		require.False(t, ok, doc)
+	}
+}
+
+func TestFollowRefreshes(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/start", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<meta http-equiv="refresh" content="0; url=/js">`)
+	})
+	mux.HandleFunc("/js", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<script>window.location = "/header"</script>`)
+	})
+	mux.HandleFunc("/header", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Refresh", "0; url=/end")
+		fmt.Fprint(w, `<title>Header</title>`)
+	})
+	mux.HandleFunc("/end", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<title>End %s</title><meta http-equiv="refresh" content="0; url=/end">`, r.Header.Get("X-Owl"))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := &Client{Client: srv.Client()}
+	doc, err := client.GetDocument(srv.URL + "/start")
+	require.NoError(t, err)
+	require.Equal(t, srv.URL+"/start", doc.URL().String())
+
+	client.MaxRefreshes = 2
+	doc, err = client.GetDocument(srv.URL + "/start")
+	require.NoError(t, err)
+	require.Equal(t, "Header", doc.Title().Text())
+
+	// Refreshing to the same page stops the chain
+	client.MaxRefreshes = 10
+	doc, err = client.GetDocument(srv.URL + "/start")
+	require.NoError(t, err)
+	require.Equal(t, srv.URL+"/end", doc.URL().String())
+	doc, err = client.GetDocument(srv.URL+"/start", WithHeader("X-Owl", "hoot"))
+	require.NoError(t, err)
+	require.Equal(t, "End hoot", doc.Title().Text())
+}
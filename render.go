@@ -0,0 +1,73 @@
+package owl
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RendererOptions tell a Renderer how to render a page
+type RendererOptions struct {
+	// Header holds the headers of the Client and of the request, User-Agent included
+	Header http.Header
+	// Cookies are the cookies of the cookie jar of the Client for the URL
+	Cookies []*http.Cookie
+	// WaitSelector is a CSS selector the Renderer waits to match before returning the HTML, see WithWaitSelector.
+	// The HTML is returned once the page is loaded when it is empty
+	WaitSelector string
+	// Timeout limits the rendering, it is the timeout of the request
+	Timeout time.Duration
+}
+
+// WithWaitSelector makes the Renderer of the Client wait for an element matching the CSS selector
+// before returning the page
+func WithWaitSelector(selector string) RequestOption {
+	return requestOptionFunc(func(r *requestConfig) { r.waitSelector = selector })
+}
+
+// WithoutRenderer fetches the page over HTTP even when the Client has a Renderer
+func WithoutRenderer() RequestOption {
+	return requestOptionFunc(func(r *requestConfig) { r.noRender = true })
+}
+
+// render gets the document at url from the Renderer of c
+func (c *Client) render(url string, cfg *requestConfig) (*Root, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	ctx := cfg.context()
+	timeout := c.requestTimeout(cfg)
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	// The request is not sent, it gathers the headers a fetch would send
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	setParameters(req, c)
+	c.authorize(req)
+	cfg.apply(req)
+	if err := c.checkRobots(req); err != nil {
+		return nil, err
+	}
+	opts := RendererOptions{Header: req.Header, WaitSelector: cfg.waitSelector, Timeout: timeout}
+	if jar := c.httpClient().Jar; jar != nil {
+		opts.Cookies = jar.Cookies(req.URL)
+	}
+	start := time.Now()
+	page, err := c.Renderer.Render(ctx, req.URL.String(), opts)
+	if err != nil {
+		c.debug("owl: render failed", "url", req.URL.String(), "error", err)
+		return nil, err
+	}
+	c.debug("owl: render", "url", req.URL.String(), "duration", time.Since(start))
+	root := HTMLParse(strings.NewReader(page))
+	if root.Error != nil {
+		return root, root.Error.Err()
+	}
+	return root.SetURL(req.URL), nil
+}
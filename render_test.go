@@ -0,0 +1,59 @@
+package owl
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testRenderer answers with page and records the options it was given
+type testRenderer struct {
+	page string
+	url  string
+	opts RendererOptions
+}
+
+func (r *testRenderer) Render(ctx context.Context, url string, opts RendererOptions) (string, error) {
+	r.url, r.opts = url, opts
+	if _, ok := ctx.Deadline(); !ok {
+		return "", errors.New("no deadline")
+	}
+	return r.page, nil
+}
+
+type disallowAll struct{}
+
+func (disallowAll) Allowed(string, *url.URL) bool { return false }
+
+func TestRenderer(t *testing.T) {
+	renderer := &testRenderer{page: `<div id="app"><a href="next">Next</a></div>`}
+	jar := NewJar()
+	u, _ := url.Parse("https://example.com/app/")
+	jar.SetCookies(u, []*http.Cookie{{Name: "session", Value: "owl"}})
+	client := NewClient(WithHTTPClient(&http.Client{Jar: jar}), WithUserAgent("owl-test"))
+	client.Renderer = renderer
+
+	doc, err := client.GetDocument("https://example.com/app/", WithWaitSelector("#app a"), WithHeader("X-Api-Key", "secret"))
+	require.NoError(t, err)
+	require.Equal(t, "Next", doc.Find("a").Text())
+	require.Equal(t, "https://example.com/app/", doc.URL().String())
+	require.Equal(t, "#app a", renderer.opts.WaitSelector)
+	require.Equal(t, "owl-test", renderer.opts.Header.Get("User-Agent"))
+	require.Equal(t, "secret", renderer.opts.Header.Get("X-Api-Key"))
+	require.Equal(t, DefaultParameters.RequestTimeout, renderer.opts.Timeout)
+	require.Len(t, renderer.opts.Cookies, 1)
+
+	// Visit renders the pages too
+	_, err = doc.Visit("next", client)
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/app/next", renderer.url)
+
+	client.Robots = disallowAll{}
+	_, err = client.GetDocument("https://example.com/private")
+	require.ErrorIs(t, err, ErrDisallowedByRobots)
+	require.Equal(t, "https://example.com/app/next", renderer.url)
+}
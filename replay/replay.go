@@ -0,0 +1,268 @@
+// Package replay records the HTTP interactions of a client to cassette files and replays them,
+// so tests of scrapers do not depend on live websites.
+// A Transport is used as the transport of the http.Client of an owl.Client:
+//
+//	tr, err := replay.New("testdata/example.json", replay.ModeReplayOrRecord)
+//	client := &owl.Client{Client: &http.Client{Transport: tr}}
+//	...
+//	err = tr.Save()
+package replay
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+)
+
+// ErrNotRecorded is returned for the requests missing from the cassette when replaying
+var ErrNotRecorded = errors.New("replay: request not recorded")
+
+// Mode tells a Transport whether to replay, record or both
+type Mode int
+
+const (
+	// ModeReplay answers from the cassette only, requests missing from it fail with ErrNotRecorded
+	ModeReplay Mode = iota
+	// ModeRecord sends every request and records the interactions, replacing the cassette on Save
+	ModeRecord
+	// ModeReplayOrRecord answers from the cassette and sends and records the requests missing from it
+	ModeReplayOrRecord
+)
+
+// Interaction is a recorded request with its response
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded request. Authorization, cookie and proxy authorization headers are not recorded
+type Request struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+	// Base64 is true when Body is encoded in base64, for bodies that are not UTF-8
+	Base64 bool `json:"base64,omitempty"`
+}
+
+// Response is a recorded response, its body is recorded as sent, before any content decoding
+type Response struct {
+	StatusCode int         `json:"status"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+	// Base64 is true when Body is encoded in base64, for bodies that are not UTF-8
+	Base64 bool `json:"base64,omitempty"`
+}
+
+// sensitiveHeaders are the request headers left out of cassettes
+var sensitiveHeaders = []string{"Authorization", "Cookie", "Proxy-Authorization"}
+
+// Transport is an http.RoundTripper recording and replaying the interactions of a cassette file
+type Transport struct {
+	// Transport sends the requests to record, http.DefaultTransport when nil
+	Transport http.RoundTripper
+	// Match tells if a recorded request answers req, DefaultMatch when nil
+	Match func(req *http.Request, body []byte, recorded *Request) bool
+
+	path string
+	mode Mode
+
+	mu           sync.Mutex
+	interactions []*Interaction
+	// replayed counts the times every interaction was replayed
+	replayed []int
+}
+
+var _ http.RoundTripper = (*Transport)(nil)
+
+// New returns a Transport for the cassette at path. The cassette is loaded unless mode is ModeRecord,
+// a missing cassette is an error only in ModeReplay
+func New(path string, mode Mode) (*Transport, error) {
+	t := &Transport{path: path, mode: mode}
+	if mode == ModeRecord {
+		return t, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if mode == ModeReplayOrRecord && errors.Is(err, os.ErrNotExist) {
+			return t, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &t.interactions); err != nil {
+		return nil, fmt.Errorf("replay: %s: %w", path, err)
+	}
+	t.replayed = make([]int, len(t.interactions))
+	return t, nil
+}
+
+// DefaultMatch matches requests with the same method, URL and body
+func DefaultMatch(req *http.Request, body []byte, recorded *Request) bool {
+	if req.Method != recorded.Method || req.URL.String() != recorded.URL {
+		return false
+	}
+	recordedBody, err := decode(recorded.Body, recorded.Base64)
+	return err == nil && bytes.Equal(body, recordedBody)
+}
+
+// RoundTrip implements http.RoundTripper. Requests matching several interactions get them in the order
+// they were recorded, then the last one again
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if t.mode != ModeRecord {
+		if recorded := t.find(req, body); recorded != nil {
+			return recorded.response(req)
+		}
+		if t.mode == ModeReplay {
+			return nil, fmt.Errorf("%w: %s %s", ErrNotRecorded, req.Method, req.URL)
+		}
+	}
+	return t.record(req, body)
+}
+
+// find returns the interaction answering req, nil when there is none
+func (t *Transport) find(req *http.Request, body []byte) *Interaction {
+	match := t.Match
+	if match == nil {
+		match = DefaultMatch
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	last := -1
+	for i, interaction := range t.interactions {
+		if !match(req, body, &interaction.Request) {
+			continue
+		}
+		if t.replayed[i] == 0 {
+			t.replayed[i]++
+			return interaction
+		}
+		last = i
+	}
+	if last < 0 {
+		return nil
+	}
+	t.replayed[last]++
+	return t.interactions[last]
+}
+
+// record sends req and records the interaction
+func (t *Transport) record(req *http.Request, body []byte) (*http.Response, error) {
+	transport := t.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	out := req.Clone(req.Context())
+	if req.Body != nil {
+		out.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	resp, err := transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	interaction := &Interaction{
+		Request:  Request{Method: req.Method, URL: req.URL.String(), Header: req.Header.Clone()},
+		Response: Response{StatusCode: resp.StatusCode, Header: resp.Header.Clone()},
+	}
+	for _, name := range sensitiveHeaders {
+		interaction.Request.Header.Del(name)
+	}
+	if len(interaction.Request.Header) == 0 {
+		interaction.Request.Header = nil
+	}
+	interaction.Request.Body, interaction.Request.Base64 = encode(body)
+	interaction.Response.Body, interaction.Response.Base64 = encode(respBody)
+	t.mu.Lock()
+	t.interactions = append(t.interactions, interaction)
+	// The interaction answered this request already
+	t.replayed = append(t.replayed, 1)
+	t.mu.Unlock()
+
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	return resp, nil
+}
+
+// Interactions returns the interactions of the cassette, with the ones recorded so far
+func (t *Transport) Interactions() []*Interaction {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]*Interaction(nil), t.interactions...)
+}
+
+// Save writes the cassette to its file, creating its directory. It does nothing in ModeReplay
+func (t *Transport) Save() error {
+	if t.mode == ModeReplay {
+		return nil
+	}
+	t.mu.Lock()
+	data, err := json.MarshalIndent(t.interactions, "", "  ")
+	t.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(t.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(t.path, append(data, '\n'), 0o644)
+}
+
+// response builds the recorded response to req
+func (i *Interaction) response(req *http.Request) (*http.Response, error) {
+	body, err := decode(i.Response.Body, i.Response.Base64)
+	if err != nil {
+		return nil, fmt.Errorf("replay: %s %s: %w", i.Request.Method, i.Request.URL, err)
+	}
+	header := i.Response.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        strconv.Itoa(i.Response.StatusCode) + " " + http.StatusText(i.Response.StatusCode),
+		StatusCode:    i.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// encode returns body as a string, in base64 when it is not UTF-8
+func encode(body []byte) (string, bool) {
+	if utf8.Valid(body) {
+		return string(body), false
+	}
+	return base64.StdEncoding.EncodeToString(body), true
+}
+
+func decode(body string, isBase64 bool) ([]byte, error) {
+	if isBase64 {
+		return base64.StdEncoding.DecodeString(body)
+	}
+	return []byte(body), nil
+}
@@ -0,0 +1,117 @@
+package replay
+
+import (
+	"compress/gzip"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Patrickmitech/owl"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplay(t *testing.T) {
+	visits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		visits++
+		switch r.URL.Path {
+		case "/zipped":
+			w.Header().Set("Content-Encoding", "gzip")
+			gz := gzip.NewWriter(w)
+			gz.Write([]byte("<html><title>Zipped</title></html>"))
+			gz.Close()
+		case "/form":
+			r.ParseForm()
+			w.Write([]byte("hello " + r.PostForm.Get("name")))
+		default:
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<html><title>Visit " + string(rune('0'+visits)) + "</title></html>"))
+		}
+	}))
+	cassette := filepath.Join(t.TempDir(), "fixtures", "cassette.json")
+
+	tr, err := New(cassette, ModeRecord)
+	require.NoError(t, err)
+	client := &owl.Client{Client: &http.Client{Transport: tr}, Header: map[string]string{"Authorization": "secret"}}
+	doc, err := client.GetDocument(srv.URL + "/")
+	require.NoError(t, err)
+	require.Equal(t, "Visit 1", doc.Find("title").Text())
+	doc, err = client.GetDocument(srv.URL + "/")
+	require.NoError(t, err)
+	require.Equal(t, "Visit 2", doc.Find("title").Text())
+	doc, err = client.GetDocument(srv.URL + "/zipped")
+	require.NoError(t, err)
+	require.Equal(t, "Zipped", doc.Find("title").Text())
+	r, err := client.Post(srv.URL+"/form", "application/x-www-form-urlencoded", "name=owl")
+	require.NoError(t, err)
+	require.NotNil(t, r)
+	require.NoError(t, tr.Save())
+	require.Len(t, tr.Interactions(), 4)
+	srv.Close()
+
+	data, err := os.ReadFile(cassette)
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "secret")
+
+	tr, err = New(cassette, ModeReplay)
+	require.NoError(t, err)
+	client = &owl.Client{Client: &http.Client{Transport: tr}}
+	for _, want := range []string{"Visit 1", "Visit 2", "Visit 2"} {
+		doc, err = client.GetDocument(srv.URL + "/")
+		require.NoError(t, err)
+		require.Equal(t, want, doc.Find("title").Text())
+	}
+	doc, err = client.GetDocument(srv.URL + "/zipped")
+	require.NoError(t, err)
+	require.Equal(t, "Zipped", doc.Find("title").Text())
+	resp, err := client.GetResponse(srv.URL + "/zipped")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	_, err = client.Post(srv.URL+"/form", "application/x-www-form-urlencoded", "name=other")
+	require.True(t, errors.Is(err, ErrNotRecorded))
+	_, err = client.GetDocument(srv.URL + "/unknown")
+	require.True(t, errors.Is(err, ErrNotRecorded))
+	require.NoError(t, tr.Save())
+	after, err := os.ReadFile(cassette)
+	require.NoError(t, err)
+	require.Equal(t, data, after)
+}
+
+func TestReplayOrRecord(t *testing.T) {
+	_, err := New(filepath.Join(t.TempDir(), "missing.json"), ModeReplay)
+	require.True(t, errors.Is(err, os.ErrNotExist))
+
+	sent := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent++
+		w.Write([]byte{0xff, 0xfe, byte(sent)})
+	}))
+	defer srv.Close()
+	cassette := filepath.Join(t.TempDir(), "cassette.json")
+	tr, err := New(cassette, ModeReplayOrRecord)
+	require.NoError(t, err)
+	client := &owl.Client{Client: &http.Client{Transport: tr}}
+	var buf strings.Builder
+	_, err = client.DownloadTo(srv.URL+"/a", &buf)
+	require.NoError(t, err)
+	require.NoError(t, tr.Save())
+	require.Equal(t, 1, sent)
+
+	tr, err = New(cassette, ModeReplayOrRecord)
+	require.NoError(t, err)
+	client = &owl.Client{Client: &http.Client{Transport: tr}}
+	buf.Reset()
+	_, err = client.DownloadTo(srv.URL+"/a", &buf)
+	require.NoError(t, err)
+	require.Equal(t, string([]byte{0xff, 0xfe, 1}), buf.String())
+	_, err = client.DownloadTo(srv.URL+"/b", &buf)
+	require.NoError(t, err)
+	require.Equal(t, 2, sent)
+	require.Len(t, tr.Interactions(), 2)
+	require.True(t, tr.Interactions()[0].Response.Base64)
+}
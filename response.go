@@ -0,0 +1,155 @@
+package owl
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/net/html/charset"
+)
+
+// Response is the response to a request of a Client, with its body read
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	// ContentType is the Content-Type header of the response
+	ContentType string
+	// FinalURL is the URL of the response after redirects
+	FinalURL *url.URL
+	Body     []byte
+
+	// tracer and spanContext trace the parse of a traced request
+	tracer      trace.Tracer
+	spanContext trace.SpanContext
+	// logger logs the parse failures
+	logger Logger
+}
+
+// Parse parses the body as HTML decoded from the charset of ContentType,
+// the returned Root records FinalURL as the URL of the document
+func (r *Response) Parse() *Root {
+	span := r.parseSpan()
+	root := r.parse()
+	if root.Error != nil {
+		if span != nil {
+			span.RecordError(root.Error.Err())
+			span.SetStatus(codes.Error, root.Error.Err().Error())
+		}
+		if r.logger != nil {
+			url := ""
+			if r.FinalURL != nil {
+				url = r.FinalURL.String()
+			}
+			r.logger.Info("owl: parse failed", "url", url, "error", root.Error.Err())
+		}
+	}
+	if span != nil {
+		span.End()
+	}
+	return root
+}
+
+func (r *Response) parse() *Root {
+	reader, err := r.decodedBody()
+	if err != nil {
+		return &Root{Error: newError(ErrUnableToParse, err)}
+	}
+	root := HTMLParse(reader)
+	if root.Error != nil {
+		return root
+	}
+	return root.SetURL(r.FinalURL)
+}
+
+// decodedBody returns a reader of the body decoded from the charset of ContentType to UTF-8
+func (r *Response) decodedBody() (io.Reader, error) {
+	if len(r.Body) == 0 {
+		// charset.NewReader fails on empty input
+		return bytes.NewReader(nil), nil
+	}
+	return charset.NewReader(bytes.NewReader(r.Body), r.ContentType)
+}
+
+// response sends a request like do and reads the whole response, following the refreshes of GET responses
+// as MaxRefreshes says
+func (c *Client) response(method, url string, body io.Reader, opts ...RequestOption) (*Response, error) {
+	r, err := c.fetch(method, url, body, opts...)
+	if err != nil {
+		return nil, err
+	}
+	cfg := newRequestConfig(opts)
+	if c.MaxRefreshes > 0 && method == http.MethodGet {
+		if r, err = c.followRefreshes(r, cfg); err != nil {
+			return nil, err
+		}
+	}
+	statusErrors := c.StatusErrors
+	if cfg.statusErrors != nil {
+		statusErrors = *cfg.statusErrors
+	}
+	if statusErrors && (r.StatusCode < 200 || r.StatusCode > 299) {
+		return r, newHTTPError(r)
+	}
+	return r, nil
+}
+
+// fetch sends a request like do and reads the whole response
+func (c *Client) fetch(method, url string, body io.Reader, opts ...RequestOption) (*Response, error) {
+	resp, release, err := c.do(method, url, body, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &Response{
+		StatusCode:  resp.StatusCode,
+		Header:      resp.Header,
+		ContentType: resp.Header.Get("Content-Type"),
+		FinalURL:    resp.Request.URL,
+		Body:        data,
+		tracer:      c.tracer(newRequestConfig(opts)),
+		spanContext: trace.SpanContextFromContext(resp.Request.Context()),
+		logger:      c.Logger,
+	}, nil
+}
+
+// HTTPError is returned for responses with a status other than 2xx when status errors are on,
+// see Client.StatusErrors
+type HTTPError struct {
+	StatusCode int
+	URL        string
+	// Snippet is the beginning of the body, to tell what went wrong
+	Snippet string
+}
+
+// snippetSize is the size in bytes of the Snippet of an HTTPError at most
+const snippetSize = 256
+
+func newHTTPError(r *Response) *HTTPError {
+	snippet := r.Body
+	if len(snippet) > snippetSize {
+		snippet = snippet[:snippetSize]
+		// Runes cut in half are dropped
+		for len(snippet) > 0 && !utf8.Valid(snippet) {
+			snippet = snippet[:len(snippet)-1]
+		}
+	}
+	e := &HTTPError{StatusCode: r.StatusCode, Snippet: strings.TrimSpace(string(snippet))}
+	if r.FinalURL != nil {
+		e.URL = r.FinalURL.String()
+	}
+	return e
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("owl: %s: %d %s", e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+}
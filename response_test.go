@@ -0,0 +1,93 @@
+package owl
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetResponse(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/old", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/new", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=iso-8859-1")
+		w.Header().Set("X-Owl", "hoot")
+		w.WriteHeader(http.StatusNotFound)
+		io.WriteString(w, "<title>Caf\xe9 <b>not</b> found</title><a href=\"menu\">Menu</a>")
+	})
+	mux.HandleFunc("/empty", func(w http.ResponseWriter, r *http.Request) {})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	client := &Client{Client: srv.Client()}
+
+	resp, err := client.GetResponse(srv.URL + "/old")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	require.Equal(t, "hoot", resp.Header.Get("X-Owl"))
+	require.Equal(t, "text/html; charset=iso-8859-1", resp.ContentType)
+	require.Equal(t, srv.URL+"/new", resp.FinalURL.String())
+	require.Equal(t, "<title>Caf\xe9 <b>not</b> found</title><a href=\"menu\">Menu</a>", string(resp.Body))
+
+	doc := resp.Parse()
+	require.Nil(t, doc.Error)
+	require.Equal(t, "Café <b>not</b> found", doc.Title().Text())
+	require.Equal(t, srv.URL+"/menu", doc.Links(nil)[0].URL.String())
+
+	body, err := client.Get(srv.URL + "/empty")
+	require.NoError(t, err)
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+	require.Empty(t, data)
+}
+
+func TestStatusErrors(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/missing", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		io.WriteString(w, "  <title>Not here</title>"+strings.Repeat("é", 200))
+	})
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "<title>Here</title>")
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	client := &Client{Client: srv.Client()}
+
+	resp, err := client.GetResponse(srv.URL + "/missing")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	client.StatusErrors = true
+	var httpErr *HTTPError
+	resp, err = client.GetResponse(srv.URL + "/missing")
+	require.True(t, errors.As(err, &httpErr))
+	require.Equal(t, http.StatusNotFound, httpErr.StatusCode)
+	require.Equal(t, srv.URL+"/missing", httpErr.URL)
+	require.True(t, strings.HasPrefix(httpErr.Snippet, "<title>Not here</title>éé"))
+	require.LessOrEqual(t, len(httpErr.Snippet), 256)
+	require.EqualError(t, err, "owl: "+srv.URL+"/missing: 404 Not Found")
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	// The error page can still be parsed
+	doc, err := client.GetDocument(srv.URL + "/missing")
+	require.True(t, errors.As(err, &httpErr))
+	require.Equal(t, "Not here", doc.Title().Text())
+	r, err := client.Get(srv.URL + "/missing")
+	require.True(t, errors.As(err, &httpErr))
+	require.NotNil(t, r)
+
+	_, err = client.GetDocument(srv.URL + "/ok")
+	require.NoError(t, err)
+	_, err = client.GetResponse(srv.URL+"/missing", WithStatusErrors(false))
+	require.NoError(t, err)
+	client.StatusErrors = false
+	_, err = client.GetResponse(srv.URL+"/missing", WithStatusErrors(true))
+	require.True(t, errors.As(err, &httpErr))
+}
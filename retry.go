@@ -0,0 +1,130 @@
+package owl
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy makes a Client retry the requests that failed for reasons that may not last,
+// with an exponential backoff and jitter between attempts
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts including the first one, requests are not retried below 2
+	MaxAttempts int
+	// MinBackoff is the longest wait before the second attempt, it doubles with every attempt
+	// up to MaxBackoff. The actual wait is random between 0 and that backoff
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// ShouldRetry decides whether the attempt that got resp or err is retried, DefaultShouldRetry when nil
+	ShouldRetry func(req *http.Request, resp *http.Response, err error) bool
+}
+
+// DefaultRetryPolicy retries up to 3 times, waiting up to 500ms, 1s and 2s
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	MinBackoff:  500 * time.Millisecond,
+	MaxBackoff:  30 * time.Second,
+}
+
+// DefaultShouldRetry retries the requests of idempotent methods that failed with a network error,
+// a timeout of the attempt, a 429 Too Many Requests or a 5xx status other than 501 Not Implemented.
+// Canceled requests and requests refused by an open circuit are not retried. The Client does not
+// retry once the context of the request is done, whatever ShouldRetry says
+func DefaultShouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+	default:
+		return false
+	}
+	if err != nil {
+		// Timeouts of the attempt, see WithTimeout, are as transient as the other network errors
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, ErrCircuitOpen)
+	}
+	return resp.StatusCode == http.StatusTooManyRequests ||
+		(resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented)
+}
+
+// shouldRetry reports whether the attempt-th attempt of req is retried and how long to wait before.
+// A Retry-After header longer than MaxBackoff stops the retries
+func (p *RetryPolicy) shouldRetry(attempt int, req *http.Request, resp *http.Response, err error) (time.Duration, bool) {
+	if p == nil || attempt >= p.MaxAttempts {
+		return 0, false
+	}
+	if req.Body != nil && req.GetBody == nil {
+		// The body was consumed and can not be sent again
+		return 0, false
+	}
+	should := p.ShouldRetry
+	if should == nil {
+		should = DefaultShouldRetry
+	}
+	if !should(req, resp, err) {
+		return 0, false
+	}
+	wait := p.backoff(attempt)
+	if resp != nil {
+		if after, ok := retryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			if p.MaxBackoff > 0 && after > p.MaxBackoff {
+				return 0, false
+			}
+			wait = max(wait, after)
+		}
+	}
+	return wait, true
+}
+
+// backoff returns a random wait before the attempt following the attempt-th one
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	backoff := p.MinBackoff
+	for i := 1; i < attempt && (p.MaxBackoff <= 0 || backoff < p.MaxBackoff); i++ {
+		backoff *= 2
+	}
+	if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+		backoff = p.MaxBackoff
+	}
+	if backoff <= 0 {
+		return 0
+	}
+	return rand.N(backoff + 1)
+}
+
+// retryAfter parses a Retry-After header, either a number of seconds or an HTTP date
+func retryAfter(header string, now time.Time) (time.Duration, bool) {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0), seconds >= 0
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// sleep waits for d, it returns the error of ctx when ctx is done first
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// discard reads a little of the body before closing it, so the connection can be reused
+func discard(body io.ReadCloser) {
+	io.CopyN(io.Discard, body, 4<<10)
+	body.Close()
+}
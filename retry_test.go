@@ -0,0 +1,133 @@
+package owl
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetry(t *testing.T) {
+	var calls atomic.Int32
+	var failures int32
+	var retryAfterHeader string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if calls.Add(1) <= failures {
+			w.Header().Set("Retry-After", retryAfterHeader)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(append([]byte("ok "), body...))
+	}))
+	defer srv.Close()
+	policy := &RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond}
+	client := &Client{Client: srv.Client(), Retry: policy}
+	reset := func(n int32, after string) {
+		calls.Store(0)
+		failures, retryAfterHeader = n, after
+	}
+
+	reset(2, "0")
+	resp, err := client.GetResponse(srv.URL)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, int32(3), calls.Load())
+
+	reset(3, "")
+	resp, err = client.GetResponse(srv.URL)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	require.Equal(t, int32(3), calls.Load())
+
+	// A Retry-After longer than MaxBackoff stops the retries
+	reset(1, "60")
+	resp, err = client.GetResponse(srv.URL)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	require.Equal(t, int32(1), calls.Load())
+
+	// POST is not idempotent, unless ShouldRetry says otherwise
+	reset(1, "")
+	_, err = client.Post(srv.URL, "text/plain", "hoot")
+	require.NoError(t, err)
+	require.Equal(t, int32(1), calls.Load())
+	policy.ShouldRetry = func(req *http.Request, resp *http.Response, err error) bool {
+		return err != nil || resp.StatusCode >= 500
+	}
+	reset(1, "")
+	r, err := client.Post(srv.URL, "text/plain", "hoot")
+	require.NoError(t, err)
+	data, _ := io.ReadAll(r)
+	require.Equal(t, "ok hoot", string(data))
+	require.Equal(t, int32(2), calls.Load())
+
+	reset(1, "")
+	_, err = (&Client{Client: srv.Client()}).GetResponse(srv.URL)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), calls.Load())
+}
+
+func TestRetryTimeout(t *testing.T) {
+	var calls atomic.Int32
+	var slow atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= slow.Load() {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(2 * time.Second):
+			}
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+	client := &Client{Client: srv.Client(), Retry: &RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond}}
+
+	// The attempt outliving the timeout of the request is retried
+	slow.Store(1)
+	resp, err := client.GetResponse(srv.URL, WithTimeout(100*time.Millisecond))
+	require.NoError(t, err)
+	require.Equal(t, "ok", string(resp.Body))
+	require.Equal(t, int32(2), calls.Load())
+
+	// The deadline of the context of the caller is final
+	calls.Store(0)
+	slow.Store(3)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = client.GetResponse(srv.URL, WithContext(ctx), WithTimeout(time.Second))
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Equal(t, int32(1), calls.Load())
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for header, want := range map[string]time.Duration{
+		"120":                           2 * time.Minute,
+		"Wed, 01 May 2024 12:00:30 GMT": 30 * time.Second,
+		"Wed, 01 May 2024 11:00:00 GMT": 0,
+	} {
+		got, ok := retryAfter(header, now)
+		require.True(t, ok, header)
+		require.Equal(t, want, got, header)
+	}
+	for _, header := range []string{"", "-1", "soon"} {
+		_, ok := retryAfter(header, now)
+		require.False(t, ok, header)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	p := &RetryPolicy{MinBackoff: 100 * time.Millisecond, MaxBackoff: 250 * time.Millisecond}
+	for i := 0; i < 50; i++ {
+		require.LessOrEqual(t, p.backoff(1), 100*time.Millisecond)
+		require.LessOrEqual(t, p.backoff(2), 200*time.Millisecond)
+		require.LessOrEqual(t, p.backoff(5), 250*time.Millisecond)
+	}
+}
@@ -0,0 +1,231 @@
+// Package robots parses robots.txt files following RFC 9309 and tells which URLs a crawler may fetch
+package robots
+
+import (
+	"bufio"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Patrickmitech/owl"
+)
+
+// Robots holds the rules of a robots.txt file
+type Robots struct {
+	groups []*group
+	// Sitemaps lists the Sitemap lines of the file
+	Sitemaps []string
+}
+
+type group struct {
+	agents     []string
+	rules      []rule
+	crawlDelay time.Duration
+}
+
+type rule struct {
+	allow   bool
+	pattern string
+}
+
+// Parse reads a robots.txt file from r, lines it does not understand are ignored
+func Parse(r io.Reader) (*Robots, error) {
+	robots := &Robots{}
+	var current *group
+	// A group starts with one or more user-agent lines, a user-agent line after a rule starts a new one
+	inAgents := false
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line, _, _ := strings.Cut(s.Text(), "#")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		switch key {
+		case "user-agent":
+			if !inAgents {
+				current = &group{}
+				robots.groups = append(robots.groups, current)
+				inAgents = true
+			}
+			current.agents = append(current.agents, strings.ToLower(value))
+		case "allow", "disallow":
+			inAgents = false
+			if current == nil || value == "" {
+				continue
+			}
+			current.rules = append(current.rules, rule{allow: key == "allow", pattern: value})
+		case "crawl-delay":
+			inAgents = false
+			if current == nil {
+				continue
+			}
+			if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds >= 0 {
+				current.crawlDelay = time.Duration(seconds * float64(time.Second))
+			}
+		case "sitemap":
+			robots.Sitemaps = append(robots.Sitemaps, value)
+		}
+	}
+	return robots, s.Err()
+}
+
+// Fetch gets and parses the robots.txt of the site of siteURL with f, an *owl.Client or a mock of it
+func Fetch(f owl.Fetcher, siteURL string) (*Robots, error) {
+	u, err := url.Parse(siteURL)
+	if err != nil {
+		return nil, err
+	}
+	r, err := f.Get((&url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/robots.txt"}).String())
+	if err != nil {
+		return nil, err
+	}
+	return Parse(r)
+}
+
+// Allowed reports whether the crawler identified by userAgent may fetch path, which may hold a query.
+// The longest matching rule wins, allow rules win ties, and /robots.txt is always allowed
+func (r *Robots) Allowed(userAgent, path string) bool {
+	if path == "" {
+		path = "/"
+	}
+	if path == "/robots.txt" {
+		return true
+	}
+	allowed, longest := true, -1
+	for _, g := range r.match(userAgent) {
+		for _, rule := range g.rules {
+			if len(rule.pattern) < longest || !matchPattern(rule.pattern, path) {
+				continue
+			}
+			if len(rule.pattern) > longest || rule.allow {
+				allowed = rule.allow
+			}
+			longest = len(rule.pattern)
+		}
+	}
+	return allowed
+}
+
+// CrawlDelay returns the delay the file asks userAgent to wait between requests, 0 when it does not ask for one
+func (r *Robots) CrawlDelay(userAgent string) time.Duration {
+	var delay time.Duration
+	for _, g := range r.match(userAgent) {
+		if g.crawlDelay > delay {
+			delay = g.crawlDelay
+		}
+	}
+	return delay
+}
+
+// match returns the groups applying to userAgent: those naming the longest prefix of its product token,
+// or the groups for * when none does
+func (r *Robots) match(userAgent string) []*group {
+	token := strings.ToLower(strings.TrimSpace(userAgent))
+	if i := strings.IndexAny(token, "/ "); i >= 0 {
+		token = token[:i]
+	}
+	var matched, wildcard []*group
+	best := 0
+	for _, g := range r.groups {
+		length := 0
+		for _, agent := range g.agents {
+			if agent == "*" {
+				wildcard = append(wildcard, g)
+			} else if token != "" && strings.HasPrefix(token, agent) && len(agent) > length {
+				length = len(agent)
+			}
+		}
+		if length > best {
+			matched, best = nil, length
+		}
+		if length > 0 && length == best {
+			matched = append(matched, g)
+		}
+	}
+	if len(matched) > 0 {
+		return matched
+	}
+	return wildcard
+}
+
+// matchPattern reports whether path starts with pattern, where * matches any sequence of characters
+// and a trailing $ anchors the pattern at the end of path
+func matchPattern(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	if anchored {
+		pattern = pattern[:len(pattern)-1]
+	}
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	rest := path[len(parts[0]):]
+	for i, part := range parts[1:] {
+		if anchored && i == len(parts)-2 {
+			return strings.HasSuffix(rest, part)
+		}
+		j := strings.Index(rest, part)
+		if j < 0 {
+			return false
+		}
+		rest = rest[j+len(part):]
+	}
+	return !anchored || rest == ""
+}
+
+// Policy implements owl.RobotsPolicy, fetching the robots.txt of each host once
+type Policy struct {
+	fetcher owl.Fetcher
+	mu      sync.Mutex
+	hosts   map[string]*Robots
+}
+
+var _ owl.RobotsPolicy = (*Policy)(nil)
+
+// NewPolicy returns a Policy fetching robots.txt files with f, usually the Client the policy is set on
+func NewPolicy(f owl.Fetcher) *Policy {
+	return &Policy{fetcher: f, hosts: make(map[string]*Robots)}
+}
+
+// Allowed reports whether userAgent may fetch u according to the robots.txt of its host.
+// Hosts whose robots.txt can not be fetched allow everything
+func (p *Policy) Allowed(userAgent string, u *url.URL) bool {
+	path := u.EscapedPath()
+	if path == "/robots.txt" {
+		return true
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+	return p.robots(u).Allowed(userAgent, path)
+}
+
+// CrawlDelay returns the delay the robots.txt of the host of u asks userAgent to wait between requests
+func (p *Policy) CrawlDelay(userAgent string, u *url.URL) time.Duration {
+	return p.robots(u).CrawlDelay(userAgent)
+}
+
+// robots returns the rules of the host of u, fetching them on first use
+func (p *Policy) robots(u *url.URL) *Robots {
+	key := u.Scheme + "://" + u.Host
+	p.mu.Lock()
+	r, ok := p.hosts[key]
+	p.mu.Unlock()
+	if ok {
+		return r
+	}
+	r, err := Fetch(p.fetcher, key)
+	if err != nil {
+		r = &Robots{}
+	}
+	p.mu.Lock()
+	p.hosts[key] = r
+	p.mu.Unlock()
+	return r
+}
@@ -0,0 +1,118 @@
+package robots
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Patrickmitech/owl"
+	"github.com/Patrickmitech/owl/owlmock"
+	"github.com/stretchr/testify/require"
+)
+
+const robotsTxt = `# Example robots.txt
+User-agent: *
+Disallow: /private
+Allow: /private/public
+Disallow: /*.pdf$
+Crawl-delay: 2
+
+User-agent: Owl
+User-agent: owlbot-news
+Disallow: /owls-only
+Allow: /page
+Disallow: /page
+Crawl-delay: 0.5
+
+User-agent: owlbot
+Disallow: /
+
+Sitemap: https://example.com/sitemap.xml
+`
+
+func TestAllowed(t *testing.T) {
+	r, err := Parse(strings.NewReader(robotsTxt))
+	require.NoError(t, err)
+	require.Equal(t, []string{"https://example.com/sitemap.xml"}, r.Sitemaps)
+
+	for _, tt := range []struct {
+		agent, path string
+		allowed     bool
+	}{
+		{"Mozilla/5.0", "/", true},
+		{"Mozilla/5.0", "/private/notes", false},
+		{"Mozilla/5.0", "/private/public/index.html", true},
+		{"Mozilla/5.0", "/files/owl.pdf", false},
+		{"Mozilla/5.0", "/files/owl.pdf?download=1", true},
+		{"Mozilla/5.0", "/robots.txt", true},
+		{"Owl Mozilla/5.0 Firefox/96.0", "/private/notes", true},
+		{"Owl Mozilla/5.0 Firefox/96.0", "/owls-only", false},
+		{"Owl Mozilla/5.0 Firefox/96.0", "/page", true},
+		{"owlbot/2.1", "/anything", false},
+		{"OwlBot-News/1.0", "/owls-only", false},
+		{"OwlBot-News/1.0", "/anything", true},
+	} {
+		require.Equal(t, tt.allowed, r.Allowed(tt.agent, tt.path), "%s %s", tt.agent, tt.path)
+	}
+	require.Equal(t, 2*time.Second, r.CrawlDelay("Mozilla/5.0"))
+	require.Equal(t, 500*time.Millisecond, r.CrawlDelay("Owl"))
+	require.Zero(t, r.CrawlDelay("owlbot"))
+}
+
+func TestMatchPattern(t *testing.T) {
+	require.True(t, matchPattern("/fish", "/fish.html"))
+	require.True(t, matchPattern("/fish*", "/fish/salmon"))
+	require.True(t, matchPattern("/*.php", "/index.php?x=1"))
+	require.True(t, matchPattern("/*.php$", "/folder/filename.php"))
+	require.False(t, matchPattern("/*.php$", "/filename.php5"))
+	require.True(t, matchPattern("/fish*.php", "/fishheads/catfish.php?parameters"))
+	require.False(t, matchPattern("/fish*.php", "/Fish.PHP"))
+	require.True(t, matchPattern("/$", "/"))
+	require.False(t, matchPattern("/$", "/a"))
+}
+
+func TestPolicy(t *testing.T) {
+	pages := owlmock.NewPages(map[string]string{"https://example.com/robots.txt": robotsTxt})
+	p := NewPolicy(pages)
+	require.False(t, p.Allowed("Mozilla/5.0", mustParse("https://example.com/private/x")))
+	require.True(t, p.Allowed("Mozilla/5.0", mustParse("https://example.com/about")))
+	require.Equal(t, 2*time.Second, p.CrawlDelay("Mozilla/5.0", mustParse("https://example.com/")))
+	require.Equal(t, 1, pages.CallCount("Get"))
+
+	// Hosts without a reachable robots.txt allow everything
+	require.True(t, p.Allowed("Mozilla/5.0", mustParse("https://other.example.com/private/x")))
+}
+
+func TestClientRobots(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			w.Write([]byte("User-agent: *\nDisallow: /private\n"))
+			return
+		}
+		w.Write([]byte("<html><body>ok</body></html>"))
+	}))
+	defer srv.Close()
+
+	client := owl.HttpClientWrapper(srv.Client())
+	client.RequestTimeout = 5 * time.Second
+	client.Robots = NewPolicy(client)
+
+	_, err := client.GetDocument(srv.URL + "/private/page")
+	require.ErrorIs(t, err, owl.ErrDisallowedByRobots)
+	_, err = client.Get(srv.URL + "/private/page")
+	require.ErrorIs(t, err, owl.ErrDisallowedByRobots)
+	doc, err := client.GetDocument(srv.URL + "/public")
+	require.NoError(t, err)
+	require.Equal(t, "ok", doc.Find("body").Text())
+}
+
+func mustParse(raw string) *url.URL {
+	u, err := url.Parse(raw)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
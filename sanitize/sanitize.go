@@ -0,0 +1,258 @@
+// Package sanitize cleans documents parsed by owl before they are rendered again, such as scraped articles
+// republished on another site. A Policy allows elements and attributes, everything else is removed:
+// scripts, event handlers, javascript: URLs and the tags and attributes the Policy does not name
+//
+//	clean := sanitize.UGCPolicy().Sanitize(article.Content)
+package sanitize
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/Patrickmitech/owl"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Policy tells which elements and attributes Sanitize keeps. Elements it does not allow are replaced
+// by their content, except the DropElements which go along with their content
+type Policy struct {
+	// Elements are the allowed elements by tag name, with the attributes allowed on each of them
+	Elements map[string][]string
+	// Attributes are allowed on every allowed element
+	Attributes []string
+	// URLSchemes are the schemes allowed in the URL attributes, such as href and src,
+	// attributes with other schemes are removed. Relative URLs are always allowed
+	URLSchemes []string
+	// DropElements are removed along with their content when not allowed, such as script and style
+	DropElements []string
+	// AllowComments keeps the comments
+	AllowComments bool
+	// LinkRel is set as the rel attribute of the links, such as "nofollow noopener", when not empty
+	LinkRel string
+}
+
+// urlAttributes hold URLs whose scheme is checked against the URLSchemes of the Policy
+var urlAttributes = map[string]bool{
+	"href": true, "src": true, "action": true, "formaction": true, "cite": true, "poster": true,
+	"background": true, "longdesc": true, "usemap": true, "manifest": true, "data": true, "codebase": true,
+	"ping": true, "srcset": true,
+}
+
+// defaultDropElements hold code or content not meant to be read as part of the page
+var defaultDropElements = []string{
+	"script", "style", "iframe", "frame", "frameset", "object", "embed", "applet", "noscript",
+	"template", "head", "title", "svg", "math", "select", "textarea", "button",
+}
+
+// StrictPolicy returns a Policy keeping nothing but the text
+func StrictPolicy() *Policy {
+	return &Policy{Elements: map[string][]string{}, DropElements: defaultDropElements}
+}
+
+// UGCPolicy returns a Policy for the content of articles and comments: text formatting, headings,
+// lists, tables, quotes, links and images, with http, https and mailto URLs
+func UGCPolicy() *Policy {
+	p := &Policy{
+		Elements:     map[string][]string{},
+		Attributes:   []string{"title", "lang", "dir"},
+		URLSchemes:   []string{"http", "https", "mailto"},
+		DropElements: defaultDropElements,
+	}
+	p.AllowElements("p", "br", "hr", "div", "span", "b", "strong", "i", "em", "u", "s", "del", "ins",
+		"mark", "small", "sub", "sup", "code", "pre", "kbd", "samp", "var", "abbr", "cite", "dfn",
+		"h1", "h2", "h3", "h4", "h5", "h6", "ul", "ol", "li", "dl", "dt", "dd", "figure", "figcaption",
+		"article", "section", "header", "footer", "aside", "main", "details", "summary", "caption",
+		"thead", "tbody", "tfoot", "tr", "picture")
+	p.AllowAttributes("a", "href", "name")
+	p.AllowAttributes("img", "src", "srcset", "alt", "width", "height", "loading")
+	p.AllowAttributes("source", "srcset", "media", "type")
+	p.AllowAttributes("blockquote", "cite")
+	p.AllowAttributes("q", "cite")
+	p.AllowAttributes("time", "datetime")
+	p.AllowAttributes("table", "summary")
+	p.AllowAttributes("th", "colspan", "rowspan", "scope")
+	p.AllowAttributes("td", "colspan", "rowspan")
+	p.AllowAttributes("ol", "start", "reversed", "type")
+	p.AllowAttributes("col", "span")
+	p.AllowAttributes("colgroup", "span")
+	return p
+}
+
+// AllowElements allows the elements with the tag names, without attributes of their own, and returns p
+func (p *Policy) AllowElements(tags ...string) *Policy {
+	if p.Elements == nil {
+		p.Elements = make(map[string][]string)
+	}
+	for _, tag := range tags {
+		tag = strings.ToLower(tag)
+		if _, ok := p.Elements[tag]; !ok {
+			p.Elements[tag] = nil
+		}
+	}
+	return p
+}
+
+// AllowAttributes allows the element with the tag name along with the attributes and returns p
+func (p *Policy) AllowAttributes(tag string, attrs ...string) *Policy {
+	p.AllowElements(tag)
+	tag = strings.ToLower(tag)
+	for _, attr := range attrs {
+		p.Elements[tag] = append(p.Elements[tag], strings.ToLower(attr))
+	}
+	return p
+}
+
+// Sanitize cleans the tree below the Node of r in place and returns r, the index built by BuildIndex
+// is invalidated. The Node itself is kept, with the attributes the Policy allows on it
+func (p *Policy) Sanitize(r *owl.Root) *owl.Root {
+	if r.Node == nil {
+		return r
+	}
+	if r.Node.Type == html.ElementNode {
+		p.cleanAttributes(r.Node)
+	}
+	p.cleanChildren(r.Node)
+	r.InvalidateIndex()
+	return r
+}
+
+// SanitizeHTML parses the HTML fragment s as the content of a body element, cleans it and renders it
+func (p *Policy) SanitizeHTML(s string) string {
+	body := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	nodes, err := html.ParseFragment(strings.NewReader(s), body)
+	if err != nil {
+		return ""
+	}
+	for _, n := range nodes {
+		body.AppendChild(n)
+	}
+	p.cleanChildren(body)
+	var b strings.Builder
+	for c := body.FirstChild; c != nil; c = c.NextSibling {
+		html.Render(&b, c)
+	}
+	return b.String()
+}
+
+func (p *Policy) cleanChildren(n *html.Node) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		p.clean(c)
+		c = next
+	}
+}
+
+// clean cleans n, which may be removed or replaced by its children
+func (p *Policy) clean(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		return
+	case html.ElementNode:
+	case html.CommentNode:
+		if !p.AllowComments {
+			n.Parent.RemoveChild(n)
+		}
+		return
+	default:
+		n.Parent.RemoveChild(n)
+		return
+	}
+	tag := strings.ToLower(n.Data)
+	if n.Namespace != "" {
+		// Elements of SVG and MathML are never allowed, nor their content
+		n.Parent.RemoveChild(n)
+		return
+	}
+	if _, ok := p.Elements[tag]; !ok {
+		for _, drop := range p.DropElements {
+			if strings.EqualFold(drop, tag) {
+				n.Parent.RemoveChild(n)
+				return
+			}
+		}
+		p.cleanChildren(n)
+		for c := n.FirstChild; c != nil; c = n.FirstChild {
+			n.RemoveChild(c)
+			n.Parent.InsertBefore(c, n)
+		}
+		n.Parent.RemoveChild(n)
+		return
+	}
+	p.cleanAttributes(n)
+	p.cleanChildren(n)
+}
+
+// cleanAttributes removes the attributes of the element n the Policy does not allow
+func (p *Policy) cleanAttributes(n *html.Node) {
+	tag := strings.ToLower(n.Data)
+	allowed, ok := p.Elements[tag]
+	attrs := n.Attr[:0]
+	for _, a := range n.Attr {
+		key := strings.ToLower(a.Key)
+		if !ok || a.Namespace != "" || strings.HasPrefix(key, "on") {
+			continue
+		}
+		if !containsFold(allowed, key) && !containsFold(p.Attributes, key) {
+			continue
+		}
+		if urlAttributes[key] && !p.allowedURLs(key, a.Val) {
+			continue
+		}
+		attrs = append(attrs, a)
+	}
+	n.Attr = attrs
+	if tag == "a" && p.LinkRel != "" && ok {
+		for i, a := range n.Attr {
+			if a.Key == "rel" {
+				n.Attr = append(n.Attr[:i], n.Attr[i+1:]...)
+				break
+			}
+		}
+		n.Attr = append(n.Attr, html.Attribute{Key: "rel", Val: p.LinkRel})
+	}
+}
+
+// allowedURLs reports whether the URLs of the attribute key, with the value, have allowed schemes
+func (p *Policy) allowedURLs(key, value string) bool {
+	if key != "srcset" {
+		return p.allowedURL(value)
+	}
+	for _, candidate := range strings.Split(value, ",") {
+		fields := strings.Fields(candidate)
+		if len(fields) > 0 && !p.allowedURL(fields[0]) {
+			return false
+		}
+	}
+	return true
+}
+
+// allowedURL reports whether u is relative or has an allowed scheme. Browsers ignore the
+// whitespace and control characters in URLs, so they are removed before the scheme is read
+func (p *Policy) allowedURL(u string) bool {
+	u = strings.Map(func(r rune) rune {
+		if r <= ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, u)
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return false
+	}
+	if parsed.Scheme == "" {
+		// A colon before any slash would be read as a scheme by browsers
+		before, _, _ := strings.Cut(u, "/")
+		return !strings.Contains(before, ":")
+	}
+	return containsFold(p.URLSchemes, parsed.Scheme)
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
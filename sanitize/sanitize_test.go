@@ -0,0 +1,76 @@
+package sanitize
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Patrickmitech/owl"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSanitizeHTML(t *testing.T) {
+	p := UGCPolicy()
+	tests := []struct {
+		in, want string
+	}{
+		{`<p onclick="steal()">Hello <b>owl</b></p>`, `<p>Hello <b>owl</b></p>`},
+		{`<script>alert(1)</script><p>text</p>`, `<p>text</p>`},
+		{`<a href="javascript:alert(1)">link</a>`, `<a>link</a>`},
+		{`<a href=" java&#09;script:alert(1)">link</a>`, `<a>link</a>`},
+		{`<a href="https://example.com/" target="_blank">link</a>`, `<a href="https://example.com/">link</a>`},
+		{`<a href="/relative?q=1">link</a>`, `<a href="/relative?q=1">link</a>`},
+		{`<font color="red">red <i>text</i></font>`, `red <i>text</i>`},
+		{`<img src="data:image/png;base64,AAAA" alt="x">`, `<img alt="x"/>`},
+		{`<img srcset="a.png 1x, javascript:x 2x" alt="x">`, `<img alt="x"/>`},
+		{`<p style="color:red" title="t">styled</p>`, `<p title="t">styled</p>`},
+		{`<!-- hidden --><p>shown</p>`, `<p>shown</p>`},
+		{`<svg><script>alert(1)</script><text>svg</text></svg>ok`, `ok`},
+		{`<iframe src="https://example.com/"></iframe><style>p{}</style>x`, `x`},
+		{`<form action="/post"><input name="q">find</form>`, `find`},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.want, p.SanitizeHTML(test.in), test.in)
+	}
+}
+
+func TestStrictPolicy(t *testing.T) {
+	p := StrictPolicy()
+	require.Equal(t, `Hello owl &amp; friends`, p.SanitizeHTML(`<p class="x">Hello <b>owl</b> &amp; friends<script>x()</script></p>`))
+}
+
+func TestSanitize(t *testing.T) {
+	root := owl.HTMLParseFromString(`<html><body><div id="post" onmouseover="x()">
+		<h2>Title</h2><p>Text <a href="https://example.com/" rel="author">link</a></p>
+		<script>track()</script><noscript><img src="pixel.gif"></noscript>
+	</div></body></html>`)
+	post := root.SelectOne("#post")
+	require.Nil(t, post.Error)
+
+	p := UGCPolicy()
+	p.LinkRel = "nofollow noopener"
+	require.Same(t, post, p.Sanitize(post))
+
+	require.Empty(t, root.Select("script").Roots)
+	require.Empty(t, root.Select("noscript").Roots)
+	_, ok := post.Attr("onmouseover")
+	require.False(t, ok)
+	// The div is allowed, its id is not
+	_, ok = post.Attr("id")
+	require.False(t, ok)
+
+	link := post.SelectOne("a")
+	require.Nil(t, link.Error)
+	rel, _ := link.Attr("rel")
+	require.Equal(t, "nofollow noopener", rel)
+	href, _ := link.Attr("href")
+	require.Equal(t, "https://example.com/", href)
+}
+
+func TestPolicyAllow(t *testing.T) {
+	p := StrictPolicy().AllowElements("p").AllowAttributes("a", "href", "ONCLICK")
+	p.URLSchemes = []string{"https"}
+	p.AllowComments = true
+	got := p.SanitizeHTML(`<!--c--><p class="x"><a href="http://example.com/" onclick="x()">a</a><a href="https://example.com/">b</a></p>`)
+	require.Equal(t, `<!--c--><p><a>a</a><a href="https://example.com/">b</a></p>`, got)
+	require.False(t, strings.Contains(got, "onclick"))
+}
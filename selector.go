@@ -0,0 +1,62 @@
+package owl
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/andybalholm/cascadia"
+	"golang.org/x/net/html"
+)
+
+// selectors caches compiled CSS selectors by their source
+var selectors sync.Map
+
+// compileSelector returns the compiled form of the CSS selector, compiling it on first use
+func compileSelector(selector string) (cascadia.Selector, error) {
+	if s, ok := selectors.Load(selector); ok {
+		return s.(cascadia.Selector), nil
+	}
+	s, err := cascadia.Compile(selector)
+	if err != nil {
+		return nil, err
+	}
+	selectors.Store(selector, s)
+	return s, nil
+}
+
+// Select returns the elements matching the CSS selector in document order.
+// Like FindAll, the Node itself is matched along with the elements below it
+func (r *Root) Select(selector string) Roots {
+	s, err := compileSelector(selector)
+	if err != nil {
+		return Roots{Error: newError(ErrInvalidSelector, err)}
+	}
+	var nodes []*html.Node
+	if r.Node != nil {
+		nodes = s.MatchAll(r.Node)
+	}
+	if len(nodes) == 0 {
+		return Roots{Roots: nil, Error: newError(ErrElementsNotFound, errors.New("no elements match the selector"))}
+	}
+	roots := make([]*Root, 0, len(nodes))
+	for _, n := range nodes {
+		roots = append(roots, &Root{Node: n, NodeValue: n.Data, doc: r.doc})
+	}
+	return Roots{Roots: roots, Len: len(roots), Error: nil}
+}
+
+// SelectOne returns the first element matching the CSS selector, see Select
+func (r *Root) SelectOne(selector string) *Root {
+	s, err := compileSelector(selector)
+	if err != nil {
+		return &Root{Error: newError(ErrInvalidSelector, err)}
+	}
+	var n *html.Node
+	if r.Node != nil {
+		n = s.MatchFirst(r.Node)
+	}
+	if n == nil {
+		return &Root{Node: nil, NodeValue: "", Error: newError(ErrElementNotFound, errors.New("no element matches the selector"))}
+	}
+	return &Root{Node: n, NodeValue: n.Data, Error: nil, doc: r.doc}
+}
@@ -0,0 +1,31 @@
+package owl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelect(t *testing.T) {
+	doc := HTMLParseFromString(`<html><body>
+		<ul id="owls"><li class="owl barn">Barn</li><li class="owl">Snowy</li><li>Not an owl</li></ul>
+		<p data-kind="note">Owls <b>hoot</b></p>
+	</body></html>`)
+
+	owls := doc.Select("ul#owls > li.owl")
+	require.Nil(t, owls.Error)
+	require.Equal(t, 2, owls.Len)
+	require.Equal(t, "Snowy", owls.Roots[1].Text())
+
+	require.Equal(t, "hoot", doc.SelectOne(`p[data-kind="note"] b`).Text())
+	require.Equal(t, "Barn", doc.SelectOne("li:first-child").Text())
+
+	ul := doc.SelectOne("ul")
+	require.Equal(t, "ul", ul.Select("ul").Roots[0].NodeValue)
+
+	missing := doc.Select("table")
+	require.Equal(t, ErrElementsNotFound, missing.Error.Type)
+	require.Equal(t, ErrElementNotFound, doc.SelectOne("table").Error.Type)
+	require.Equal(t, ErrInvalidSelector, doc.Select("li[").Error.Type)
+	require.Equal(t, ErrInvalidSelector, doc.SelectOne("li[").Error.Type)
+}
@@ -0,0 +1,120 @@
+package owl
+
+import (
+	"hash/fnv"
+	"math/bits"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// simHashShingle is the number of words of the shingles SimHash hashes
+const simHashShingle = 3
+
+// SimHash returns the SimHash fingerprint of text: texts differing by a few words have fingerprints
+// differing by a few bits, see HammingDistance. Words are compared ignoring case and punctuation,
+// hashed three at a time so their order matters. Texts without words have the fingerprint 0
+func SimHash(text string) uint64 {
+	return simHash(simHashWords(text))
+}
+
+// simHashWords splits text into the lowercase words SimHash compares
+func simHashWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+func simHash(words []string) uint64 {
+	if len(words) == 0 {
+		return 0
+	}
+	var weights [64]int
+	h := fnv.New64a()
+	for i := 0; i+simHashShingle <= len(words) || i == 0; i++ {
+		h.Reset()
+		end := min(i+simHashShingle, len(words))
+		for _, w := range words[i:end] {
+			h.Write([]byte(w))
+			h.Write([]byte{' '})
+		}
+		sum := h.Sum64()
+		for b := 0; b < 64; b++ {
+			if sum&(1<<b) != 0 {
+				weights[b]++
+			} else {
+				weights[b]--
+			}
+		}
+	}
+	var fingerprint uint64
+	for b, w := range weights {
+		if w > 0 {
+			fingerprint |= 1 << b
+		}
+	}
+	return fingerprint
+}
+
+// HammingDistance returns the number of bits a and b differ by
+func HammingDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// DuplicateFilter remembers the SimHash of the texts it sees to detect the texts seen before,
+// or near-duplicates of them. It is safe for concurrent use
+type DuplicateFilter struct {
+	distance int
+	mu       sync.Mutex
+	// blocks index the fingerprints by each of their distance+1 blocks of bits: fingerprints
+	// within distance bits of each other have at least one block in common
+	blocks []map[uint64][]uint64
+}
+
+// NewDuplicateFilter returns a DuplicateFilter taking the texts whose SimHash differ by at most distance
+// bits for duplicates, only the texts with the same fingerprint when 0. 3 suits most web pages
+func NewDuplicateFilter(distance int) *DuplicateFilter {
+	distance = max(0, min(distance, 63))
+	f := &DuplicateFilter{distance: distance, blocks: make([]map[uint64][]uint64, distance+1)}
+	for i := range f.blocks {
+		f.blocks[i] = make(map[uint64][]uint64)
+	}
+	return f
+}
+
+// Seen reports whether text is a duplicate of a text seen before, and remembers it when it is not.
+// Texts of fewer than three words, such as the text of framesets or of pages rendered by scripts,
+// have too little to compare: they are never duplicates and are not remembered
+func (f *DuplicateFilter) Seen(text string) bool {
+	words := simHashWords(text)
+	if len(words) < simHashShingle {
+		return false
+	}
+	return f.SeenHash(simHash(words))
+}
+
+// SeenHash is Seen for a SimHash fingerprint
+func (f *DuplicateFilter) SeenHash(fingerprint uint64) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	keys := make([]uint64, len(f.blocks))
+	for i := range f.blocks {
+		keys[i] = f.block(fingerprint, i)
+		for _, seen := range f.blocks[i][keys[i]] {
+			if HammingDistance(seen, fingerprint) <= f.distance {
+				return true
+			}
+		}
+	}
+	for i, key := range keys {
+		f.blocks[i][key] = append(f.blocks[i][key], fingerprint)
+	}
+	return false
+}
+
+// block returns the bits of the block i of fingerprint
+func (f *DuplicateFilter) block(fingerprint uint64, i int) uint64 {
+	n := len(f.blocks)
+	start, end := i*64/n, (i+1)*64/n
+	return fingerprint >> start & (1<<(end-start) - 1)
+}
@@ -0,0 +1,107 @@
+package owl
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// article is a text of 400 words
+var article = func() string {
+	words := make([]string, 400)
+	for i := range words {
+		words[i] = fmt.Sprintf("word%d", i*7919%1000)
+	}
+	return "The owls " + strings.Join(words[2:], " ")
+}()
+
+func TestSimHash(t *testing.T) {
+	require.Equal(t, SimHash(article), SimHash(strings.ToUpper(article)+" !"))
+	near := strings.Replace(article, "owls", "birds", 1)
+	require.LessOrEqual(t, HammingDistance(SimHash(article), SimHash(near)), 3)
+	other := "Go is a statically typed, compiled programming language designed at Google, with memory safety and garbage collection."
+	require.Greater(t, HammingDistance(SimHash(article), SimHash(other)), 12)
+	require.Zero(t, SimHash(" .. "))
+	require.NotZero(t, SimHash("owl"))
+}
+
+func TestDuplicateFilter(t *testing.T) {
+	exact := NewDuplicateFilter(0)
+	require.False(t, exact.Seen(article))
+	require.True(t, exact.Seen(article+"\n"))
+	require.False(t, exact.SeenHash(SimHash(article)^1))
+
+	near := NewDuplicateFilter(3)
+	require.False(t, near.Seen(article))
+	require.True(t, near.Seen(strings.Replace(article, "owls", "birds", 1)))
+	require.False(t, near.Seen("Go is a statically typed, compiled programming language designed at Google."))
+	require.True(t, near.SeenHash(SimHash(article)^0b1000_0000_0001))
+
+	// Texts too short to compare are never duplicates
+	require.False(t, near.Seen(""))
+	require.False(t, near.Seen("  "))
+	require.False(t, near.Seen("Owls"))
+	require.False(t, near.Seen("Owls"))
+}
+
+func TestCrawlerDuplicates(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			fmt.Fprint(w, `<a href="/owls">owls</a><a href="/owls?session=1">owls</a><a href="/mirror/owls">mirror</a>`)
+			return
+		}
+		fmt.Fprintf(w, `<title>Owls</title><p>%s</p>`, article)
+	}))
+	defer srv.Close()
+	crawler := NewCrawler(&Client{Client: srv.Client()})
+	crawler.Duplicates = NewDuplicateFilter(3)
+	crawler.OnHTML("a[href]", func(e *Root, ctx *CrawlContext) {
+		href, _ := e.Attr("href")
+		ctx.Visit(href)
+	})
+	titles, duplicates := 0, 0
+	crawler.OnHTML("title", func(e *Root, ctx *CrawlContext) { titles++ })
+	crawler.OnError(func(err error, ctx *CrawlContext) {
+		if errors.Is(err, ErrDuplicatePage) {
+			duplicates++
+		}
+	})
+	require.NoError(t, crawler.Start(srv.URL+"/"))
+	require.Equal(t, 1, titles)
+	require.Equal(t, 2, duplicates)
+}
+
+func TestCrawlerDuplicatesWithoutText(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			fmt.Fprint(w, `<frameset><frame src="/frame"></frameset>`)
+		case "/frame":
+			fmt.Fprint(w, `<div id="app"></div><img src="/owl.png"><a href="/end"></a>`)
+		default:
+			fmt.Fprintf(w, `<title>End</title><p>%s</p>`, article)
+		}
+	}))
+	defer srv.Close()
+	crawler := NewCrawler(&Client{Client: srv.Client()})
+	crawler.Duplicates = NewDuplicateFilter(3)
+	crawler.OnHTML("frame[src], a[href]", func(e *Root, ctx *CrawlContext) {
+		src, ok := e.Attr("src")
+		if !ok {
+			src, _ = e.Attr("href")
+		}
+		ctx.Visit(src)
+	})
+	var titles []string
+	crawler.OnHTML("title", func(e *Root, ctx *CrawlContext) { titles = append(titles, e.Text()) })
+	crawler.OnError(func(err error, ctx *CrawlContext) {
+		require.NotErrorIs(t, err, ErrDuplicatePage)
+	})
+	require.NoError(t, crawler.Start(srv.URL+"/"))
+	require.Equal(t, []string{"End"}, titles)
+}
@@ -0,0 +1,313 @@
+package owl
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrSinkClosed is returned when writing to a Sink that was closed
+	ErrSinkClosed = errors.New("owl: sink closed")
+	// ErrNoSink is returned by CrawlContext.Emit when the Crawler has no Sink
+	ErrNoSink = errors.New("owl: crawler has no sink")
+)
+
+// JSONLSink writes records as JSON Lines, one JSON value per line
+type JSONLSink struct {
+	mu     sync.Mutex
+	w      *bufio.Writer
+	closer io.Closer
+	closed bool
+}
+
+var _ Sink = (*JSONLSink)(nil)
+
+// NewJSONLSink returns a JSONLSink writing to w
+func NewJSONLSink(w io.Writer) *JSONLSink {
+	return &JSONLSink{w: bufio.NewWriter(w)}
+}
+
+// CreateJSONLSink returns a JSONLSink writing to the file at path, appended to when it exists.
+// Close closes the file
+func CreateJSONLSink(path string) (*JSONLSink, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	s := NewJSONLSink(f)
+	s.closer = f
+	return s, nil
+}
+
+// Write implements Sink
+func (s *JSONLSink) Write(record interface{}) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrSinkClosed
+	}
+	s.w.Write(line)
+	return s.w.WriteByte('\n')
+}
+
+// Flush writes the buffered records
+func (s *JSONLSink) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.w.Flush()
+}
+
+// Close implements Sink
+func (s *JSONLSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	err := s.w.Flush()
+	if s.closer != nil {
+		err = errors.Join(err, s.closer.Close())
+	}
+	return err
+}
+
+// CSVSink writes records as the rows of a CSV table with a header. Records are structs, pointers to structs,
+// or maps with string keys. The columns of struct fields are named by their csv tag, or else by their json tag
+// or their name, fields tagged "-" are skipped. Values other than strings, numbers, booleans and times
+// are written as JSON
+type CSVSink struct {
+	mu      sync.Mutex
+	w       *csv.Writer
+	closer  io.Closer
+	columns []string
+	header  bool
+	closed  bool
+}
+
+var _ Sink = (*CSVSink)(nil)
+
+// NewCSVSink returns a CSVSink writing to w the columns, or those of the first record when none are given:
+// its fields in order, or its sorted keys
+func NewCSVSink(w io.Writer, columns ...string) *CSVSink {
+	return &CSVSink{w: csv.NewWriter(w), columns: columns}
+}
+
+// CreateCSVSink returns a CSVSink writing to a new file at path, see NewCSVSink. Close closes the file
+func CreateCSVSink(path string, columns ...string) (*CSVSink, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	s := NewCSVSink(f, columns...)
+	s.closer = f
+	return s, nil
+}
+
+// Write implements Sink
+func (s *CSVSink) Write(record interface{}) error {
+	names, values, err := recordFields(record)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrSinkClosed
+	}
+	if !s.header {
+		if len(s.columns) == 0 {
+			s.columns = names
+		}
+		if err := s.w.Write(s.columns); err != nil {
+			return err
+		}
+		s.header = true
+	}
+	row := make([]string, len(s.columns))
+	for i, column := range s.columns {
+		for j, name := range names {
+			if name == column {
+				if row[i], err = csvValue(values[j]); err != nil {
+					return fmt.Errorf("owl: column %s: %w", column, err)
+				}
+				break
+			}
+		}
+	}
+	return s.w.Write(row)
+}
+
+// Flush writes the buffered records
+func (s *CSVSink) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.w.Flush()
+	return s.w.Error()
+}
+
+// Close implements Sink
+func (s *CSVSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	s.w.Flush()
+	err := s.w.Error()
+	if s.closer != nil {
+		err = errors.Join(err, s.closer.Close())
+	}
+	return err
+}
+
+// recordFields returns the column names and the values of a record written by CSVSink
+func recordFields(record interface{}) ([]string, []interface{}, error) {
+	v := reflect.ValueOf(record)
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	switch {
+	case v.Kind() == reflect.Struct:
+		var names []string
+		var values []interface{}
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name := field.Name
+			for _, key := range []string{"csv", "json"} {
+				if tag, ok := field.Tag.Lookup(key); ok {
+					name, _, _ = strings.Cut(tag, ",")
+					break
+				}
+			}
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			names = append(names, name)
+			values = append(values, v.Field(i).Interface())
+		}
+		return names, values, nil
+	case v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String:
+		names := make([]string, 0, v.Len())
+		for _, key := range v.MapKeys() {
+			names = append(names, key.String())
+		}
+		sort.Strings(names)
+		values := make([]interface{}, len(names))
+		for i, name := range names {
+			values[i] = v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key())).Interface()
+		}
+		return names, values, nil
+	}
+	return nil, nil, fmt.Errorf("owl: CSV records are structs or maps, not %T", record)
+}
+
+// csvValue formats a value of a CSV cell
+func csvValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case time.Time:
+		if v.IsZero() {
+			return "", nil
+		}
+		return v.Format(time.RFC3339), nil
+	case fmt.Stringer:
+		return v.String(), nil
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, rv.Type().Bits()), nil
+	case reflect.String:
+		return rv.String(), nil
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return "", nil
+		}
+		return csvValue(rv.Elem().Interface())
+	}
+	b, err := json.Marshal(value)
+	return string(b), err
+}
+
+// ChanSink sends the records to a channel, so they are processed as the crawl goes.
+// Write blocks until the record is received
+type ChanSink[T any] struct {
+	ch     chan<- T
+	mu     sync.RWMutex
+	closed bool
+	done   chan struct{}
+	once   sync.Once
+}
+
+// NewChanSink returns a ChanSink sending the records to ch, they must be of type T.
+// Close closes ch, so a range over it ends with the crawl
+func NewChanSink[T any](ch chan<- T) *ChanSink[T] {
+	return &ChanSink[T]{ch: ch, done: make(chan struct{})}
+}
+
+// Write implements Sink
+func (s *ChanSink[T]) Write(record interface{}) error {
+	value, ok := record.(T)
+	if !ok {
+		return fmt.Errorf("owl: record of type %T, not %T", record, value)
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return ErrSinkClosed
+	}
+	select {
+	case s.ch <- value:
+		return nil
+	case <-s.done:
+		return ErrSinkClosed
+	}
+}
+
+// Close implements Sink, writes blocked waiting for a receiver fail with ErrSinkClosed
+func (s *ChanSink[T]) Close() error {
+	s.once.Do(func() {
+		// Writes blocked in a send hold the read lock, they give up once done is closed
+		close(s.done)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.closed = true
+		close(s.ch)
+	})
+	return nil
+}
@@ -0,0 +1,125 @@
+package owl
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type product struct {
+	Name    string    `json:"name"`
+	Price   float64   `json:"price" csv:"price_usd"`
+	Tags    []string  `json:"tags,omitempty"`
+	Added   time.Time `json:"added"`
+	Secret  string    `json:"-"`
+	private string
+}
+
+func TestJSONLSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewJSONLSink(&buf)
+	require.NoError(t, sink.Write(map[string]int{"a": 1}))
+	require.NoError(t, sink.Write(product{Name: "Owl", Price: 9.5, Secret: "x"}))
+	require.Empty(t, buf.String())
+	require.NoError(t, sink.Close())
+	require.Equal(t, "{\"a\":1}\n{\"name\":\"Owl\",\"price\":9.5,\"added\":\"0001-01-01T00:00:00Z\"}\n", buf.String())
+	require.ErrorIs(t, sink.Write(1), ErrSinkClosed)
+	require.NoError(t, sink.Close())
+
+	path := filepath.Join(t.TempDir(), "items.jsonl")
+	for i := 0; i < 2; i++ {
+		sink, err := CreateJSONLSink(path)
+		require.NoError(t, err)
+		require.NoError(t, sink.Write(i))
+		require.NoError(t, sink.Close())
+	}
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "0\n1\n", string(b))
+}
+
+func TestCSVSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewCSVSink(&buf)
+	added := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, sink.Write(&product{Name: "Owl, grey", Price: 9.5, Tags: []string{"bird"}, Added: added}))
+	require.NoError(t, sink.Write(product{Name: "Nest", Price: 12}))
+	require.Error(t, sink.Write("not a record"))
+	require.NoError(t, sink.Close())
+	require.Equal(t, "name,price_usd,tags,added\n"+
+		"\"Owl, grey\",9.5,\"[\"\"bird\"\"]\",2024-05-01T12:00:00Z\n"+
+		"Nest,12,null,\n", buf.String())
+
+	buf.Reset()
+	sink = NewCSVSink(&buf, "url", "title", "missing")
+	require.NoError(t, sink.Write(map[string]interface{}{"title": "Home", "url": "https://example.com/", "rank": 1}))
+	require.NoError(t, sink.Flush())
+	require.Equal(t, "url,title,missing\nhttps://example.com/,Home,\n", buf.String())
+}
+
+func TestChanSink(t *testing.T) {
+	ch := make(chan string)
+	sink := NewChanSink[string](ch)
+	go func() {
+		sink.Write("a")
+		sink.Write("b")
+		sink.Close()
+	}()
+	var got []string
+	for s := range ch {
+		got = append(got, s)
+	}
+	require.Equal(t, []string{"a", "b"}, got)
+	require.ErrorIs(t, sink.Write("c"), ErrSinkClosed)
+	require.ErrorContains(t, NewChanSink[string](make(chan string)).Write(1), "record of type int, not string")
+
+	// Close releases the writes waiting for a receiver
+	sink = NewChanSink[string](make(chan string))
+	errs := make(chan error)
+	go func() { errs <- sink.Write("blocked") }()
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, sink.Close())
+	require.ErrorIs(t, <-errs, ErrSinkClosed)
+}
+
+func TestCrawlerSink(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<ul><li class="product"><b>Owl</b> <i>9.50</i></li><li class="product"><b>Nest</b> <i>12</i></li></ul>`)
+	}))
+	defer srv.Close()
+	crawler := NewCrawler(&Client{Client: srv.Client()})
+	crawler.OnHTML("li.product", func(e *Root, ctx *CrawlContext) {
+		require.ErrorIs(t, ctx.Emit(nil), ErrNoSink)
+	})
+	require.NoError(t, crawler.Start(srv.URL+"/"))
+
+	items := make(chan product)
+	crawler = NewCrawler(&Client{Client: srv.Client()})
+	crawler.Sink = NewChanSink[product](items)
+	crawler.OnHTML("li.product", func(e *Root, ctx *CrawlContext) {
+		price, err := Extract[float64](e, Pipe(Select("i"), Text(), ParseFloat()))
+		require.NoError(t, err)
+		require.NoError(t, ctx.Emit(product{Name: e.Find("b").Text(), Price: price}))
+	})
+	done := make(chan error, 1)
+	go func() {
+		done <- crawler.Start(srv.URL + "/")
+		crawler.Sink.Close()
+	}()
+	var names []string
+	for item := range items {
+		names = append(names, fmt.Sprintf("%s %v", item.Name, item.Price))
+	}
+	require.NoError(t, <-done)
+	sort.Strings(names)
+	require.Equal(t, "Nest 12,Owl 9.5", strings.Join(names, ","))
+}
@@ -0,0 +1,142 @@
+// Package sitemap parses the sitemaps and sitemap indexes of the sitemaps.org protocol,
+// plain or gzip compressed
+package sitemap
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Patrickmitech/owl"
+	"golang.org/x/net/html/charset"
+)
+
+// ErrNotSitemap is returned when the document is neither a urlset nor a sitemapindex
+var ErrNotSitemap = errors.New("sitemap: not a sitemap")
+
+// DefaultPriority is the priority of URLs whose entry has none
+const DefaultPriority = 0.5
+
+// Sitemap is a parsed sitemap, URLs is set for a urlset and Sitemaps for a sitemapindex
+type Sitemap struct {
+	URLs     []URL
+	Sitemaps []Index
+}
+
+// URL is an entry of a urlset
+type URL struct {
+	Loc string
+	// LastMod is the zero time when the entry does not tell
+	LastMod    time.Time
+	ChangeFreq string
+	Priority   float64
+}
+
+// Index is an entry of a sitemapindex, pointing to another sitemap
+type Index struct {
+	Loc     string
+	LastMod time.Time
+}
+
+// Fetch gets the sitemap at url with f, an *owl.Client or a mock of it, and parses it
+func Fetch(f owl.Fetcher, url string) (*Sitemap, error) {
+	r, err := f.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSitemap(r)
+}
+
+// ParseSitemap reads a urlset or a sitemapindex from r, gzip compressed input is recognized by its first bytes
+func ParseSitemap(r io.Reader) (*Sitemap, error) {
+	br := bufio.NewReader(r)
+	if magic, _ := br.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("sitemap: %w", err)
+		}
+		defer gz.Close()
+		return parse(gz)
+	}
+	return parse(br)
+}
+
+type document struct {
+	XMLName xml.Name
+	URLs    []struct {
+		Loc        string `xml:"loc"`
+		LastMod    string `xml:"lastmod"`
+		ChangeFreq string `xml:"changefreq"`
+		Priority   string `xml:"priority"`
+	} `xml:"url"`
+	Sitemaps []struct {
+		Loc     string `xml:"loc"`
+		LastMod string `xml:"lastmod"`
+	} `xml:"sitemap"`
+}
+
+func parse(r io.Reader) (*Sitemap, error) {
+	d := xml.NewDecoder(r)
+	d.CharsetReader = charset.NewReaderLabel
+	var doc document
+	if err := d.Decode(&doc); err != nil {
+		if err == io.EOF {
+			return nil, ErrNotSitemap
+		}
+		return nil, fmt.Errorf("sitemap: %w", err)
+	}
+	sm := &Sitemap{}
+	switch doc.XMLName.Local {
+	case "urlset":
+		for _, u := range doc.URLs {
+			entry := URL{
+				Loc:        strings.TrimSpace(u.Loc),
+				LastMod:    parseTime(u.LastMod),
+				ChangeFreq: strings.ToLower(strings.TrimSpace(u.ChangeFreq)),
+				Priority:   DefaultPriority,
+			}
+			if p, err := strconv.ParseFloat(strings.TrimSpace(u.Priority), 64); err == nil && p >= 0 && p <= 1 {
+				entry.Priority = p
+			}
+			if entry.Loc != "" {
+				sm.URLs = append(sm.URLs, entry)
+			}
+		}
+	case "sitemapindex":
+		for _, s := range doc.Sitemaps {
+			if loc := strings.TrimSpace(s.Loc); loc != "" {
+				sm.Sitemaps = append(sm.Sitemaps, Index{Loc: loc, LastMod: parseTime(s.LastMod)})
+			}
+		}
+	default:
+		return nil, ErrNotSitemap
+	}
+	return sm, nil
+}
+
+// timeLayouts are the W3C datetime formats lastmod is written in
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+	"2006-01",
+	"2006",
+}
+
+// parseTime returns the zero time when v matches none of timeLayouts
+func parseTime(v string) time.Time {
+	v = strings.TrimSpace(v)
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
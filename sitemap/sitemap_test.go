@@ -0,0 +1,81 @@
+package sitemap
+
+import (
+	"bytes"
+	"compress/gzip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Patrickmitech/owl/owlmock"
+	"github.com/stretchr/testify/require"
+)
+
+const urlset = `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+	<url>
+		<loc> https://example.com/ </loc>
+		<lastmod>2024-05-01</lastmod>
+		<changefreq>Daily</changefreq>
+		<priority>1.0</priority>
+	</url>
+	<url>
+		<loc>https://example.com/owls?sort=name&amp;page=2</loc>
+		<lastmod>2024-05-01T22:00:00+02:00</lastmod>
+	</url>
+	<url><priority>0.8</priority></url>
+</urlset>`
+
+const index = `<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+	<sitemap><loc>https://example.com/sitemap-posts.xml.gz</loc><lastmod>2024-04</lastmod></sitemap>
+	<sitemap><loc>https://example.com/sitemap-pages.xml</loc></sitemap>
+</sitemapindex>`
+
+func TestParseSitemap(t *testing.T) {
+	sm, err := ParseSitemap(strings.NewReader(urlset))
+	require.NoError(t, err)
+	require.Empty(t, sm.Sitemaps)
+	require.Equal(t, []URL{
+		{Loc: "https://example.com/", LastMod: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), ChangeFreq: "daily", Priority: 1},
+		{Loc: "https://example.com/owls?sort=name&page=2", LastMod: time.Date(2024, 5, 1, 20, 0, 0, 0, time.UTC), Priority: DefaultPriority},
+	}, normalize(sm.URLs))
+}
+
+func TestParseSitemapIndex(t *testing.T) {
+	var gz bytes.Buffer
+	w := gzip.NewWriter(&gz)
+	w.Write([]byte(index))
+	w.Close()
+
+	sm, err := ParseSitemap(&gz)
+	require.NoError(t, err)
+	require.Empty(t, sm.URLs)
+	require.Len(t, sm.Sitemaps, 2)
+	require.Equal(t, "https://example.com/sitemap-posts.xml.gz", sm.Sitemaps[0].Loc)
+	require.Equal(t, time.April, sm.Sitemaps[0].LastMod.Month())
+	require.True(t, sm.Sitemaps[1].LastMod.IsZero())
+}
+
+func TestParseSitemapInvalid(t *testing.T) {
+	_, err := ParseSitemap(strings.NewReader(`<html><body></body></html>`))
+	require.ErrorIs(t, err, ErrNotSitemap)
+	_, err = ParseSitemap(strings.NewReader(""))
+	require.ErrorIs(t, err, ErrNotSitemap)
+	_, err = ParseSitemap(strings.NewReader(`<urlset><url>`))
+	require.Error(t, err)
+}
+
+func TestFetch(t *testing.T) {
+	sm, err := Fetch(owlmock.NewPages(map[string]string{"https://example.com/sitemap.xml": index}), "https://example.com/sitemap.xml")
+	require.NoError(t, err)
+	require.Len(t, sm.Sitemaps, 2)
+}
+
+// normalize converts the times of urls to UTC so they compare with require.Equal
+func normalize(urls []URL) []URL {
+	for i := range urls {
+		urls[i].LastMod = urls[i].LastMod.UTC()
+	}
+	return urls
+}
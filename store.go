@@ -0,0 +1,88 @@
+package owl
+
+import "sync"
+
+// Store keeps the visited URLs and the queue of the pages to visit of a Crawler, see NewMemoryStore
+// and the owlbolt and owlredis modules. A persistent Store lets a crawl resume after a crash, and a shared one
+// lets several Crawlers split a crawl. Implementations must be safe for concurrent use
+type Store interface {
+	// Visited reports whether url was marked visited
+	Visited(url string) (bool, error)
+	// MarkVisited marks url visited, it reports false when it already was
+	MarkVisited(url string) (bool, error)
+	// Enqueue adds req to the queue
+	Enqueue(req CrawlRequest) error
+	// Dequeue removes the next request from the queue and returns it, false when the queue is empty
+	Dequeue() (CrawlRequest, bool, error)
+}
+
+// MemoryStore is a Store in memory, visiting pages in the order they were queued
+type MemoryStore struct {
+	mu      sync.Mutex
+	visited map[string]bool
+	queue   []CrawlRequest
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore returns an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{visited: make(map[string]bool)}
+}
+
+// Visited implements Store
+func (s *MemoryStore) Visited(url string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.visited[url], nil
+}
+
+// MarkVisited implements Store
+func (s *MemoryStore) MarkVisited(url string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.visited[url] {
+		return false, nil
+	}
+	s.visited[url] = true
+	return true, nil
+}
+
+// Enqueue implements Store
+func (s *MemoryStore) Enqueue(req CrawlRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queue = append(s.queue, req)
+	return nil
+}
+
+// Dequeue implements Store
+func (s *MemoryStore) Dequeue() (CrawlRequest, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.queue) == 0 {
+		return CrawlRequest{}, false, nil
+	}
+	req := s.queue[0]
+	s.queue[0] = CrawlRequest{}
+	s.queue = s.queue[1:]
+	return req, true, nil
+}
+
+// Len returns the number of queued requests
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.queue)
+}
+
+// snapshot returns the visited URLs and the queue, for Crawler.Checkpoint
+func (s *MemoryStore) snapshot() ([]string, []CrawlRequest) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	visited := make([]string, 0, len(s.visited))
+	for u := range s.visited {
+		visited = append(visited, u)
+	}
+	return visited, append([]CrawlRequest(nil), s.queue...)
+}
@@ -0,0 +1,60 @@
+package owl
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore()
+	visited, err := store.Visited("https://example.com/")
+	require.NoError(t, err)
+	require.False(t, visited)
+	marked, err := store.MarkVisited("https://example.com/")
+	require.NoError(t, err)
+	require.True(t, marked)
+	marked, _ = store.MarkVisited("https://example.com/")
+	require.False(t, marked)
+	visited, _ = store.Visited("https://example.com/")
+	require.True(t, visited)
+
+	require.NoError(t, store.Enqueue(CrawlRequest{URL: "https://example.com/a"}))
+	require.NoError(t, store.Enqueue(CrawlRequest{URL: "https://example.com/b", Depth: 1}))
+	require.Equal(t, 2, store.Len())
+	req, ok, err := store.Dequeue()
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "https://example.com/a", req.URL)
+	req, _, _ = store.Dequeue()
+	require.Equal(t, CrawlRequest{URL: "https://example.com/b", Depth: 1}, req)
+	_, ok, err = store.Dequeue()
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestCrawlerResume(t *testing.T) {
+	srv := newSiteServer(t)
+	store := NewMemoryStore()
+	// A previous crawl visited the home page and queued /b before stopping
+	store.MarkVisited(srv.URL + "/")
+	store.MarkVisited(srv.URL + "/b")
+	store.Enqueue(CrawlRequest{URL: srv.URL + "/b", Depth: 1})
+
+	crawler := NewCrawler(&Client{Client: srv.Client()})
+	crawler.Store = store
+	host, _ := url.Parse(srv.URL)
+	crawler.AllowedDomains = []string{host.Hostname()}
+	var fetched []string
+	crawler.OnHTML("a[href]", func(e *Root, ctx *CrawlContext) {
+		href, _ := e.Attr("href")
+		ctx.Visit(href)
+	})
+	crawler.OnResponse(func(resp *Response, ctx *CrawlContext) {
+		fetched = append(fetched, resp.FinalURL.Path)
+	})
+	require.NoError(t, crawler.Start(srv.URL+"/"))
+	require.Equal(t, []string{"/b"}, fetched)
+	require.Zero(t, store.Len())
+}
@@ -0,0 +1,69 @@
+package owl
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/net/html/charset"
+)
+
+// ResponseInfo describes a response whose body is streamed, see GetStream
+type ResponseInfo struct {
+	StatusCode int
+	Header     http.Header
+	// ContentType is the Content-Type header of the response
+	ContentType string
+	// ContentLength is the length of the body before charset decoding, -1 when unknown
+	ContentLength int64
+	// FinalURL is the URL of the response after redirects
+	FinalURL *url.URL
+}
+
+// GetStream fetches url and returns its live body decoded from the charset of the response to UTF-8,
+// which the caller reads and closes. With status errors on, the stream is returned along with the *HTTPError
+func (c *Client) GetStream(url string, opts ...RequestOption) (io.ReadCloser, *ResponseInfo, error) {
+	resp, release, err := c.do(http.MethodGet, url, nil, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	info := &ResponseInfo{
+		StatusCode:    resp.StatusCode,
+		Header:        resp.Header,
+		ContentType:   resp.Header.Get("Content-Type"),
+		ContentLength: resp.ContentLength,
+		FinalURL:      resp.Request.URL,
+	}
+	br := bufio.NewReader(resp.Body)
+	var statusErr error
+	statusErrors := c.StatusErrors
+	if cfg := newRequestConfig(opts); cfg.statusErrors != nil {
+		statusErrors = *cfg.statusErrors
+	}
+	if statusErrors && (resp.StatusCode < 200 || resp.StatusCode > 299) {
+		// The snippet is peeked so the stream still holds the whole body
+		snippet, _ := br.Peek(snippetSize)
+		statusErr = newHTTPError(&Response{StatusCode: resp.StatusCode, FinalURL: info.FinalURL, Body: snippet})
+	}
+	var reader io.Reader = br
+	if _, err := br.Peek(1); err == nil {
+		// charset.NewReader fails on empty input
+		if reader, err = charset.NewReader(br, info.ContentType); err != nil {
+			release()
+			return nil, nil, err
+		}
+	}
+	return &stream{Reader: reader, release: release}, info, statusErr
+}
+
+// stream is a response body that releases the response once closed
+type stream struct {
+	io.Reader
+	release func()
+}
+
+func (s *stream) Close() error {
+	s.release()
+	return nil
+}
@@ -0,0 +1,52 @@
+package owl
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetStream(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/latin1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=iso-8859-1")
+		io.WriteString(w, "<title>Caf\xe9 owl</title>")
+	})
+	mux.HandleFunc("/empty", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("/gone", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusGone)
+		io.WriteString(w, "<title>Gone</title>")
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	client := &Client{Client: srv.Client()}
+
+	body, info, err := client.GetStream(srv.URL + "/latin1")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, info.StatusCode)
+	require.Equal(t, int64(23), info.ContentLength)
+	require.Equal(t, srv.URL+"/latin1", info.FinalURL.String())
+	doc := HTMLParse(body)
+	require.NoError(t, body.Close())
+	require.Equal(t, "Café owl", doc.Title().Text())
+
+	body, _, err = client.GetStream(srv.URL + "/empty")
+	require.NoError(t, err)
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+	require.Empty(t, data)
+	body.Close()
+
+	var httpErr *HTTPError
+	body, info, err = client.GetStream(srv.URL+"/gone", WithStatusErrors(true))
+	require.True(t, errors.As(err, &httpErr))
+	require.Equal(t, "<title>Gone</title>", httpErr.Snippet)
+	require.Equal(t, http.StatusGone, info.StatusCode)
+	data, _ = io.ReadAll(body)
+	require.Equal(t, "<title>Gone</title>", string(data))
+	body.Close()
+}
@@ -0,0 +1,57 @@
+package owl
+
+import (
+	"golang.org/x/net/html"
+)
+
+// NodeKind classifies the nodes Strip removes
+type NodeKind string
+
+const (
+	NodeScript   NodeKind = "script"
+	NodeStyle    NodeKind = "style"
+	NodeNoscript NodeKind = "noscript"
+	NodeTemplate NodeKind = "template"
+	NodeComment  NodeKind = "comment"
+)
+
+// defaultStripKinds are removed by Strip when it is given no kinds
+var defaultStripKinds = []NodeKind{NodeScript, NodeStyle, NodeNoscript, NodeTemplate, NodeComment}
+
+// Strip removes the nodes of the kinds below the Node, along with everything below them, and returns the Root,
+// the index built by BuildIndex is invalidated. Without kinds, scripts, styles, noscripts, templates
+// and comments are all removed, as wanted before extracting text or rendering the document again
+func (r *Root) Strip(kinds ...NodeKind) *Root {
+	if r.Node == nil {
+		return r
+	}
+	if len(kinds) == 0 {
+		kinds = defaultStripKinds
+	}
+	var nodes []*html.Node
+	walk(r.Node, func(n *html.Node) bool {
+		if n != r.Node && stripped(n, kinds) {
+			nodes = append(nodes, n)
+			return false
+		}
+		return true
+	})
+	for _, n := range nodes {
+		n.Parent.RemoveChild(n)
+	}
+	r.InvalidateIndex()
+	return r
+}
+
+// stripped reports whether n is of one of kinds
+func stripped(n *html.Node, kinds []NodeKind) bool {
+	for _, kind := range kinds {
+		switch {
+		case kind == NodeComment && n.Type == html.CommentNode:
+			return true
+		case kind != NodeComment && n.Type == html.ElementNode && n.Data == string(kind):
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,33 @@
+package owl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStrip(t *testing.T) {
+	const page = `<body><!-- ad --><p>text<script>track()</script></p><style>p{}</style>` +
+		`<noscript><img src="pixel.gif"></noscript><template><p>row</p></template></body>`
+
+	doc := HTMLParseFromString(page)
+	require.Equal(t, doc, doc.Strip())
+	require.Equal(t, `<body><p>text</p></body>`, body(doc))
+
+	doc = HTMLParseFromString(page)
+	doc.Strip(NodeScript, NodeComment)
+	require.Equal(t, `<body><p>text</p><style>p{}</style><noscript><img src="pixel.gif"></noscript><template><p>row</p></template></body>`, body(doc))
+
+	// The Node itself is kept
+	p := HTMLParseFromString(`<script>x()</script><p>1</p>`).Find("script")
+	p.Strip()
+	require.Equal(t, `<script>x()</script>`, string(p.Render()))
+}
+
+func TestStripInvalidatesIndex(t *testing.T) {
+	doc := HTMLParseFromString(`<p>1</p><script>x()</script>`)
+	doc.BuildIndex()
+	require.Len(t, doc.FindAll("script").Roots, 1)
+	doc.Strip()
+	require.Empty(t, doc.FindAll("script").Roots)
+}
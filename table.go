@@ -0,0 +1,234 @@
+package owl
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// ErrNoTable is returned by Table when there is no table element at or below the Node
+var ErrNoTable = errors.New("owl: no table found")
+
+// Table is the content of a table element with its spanning cells expanded,
+// every row holding as many cells as Headers or the widest row
+type Table struct {
+	Caption string
+	// Headers holds the cells of the last header row, from thead or a leading row of th cells
+	Headers []string
+	Rows    [][]string
+}
+
+// Table reads the table element the Node is, or the first one below it.
+// Cells spanning several rows or columns are copied in each of them, and nested tables are left out
+func (r *Root) Table() (*Table, error) {
+	var table *html.Node
+	walk(r.Node, func(n *html.Node) bool {
+		if table == nil && n.Type == html.ElementNode && n.Data == "table" {
+			table = n
+		}
+		return table == nil
+	})
+	if table == nil {
+		return nil, ErrNoTable
+	}
+
+	t := &Table{}
+	var header, body []*html.Node
+	for c := table.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+		switch c.Data {
+		case "caption":
+			t.Caption = collapsedText(c)
+		case "thead":
+			header = append(header, rowsOf(c)...)
+		case "tbody", "tfoot":
+			body = append(body, rowsOf(c)...)
+		case "tr":
+			body = append(body, c)
+		}
+	}
+	grid := expandRows(append(header, body...))
+	headerRows := len(header)
+	if headerRows == 0 && len(body) > 0 && onlyHeaderCells(body[0]) {
+		headerRows = 1
+	}
+	if headerRows > 0 {
+		t.Headers = grid[headerRows-1]
+	}
+	t.Rows = grid[headerRows:]
+	return t, nil
+}
+
+// rowsOf returns the tr children of a table section
+func rowsOf(section *html.Node) []*html.Node {
+	var rows []*html.Node
+	for c := section.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && c.Data == "tr" {
+			rows = append(rows, c)
+		}
+	}
+	return rows
+}
+
+// onlyHeaderCells reports whether every cell of the row tr is a th
+func onlyHeaderCells(tr *html.Node) bool {
+	cells := 0
+	for c := tr.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+		if c.Data == "td" {
+			return false
+		}
+		if c.Data == "th" {
+			cells++
+		}
+	}
+	return cells > 0
+}
+
+// expandRows lays the cells of rows out on a grid, copying the text of spanning cells into each slot they cover
+func expandRows(rows []*html.Node) [][]string {
+	grid := make([][]string, len(rows))
+	filled := make([][]bool, len(rows))
+	width := 0
+	set := func(row, col int, text string) {
+		for len(grid[row]) <= col {
+			grid[row] = append(grid[row], "")
+			filled[row] = append(filled[row], false)
+		}
+		grid[row][col], filled[row][col] = text, true
+		if col+1 > width {
+			width = col + 1
+		}
+	}
+	for i, tr := range rows {
+		col := 0
+		for c := tr.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode || (c.Data != "td" && c.Data != "th") {
+				continue
+			}
+			for col < len(filled[i]) && filled[i][col] {
+				col++
+			}
+			text := collapsedText(c)
+			colspan := spanOf(c, "colspan", 1000)
+			rowspan := spanOf(c, "rowspan", 65534)
+			if rowspan == 0 || i+rowspan > len(rows) {
+				rowspan = len(rows) - i
+			}
+			for dr := 0; dr < rowspan; dr++ {
+				for dc := 0; dc < colspan; dc++ {
+					set(i+dr, col+dc, text)
+				}
+			}
+			col += colspan
+		}
+	}
+	for i := range grid {
+		for len(grid[i]) < width {
+			grid[i] = append(grid[i], "")
+		}
+	}
+	return grid
+}
+
+// spanOf returns the colspan or rowspan of a cell, 1 when missing or invalid. rowspan may be 0,
+// meaning the cell spans the rest of the table
+func spanOf(cell *html.Node, key string, limit int) int {
+	v, ok := attrLookup(cell, key)
+	if !ok {
+		return 1
+	}
+	n := atoi(strings.TrimSpace(v))
+	switch {
+	case n == 0 && key == "rowspan" && strings.TrimSpace(v) == "0":
+		return 0
+	case n < 1:
+		return 1
+	case n > limit:
+		return limit
+	}
+	return n
+}
+
+// WriteCSV writes the table to w as CSV, the headers first when there are any
+func (t *Table) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if len(t.Headers) > 0 {
+		if err := cw.Write(t.Headers); err != nil {
+			return err
+		}
+	}
+	if err := cw.WriteAll(t.Rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+// UnmarshalRows fills the slice of structs v points to with a struct per row. Columns map to fields by the header
+// named in the table tag of the field, or by the field name, case-insensitively. Fields tagged table:"-" and
+// columns without a field are skipped, values convert to the field types like Unmarshal does
+//
+//	type Owl struct {
+//		Name     string
+//		Length   int `table:"Length (cm)"`
+//	}
+//	var owls []Owl
+//	err := table.UnmarshalRows(&owls)
+func (t *Table) UnmarshalRows(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Slice ||
+		rv.Elem().Type().Elem().Kind() != reflect.Struct {
+		return errors.New("owl: UnmarshalRows needs a non-nil pointer to a slice of structs")
+	}
+	slice := rv.Elem()
+	rowType := slice.Type().Elem()
+
+	columns := make(map[int]int)
+	for i := 0; i < rowType.NumField(); i++ {
+		field := rowType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := field.Name
+		if tag, ok := field.Tag.Lookup("table"); ok {
+			if tag == "-" {
+				continue
+			}
+			name = tag
+		}
+		for col, header := range t.Headers {
+			if strings.EqualFold(strings.TrimSpace(header), name) {
+				if _, taken := columns[col]; !taken {
+					columns[col] = i
+				}
+				break
+			}
+		}
+	}
+
+	rows := reflect.MakeSlice(slice.Type(), 0, len(t.Rows))
+	for r, cells := range t.Rows {
+		row := reflect.New(rowType).Elem()
+		for col, field := range columns {
+			if col >= len(cells) {
+				continue
+			}
+			name := fmt.Sprintf("row %d: %s", r, rowType.Field(field).Name)
+			if err := setString(row.Field(field), cells[col], name); err != nil {
+				return err
+			}
+		}
+		rows = reflect.Append(rows, row)
+	}
+	slice.Set(rows)
+	return nil
+}
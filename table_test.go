@@ -0,0 +1,95 @@
+package owl
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTable(t *testing.T) {
+	doc := HTMLParseFromString(`<html><body><div>
+	<table>
+		<caption> Owl  species </caption>
+		<thead>
+			<tr><th rowspan="2">Name</th><th colspan="2">Size</th></tr>
+			<tr><th>Length</th><th>Wingspan</th></tr>
+		</thead>
+		<tbody>
+			<tr><td>Barn owl</td><td>34 cm</td><td rowspan="2">90 cm</td></tr>
+			<tr><td>Tawny <b>owl</b></td><td>38 cm</td></tr>
+			<tr><td colspan="3">Data from <table><tr><td>nested</td></tr></table></td></tr>
+		</tbody>
+	</table></div></body></html>`)
+
+	table, err := doc.Table()
+	require.NoError(t, err)
+	require.Equal(t, "Owl species", table.Caption)
+	require.Equal(t, []string{"Name", "Length", "Wingspan"}, table.Headers)
+	require.Len(t, table.Rows, 3)
+	require.Equal(t, []string{"Barn owl", "34 cm", "90 cm"}, table.Rows[0])
+	require.Equal(t, []string{"Tawny owl", "38 cm", "90 cm"}, table.Rows[1])
+	require.Equal(t, "Data from nested", table.Rows[2][0])
+	require.Len(t, table.Rows[2], 3)
+}
+
+func TestTableWithoutHead(t *testing.T) {
+	table, err := HTMLParseFromString(`<table>
+		<tr><th>Name</th><th>Diet</th></tr>
+		<tr><td>Snowy owl</td><td>Lemmings</td></tr>
+		<tr><td rowspan="0">Eagle owl</td></tr>
+		<tr><td>Ragged</td></tr>
+	</table>`).Table()
+	require.NoError(t, err)
+	require.Equal(t, []string{"Name", "Diet"}, table.Headers)
+	require.Equal(t, [][]string{{"Snowy owl", "Lemmings"}, {"Eagle owl", ""}, {"Eagle owl", "Ragged"}}, table.Rows)
+
+	table, err = HTMLParseFromString(`<table><tr><td>a</td><td>b</td></tr></table>`).Table()
+	require.NoError(t, err)
+	require.Nil(t, table.Headers)
+	require.Equal(t, [][]string{{"a", "b"}}, table.Rows)
+
+	_, err = HTMLParseFromString(`<html><body><p>No table</p></body></html>`).Table()
+	require.ErrorIs(t, err, ErrNoTable)
+}
+
+const speciesTable = `<table>
+	<tr><th>Name</th><th>Length (cm)</th><th>Nocturnal</th><th>Notes</th></tr>
+	<tr><td>Barn owl</td><td>34</td><td>true</td><td>Heart-shaped face, "ghost owl"</td></tr>
+	<tr><td>Little owl</td><td>22</td><td>false</td><td></td></tr>
+</table>`
+
+func TestTableWriteCSV(t *testing.T) {
+	table, err := HTMLParseFromString(speciesTable).Table()
+	require.NoError(t, err)
+	var b strings.Builder
+	require.NoError(t, table.WriteCSV(&b))
+	require.Equal(t, "Name,Length (cm),Nocturnal,Notes\n"+
+		"Barn owl,34,true,\"Heart-shaped face, \"\"ghost owl\"\"\"\n"+
+		"Little owl,22,false,\n", b.String())
+}
+
+func TestTableUnmarshalRows(t *testing.T) {
+	table, err := HTMLParseFromString(speciesTable).Table()
+	require.NoError(t, err)
+
+	type owl struct {
+		Name      string
+		Length    int `table:"Length (cm)"`
+		Nocturnal bool
+		Notes     string `table:"-"`
+		Habitat   string
+	}
+	var owls []owl
+	require.NoError(t, table.UnmarshalRows(&owls))
+	require.Equal(t, []owl{
+		{Name: "Barn owl", Length: 34, Nocturnal: true},
+		{Name: "Little owl", Length: 22},
+	}, owls)
+
+	var bad []struct {
+		Name float64
+	}
+	require.ErrorContains(t, table.UnmarshalRows(&bad), "row 0: Name")
+	require.Error(t, table.UnmarshalRows(owls))
+}
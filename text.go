@@ -0,0 +1,368 @@
+package owl
+
+import (
+	"bytes"
+	"iter"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+)
+
+// TextOptions controls how TextOpts and FullTextOpts normalize the text nodes they return
+type TextOptions struct {
+	// Trim removes leading and trailing whitespace from every text node and drops blank ones
+	Trim bool
+	// CollapseWhitespace replaces every run of whitespace inside a text node with a single space
+	CollapseWhitespace bool
+	// Separator is written between text nodes by FullTextOpts
+	Separator string
+	// Bidi selects how bidirectional control characters and dir attributes are handled
+	Bidi BidiMode
+}
+
+// BidiMode selects how TextOptions treat bidirectional text
+type BidiMode int
+
+const (
+	// BidiPreserve keeps bidi control characters as found in the document
+	BidiPreserve BidiMode = iota
+	// BidiStrip removes every bidi control character
+	BidiStrip
+	// BidiIsolate removes the bidi control characters found in the document
+	// and wraps the text of elements with a dir attribute in Unicode bidi isolates
+	BidiIsolate
+)
+
+// Unicode bidi isolates, see https://www.w3.org/International/questions/qa-bidi-unicode-controls
+const (
+	leftToRightIsolate    = "\u2066"
+	rightToLeftIsolate    = "\u2067"
+	firstStrongIsolate    = "\u2068"
+	popDirectionalIsolate = "\u2069"
+)
+
+// TextOpts returns the same text as Text normalized by opts
+func (r *Root) TextOpts(opts TextOptions) string {
+	s, _ := opts.normalize(r.Text())
+	if s == "" || opts.Bidi != BidiIsolate {
+		return s
+	}
+	for n := r.Node; n != nil; n = n.Parent {
+		if iso := isolateFor(n); iso != "" {
+			return iso + s + popDirectionalIsolate
+		}
+	}
+	return s
+}
+
+// FullTextOpts returns the text of every text node below the Node like FullText,
+// normalized by opts and joined with opts.Separator
+func (r *Root) FullTextOpts(opts TextOptions) string {
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	first := true
+	// isolates opened by elements with a dir attribute, written along with the next text
+	pending := ""
+	var f func(*html.Node)
+	f = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			switch c.Type {
+			case html.TextNode:
+				s, ok := opts.normalize(c.Data)
+				if !ok {
+					continue
+				}
+				if !first {
+					buf.WriteString(opts.Separator)
+				}
+				buf.WriteString(pending)
+				buf.WriteString(s)
+				pending = ""
+				first = false
+			case html.ElementNode:
+				iso := ""
+				if opts.Bidi == BidiIsolate {
+					iso = isolateFor(c)
+				}
+				if iso == "" {
+					f(c)
+					continue
+				}
+				pending += iso
+				f(c)
+				if strings.HasSuffix(pending, iso) {
+					pending = strings.TrimSuffix(pending, iso)
+				} else {
+					buf.WriteString(popDirectionalIsolate)
+				}
+			}
+		}
+	}
+	f(r.Node)
+	return buf.String()
+}
+
+// collapsedText returns the text below n trimmed, with every run of whitespace replaced by a single space
+func collapsedText(n *html.Node) string {
+	return strings.Join(strings.Fields((&Root{Node: n}).FullText()), " ")
+}
+
+// normalize applies the options to the text of a single node,
+// it reports false when the text should be dropped
+func (opts TextOptions) normalize(s string) (string, bool) {
+	if opts.Bidi != BidiPreserve {
+		s = stripBidi(s)
+	}
+	if opts.CollapseWhitespace {
+		s = collapseWhitespace(s)
+	}
+	if opts.Trim {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			return "", false
+		}
+	}
+	return s, true
+}
+
+// collapseWhitespace replaces every run of whitespace in s with a single space
+func collapseWhitespace(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	space := false
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		if unicode.IsSpace(r) {
+			if !space {
+				b.WriteByte(' ')
+			}
+			space = true
+			continue
+		}
+		space = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// isBidiControl reports whether r is one of the Unicode bidi formatting characters
+func isBidiControl(r rune) bool {
+	switch {
+	case r == '\u200e', r == '\u200f', r == '\u061c':
+		return true
+	case r >= '\u202a' && r <= '\u202e':
+		return true
+	case r >= '\u2066' && r <= '\u2069':
+		return true
+	}
+	return false
+}
+
+// stripBidi removes every bidi formatting character from s
+func stripBidi(s string) string {
+	if strings.IndexFunc(s, isBidiControl) < 0 {
+		return s
+	}
+	return strings.Map(func(r rune) rune {
+		if isBidiControl(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// isolateFor returns the isolate opening the text of n according to its dir attribute
+func isolateFor(n *html.Node) string {
+	if n.Type != html.ElementNode {
+		return ""
+	}
+	for _, a := range n.Attr {
+		if a.Key != "dir" {
+			continue
+		}
+		switch strings.ToLower(strings.TrimSpace(a.Val)) {
+		case "ltr":
+			return leftToRightIsolate
+		case "rtl":
+			return rightToLeftIsolate
+		case "auto":
+			return firstStrongIsolate
+		}
+	}
+	return ""
+}
+
+// blockElements start and end on a line of their own in PlainText
+var blockElements = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true, "caption": true,
+	"dd": true, "details": true, "dialog": true, "div": true, "dl": true, "dt": true,
+	"fieldset": true, "figcaption": true, "figure": true, "footer": true, "form": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"header": true, "hgroup": true, "hr": true, "li": true, "main": true, "nav": true,
+	"ol": true, "p": true, "pre": true, "section": true, "summary": true, "table": true,
+	"tbody": true, "tfoot": true, "thead": true, "tr": true, "ul": true,
+}
+
+// hiddenElements never contribute to PlainText
+var hiddenElements = map[string]bool{
+	"head": true, "script": true, "style": true, "noscript": true, "template": true,
+}
+
+// PlainText returns the text below the Node laid out the way a browser copies it:
+// block level elements start new lines, paragraphs are separated by a blank line,
+// table cells by tabs, whitespace is collapsed outside pre elements
+// and the contents of script and style elements are skipped
+func (r *Root) PlainText() string {
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	l := textLayout{buf: buf}
+	l.children(r.Node, false)
+	return buf.String()
+}
+
+// textLayout writes text to buf holding back separators
+// until it is known that more text follows them
+type textLayout struct {
+	buf     *bytes.Buffer
+	breaks  int
+	tab     bool
+	space   bool
+	started bool
+}
+
+func (l *textLayout) children(n *html.Node, pre bool) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		switch c.Type {
+		case html.TextNode:
+			l.text(c.Data, pre)
+		case html.ElementNode:
+			l.element(c, pre)
+		}
+	}
+}
+
+func (l *textLayout) element(n *html.Node, pre bool) {
+	if hiddenElements[n.Data] {
+		return
+	}
+	switch {
+	case n.Data == "br":
+		l.breaks++
+		return
+	case n.Data == "p":
+		l.lineBreaks(2)
+	case blockElements[n.Data]:
+		l.lineBreaks(1)
+	}
+	l.children(n, pre || n.Data == "pre" || n.Data == "textarea")
+	switch {
+	case n.Data == "p":
+		l.lineBreaks(2)
+	case blockElements[n.Data]:
+		l.lineBreaks(1)
+	case n.Data == "td" || n.Data == "th":
+		for s := n.NextSibling; s != nil; s = s.NextSibling {
+			if s.Type == html.ElementNode && (s.Data == "td" || s.Data == "th") {
+				l.tab = true
+				break
+			}
+		}
+	}
+}
+
+// lineBreaks requires at least n line breaks before the next text
+func (l *textLayout) lineBreaks(n int) {
+	if n > l.breaks {
+		l.breaks = n
+	}
+}
+
+func (l *textLayout) text(s string, pre bool) {
+	if pre {
+		if s != "" {
+			l.flush()
+			l.buf.WriteString(s)
+		}
+		return
+	}
+	start := -1
+	for i, r := range s {
+		if unicode.IsSpace(r) {
+			if start >= 0 {
+				l.flush()
+				l.buf.WriteString(s[start:i])
+				start = -1
+			}
+			l.space = true
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		l.flush()
+		l.buf.WriteString(s[start:])
+	}
+}
+
+// flush writes the separator owed before the next piece of text
+func (l *textLayout) flush() {
+	if l.started {
+		switch {
+		case l.breaks > 0:
+			l.buf.WriteString(strings.Repeat("\n", l.breaks))
+		case l.tab:
+			l.buf.WriteString("\t")
+		case l.space:
+			l.buf.WriteString(" ")
+		}
+	}
+	l.breaks, l.tab, l.space, l.started = 0, false, false, true
+}
+
+// Strings returns an iterator over every text node below the Node in document order,
+// the strings concatenated are what FullText returns
+func (r *Root) Strings() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		eachText(r.Node, func(s string) bool {
+			return yield(s)
+		})
+	}
+}
+
+// StrippedStrings is like Strings with the strings trimmed of whitespace,
+// blank strings are skipped
+func (r *Root) StrippedStrings() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		eachText(r.Node, func(s string) bool {
+			if s = strings.TrimSpace(s); s == "" {
+				return true
+			}
+			return yield(s)
+		})
+	}
+}
+
+// eachText calls f with the text nodes below n in document order until f returns false,
+// it reports whether all nodes were visited
+func eachText(n *html.Node, f func(string) bool) bool {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		switch c.Type {
+		case html.TextNode:
+			if !f(c.Data) {
+				return false
+			}
+		case html.ElementNode:
+			if !eachText(c, f) {
+				return false
+			}
+		}
+	}
+	return true
+}
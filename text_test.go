@@ -0,0 +1,78 @@
+package owl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTextOpts(t *testing.T) {
+	div := HTMLParseFromString(`<div>
+		Some   spaced
+		text <b> bold </b>
+	</div>`).Find("div")
+
+	require.Equal(t, "Some spaced text", div.TextOpts(TextOptions{Trim: true, CollapseWhitespace: true}))
+	require.Equal(t, " Some spaced text ", div.TextOpts(TextOptions{CollapseWhitespace: true}))
+}
+
+func TestFullTextOpts(t *testing.T) {
+	li := HtmlRoot.Find("ul").Find("li")
+	require.Equal(t, li.FullText(), li.FullTextOpts(TextOptions{}))
+	require.Equal(t, "To a JSP page right?", li.FullTextOpts(TextOptions{Trim: true, Separator: " "}))
+	require.Equal(t, "To a|JSP page|right?", li.FullTextOpts(TextOptions{Trim: true, Separator: "|"}))
+
+	ul := HtmlRoot.Find("ul")
+	require.Equal(t, "To a JSP page right? To a servlet",
+		ul.FullTextOpts(TextOptions{Trim: true, CollapseWhitespace: true, Separator: " "}))
+}
+
+func TestPlainText(t *testing.T) {
+	root := HTMLParseFromString(`<html><head><title>Ignored</title><style>p { color: red }</style></head>
+<body>
+  <h1>Title</h1>
+  <p>First   paragraph with <b>bold</b>
+     text.</p>
+  <p>Second<br>line</p>
+  <script>var x = 1;</script>
+  <ul><li>One</li><li>Two</li></ul>
+  <table>
+    <tr><th>Name</th><th>Price</th></tr>
+    <tr><td>Tea</td><td>2.50</td></tr>
+  </table>
+  <pre>keep
+  this</pre>
+</body></html>`)
+
+	require.Equal(t, "Title\n\nFirst paragraph with bold text.\n\nSecond\nline\n\nOne\nTwo\nName\tPrice\nTea\t2.50\nkeep\n  this",
+		root.Find("body").PlainText())
+}
+
+func TestTextOptsBidi(t *testing.T) {
+	// U+200F RIGHT-TO-LEFT MARK and U+202B/U+202C embeddings as scraped from a real page
+	root := HTMLParseFromString("<p>Price \u200f<span dir=\"rtl\">\u202b\u05e9\u05dc\u05d5\u05dd\u202c</span> <span dir=\"ltr\"></span>today</p>")
+	p := root.Find("p")
+
+	require.Equal(t, "Price \u200f\u202b\u05e9\u05dc\u05d5\u05dd\u202c today", p.FullTextOpts(TextOptions{}))
+	require.Equal(t, "Price \u05e9\u05dc\u05d5\u05dd today", p.FullTextOpts(TextOptions{Bidi: BidiStrip}))
+	require.Equal(t, "Price \u2067\u05e9\u05dc\u05d5\u05dd\u2069 today", p.FullTextOpts(TextOptions{Bidi: BidiIsolate}))
+	require.Equal(t, "\u2067\u05e9\u05dc\u05d5\u05dd\u2069", p.Find("span").TextOpts(TextOptions{Bidi: BidiIsolate}))
+}
+
+func TestStrings(t *testing.T) {
+	li := HtmlRoot.Find("ul").Find("li")
+	var all []string
+	for s := range li.Strings() {
+		all = append(all, s)
+	}
+	require.Equal(t, []string{"To a ", "JSP page", " right?"}, all)
+
+	var stripped []string
+	for s := range HtmlRoot.Find("ul").StrippedStrings() {
+		stripped = append(stripped, s)
+		if len(stripped) == 4 {
+			break
+		}
+	}
+	require.Equal(t, []string{"To a", "JSP page", "right?", "To a"}, stripped)
+}
@@ -0,0 +1,56 @@
+package owl
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"os"
+)
+
+// TLSOptions configures the TLS connections of a Client
+type TLSOptions struct {
+	// InsecureSkipVerify accepts any certificate, for internal hosts with self-signed certificates only
+	InsecureSkipVerify bool
+	// RootCAs are the certificate authorities servers are verified against, the system ones when nil.
+	// See CertPool
+	RootCAs *x509.CertPool
+	// Certificates are presented to servers asking for a client certificate, see tls.LoadX509KeyPair
+	Certificates []tls.Certificate
+	// MinVersion and MaxVersion bound the TLS versions, such as tls.VersionTLS12, Go's defaults when 0
+	MinVersion uint16
+	MaxVersion uint16
+}
+
+// SetTLS configures the TLS connections of c with opts, replacing the TLS configuration it had
+func (c *Client) SetTLS(opts TLSOptions) error {
+	t, err := c.transport()
+	if err != nil {
+		return err
+	}
+	t.TLSClientConfig = &tls.Config{
+		InsecureSkipVerify: opts.InsecureSkipVerify,
+		RootCAs:            opts.RootCAs,
+		Certificates:       opts.Certificates,
+		MinVersion:         opts.MinVersion,
+		MaxVersion:         opts.MaxVersion,
+	}
+	return nil
+}
+
+// CertPool returns the system certificate authorities along with those of the PEM files
+func CertPool(pemFiles ...string) (*x509.CertPool, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	for _, file := range pemFiles {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, errors.New("owl: no certificate in " + file)
+		}
+	}
+	return pool, nil
+}
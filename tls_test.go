@@ -0,0 +1,58 @@
+package owl
+
+import (
+	"crypto/tls"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetTLS(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.TLS.PeerCertificates[0].Subject.Organization[0]))
+	}))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert, MaxVersion: tls.VersionTLS12}
+	srv.StartTLS()
+	defer srv.Close()
+	clientCert := srv.TLS.Certificates
+
+	get := func(opts TLSOptions) (string, error) {
+		client := NewClient(&Parameters{TLS: &opts})
+		resp, err := client.GetResponse(srv.URL)
+		if err != nil {
+			return "", err
+		}
+		return string(resp.Body), nil
+	}
+	_, err := get(TLSOptions{Certificates: clientCert})
+	require.Error(t, err)
+
+	body, err := get(TLSOptions{InsecureSkipVerify: true, Certificates: clientCert})
+	require.NoError(t, err)
+	require.Equal(t, "Acme Co", body)
+
+	file := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}), 0o600))
+	pool, err := CertPool(file)
+	require.NoError(t, err)
+	body, err = get(TLSOptions{RootCAs: pool, Certificates: clientCert})
+	require.NoError(t, err)
+	require.Equal(t, "Acme Co", body)
+
+	// The server requires a client certificate and speaks TLS 1.2 at most
+	_, err = get(TLSOptions{RootCAs: pool})
+	require.Error(t, err)
+	_, err = get(TLSOptions{RootCAs: pool, Certificates: clientCert, MinVersion: tls.VersionTLS13})
+	require.Error(t, err)
+
+	_, err = CertPool(filepath.Join(t.TempDir(), "missing.pem"))
+	require.Error(t, err)
+	require.NoError(t, os.WriteFile(file, []byte("not a certificate"), 0o600))
+	_, err = CertPool(file)
+	require.Error(t, err)
+}
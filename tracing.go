@@ -0,0 +1,97 @@
+package owl
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the instrumentation name of the spans of owl
+const tracerName = "github.com/Patrickmitech/owl"
+
+// WithTracer traces the request with tracer instead of the Tracer of the Client
+func WithTracer(tracer trace.Tracer) RequestOption {
+	return requestOptionFunc(func(r *requestConfig) { r.tracer = tracer })
+}
+
+// NewTracer returns the tracer of owl from provider, otel.GetTracerProvider() when it is nil,
+// to set as the Tracer of a Client
+func NewTracer(provider trace.TracerProvider) trace.Tracer {
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	return provider.Tracer(tracerName)
+}
+
+// tracer returns the tracer of a request, nil when it is not traced
+func (c *Client) tracer(cfg *requestConfig) trace.Tracer {
+	if cfg.tracer != nil {
+		return cfg.tracer
+	}
+	return c.Tracer
+}
+
+// startSpan starts the client span of req, which gets the context of the span
+// and the trace context headers of the global propagator
+func startSpan(req *http.Request, tracer trace.Tracer) (*http.Request, trace.Span) {
+	ctx, span := tracer.Start(req.Context(), req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("url.full", req.URL.String()),
+			attribute.String("server.address", req.URL.Hostname()),
+		))
+	req = req.WithContext(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+	return req, span
+}
+
+// endSpan records the outcome of a request on its span, which ends when release is called.
+// It returns the release function to use instead
+func endSpan(span trace.Span, resp *http.Response, release func(), err error) func() {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.End()
+		return release
+	}
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= 400 {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+	return func() {
+		release()
+		span.End()
+	}
+}
+
+// traceRetry records on the span of req that it is sent again
+func traceRetry(req *http.Request, attempt int) {
+	span := trace.SpanFromContext(req.Context())
+	if !span.IsRecording() {
+		return
+	}
+	span.AddEvent("retry", trace.WithAttributes(attribute.Int("http.request.resend_count", attempt)))
+	span.SetAttributes(attribute.Int("http.request.resend_count", attempt))
+}
+
+// parseSpan starts the span of the parse of r, nil when r was not traced
+func (r *Response) parseSpan() trace.Span {
+	if r.tracer == nil {
+		return nil
+	}
+	ctx := trace.ContextWithSpanContext(context.Background(), r.spanContext)
+	_, span := r.tracer.Start(ctx, "owl.Parse", trace.WithAttributes(
+		attribute.Int("owl.body.size", len(r.Body)),
+		attribute.String("owl.content_type", r.ContentType),
+	))
+	if r.FinalURL != nil {
+		span.SetAttributes(attribute.String("url.full", r.FinalURL.String()))
+	}
+	return span
+}
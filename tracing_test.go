@@ -0,0 +1,130 @@
+package owl
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// testTracer records the spans it starts
+type testTracer struct {
+	noop.Tracer
+	mu    sync.Mutex
+	spans []*testSpan
+}
+
+type testSpan struct {
+	noop.Span
+	name   string
+	parent trace.SpanContext
+	sc     trace.SpanContext
+	attrs  map[attribute.Key]attribute.Value
+	events []string
+	status codes.Code
+	ended  bool
+}
+
+func (t *testTracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	cfg := trace.NewSpanStartConfig(opts...)
+	span := &testSpan{name: name, parent: trace.SpanContextFromContext(ctx), attrs: make(map[attribute.Key]attribute.Value)}
+	span.sc = trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{byte(len(t.spans) + 1)},
+		TraceFlags: trace.FlagsSampled,
+	})
+	span.SetAttributes(cfg.Attributes()...)
+	t.spans = append(t.spans, span)
+	return trace.ContextWithSpan(ctx, span), span
+}
+
+func (s *testSpan) SpanContext() trace.SpanContext { return s.sc }
+func (s *testSpan) IsRecording() bool              { return !s.ended }
+func (s *testSpan) End(...trace.SpanEndOption)     { s.ended = true }
+func (s *testSpan) SetStatus(code codes.Code, _ string) {
+	s.status = code
+}
+func (s *testSpan) AddEvent(name string, _ ...trace.EventOption) {
+	s.events = append(s.events, name)
+}
+func (s *testSpan) SetAttributes(attrs ...attribute.KeyValue) {
+	for _, a := range attrs {
+		s.attrs[a.Key] = a.Value
+	}
+}
+
+func TestTracing(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+
+	attempts := 0
+	var traceparent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("Traceparent")
+		if r.URL.Path == "/flaky" {
+			attempts++
+			if attempts == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+		}
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("<title>traced</title>"))
+	}))
+	defer srv.Close()
+
+	tracer := &testTracer{}
+	client := &Client{
+		Client: srv.Client(),
+		Tracer: tracer,
+		Retry:  &RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond},
+	}
+	doc, err := client.GetDocument(srv.URL + "/flaky")
+	require.NoError(t, err)
+	require.Equal(t, "traced", doc.Find("title").Text())
+	require.Len(t, tracer.spans, 2)
+
+	request, parse := tracer.spans[0], tracer.spans[1]
+	require.Equal(t, "GET", request.name)
+	require.True(t, request.ended)
+	require.Equal(t, srv.URL+"/flaky", request.attrs["url.full"].AsString())
+	require.Equal(t, int64(200), request.attrs["http.response.status_code"].AsInt64())
+	require.Equal(t, int64(1), request.attrs["http.request.resend_count"].AsInt64())
+	require.Equal(t, []string{"retry"}, request.events)
+	require.Equal(t, "00-01000000000000000000000000000000-0100000000000000-01", traceparent)
+
+	require.Equal(t, "owl.Parse", parse.name)
+	require.True(t, parse.ended)
+	require.Equal(t, request.sc, parse.parent)
+
+	_, err = client.GetResponse(srv.URL + "/missing")
+	require.NoError(t, err)
+	require.Equal(t, codes.Error, tracer.spans[2].status)
+
+	// WithTracer traces a single request
+	other := &testTracer{}
+	client.Tracer = nil
+	_, err = client.Get(srv.URL+"/", WithTracer(other))
+	require.NoError(t, err)
+	_, err = client.Get(srv.URL + "/")
+	require.NoError(t, err)
+	require.Len(t, other.spans, 1)
+	require.Len(t, tracer.spans, 3)
+
+	require.NotNil(t, NewTracer(nil))
+}
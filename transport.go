@@ -0,0 +1,46 @@
+package owl
+
+import "time"
+
+// TransportOptions tunes the connections of a Client, the fields left zero keep the values of the transport
+type TransportOptions struct {
+	// MaxIdleConns is the number of idle connections kept across all hosts
+	MaxIdleConns int
+	// MaxIdleConnsPerHost is the number of idle connections kept for each host, 2 in net/http
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost is the number of connections to a host at once, dialing included
+	MaxConnsPerHost int
+	// IdleConnTimeout is how long an idle connection is kept
+	IdleConnTimeout time.Duration
+	// ForceAttemptHTTP2 tries HTTP/2 even with a custom dialer or TLS configuration
+	ForceAttemptHTTP2 bool
+	// DisableKeepAlives uses every connection for a single request
+	DisableKeepAlives bool
+}
+
+// SetTransportOptions tunes the connections of c with opts
+func (c *Client) SetTransportOptions(opts TransportOptions) error {
+	t, err := c.transport()
+	if err != nil {
+		return err
+	}
+	if opts.MaxIdleConns > 0 {
+		t.MaxIdleConns = opts.MaxIdleConns
+	}
+	if opts.MaxIdleConnsPerHost > 0 {
+		t.MaxIdleConnsPerHost = opts.MaxIdleConnsPerHost
+	}
+	if opts.MaxConnsPerHost > 0 {
+		t.MaxConnsPerHost = opts.MaxConnsPerHost
+	}
+	if opts.IdleConnTimeout > 0 {
+		t.IdleConnTimeout = opts.IdleConnTimeout
+	}
+	if opts.ForceAttemptHTTP2 {
+		t.ForceAttemptHTTP2 = true
+	}
+	if opts.DisableKeepAlives {
+		t.DisableKeepAlives = true
+	}
+	return nil
+}
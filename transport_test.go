@@ -0,0 +1,64 @@
+package owl
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetTransportOptions(t *testing.T) {
+	var conns atomic.Int32
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Proto))
+	}))
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	srv.EnableHTTP2 = true
+	srv.StartTLS()
+	defer srv.Close()
+
+	client := NewClient(&Parameters{
+		TLS: &TLSOptions{InsecureSkipVerify: true},
+		Transport: &TransportOptions{
+			MaxIdleConnsPerHost: 16,
+			MaxConnsPerHost:     32,
+			IdleConnTimeout:     time.Minute,
+			ForceAttemptHTTP2:   true,
+		},
+	})
+	transport := client.Transport.(*http.Transport)
+	require.Equal(t, 16, transport.MaxIdleConnsPerHost)
+	require.Equal(t, 32, transport.MaxConnsPerHost)
+	require.Equal(t, time.Minute, transport.IdleConnTimeout)
+	// Fields left zero keep the values of the transport
+	require.Equal(t, 100, transport.MaxIdleConns)
+
+	for i := 0; i < 3; i++ {
+		resp, err := client.GetResponse(srv.URL)
+		require.NoError(t, err)
+		require.Equal(t, "HTTP/2.0", string(resp.Body))
+	}
+	require.Equal(t, int32(1), conns.Load())
+
+	conns.Store(0)
+	client = NewClient(&Parameters{
+		TLS:       &TLSOptions{InsecureSkipVerify: true},
+		Transport: &TransportOptions{DisableKeepAlives: true},
+	})
+	for i := 0; i < 3; i++ {
+		_, err := client.GetResponse(srv.URL)
+		require.NoError(t, err)
+	}
+	require.Equal(t, int32(3), conns.Load())
+
+	client = &Client{Client: &http.Client{Transport: roundTripperFunc(nil)}}
+	require.ErrorIs(t, client.SetTransportOptions(TransportOptions{}), ErrNoTransport)
+}
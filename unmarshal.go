@@ -0,0 +1,351 @@
+package owl
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Unmarshal fills the struct v points to from the elements of root, following the struct tags of its fields:
+//
+//	type Product struct {
+//		Name   string   `owl:"h1.title"`
+//		Price  float64  `owl:"span.price" attr:"data-value"`
+//		Image  string   `owl:"img" attr:"src"`
+//		Body   string   `owl:"div.description,html"`
+//		Tags   []string `owl:"ul.tags li"`
+//		Seller struct {
+//			Name string `owl:".name"`
+//		} `owl:"div.seller"`
+//	}
+//
+// The owl tag holds a CSS selector, see Select, followed by options separated by commas:
+// "html" for the rendered element, "innerhtml" for the rendered children, "text" for the text
+// directly inside the element, "required" to fail when nothing matches and "optional" to leave the field
+// as it is when the value is empty, instead of failing to parse it as a number.
+// The value defaults to the trimmed FullText of the element, or to the attribute named by the attr tag.
+// The default tag holds the value used when nothing matches or the value is empty, such as default:"0".
+// The layout tag holds the layout of time.Time fields, such as layout:"2 January 2006", see ParseTime.
+// An empty selector stands for the element being decoded.
+//
+// Slices receive every match, nested structs and pointers to structs are decoded from the first match.
+// Pointers stay nil when nothing matches, so a *string tells an absent element from an empty one.
+// A struct field of type Presence, without tags, records that for every field, see Presence.
+// Strings, booleans, numbers and encoding.TextUnmarshaler implementations are supported,
+// numbers are parsed from the trimmed value
+func Unmarshal(root *Root, v any) error {
+	return unmarshal(root, v, nil, nil)
+}
+
+// UnmarshalLocale is Unmarshal reading the numbers and the month names of the values as written in the Locale l,
+// such as "1.299,50" in LocaleGerman. Thousands separators are allowed, see ParseFloat.
+// HostLocales.Lookup(root.URL()) picks the Locale of the site the document comes from
+func UnmarshalLocale(root *Root, v any, l Locale) error {
+	return unmarshal(root, v, nil, &l)
+}
+
+// unmarshal is Unmarshal scoring the fields in c when it is set, see UnmarshalScored,
+// and parsing values as written in l when it is set
+func unmarshal(root *Root, v any, c *Confidence, l *Locale) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("owl: Unmarshal needs a non-nil pointer to a struct")
+	}
+	if root == nil || root.Node == nil {
+		return errors.New("owl: Unmarshal of an empty Root")
+	}
+	return unmarshalStruct(root, rv.Elem(), "", c, l)
+}
+
+// Presence tells, for every field of a struct filled by Unmarshal with an owl or attr tag, whether an element
+// matched its selector and had a value. Fields whose default tag was used keep the state of the document
+type Presence map[string]FieldPresence
+
+// FieldPresence is the state of a field in the document, see Presence
+type FieldPresence int
+
+const (
+	// FieldAbsent is the state of the fields whose selector matched nothing
+	FieldAbsent FieldPresence = iota
+	// FieldEmpty is the state of the fields whose element has a value made of whitespace only
+	FieldEmpty
+	// FieldPresent is the state of the fields whose element has a value, and of the slices and structs matched
+	FieldPresent
+)
+
+func (p FieldPresence) String() string {
+	switch p {
+	case FieldEmpty:
+		return "empty"
+	case FieldPresent:
+		return "present"
+	}
+	return "absent"
+}
+
+var presenceType = reflect.TypeOf(Presence(nil))
+
+// fieldTag is the parsed form of the owl, attr and default tags of a field
+type fieldTag struct {
+	selector   string
+	attr       string
+	mode       string
+	required   bool
+	optional   bool
+	def        string
+	hasDefault bool
+	layout     string
+}
+
+func parseFieldTag(field reflect.StructField) (fieldTag, bool) {
+	owlTag, hasOwl := field.Tag.Lookup("owl")
+	attr, hasAttr := field.Tag.Lookup("attr")
+	if !hasOwl && !hasAttr {
+		return fieldTag{}, false
+	}
+	parts := strings.Split(owlTag, ",")
+	tag := fieldTag{selector: strings.TrimSpace(parts[0]), attr: attr}
+	tag.def, tag.hasDefault = field.Tag.Lookup("default")
+	tag.layout = field.Tag.Get("layout")
+	for _, opt := range parts[1:] {
+		switch opt = strings.TrimSpace(opt); opt {
+		case "html", "innerhtml", "text":
+			tag.mode = opt
+		case "required":
+			tag.required = true
+		case "optional":
+			tag.optional = true
+		}
+	}
+	return tag, true
+}
+
+func unmarshalStruct(root *Root, v reflect.Value, path string, c *Confidence, l *Locale) error {
+	t := v.Type()
+	var presence Presence
+	for i := 0; i < t.NumField(); i++ {
+		if field := t.Field(i); field.IsExported() && field.Type == presenceType {
+			presence = make(Presence)
+			v.Field(i).Set(reflect.ValueOf(presence))
+		}
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag, ok := parseFieldTag(field)
+		if !ok {
+			continue
+		}
+		name := path + field.Name
+		matches := []*Root{root}
+		if tag.selector != "" {
+			found := root.Select(tag.selector)
+			if found.Error != nil && found.Error.Type == ErrInvalidSelector {
+				return fmt.Errorf("owl: field %s: %w", name, found.Error.Err())
+			}
+			matches = found.Roots
+		}
+		if len(matches) == 0 {
+			if tag.required {
+				return fmt.Errorf("owl: field %s: no element matches %q", name, tag.selector)
+			}
+			c.missing(name, fmt.Sprintf("no element matches %q", tag.selector))
+			if presence != nil {
+				presence[field.Name] = FieldAbsent
+			}
+			if tag.hasDefault {
+				if err := setDefault(v.Field(i), tag, name); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if presence != nil {
+			presence[field.Name] = FieldPresent
+			if decodesValue(field.Type, tag) && strings.TrimSpace(elementValue(matches[0], tag)) == "" {
+				presence[field.Name] = FieldEmpty
+			}
+		}
+		if err := unmarshalField(matches, v.Field(i), tag, name, c, l); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// unmarshalField sets the field f from the elements matches, scoring it in c when it is set
+func unmarshalField(matches []*Root, f reflect.Value, tag fieldTag, name string, c *Confidence, l *Locale) error {
+	if reflect.PointerTo(f.Type()).Implements(textUnmarshalerType) {
+		c.choose(name, len(matches))
+		return unmarshalValue(matches[0], f, tag, name, c, l)
+	}
+	switch f.Kind() {
+	case reflect.Slice:
+		if f.Type().Elem().Kind() == reflect.Uint8 {
+			break
+		}
+		if c != nil {
+			c.Fields++
+			c.Filled++
+		}
+		slice := reflect.MakeSlice(f.Type(), 0, len(matches))
+		for i, m := range matches {
+			elem := reflect.New(f.Type().Elem()).Elem()
+			if err := unmarshalField([]*Root{m}, elem, tag, fmt.Sprintf("%s[%d]", name, i), nil, l); err != nil {
+				return err
+			}
+			slice = reflect.Append(slice, elem)
+		}
+		f.Set(slice)
+		return nil
+	case reflect.Pointer:
+		if tag.optional && !tag.hasDefault && decodesValue(f.Type(), tag) && strings.TrimSpace(elementValue(matches[0], tag)) == "" {
+			// Optional pointers stay nil on empty values
+			return nil
+		}
+		elem := reflect.New(f.Type().Elem())
+		if err := unmarshalField(matches, elem.Elem(), tag, name, c, l); err != nil {
+			return err
+		}
+		f.Set(elem)
+		return nil
+	case reflect.Struct:
+		if tag.attr == "" && tag.mode == "" {
+			c.choose(name, len(matches))
+			return unmarshalStruct(matches[0], f, name+".", c, l)
+		}
+	}
+	c.choose(name, len(matches))
+	return unmarshalValue(matches[0], f, tag, name, c, l)
+}
+
+// unmarshalValue sets the scalar field f from the value of the element m
+func unmarshalValue(m *Root, f reflect.Value, tag fieldTag, name string, c *Confidence, l *Locale) error {
+	s := elementValue(m, tag)
+	c.text(name, s, tag.mode != "html" && tag.mode != "innerhtml")
+	if strings.TrimSpace(s) == "" {
+		switch {
+		case tag.hasDefault:
+			// Defaults are not written in the Locale
+			return setValue(f, tag.def, name, tag, nil)
+		case tag.optional:
+			return nil
+		}
+	}
+	return setValue(f, s, name, tag, l)
+}
+
+// elementValue returns the value of the element m for a field with tag
+func elementValue(m *Root, tag fieldTag) string {
+	switch {
+	case tag.attr != "":
+		return m.AttrOr(tag.attr, "")
+	case tag.mode == "html":
+		return string(m.Render())
+	case tag.mode == "innerhtml":
+		return m.InnerHTML()
+	case tag.mode == "text":
+		return strings.TrimSpace(m.Text())
+	}
+	return strings.TrimSpace(m.FullText())
+}
+
+// decodesValue reports whether a field of type t with tag is set from the value of an element,
+// rather than being a slice of matches or a struct decoded from the fields of an element
+func decodesValue(t reflect.Type, tag fieldTag) bool {
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.Uint8
+	case reflect.Pointer:
+		return decodesValue(t.Elem(), tag)
+	case reflect.Struct:
+		return tag.attr != "" || tag.mode != ""
+	}
+	return true
+}
+
+// setDefault sets the field f, whose selector matched nothing, to the value of the default tag
+func setDefault(f reflect.Value, tag fieldTag, name string) error {
+	if f.Kind() == reflect.Pointer && !reflect.PointerTo(f.Type()).Implements(textUnmarshalerType) {
+		elem := reflect.New(f.Type().Elem())
+		if err := setDefault(elem.Elem(), tag, name); err != nil {
+			return err
+		}
+		f.Set(elem)
+		return nil
+	}
+	return setValue(f, tag.def, name, tag, nil)
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// setValue is setString parsing times with the layout of tag, and numbers as written in l when it is set
+func setValue(f reflect.Value, s string, name string, tag fieldTag, l *Locale) error {
+	switch {
+	case tag.layout != "" && f.Type() == timeType:
+		t, err := parseTimeIn(l, s, []string{tag.layout})
+		if err != nil {
+			return fmt.Errorf("owl: field %s: %w", name, err)
+		}
+		f.Set(reflect.ValueOf(t))
+		return nil
+	case l != nil && f.Kind() >= reflect.Int && f.Kind() <= reflect.Float64:
+		number, err := cleanNumber(s, l.Decimal)
+		if err != nil {
+			return fmt.Errorf("owl: field %s: %w", name, err)
+		}
+		s = number
+	}
+	return setString(f, s, name)
+}
+
+// setString converts s to the type of f and sets it, name is the field path used in errors
+func setString(f reflect.Value, s string, name string) error {
+	if u, ok := f.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		if err := u.UnmarshalText([]byte(s)); err != nil {
+			return fmt.Errorf("owl: field %s: %w", name, err)
+		}
+		return nil
+	}
+	trimmed := strings.TrimSpace(s)
+	var err error
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(s)
+	case reflect.Slice:
+		f.SetBytes([]byte(s))
+	case reflect.Bool:
+		var b bool
+		b, err = strconv.ParseBool(trimmed)
+		f.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var n int64
+		n, err = strconv.ParseInt(trimmed, 10, f.Type().Bits())
+		f.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var n uint64
+		n, err = strconv.ParseUint(trimmed, 10, f.Type().Bits())
+		f.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		var n float64
+		n, err = strconv.ParseFloat(trimmed, f.Type().Bits())
+		f.SetFloat(n)
+	default:
+		return fmt.Errorf("owl: field %s: unsupported type %s", name, f.Type())
+	}
+	if err != nil {
+		return fmt.Errorf("owl: field %s: %w", name, err)
+	}
+	return nil
+}
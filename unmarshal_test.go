@@ -0,0 +1,144 @@
+package owl
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const productPage = `<html><body>
+<div class="product">
+	<h1 class="title">  Owl   plush </h1>
+	<span class="price" data-value="19.99">€19.99</span>
+	<span class="stock">12</span>
+	<span class="sale">true</span>
+	<img src="/owl.jpg">
+	<div class="description"><p>Soft <b>and</b> cuddly</p></div>
+	<ul class="tags"><li>toys</li><li>birds</li></ul>
+	<div class="seller"><span class="name">Owl Shop</span><a href="/shop">Visit</a></div>
+	<table class="specs">
+		<tr><th>Height</th><td>20</td></tr>
+		<tr><th>Width</th><td>15</td></tr>
+	</table>
+	<code class="server">192.0.2.1</code>
+</div>
+</body></html>`
+
+func TestUnmarshal(t *testing.T) {
+	type spec struct {
+		Name  string `owl:"th"`
+		Value int    `owl:"td"`
+	}
+	var product struct {
+		Name        string   `owl:"h1.title"`
+		Price       float64  `owl:"span.price" attr:"data-value"`
+		Stock       int      `owl:".stock"`
+		Sale        bool     `owl:".sale"`
+		Image       string   `owl:"img" attr:"src"`
+		Description string   `owl:"div.description,innerhtml"`
+		Tags        []string `owl:"ul.tags li"`
+		Seller      struct {
+			Name string `owl:".name"`
+			URL  string `owl:"a" attr:"href"`
+			HTML string `owl:",html"`
+		} `owl:"div.seller"`
+		Specs    []spec      `owl:"table.specs tr"`
+		Server   *netip.Addr `owl:"code.server"`
+		Missing  *string     `owl:"del"`
+		Ignored  string
+		internal string `owl:"h1"`
+	}
+	require.NoError(t, Unmarshal(HTMLParseFromString(productPage), &product))
+
+	require.Equal(t, "Owl   plush", product.Name)
+	require.Equal(t, 19.99, product.Price)
+	require.Equal(t, 12, product.Stock)
+	require.True(t, product.Sale)
+	require.Equal(t, "/owl.jpg", product.Image)
+	require.Equal(t, "<p>Soft <b>and</b> cuddly</p>", product.Description)
+	require.Equal(t, []string{"toys", "birds"}, product.Tags)
+	require.Equal(t, "Owl Shop", product.Seller.Name)
+	require.Equal(t, "/shop", product.Seller.URL)
+	require.Contains(t, product.Seller.HTML, `<div class="seller">`)
+	require.Equal(t, []spec{{"Height", 20}, {"Width", 15}}, product.Specs)
+	require.Equal(t, "192.0.2.1", product.Server.String())
+	require.Nil(t, product.Missing)
+	require.Empty(t, product.internal)
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	doc := HTMLParseFromString(productPage)
+
+	var notStruct string
+	require.Error(t, Unmarshal(doc, &notStruct))
+
+	var required struct {
+		Discount string `owl:"span.discount,required"`
+	}
+	require.EqualError(t, Unmarshal(doc, &required), `owl: field Discount: no element matches "span.discount"`)
+
+	var badNumber struct {
+		Seller struct {
+			Name int `owl:".name"`
+		} `owl:".seller"`
+	}
+	err := Unmarshal(doc, &badNumber)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "field Seller.Name")
+
+	var badSelector struct {
+		Name string `owl:"h1["`
+	}
+	require.Error(t, Unmarshal(doc, &badSelector))
+}
+
+func TestUnmarshalPresence(t *testing.T) {
+	doc := HTMLParseFromString(`<h1>Owl plush</h1><span class="stock"> </span><span class="note"></span>
+		<span class="rating">4.5</span><div class="seller"><b class="name">Hoot</b></div>`)
+	var product struct {
+		Name     string   `owl:"h1"`
+		Stock    int      `owl:".stock" default:"0"`
+		Discount float64  `owl:".discount" default:"0.1"`
+		Rating   *float64 `owl:".rating"`
+		Reviews  *int     `owl:".reviews"`
+		Note     *string  `owl:".note"`
+		Color    *string  `owl:".color"`
+		Weight   *float64 `owl:".note,optional"`
+		Size     int      `owl:".stock,optional"`
+		Seller   struct {
+			Name     string `owl:".name"`
+			City     string `owl:".city" default:"Paris"`
+			Presence Presence
+		} `owl:".seller"`
+		Presence Presence
+	}
+	require.NoError(t, Unmarshal(doc, &product))
+	require.Equal(t, 0, product.Stock)
+	require.Equal(t, 0.1, product.Discount)
+	require.Equal(t, 4.5, *product.Rating)
+	require.Nil(t, product.Reviews)
+	require.Equal(t, "", *product.Note)
+	require.Nil(t, product.Color)
+	require.Nil(t, product.Weight)
+	require.Equal(t, 0, product.Size)
+	require.Equal(t, "Paris", product.Seller.City)
+
+	require.Equal(t, Presence{
+		"Name": FieldPresent, "Stock": FieldEmpty, "Discount": FieldAbsent, "Rating": FieldPresent,
+		"Reviews": FieldAbsent, "Note": FieldEmpty, "Color": FieldAbsent, "Weight": FieldEmpty,
+		"Size": FieldEmpty, "Seller": FieldPresent,
+	}, product.Presence)
+	require.Equal(t, Presence{"Name": FieldPresent, "City": FieldAbsent}, product.Seller.Presence)
+	require.Equal(t, "empty", FieldEmpty.String())
+
+	// Empty numbers fail without optional or default
+	var strict struct {
+		Stock int `owl:".stock"`
+	}
+	require.ErrorContains(t, Unmarshal(doc, &strict), "field Stock")
+	var badDefault struct {
+		Stock int `owl:".missing" default:"many"`
+	}
+	require.ErrorContains(t, Unmarshal(doc, &badDefault), "field Stock")
+}
@@ -0,0 +1,65 @@
+package owl
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// ErrNoBaseURL is returned by ResolveURL for relative references when the document has no base URL
+var ErrNoBaseURL = errors.New("owl: relative URL without a base URL")
+
+// URL returns the URL the document was fetched from after redirects, nil when it was parsed from elsewhere
+func (r *Root) URL() *url.URL {
+	if r.doc == nil {
+		return nil
+	}
+	return r.doc.url
+}
+
+// SetURL records u as the URL of the document, for documents that were not fetched by Visit
+func (r *Root) SetURL(u *url.URL) *Root {
+	if r.doc != nil {
+		r.doc.url = u
+	}
+	return r
+}
+
+// BaseURL returns the URL relative references of the document resolve against:
+// the href of its base element resolved against URL, or URL itself
+func (r *Root) BaseURL() *url.URL {
+	return documentBase(r.Node, r.URL())
+}
+
+// ResolveURL resolves href against BaseURL,
+// absolute URLs are returned as they are even when the document has no base URL
+func (r *Root) ResolveURL(href string) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimSpace(href))
+	if err != nil {
+		return nil, err
+	}
+	if u.IsAbs() {
+		return u, nil
+	}
+	base := r.BaseURL()
+	if base == nil {
+		return nil, ErrNoBaseURL
+	}
+	return base.ResolveReference(u), nil
+}
+
+// resolveString returns href resolved by ResolveURL, or href itself trimmed when it can not be resolved
+func (r *Root) resolveString(href string) string {
+	if u, err := r.ResolveURL(href); err == nil {
+		return u.String()
+	}
+	return strings.TrimSpace(href)
+}
+
+// pageURL returns base, or the URL of the document when base is nil
+func (r *Root) pageURL(base *url.URL) *url.URL {
+	if base != nil {
+		return base
+	}
+	return r.URL()
+}
@@ -0,0 +1,49 @@
+package owl
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocumentURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/blog/post", http.StatusMovedPermanently)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(`<html><body><a href="next">Next</a><img src="/owl.png"></body></html>`))
+	}))
+	defer srv.Close()
+
+	doc, err := HttpClientWrapper(srv.Client()).GetDocument(srv.URL + "/old")
+	require.NoError(t, err)
+	require.Equal(t, srv.URL+"/blog/post", doc.URL().String())
+	require.Equal(t, doc.URL(), doc.Find("a").URL())
+
+	u, err := doc.ResolveURL("next")
+	require.NoError(t, err)
+	require.Equal(t, srv.URL+"/blog/next", u.String())
+	require.Equal(t, srv.URL+"/blog/next", doc.Links(nil)[0].URL.String())
+	require.Equal(t, srv.URL+"/owl.png", doc.Images(nil)[0].URL.String())
+}
+
+func TestResolveURL(t *testing.T) {
+	doc := HTMLParseFromString(`<html><head><base href="/static/"></head><body></body></html>`)
+	_, err := doc.ResolveURL("owl.png")
+	require.ErrorIs(t, err, ErrNoBaseURL)
+	u, err := doc.ResolveURL("https://example.org/owl.png")
+	require.NoError(t, err)
+	require.Equal(t, "https://example.org/owl.png", u.String())
+
+	page, _ := url.Parse("https://example.com/docs/index.html")
+	doc.SetURL(page)
+	require.Equal(t, "https://example.com/static/", doc.BaseURL().String())
+	u, err = doc.ResolveURL("owl.png")
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/static/owl.png", u.String())
+}
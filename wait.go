@@ -0,0 +1,44 @@
+package owl
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// defaultWaitInterval is the time between two fetches of WaitFor when none is given
+const defaultWaitInterval = time.Second
+
+// WaitFor calls fetch every interval, 1s when it is 0, until the document it returns has an element matching
+// the CSS selector, and returns that document. Failed fetches are tried again.
+// When ctx is done first, the last document fetched is returned with an error wrapping the error of ctx
+// and the one of the last failed fetch
+func WaitFor(ctx context.Context, fetch func() (*Root, error), selector string, interval time.Duration) (*Root, error) {
+	s, err := compileSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	if interval <= 0 {
+		interval = defaultWaitInterval
+	}
+	var last *Root
+	var lastErr error
+	for {
+		root, err := fetch()
+		switch {
+		case err != nil:
+			lastErr = err
+		case root != nil && root.Node != nil:
+			if s.MatchFirst(root.Node) != nil {
+				return root, nil
+			}
+			last, lastErr = root, nil
+		}
+		if err := sleep(ctx, interval); err != nil {
+			if lastErr != nil {
+				return last, fmt.Errorf("owl: waiting for %s: %w: %w", selector, err, lastErr)
+			}
+			return last, fmt.Errorf("owl: waiting for %s: %w", selector, err)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package owl
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitFor(t *testing.T) {
+	fetches := 0
+	fetch := func() (*Root, error) {
+		fetches++
+		switch fetches {
+		case 1:
+			return HTMLParse(strings.NewReader(`<div id="results">Loading</div>`)), nil
+		case 2:
+			return nil, errors.New("queue busy")
+		}
+		return HTMLParse(strings.NewReader(`<div id="results"><p class="item">owl</p></div>`)), nil
+	}
+	doc, err := WaitFor(context.Background(), fetch, "#results .item", time.Millisecond)
+	require.NoError(t, err)
+	require.Equal(t, "owl", doc.Find("p").Text())
+	require.Equal(t, 3, fetches)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	pending := func() (*Root, error) {
+		return HTMLParse(strings.NewReader(`<div id="results">Loading</div>`)), nil
+	}
+	doc, err = WaitFor(ctx, pending, "#results .item", 5*time.Millisecond)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Equal(t, "Loading", doc.Find("div").Text())
+
+	queueErr := errors.New("queue busy")
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = WaitFor(ctx, func() (*Root, error) { return nil, queueErr }, "p", 5*time.Millisecond)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.ErrorIs(t, err, queueErr)
+
+	_, err = WaitFor(context.Background(), pending, "p[", time.Millisecond)
+	require.Error(t, err)
+}
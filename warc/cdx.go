@@ -0,0 +1,204 @@
+package warc
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/Patrickmitech/owl"
+)
+
+// timestampLayout is the layout of the timestamps of CDX indexes
+const timestampLayout = "20060102150405"
+
+// cdxFields are the fields of the lines of CDX files without a header: the SURT of the URL,
+// its timestamp, URL, MIME type, status, digest, redirect, meta tags, length, offset and filename
+const cdxFields = "NbamskrMSVg"
+
+// CDXRecord is an entry of a CDX index, locating the record of a URL in a WARC file
+type CDXRecord struct {
+	// URLKey is the SURT of URL, the index is sorted by it
+	URLKey string
+	// Timestamp is the time of the capture as YYYYMMDDhhmmss
+	Timestamp string
+	URL       string
+	MIME      string
+	Status    int
+	// Digest is the SHA-1 digest of the payload in base32
+	Digest string
+	// Length is the length of the record in the WARC file, compressed when the file is
+	Length int64
+	// Offset is the offset of the record in the WARC file
+	Offset   int64
+	Filename string
+}
+
+// cdxjFields are the JSON fields of the CDXJ lines, numbers are read from strings too
+type cdxjFields struct {
+	URL      string      `json:"url"`
+	MIME     string      `json:"mime,omitempty"`
+	Status   json.Number `json:"status,omitempty"`
+	Digest   string      `json:"digest,omitempty"`
+	Length   json.Number `json:"length,omitempty"`
+	Offset   json.Number `json:"offset"`
+	Filename string      `json:"filename,omitempty"`
+}
+
+// cdxjOutput are the JSON fields of the CDXJ lines written, numbers are written as strings as Common Crawl does
+type cdxjOutput struct {
+	URL      string `json:"url"`
+	MIME     string `json:"mime,omitempty"`
+	Status   string `json:"status,omitempty"`
+	Digest   string `json:"digest,omitempty"`
+	Length   string `json:"length"`
+	Offset   string `json:"offset"`
+	Filename string `json:"filename,omitempty"`
+}
+
+// ReadCDX reads a CDX index, either CDXJ, as Common Crawl and pywb write them, or the classic
+// space separated format whose fields are given by its " CDX" header line
+func ReadCDX(r io.Reader) ([]CDXRecord, error) {
+	var records []CDXRecord
+	fields := cdxFields
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "CDX ") {
+			fields = strings.Join(strings.Fields(line)[1:], "")
+			continue
+		}
+		var rec CDXRecord
+		var err error
+		if i := strings.Index(line, " {"); i >= 0 {
+			rec, err = parseCDXJ(line[:i], line[i+1:])
+		} else {
+			rec, err = parseCDX(strings.Fields(line), fields)
+		}
+		if err != nil {
+			return records, fmt.Errorf("warc: CDX line %d: %w", n, err)
+		}
+		records = append(records, rec)
+	}
+	return records, scanner.Err()
+}
+
+func parseCDXJ(prefix, data string) (CDXRecord, error) {
+	var rec CDXRecord
+	key, timestamp, ok := strings.Cut(prefix, " ")
+	if !ok {
+		return rec, fmt.Errorf("missing timestamp")
+	}
+	var fields cdxjFields
+	if err := json.Unmarshal([]byte(data), &fields); err != nil {
+		return rec, err
+	}
+	rec = CDXRecord{URLKey: key, Timestamp: timestamp, URL: fields.URL, MIME: fields.MIME, Digest: fields.Digest, Filename: fields.Filename}
+	var err error
+	if fields.Status != "" {
+		var status int64
+		status, err = fields.Status.Int64()
+		rec.Status = int(status)
+	}
+	if err == nil && fields.Length != "" {
+		rec.Length, err = fields.Length.Int64()
+	}
+	if err == nil && fields.Offset != "" {
+		rec.Offset, err = fields.Offset.Int64()
+	}
+	return rec, err
+}
+
+func parseCDX(values []string, fields string) (CDXRecord, error) {
+	var rec CDXRecord
+	if len(values) != len(fields) {
+		return rec, fmt.Errorf("%d fields, not %d", len(values), len(fields))
+	}
+	for i, value := range values {
+		if value == "-" {
+			continue
+		}
+		var err error
+		switch fields[i] {
+		case 'N':
+			rec.URLKey = value
+		case 'b':
+			rec.Timestamp = value
+		case 'a':
+			rec.URL = value
+		case 'm':
+			rec.MIME = value
+		case 's':
+			rec.Status, err = strconv.Atoi(value)
+		case 'k':
+			rec.Digest = value
+		case 'S':
+			rec.Length, err = strconv.ParseInt(value, 10, 64)
+		case 'V':
+			rec.Offset, err = strconv.ParseInt(value, 10, 64)
+		case 'g':
+			rec.Filename = value
+		}
+		if err != nil {
+			return rec, err
+		}
+	}
+	return rec, nil
+}
+
+// WriteCDXJ writes records as a CDXJ index, sort them by URLKey and Timestamp first for lookups
+func WriteCDXJ(w io.Writer, records []CDXRecord) error {
+	bw := bufio.NewWriter(w)
+	for _, rec := range records {
+		fields := cdxjOutput{
+			URL:      rec.URL,
+			MIME:     rec.MIME,
+			Digest:   rec.Digest,
+			Length:   strconv.FormatInt(rec.Length, 10),
+			Offset:   strconv.FormatInt(rec.Offset, 10),
+			Filename: rec.Filename,
+		}
+		if rec.Status != 0 {
+			fields.Status = strconv.Itoa(rec.Status)
+		}
+		data, err := json.Marshal(fields)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(bw, "%s %s %s\n", rec.URLKey, rec.Timestamp, data)
+	}
+	return bw.Flush()
+}
+
+// SURT returns the Sort-friendly URI Reordering Transform of u that CDX indexes are keyed by,
+// such as com,example)/path?a=1&b=2 for https://www.example.com/path?b=2&a=1
+func SURT(u string) string {
+	normalized, err := owl.NormalizeURL(u, owl.KeepTrackingParams(), owl.StripWWW())
+	if err != nil {
+		return strings.ToLower(u)
+	}
+	parsed, err := url.Parse(normalized)
+	if err != nil || parsed.Host == "" {
+		return strings.ToLower(normalized)
+	}
+	labels := strings.Split(parsed.Hostname(), ".")
+	for i, j := 0, len(labels)-1; i < j; i, j = i+1, j-1 {
+		labels[i], labels[j] = labels[j], labels[i]
+	}
+	key := strings.Join(labels, ",")
+	if port := parsed.Port(); port != "" {
+		key += ":" + port
+	}
+	path := parsed.EscapedPath()
+	if parsed.RawQuery != "" {
+		path += "?" + parsed.RawQuery
+	}
+	return key + ")" + strings.ToLower(path)
+}
@@ -0,0 +1,61 @@
+package warc
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadCDX(t *testing.T) {
+	cdxj := `com,example)/ 20240501120000 {"url": "https://example.com/", "mime": "text/html", "status": "200", "digest": "ABC", "length": "1043", "offset": "5120", "filename": "crawl-1.warc.gz"}
+com,example)/about 20240501120005 {"url": "https://example.com/about", "status": 301, "offset": 0}
+`
+	records, err := ReadCDX(strings.NewReader(cdxj))
+	require.NoError(t, err)
+	require.Equal(t, []CDXRecord{
+		{URLKey: "com,example)/", Timestamp: "20240501120000", URL: "https://example.com/", MIME: "text/html",
+			Status: 200, Digest: "ABC", Length: 1043, Offset: 5120, Filename: "crawl-1.warc.gz"},
+		{URLKey: "com,example)/about", Timestamp: "20240501120005", URL: "https://example.com/about", Status: 301},
+	}, records)
+
+	classic := ` CDX N b a m s k r M S V g
+com,example)/ 20240501120000 https://example.com/ text/html 200 ABC - - 1043 5120 crawl-1.warc.gz
+com,example)/robots.txt 20240501120001 https://example.com/robots.txt - - - - - 10 6163 crawl-1.warc.gz
+`
+	records, err = ReadCDX(strings.NewReader(classic))
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	require.Equal(t, CDXRecord{URLKey: "com,example)/", Timestamp: "20240501120000", URL: "https://example.com/",
+		MIME: "text/html", Status: 200, Digest: "ABC", Length: 1043, Offset: 5120, Filename: "crawl-1.warc.gz"}, records[0])
+	require.Zero(t, records[1].Status)
+
+	_, err = ReadCDX(strings.NewReader("com,example)/ 20240501120000 https://example.com/\n"))
+	require.ErrorContains(t, err, "CDX line 1")
+	_, err = ReadCDX(strings.NewReader("com,example)/ 20240501120000 {\"offset\": \"x\"}\n"))
+	require.Error(t, err)
+}
+
+func TestWriteCDXJ(t *testing.T) {
+	records := []CDXRecord{{URLKey: "com,example)/", Timestamp: "20240501120000", URL: "https://example.com/",
+		MIME: "text/html", Status: 200, Digest: "ABC", Length: 1043, Offset: 5120, Filename: "crawl.warc.gz"}}
+	var buf bytes.Buffer
+	require.NoError(t, WriteCDXJ(&buf, records))
+	require.Equal(t, `com,example)/ 20240501120000 {"url":"https://example.com/","mime":"text/html","status":"200",`+
+		`"digest":"ABC","length":"1043","offset":"5120","filename":"crawl.warc.gz"}`+"\n", buf.String())
+	read, err := ReadCDX(&buf)
+	require.NoError(t, err)
+	require.Equal(t, records, read)
+}
+
+func TestSURT(t *testing.T) {
+	for u, key := range map[string]string{
+		"https://www.Example.com/Path?b=2&a=1#top": "com,example)/path?a=1&b=2",
+		"http://example.com":                       "com,example)/",
+		"http://sub.example.co.uk:8080/a":          "uk,co,example,sub:8080)/a",
+		"https://example.com/?utm_source=x":        "com,example)/?utm_source=x",
+	} {
+		require.Equal(t, key, SURT(u), u)
+	}
+}
@@ -0,0 +1,516 @@
+// Package warc writes the responses of an owl.Client to WARC (ISO 28500) files and reads WARC files,
+// such as those of Common Crawl, back into documents. CDX indexes locate the records of a file by URL:
+//
+//	f, err := os.Create("crawl.warc.gz")
+//	w := warc.NewGzipWriter(f)
+//	warc.RecordClient(client, w)
+//	...
+//	err = warc.WriteCDXJ(index, w.Index())
+package warc
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Patrickmitech/owl"
+)
+
+// Version is the WARC version of the records written
+const Version = "WARC/1.1"
+
+// Record types
+const (
+	TypeWarcinfo = "warcinfo"
+	TypeResponse = "response"
+	TypeRequest  = "request"
+	TypeResource = "resource"
+	TypeMetadata = "metadata"
+	TypeRevisit  = "revisit"
+)
+
+// ErrNotResponse is returned by Record.Response for the records that do not hold an HTTP response
+var ErrNotResponse = errors.New("warc: record is not an HTTP response")
+
+// Field is a named field of the header of a record
+type Field struct {
+	Name  string
+	Value string
+}
+
+// Header is the header of a record, its fields in order
+type Header []Field
+
+// Get returns the value of the first field named name, ignoring case
+func (h Header) Get(name string) string {
+	for _, f := range h {
+		if strings.EqualFold(f.Name, name) {
+			return f.Value
+		}
+	}
+	return ""
+}
+
+// Set replaces the fields named name, ignoring case, by one with value
+func (h *Header) Set(name, value string) {
+	for i, f := range *h {
+		if strings.EqualFold(f.Name, name) {
+			(*h)[i].Value = value
+			h.del(name, i+1)
+			return
+		}
+	}
+	*h = append(*h, Field{Name: name, Value: value})
+}
+
+// del removes the fields named name from the index from on
+func (h *Header) del(name string, from int) {
+	fields := (*h)[:from]
+	for _, f := range (*h)[from:] {
+		if !strings.EqualFold(f.Name, name) {
+			fields = append(fields, f)
+		}
+	}
+	*h = fields
+}
+
+// Record is a WARC record, its Content is the block of the record
+type Record struct {
+	// Version is the version of the record, such as WARC/1.0, Version when empty
+	Version string
+	Header  Header
+	Content []byte
+}
+
+// Type returns the WARC-Type of the record
+func (r *Record) Type() string {
+	return r.Header.Get("WARC-Type")
+}
+
+// TargetURI returns the WARC-Target-URI of the record, without the angle brackets of WARC 1.0
+func (r *Record) TargetURI() string {
+	return strings.TrimSuffix(strings.TrimPrefix(r.Header.Get("WARC-Target-URI"), "<"), ">")
+}
+
+// Date returns the WARC-Date of the record, the zero time when it has none
+func (r *Record) Date() time.Time {
+	date, _ := time.Parse(time.RFC3339Nano, r.Header.Get("WARC-Date"))
+	return date
+}
+
+// Response parses the HTTP response of a response record, its body decoded from its Content-Encoding.
+// The FinalURL of the Response is the target URI of the record
+func (r *Record) Response() (*owl.Response, error) {
+	if r.Type() != TypeResponse && r.Type() != TypeResource {
+		return nil, ErrNotResponse
+	}
+	u, err := url.Parse(r.TargetURI())
+	if err != nil {
+		return nil, fmt.Errorf("warc: %w", err)
+	}
+	if r.Type() == TypeResource {
+		return &owl.Response{StatusCode: http.StatusOK, Header: make(http.Header),
+			ContentType: r.Header.Get("Content-Type"), FinalURL: u, Body: r.Content}, nil
+	}
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/http" {
+		return nil, ErrNotResponse
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(r.Content)), &http.Request{Method: http.MethodGet, URL: u})
+	if err != nil {
+		return nil, fmt.Errorf("warc: %s: %w", u, err)
+	}
+	owl.DecodeContent(resp)
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, fmt.Errorf("warc: %s: %w", u, err)
+	}
+	return &owl.Response{
+		StatusCode:  resp.StatusCode,
+		Header:      resp.Header,
+		ContentType: resp.Header.Get("Content-Type"),
+		FinalURL:    u,
+		Body:        body,
+	}, nil
+}
+
+// Parse parses the document of a response record, see Record.Response
+func (r *Record) Parse() (*owl.Root, error) {
+	resp, err := r.Response()
+	if err != nil {
+		return nil, err
+	}
+	return resp.Parse(), nil
+}
+
+// Reader reads the records of a WARC file, compressed with gzip or not
+type Reader struct {
+	r *bufio.Reader
+}
+
+// NewReader returns a Reader of the records of r, it is decompressed when it starts with gzip data
+func NewReader(r io.Reader) (*Reader, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(2)
+	if err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("warc: %w", err)
+		}
+		br = bufio.NewReader(gz)
+	}
+	return &Reader{r: br}, nil
+}
+
+// Next returns the next record, io.EOF after the last one
+func (r *Reader) Next() (*Record, error) {
+	var version string
+	for version == "" {
+		line, err := r.r.ReadString('\n')
+		if err == io.EOF && line == "" {
+			return nil, io.EOF
+		}
+		if err != nil && err != io.EOF {
+			return nil, fmt.Errorf("warc: %w", err)
+		}
+		// Blank lines between records are skipped
+		version = strings.TrimRight(line, "\r\n")
+		if err == io.EOF && version == "" {
+			return nil, io.EOF
+		}
+	}
+	if !strings.HasPrefix(version, "WARC/") {
+		return nil, fmt.Errorf("warc: invalid record version %q", version)
+	}
+	rec := &Record{Version: version}
+	for {
+		line, err := r.r.ReadString('\n')
+		if err != nil {
+			return nil, fmt.Errorf("warc: record header: %w", unexpected(err))
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+		if (line[0] == ' ' || line[0] == '\t') && len(rec.Header) > 0 {
+			rec.Header[len(rec.Header)-1].Value += " " + strings.TrimSpace(line)
+			continue
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("warc: invalid header line %q", line)
+		}
+		rec.Header = append(rec.Header, Field{Name: strings.TrimSpace(name), Value: strings.TrimSpace(value)})
+	}
+	length, err := strconv.ParseInt(rec.Header.Get("Content-Length"), 10, 64)
+	if err != nil || length < 0 {
+		return nil, fmt.Errorf("warc: invalid Content-Length %q", rec.Header.Get("Content-Length"))
+	}
+	rec.Content = make([]byte, length)
+	if _, err := io.ReadFull(r.r, rec.Content); err != nil {
+		return nil, fmt.Errorf("warc: record content: %w", unexpected(err))
+	}
+	return rec, nil
+}
+
+func unexpected(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// ReadRecordAt reads the record at offset in r, length bytes long, such as the record of a CDXRecord
+// in a WARC file compressed record by record
+func ReadRecordAt(r io.ReaderAt, offset, length int64) (*Record, error) {
+	rr, err := NewReader(io.NewSectionReader(r, offset, length))
+	if err != nil {
+		return nil, err
+	}
+	return rr.Next()
+}
+
+// Documents parses the documents of the HTML response records of r in order,
+// every Root records the target URI of its record
+func Documents(r io.Reader) ([]*owl.Root, error) {
+	rr, err := NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	var docs []*owl.Root
+	for {
+		rec, err := rr.Next()
+		if err == io.EOF {
+			return docs, nil
+		}
+		if err != nil {
+			return docs, err
+		}
+		resp, err := rec.Response()
+		if err != nil {
+			continue
+		}
+		if mediaType, _, _ := mime.ParseMediaType(resp.ContentType); mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+			continue
+		}
+		docs = append(docs, resp.Parse())
+	}
+}
+
+// Writer writes records to a WARC file, keeping the CDX index of the responses it writes.
+// It is safe for concurrent use
+type Writer struct {
+	// Filename is the name of the file written, for the index
+	Filename string
+
+	mu     sync.Mutex
+	w      io.Writer
+	gzip   bool
+	offset int64
+	index  []CDXRecord
+}
+
+// NewWriter returns a Writer of uncompressed records to w
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// NewGzipWriter returns a Writer compressing every record to w as a gzip member of its own,
+// as .warc.gz files are, so the records can be read at their offset
+func NewGzipWriter(w io.Writer) *Writer {
+	return &Writer{w: w, gzip: true}
+}
+
+// WriteRecord writes rec, its WARC-Record-ID, WARC-Date and Content-Length are set when missing
+func (w *Writer) WriteRecord(rec *Record) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, _, err := w.write(rec)
+	return err
+}
+
+// write writes rec and returns its offset and length
+func (w *Writer) write(rec *Record) (int64, int64, error) {
+	if rec.Header.Get("WARC-Record-ID") == "" {
+		rec.Header.Set("WARC-Record-ID", newRecordID())
+	}
+	if rec.Header.Get("WARC-Date") == "" {
+		rec.Header.Set("WARC-Date", time.Now().UTC().Format(time.RFC3339))
+	}
+	rec.Header.Set("Content-Length", strconv.Itoa(len(rec.Content)))
+	version := rec.Version
+	if version == "" {
+		version = Version
+	}
+
+	var buf bytes.Buffer
+	var out io.Writer = &buf
+	var gz *gzip.Writer
+	if w.gzip {
+		gz = gzip.NewWriter(&buf)
+		out = gz
+	}
+	fmt.Fprintf(out, "%s\r\n", version)
+	for _, f := range rec.Header {
+		fmt.Fprintf(out, "%s: %s\r\n", f.Name, f.Value)
+	}
+	io.WriteString(out, "\r\n")
+	out.Write(rec.Content)
+	io.WriteString(out, "\r\n\r\n")
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			return 0, 0, err
+		}
+	}
+	n, err := w.w.Write(buf.Bytes())
+	offset := w.offset
+	w.offset += int64(n)
+	return offset, int64(n), err
+}
+
+// WriteWarcinfo writes a warcinfo record with fields, such as software and operator,
+// it usually starts a file
+func (w *Writer) WriteWarcinfo(fields Header) error {
+	var content bytes.Buffer
+	for _, f := range fields {
+		fmt.Fprintf(&content, "%s: %s\r\n", f.Name, f.Value)
+	}
+	return w.WriteRecord(&Record{
+		Header: Header{
+			{Name: "WARC-Type", Value: TypeWarcinfo},
+			{Name: "WARC-Filename", Value: w.Filename},
+			{Name: "Content-Type", Value: "application/warc-fields"},
+		},
+		Content: content.Bytes(),
+	})
+}
+
+// WriteResponse writes a response record of resp, such as the responses a Crawler gives its callbacks.
+// The body is written decoded, as the Client decoded it
+func (w *Writer) WriteResponse(resp *owl.Response) error {
+	if resp.FinalURL == nil {
+		return errors.New("warc: response without URL")
+	}
+	header := resp.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	header.Del("Transfer-Encoding")
+	header.Set("Content-Length", strconv.Itoa(len(resp.Body)))
+	return w.writeResponse(resp.FinalURL.String(), resp.StatusCode, header, resp.Body, time.Now(), "")
+}
+
+// writeResponse writes a response record and indexes it, concurrent to the request record with the ID request
+func (w *Writer) writeResponse(target string, status int, header http.Header, body []byte, date time.Time, request string) error {
+	var block bytes.Buffer
+	fmt.Fprintf(&block, "HTTP/1.1 %d %s\r\n", status, http.StatusText(status))
+	header.Write(&block)
+	block.WriteString("\r\n")
+	block.Write(body)
+	rec := &Record{
+		Header: Header{
+			{Name: "WARC-Type", Value: TypeResponse},
+			{Name: "WARC-Record-ID", Value: newRecordID()},
+			{Name: "WARC-Date", Value: date.UTC().Format(time.RFC3339)},
+			{Name: "WARC-Target-URI", Value: target},
+			{Name: "Content-Type", Value: "application/http;msgtype=response"},
+			{Name: "WARC-Payload-Digest", Value: digest(body)},
+			{Name: "WARC-Block-Digest", Value: digest(block.Bytes())},
+		},
+		Content: block.Bytes(),
+	}
+	if request != "" {
+		rec.Header.Set("WARC-Concurrent-To", request)
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	offset, length, err := w.write(rec)
+	if err != nil {
+		return err
+	}
+	mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	w.index = append(w.index, CDXRecord{
+		URLKey:    SURT(target),
+		Timestamp: date.UTC().Format(timestampLayout),
+		URL:       target,
+		MIME:      mediaType,
+		Status:    status,
+		Digest:    strings.TrimPrefix(rec.Header.Get("WARC-Payload-Digest"), "sha1:"),
+		Length:    length,
+		Offset:    offset,
+		Filename:  w.Filename,
+	})
+	return nil
+}
+
+// Index returns the CDX index of the response records written so far, their offsets count
+// from the first byte written
+func (w *Writer) Index() []CDXRecord {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]CDXRecord(nil), w.index...)
+}
+
+// Recorder is an http.RoundTripper writing the requests it sends and their responses, as received,
+// to a Writer. Response bodies are read whole before they are returned
+type Recorder struct {
+	// Transport sends the requests, http.DefaultTransport when nil
+	Transport http.RoundTripper
+	// Errors receives the errors of writing the records, they are dropped when nil
+	Errors func(error)
+
+	w *Writer
+}
+
+var _ http.RoundTripper = (*Recorder)(nil)
+
+// RecordClient makes the http.Client of c send its requests through a new Recorder writing to w and returns it.
+// Proxies and TLS options must be set on c before
+func RecordClient(c *owl.Client, w *Writer) *Recorder {
+	if c.Client == nil {
+		c.Client = &http.Client{}
+	}
+	r := &Recorder{Transport: c.Client.Transport, w: w}
+	c.Client.Transport = r
+	return r
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	var reqBody []byte
+	if req.Body != nil && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			reqBody, _ = io.ReadAll(body)
+			body.Close()
+		}
+	}
+	date := time.Now()
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(raw))
+
+	requestID := newRecordID()
+	var block bytes.Buffer
+	fmt.Fprintf(&block, "%s %s HTTP/1.1\r\nHost: %s\r\n", req.Method, req.URL.RequestURI(), req.URL.Host)
+	req.Header.Write(&block)
+	block.WriteString("\r\n")
+	block.Write(reqBody)
+	target := req.URL.String()
+	err = r.w.WriteRecord(&Record{
+		Header: Header{
+			{Name: "WARC-Type", Value: TypeRequest},
+			{Name: "WARC-Record-ID", Value: requestID},
+			{Name: "WARC-Date", Value: date.UTC().Format(time.RFC3339)},
+			{Name: "WARC-Target-URI", Value: target},
+			{Name: "Content-Type", Value: "application/http;msgtype=request"},
+		},
+		Content: block.Bytes(),
+	})
+	if err == nil {
+		err = r.w.writeResponse(target, resp.StatusCode, resp.Header.Clone(), raw, date, requestID)
+	}
+	if err != nil && r.Errors != nil {
+		r.Errors(err)
+	}
+	return resp, nil
+}
+
+// digest returns the SHA-1 digest of b as WARC files write it
+func digest(b []byte) string {
+	sum := sha1.Sum(b)
+	return "sha1:" + base32.StdEncoding.EncodeToString(sum[:])
+}
+
+// newRecordID returns a random record ID
+func newRecordID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	// Version 4, variant 10
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("<urn:uuid:%x-%x-%x-%x-%x>", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
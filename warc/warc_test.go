@@ -0,0 +1,157 @@
+package warc
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/Patrickmitech/owl"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/logo.png" {
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte{0x89, 'P', 'N', 'G'})
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprintf(w, `<title>%s</title>`, r.URL.Path)
+	}))
+	defer srv.Close()
+
+	var file bytes.Buffer
+	w := NewGzipWriter(&file)
+	w.Filename = "crawl.warc.gz"
+	require.NoError(t, w.WriteWarcinfo(Header{{Name: "software", Value: "owl"}}))
+	client := &owl.Client{}
+	RecordClient(client, w)
+	for _, path := range []string{"/a", "/logo.png", "/b"} {
+		_, err := client.Get(srv.URL + path)
+		require.NoError(t, err)
+	}
+
+	r, err := NewReader(bytes.NewReader(file.Bytes()))
+	require.NoError(t, err)
+	var types []string
+	for {
+		rec, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		types = append(types, rec.Type())
+		require.Equal(t, Version, rec.Version)
+		require.Regexp(t, `^<urn:uuid:[0-9a-f-]{36}>$`, rec.Header.Get("warc-record-id"))
+	}
+	require.Equal(t, []string{"warcinfo", "request", "response", "request", "response", "request", "response"}, types)
+
+	docs, err := Documents(bytes.NewReader(file.Bytes()))
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	require.Equal(t, "/a", docs[0].Find("title").Text())
+	require.Equal(t, srv.URL+"/b", docs[1].URL().String())
+
+	index := w.Index()
+	require.Len(t, index, 3)
+	require.Equal(t, srv.URL+"/logo.png", index[1].URL)
+	require.Equal(t, "image/png", index[1].MIME)
+	require.Equal(t, 200, index[1].Status)
+	require.Equal(t, "crawl.warc.gz", index[1].Filename)
+	rec, err := ReadRecordAt(bytes.NewReader(file.Bytes()), index[2].Offset, index[2].Length)
+	require.NoError(t, err)
+	require.Equal(t, srv.URL+"/b", rec.TargetURI())
+	require.NotEmpty(t, rec.Header.Get("WARC-Concurrent-To"))
+	require.Equal(t, "sha1:"+index[2].Digest, rec.Header.Get("WARC-Payload-Digest"))
+	doc, err := rec.Parse()
+	require.NoError(t, err)
+	require.Equal(t, "/b", doc.Find("title").Text())
+}
+
+func TestWriteResponse(t *testing.T) {
+	var file bytes.Buffer
+	w := NewWriter(&file)
+	u, _ := url.Parse("https://example.com/page")
+	resp := &owl.Response{
+		StatusCode:  404,
+		Header:      http.Header{"Content-Type": {"text/html"}},
+		ContentType: "text/html",
+		FinalURL:    u,
+		Body:        []byte("<p>Not here</p>"),
+	}
+	require.NoError(t, w.WriteResponse(resp))
+	require.Error(t, w.WriteResponse(&owl.Response{}))
+	require.True(t, strings.HasPrefix(file.String(), "WARC/1.1\r\nWARC-Type: response\r\n"))
+
+	r, err := NewReader(&file)
+	require.NoError(t, err)
+	rec, err := r.Next()
+	require.NoError(t, err)
+	got, err := rec.Response()
+	require.NoError(t, err)
+	require.Equal(t, 404, got.StatusCode)
+	require.Equal(t, "<p>Not here</p>", string(got.Body))
+	require.Equal(t, "https://example.com/page", got.FinalURL.String())
+	_, err = r.Next()
+	require.Equal(t, io.EOF, err)
+	require.Equal(t, SURT("https://example.com/page"), w.Index()[0].URLKey)
+}
+
+func TestReader(t *testing.T) {
+	// A WARC 1.0 file with a folded header field, a gzipped body and a resource record
+	body := "HTTP/1.1 200 OK\r\nContent-Type: text/html\r\nContent-Encoding: gzip\r\n\r\n" + gzipString("<title>Old</title>")
+	file := "WARC/1.0\r\nWARC-Type: response\r\nWARC-Target-URI: <http://example.com/>\r\nContent-Type: application/http;\r\n msgtype=response\r\n" +
+		fmt.Sprintf("Content-Length: %d\r\n\r\n%s\r\n\r\n", len(body), body) +
+		"WARC/1.0\r\nWARC-Type: resource\r\nWARC-Target-URI: file:///notes.html\r\nContent-Type: text/html\r\nContent-Length: 20\r\n\r\n<title>Notes</title>\r\n\r\n" +
+		"WARC/1.0\r\nWARC-Type: metadata\r\nContent-Length: 0\r\n\r\n\r\n\r\n"
+	docs, err := Documents(strings.NewReader(file))
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	require.Equal(t, "Old", docs[0].Find("title").Text())
+	require.Equal(t, "http://example.com/", docs[0].URL().String())
+	require.Equal(t, "Notes", docs[1].Find("title").Text())
+
+	r, _ := NewReader(strings.NewReader(file))
+	for i := 0; i < 2; i++ {
+		r.Next()
+	}
+	rec, err := r.Next()
+	require.NoError(t, err)
+	_, err = rec.Response()
+	require.ErrorIs(t, err, ErrNotResponse)
+
+	for _, bad := range []string{
+		"HTTP/1.1 200 OK\r\n\r\n",
+		"WARC/1.1\r\nWARC-Type: response\r\n",
+		"WARC/1.1\r\nContent-Length: x\r\n\r\n",
+		"WARC/1.1\r\nContent-Length: 10\r\n\r\nshort",
+	} {
+		r, err := NewReader(strings.NewReader(bad))
+		require.NoError(t, err)
+		_, err = r.Next()
+		require.Error(t, err, bad)
+	}
+}
+
+func TestHeader(t *testing.T) {
+	h := Header{{Name: "A", Value: "1"}, {Name: "b", Value: "2"}, {Name: "a", Value: "3"}}
+	require.Equal(t, "1", h.Get("a"))
+	h.Set("a", "4")
+	h.Set("C", "5")
+	require.Equal(t, Header{{Name: "A", Value: "4"}, {Name: "b", Value: "2"}, {Name: "C", Value: "5"}}, h)
+}
+
+func gzipString(s string) string {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write([]byte(s))
+	gz.Close()
+	return buf.String()
+}